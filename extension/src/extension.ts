@@ -525,7 +525,7 @@ async function applyAnalyzerDiagnostics(
         diagnosticFilePath = path.join(baseDir, templateRoot, err.template);
         diagnosticLine = Math.max(0, err.line - 1);
         diagnosticCol = Math.max(0, err.column - 1);
-        diagnosticEndCol = diagnosticCol + (err.variable?.length || 1);
+        diagnosticEndCol = validationErrorEndCol(err, diagnosticCol);
 
         if (contextFile) {
           relatedInfo = [
@@ -556,7 +556,7 @@ async function applyAnalyzerDiagnostics(
 
       diagnosticLine = Math.max(0, err.line - 1);
       diagnosticCol = Math.max(0, err.column - 1);
-      diagnosticEndCol = diagnosticCol + (err.variable?.length || 1);
+      diagnosticEndCol = validationErrorEndCol(err, diagnosticCol);
 
       if (err.goFile) {
         const goFileAbs = path.join(path.resolve(workspaceRoot, sourceDir), err.goFile);
@@ -576,7 +576,7 @@ async function applyAnalyzerDiagnostics(
     const diag = new vscode.Diagnostic(
       range,
       err.message,
-      err.severity === 'warning' ? vscode.DiagnosticSeverity.Warning : vscode.DiagnosticSeverity.Error
+      validationErrorSeverity(err)
     );
     diag.source = 'GoTpl';
     if (relatedInfo) {
@@ -684,17 +684,34 @@ async function validateOpenTemplateDocuments(excludeDocKey?: string) {
   }
 }
 
+function validationErrorSeverity(err: GoValidationError): vscode.DiagnosticSeverity {
+  switch (err.severity) {
+    case 'warning': return vscode.DiagnosticSeverity.Warning;
+    case 'info': return vscode.DiagnosticSeverity.Information;
+    default: return vscode.DiagnosticSeverity.Error;
+  }
+}
+
+// validationErrorEndCol returns the zero-based end column of err, preferring
+// the exact span reported by the analyzer over the length of the variable.
+function validationErrorEndCol(err: GoValidationError, startCol: number): number {
+  if (err.endColumn && err.endColumn > err.column) {
+    return err.endColumn - 1;
+  }
+  return startCol + (err.variable?.length || 1);
+}
+
 function diagnosticsFromValidationErrors(errors: GoValidationError[]): vscode.Diagnostic[] {
   if (!errors) return [];
 
   return errors.map(err => {
     const line = Math.max(0, err.line - 1);
     const col = Math.max(0, err.column - 1);
-    const range = new vscode.Range(line, col, line, col + (err.variable?.length || 1));
+    const range = new vscode.Range(line, col, line, validationErrorEndCol(err, col));
     const diagnostic = new vscode.Diagnostic(
       range,
       err.message,
-      err.severity === 'warning' ? vscode.DiagnosticSeverity.Warning : vscode.DiagnosticSeverity.Error
+      validationErrorSeverity(err)
     );
     diagnostic.source = 'GoTpl';
     return diagnostic;
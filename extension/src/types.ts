@@ -46,9 +46,11 @@ export interface GoValidationError {
   template: string;
   line: number;
   column: number;
+  endLine?: number;    // end of the offending expression, when known
+  endColumn?: number;  // column just past the offending expression
   variable: string;
   message: string;
-  severity: 'error' | 'warning';
+  severity: 'error' | 'warning' | 'info' | 'syntax';
   goFile?: string;  // relative path to the .go file with the c.Render() call
   goLine?: number;  // line number of the c.Render() call
   templateNameStartCol?: number;
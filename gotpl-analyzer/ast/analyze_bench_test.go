@@ -0,0 +1,41 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// BenchmarkAnalyzeDirNoTemplates profiles the analyze-only path (no
+// -validate) on a generated codebase of many handlers and no template tree.
+func BenchmarkAnalyzeDirNoTemplates(b *testing.B) {
+	dir := b.TempDir()
+	files := map[string]string{
+		"go.mod": "module example.com/bench\ngo 1.21\n",
+		"main.go": `package main
+
+type Context struct{}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+func main() {}
+`,
+	}
+	for i := range 200 {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "package main\n\ntype Model%d struct {\n\tID   int\n\tName string\n\tTags []string\n}\n", i)
+		for j := range 5 {
+			fmt.Fprintf(&sb, "\nfunc handler%d_%d(c *Context) {\n\tc.Render(\"page%d.html\", map[string]interface{}{\"model\": Model%d{}})\n}\n", i, j, j, i)
+		}
+		files[fmt.Sprintf("h%d.go", i)] = sb.String()
+	}
+	writeTree(b, dir, files)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		result := AnalyzeDir(dir, "", DefaultConfig)
+		if len(result.RenderCalls) != 1000 {
+			b.Fatalf("expected 1000 render calls, got %d (errors: %v)", len(result.RenderCalls), result.Errors)
+		}
+		result.Flatten()
+	}
+}
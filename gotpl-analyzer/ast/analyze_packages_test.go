@@ -0,0 +1,81 @@
+package ast
+
+import "testing"
+
+// TestAnalyzePackagesRestrictsToPattern verifies that only the matched
+// package is scanned for render calls while struct docs from an imported
+// package of the same module are still indexed.
+func TestAnalyzePackagesRestrictsToPattern(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"go.mod": "module example.com/test\ngo 1.21\n",
+		"models/user.go": `package models
+
+type User struct {
+	// Name is the user's display name.
+	Name string
+}
+`,
+		"handlers/users.go": `package handlers
+
+import "example.com/test/models"
+
+type Context struct{}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+func Users(c *Context) {
+	c.Render("users.html", map[string]interface{}{
+		"user": models.User{},
+	})
+}
+`,
+		"admin/admin.go": `package admin
+
+type Context struct{}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+func Dashboard(c *Context) {
+	c.Render("admin.html", map[string]interface{}{})
+}
+`,
+	}
+	writeTree(t, tmpDir, files)
+
+	result := AnalyzePackagesIn(tmpDir, []string{"./handlers"}, "", DefaultConfig)
+	if len(result.Errors) > 0 {
+		t.Fatalf("analysis errors: %v", result.Errors)
+	}
+
+	if len(result.RenderCalls) != 1 {
+		t.Fatalf("expected 1 render call, got %d: %+v", len(result.RenderCalls), result.RenderCalls)
+	}
+	rc := result.RenderCalls[0]
+	if rc.Template != "users.html" || rc.File != "handlers/users.go" {
+		t.Errorf("unexpected render call %s in %s", rc.Template, rc.File)
+	}
+
+	var name *FieldInfo
+	for _, v := range rc.Vars {
+		if v.Name != "user" {
+			continue
+		}
+		for i := range v.Fields {
+			if v.Fields[i].Name == "Name" {
+				name = &v.Fields[i]
+			}
+		}
+	}
+	if name == nil {
+		t.Fatalf("expected user.Name field, got %+v", rc.Vars)
+	}
+	if name.Doc == "" {
+		t.Error("expected doc for models.User.Name from the imported package")
+	}
+
+	t.Chdir(tmpDir)
+	cwdResult := AnalyzePackages([]string{"./handlers"}, "", DefaultConfig)
+	if len(cwdResult.RenderCalls) != 1 || cwdResult.RenderCalls[0].File != rc.File {
+		t.Errorf("expected AnalyzePackages to resolve the pattern against the working directory, got %+v", cwdResult.RenderCalls)
+	}
+}
@@ -9,8 +9,12 @@ import (
 	goast "go/ast"
 	"go/token"
 	"io/fs"
+	"log/slog"
+	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"time"
 
 	"golang.org/x/tools/go/packages"
 )
@@ -28,28 +32,21 @@ import (
 // passes the pkgs slice to every downstream step, eliminating the redundant
 // packages.Load that previously happened inside the context-enrichment branch.
 func AnalyzeDir(dir string, contextFile string, config AnalysisConfig) AnalysisResult {
-	result := AnalysisResult{}
-	fset := token.NewFileSet()
-	cfg := &packages.Config{
-		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
-			packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports,
-		Dir:   dir,
-		Fset:  fset,
-		Tests: false,
-	}
-
 	var loadDirs []string
 	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
 		if d.IsDir() {
 			name := d.Name()
 			if name == "vendor" ||
 				name == "node_modules" ||
-				name == "testdata" ||
+				(name == "testdata" && !config.IncludeTestdata) ||
 				name == "tests" ||
 				strings.HasPrefix(name, ".") ||
 				strings.HasPrefix(name, "generated") {
 				return filepath.SkipDir
 			}
+			if path != dir && config.IgnoreDir != nil && config.IgnoreDir(path) {
+				return filepath.SkipDir
+			}
 
 			relPath, err := filepath.Rel(dir, path)
 			if err != nil {
@@ -61,44 +58,162 @@ func AnalyzeDir(dir string, contextFile string, config AnalysisConfig) AnalysisR
 		return nil
 	})
 
-	pkgs, err := packages.Load(cfg, loadDirs...)
+	return analyzePatterns(dir, loadDirs, contextFile, config)
+}
+
+// AnalyzePackages is AnalyzeDir restricted to the packages matched by
+// patterns, which are passed to packages.Load as-is (for example "./handlers",
+// "example.com/app/..." or "file=handlers/users.go") and resolved relative to
+// the current working directory.
+//
+// Render calls, function maps and context setters are only reported for the
+// matched packages, not their dependencies. Struct types declared in imported
+// packages of the same module are still indexed so that field documentation
+// and definition positions resolve as they do for a whole-tree analysis.
+func AnalyzePackages(patterns []string, contextFile string, config AnalysisConfig) AnalysisResult {
+	dir, err := os.Getwd()
+	if err != nil {
+		return AnalysisResult{Errors: []string{fmt.Sprintf("load error: %v", err)}}
+	}
+	return analyzePatterns(dir, patterns, contextFile, config)
+}
+
+// AnalyzePackagesIn is AnalyzePackages with patterns resolved relative to dir
+// instead of the current working directory.
+func AnalyzePackagesIn(dir string, patterns []string, contextFile string, config AnalysisConfig) AnalysisResult {
+	return analyzePatterns(dir, patterns, contextFile, config)
+}
+
+// analyzePatterns loads the packages matched by patterns relative to dir and
+// runs the analysis on them. File paths in the result are relative to dir.
+func analyzePatterns(dir string, patterns []string, contextFile string, config AnalysisConfig) AnalysisResult {
+	result := AnalysisResult{}
+	logger := config.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+
+	fset := token.NewFileSet()
+	start := time.Now()
+	pkgs, err := packages.Load(newLoadConfig(dir, fset, config), patterns...)
 	if err != nil {
 		result.Errors = append(result.Errors, fmt.Sprintf("load error: %v", err))
 		return result
 	}
 
-	info, allFiles := mergeTypeInfo(pkgs, &result)
+	info, allFiles := mergeTypeInfo(pkgs, &result, config.IncludeTests)
+	config.contextInterface = lookupContextInterface(info, config.ContextInterfaceName)
+	logger.Info("loaded packages", "patterns", len(patterns), "packages", len(pkgs), "files", len(allFiles), "duration", time.Since(start))
 
 	var filesMap map[string]*goast.File
 	var structIndex map[string]structIndexEntry
 
-	filesMap = buildFileMap(allFiles, fset)
+	// Imported module packages are indexed for struct metadata only; their
+	// functions are not scanned for render calls.
+	indexFiles := append(slices.Clip(allFiles), loadDependencySyntax(pkgs, fset, dir, config.BuildFlags)...)
+	start = time.Now()
+	filesMap = buildFileMap(indexFiles, fset)
 	structIndex = buildStructIndex(fset, filesMap)
+	logger.Debug("indexed structs", "files", len(indexFiles), "structs", len(structIndex), "duration", time.Since(start))
 
 	fc := newFieldCache()
+	fc.includeUnexported = config.IncludeUnexported
+	fc.warnDeprecated = config.WarnDeprecated
 	seenPool := newSeenMapPool()
 
 	//  Collect function scopes (concurrent)
+	start = time.Now()
 	scopes := collectFuncScopesOptimized(allFiles, info, fset, structIndex, fc, config, filesMap, seenPool)
+	logger.Info("collected func scopes", "scopes", len(scopes), "duration", time.Since(start))
 
 	// Extract global implicit variables
 	globalImplicitVars := extractGlobalImplicitVars(scopes)
 
 	// Generate render calls
-	result.RenderCalls = generateRenderCalls(scopes, globalImplicitVars, info, fset, dir, structIndex, fc, seenPool)
+	start = time.Now()
+	result.RenderCalls = generateRenderCalls(scopes, globalImplicitVars, info, fset, dir, structIndex, fc, seenPool, config)
+	result.UnresolvedRenderCalls = collectUnresolvedRenderCalls(scopes, fset, dir)
+	result.Errors = append(result.Errors, collectCappedNameNotes(scopes, fset, dir, config)...)
+	if config.IncludeTests {
+		// A package's non-test files are loaded twice, once on their own and
+		// once in the package's test variant, so their calls are found twice.
+		result.RenderCalls = dedupeRenderCalls(result.RenderCalls)
+		result.UnresolvedRenderCalls = dedupeRenderCalls(result.UnresolvedRenderCalls)
+	}
 
 	// Aggregate function maps
 	result.FuncMaps = aggregateFuncMaps(scopes)
+	if config.SecurityLints {
+		markUnescapedFuncs(result.FuncMaps, config.RawHTMLFuncs)
+	}
+	logger.Info("generated render calls",
+		"renderCalls", len(result.RenderCalls), "unresolved", len(result.UnresolvedRenderCalls),
+		"funcMaps", len(result.FuncMaps), "duration", time.Since(start))
 
 	// Context enrichment – reuse already-loaded pkgs, no second Load! ───
 	if contextFile != "" {
-		result.RenderCalls = enrichRenderCallsWithContext(
+		start = time.Now()
+		calls, err := enrichRenderCallsWithContext(
 			result.RenderCalls, contextFile, pkgs, structIndex, fc, fset, config, seenPool,
 		)
+		if err != nil {
+			result.Errors = append(result.Errors, err.Error())
+		}
+		result.RenderCalls = calls
+		logger.Info("applied context file", "file", contextFile, "renderCalls", len(calls), "duration", time.Since(start))
 	}
+
+	result.Errors = append(result.Errors, fc.shadowedMethodNotes()...)
 	return result
 }
 
+// AnalyzeContextFile builds render calls from contextFile alone, without
+// loading any Go packages. Every template named in the file becomes a
+// synthetic render call carrying its own variables plus the global ones.
+// Types cannot be resolved without source, so variables keep the type
+// strings written in the file and have no field trees.
+func AnalyzeContextFile(contextFile string, config AnalysisConfig) AnalysisResult {
+	result := AnalysisResult{}
+	logger := config.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+
+	start := time.Now()
+	fc := newFieldCache()
+	fc.includeUnexported = config.IncludeUnexported
+	fc.warnDeprecated = config.WarnDeprecated
+	calls, err := enrichRenderCallsWithContext(
+		nil, contextFile, nil, map[string]structIndexEntry{}, fc, token.NewFileSet(), config, newSeenMapPool(),
+	)
+	if err != nil {
+		result.Errors = append(result.Errors, err.Error())
+	}
+	result.RenderCalls = calls
+	logger.Info("applied context file", "file", contextFile, "renderCalls", len(calls), "duration", time.Since(start))
+
+	return result
+}
+
+// dedupeRenderCalls drops every render call with the same file, line and
+// template as an earlier one, keeping the order of the rest.
+func dedupeRenderCalls(calls []RenderCall) []RenderCall {
+	type callKey struct {
+		file     string
+		line     int
+		template string
+	}
+	seen := make(map[callKey]bool, len(calls))
+	return slices.DeleteFunc(calls, func(rc RenderCall) bool {
+		key := callKey{rc.File, rc.Line, rc.Template}
+		if seen[key] {
+			return true
+		}
+		seen[key] = true
+		return false
+	})
+}
+
 // extractGlobalImplicitVars identifies template variables that are set outside
 // any render call context (e.g. in middleware functions).  These are available
 // to every template.
@@ -112,6 +227,16 @@ func extractGlobalImplicitVars(scopes []FuncScope) []TemplateVar {
 	return globalVars
 }
 
+// markUnescapedFuncs flags the function-map entries named in rawFuncs as
+// bypassing auto-escaping.
+func markUnescapedFuncs(funcMaps []FuncMapInfo, rawFuncs []string) {
+	for i := range funcMaps {
+		if slices.Contains(rawFuncs, funcMaps[i].Name) {
+			funcMaps[i].Unescaped = true
+		}
+	}
+}
+
 // aggregateFuncMaps collects all function-map definitions from scopes and
 // deduplicates by name.
 func aggregateFuncMaps(scopes []FuncScope) []FuncMapInfo {
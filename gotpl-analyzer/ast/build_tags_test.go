@@ -0,0 +1,60 @@
+package ast
+
+import "testing"
+
+// TestBuildFlagsIncludeTaggedFiles verifies that render calls in files behind
+// a build constraint are only found when the tag is passed via BuildFlags.
+func TestBuildFlagsIncludeTaggedFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"go.mod": "module example.com/test\ngo 1.21\n",
+		"main.go": `package main
+
+type Context struct{}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+func home(c *Context) {
+	c.Render("home.html", map[string]interface{}{})
+}
+`,
+		"prod.go": `//go:build prod
+
+package main
+
+func status(c *Context) {
+	c.Render("status.html", map[string]interface{}{})
+}
+`,
+		// A package consisting only of tagged files must not surface
+		// "build constraints exclude all Go files" as an analysis error.
+		"ops/ops.go": `//go:build prod
+
+package ops
+`,
+	}
+	writeTree(t, tmpDir, files)
+
+	templates := func(result AnalysisResult) map[string]bool {
+		if len(result.Errors) > 0 {
+			t.Fatalf("analysis errors: %v", result.Errors)
+		}
+		found := make(map[string]bool)
+		for _, rc := range result.RenderCalls {
+			found[rc.Template] = true
+		}
+		return found
+	}
+
+	found := templates(AnalyzeDir(tmpDir, "", DefaultConfig))
+	if !found["home.html"] || found["status.html"] {
+		t.Errorf("expected only home.html without tags, got %v", found)
+	}
+
+	config := DefaultConfig
+	config.BuildFlags = []string{"-tags=prod"}
+	found = templates(AnalyzeDir(tmpDir, "", config))
+	if !found["home.html"] || !found["status.html"] {
+		t.Errorf("expected home.html and status.html with -tags=prod, got %v", found)
+	}
+}
@@ -1,26 +1,38 @@
 package ast
 
-import "sync"
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
 
 // cachedFields stores pre-extracted field information to avoid redundant work.
 // Each struct type's fields are computed once and reused throughout analysis.
 type cachedFields struct {
-	fields []FieldInfo // Exported fields and methods
+	fields []FieldInfo // Exported fields and methods (plus unexported fields if enabled)
 	doc    string      // Struct-level documentation
 }
 
 // fieldCache provides concurrent-safe caching for struct field extraction.
 // This is critical for performance when analyzing large codebases with
 // many references to the same types.
+//
+// The cache also carries extraction options that change what gets cached, so
+// a cache is only ever shared by extractions using the same options.
 type fieldCache struct {
-	mu    sync.RWMutex            // Protects concurrent map access
-	cache map[string]cachedFields // Cache storage (keyed by full type string)
+	mu                sync.RWMutex            // Protects concurrent map access
+	cache             map[string]cachedFields // Cache storage (keyed by full type string)
+	includeUnexported bool                    // Extract unexported struct fields too
+	warnDeprecated    bool                    // Record deprecation notes on fields
+	shadowNotes       map[string]bool         // Field/method name collisions found during extraction
 }
 
 // newFieldCache initializes a fieldCache with reasonable default capacity.
 func newFieldCache() *fieldCache {
 	return &fieldCache{
-		cache: make(map[string]cachedFields, 256),
+		cache:       make(map[string]cachedFields, 256),
+		shadowNotes: make(map[string]bool),
 	}
 }
 
@@ -40,6 +52,22 @@ func (fc *fieldCache) set(k string, v cachedFields) {
 	fc.mu.Unlock()
 }
 
+// noteShadowedMethod records that typeName has a field and a method both
+// called name, where the field wins.
+func (fc *fieldCache) noteShadowedMethod(typeName, name string) {
+	fc.mu.Lock()
+	fc.shadowNotes[fmt.Sprintf("Type %s has both a field and method named %q", typeName, name)] = true
+	fc.mu.Unlock()
+}
+
+// shadowedMethodNotes returns the recorded collision warnings, sorted.
+func (fc *fieldCache) shadowedMethodNotes() []string {
+	fc.mu.RLock()
+	notes := slices.Sorted(maps.Keys(fc.shadowNotes))
+	fc.mu.RUnlock()
+	return notes
+}
+
 // seenMapPool manages a pool of maps used to track visited types during
 // recursive traversals. Pooling prevents excessive allocations, especially
 // important when processing deeply nested type hierarchies.
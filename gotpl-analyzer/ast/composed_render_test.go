@@ -0,0 +1,54 @@
+package ast
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestComposedRenderCall verifies that a []string of template names yields a
+// single render call of the last name, with the others in ComposedWith.
+func TestComposedRenderCall(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	src := `package main
+
+type Context struct{}
+func (c *Context) Render(tpl any, data map[string]any) {}
+
+const layout = "layouts/base.html"
+
+func handler(c *Context) {
+	c.Render([]string{layout, "partials/nav.html", "page.html"}, map[string]any{"title": "Home"})
+	c.Render([]string{"single.html"}, map[string]any{"title": "Single"})
+}
+`
+	writeTestModule(t, tmpDir, src)
+
+	result := AnalyzeDir(tmpDir, "", DefaultConfig)
+	if len(result.Errors) > 0 {
+		t.Fatalf("analysis errors: %v", result.Errors)
+	}
+	if len(result.RenderCalls) != 2 {
+		t.Fatalf("expected 2 render calls, got %+v", result.RenderCalls)
+	}
+
+	rc := result.RenderCalls[0]
+	if rc.Template != "page.html" || rc.ResolvedVia != ResolvedViaSlice {
+		t.Errorf("expected page.html resolved via slice, got %q via %q", rc.Template, rc.ResolvedVia)
+	}
+	if want := []string{"layouts/base.html", "partials/nav.html"}; !slices.Equal(rc.ComposedWith, want) {
+		t.Errorf("expected ComposedWith %v, got %v", want, rc.ComposedWith)
+	}
+	if len(rc.Vars) == 0 || rc.Vars[0].Name != "title" {
+		t.Errorf("expected the data to be attached to page.html, got %+v", rc.Vars)
+	}
+	// The name range covers the "page.html" literal without its quotes.
+	line := `	c.Render([]string{layout, "partials/nav.html", "page.html"}, map[string]any{"title": "Home"})`
+	if start := rc.TemplateNameStartCol; start < 1 || line[start-1:rc.TemplateNameEndCol-1] != "page.html" {
+		t.Errorf("unexpected name range %d-%d", rc.TemplateNameStartCol, rc.TemplateNameEndCol)
+	}
+
+	if single := result.RenderCalls[1]; single.Template != "single.html" || len(single.ComposedWith) != 0 {
+		t.Errorf("expected a plain call of single.html, got %+v", single)
+	}
+}
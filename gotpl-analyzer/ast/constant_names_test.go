@@ -0,0 +1,63 @@
+package ast
+
+import "testing"
+
+// TestConstantTemplateNames verifies that template names given as typed
+// string constants, constants of another package and constant expressions are
+// resolved.
+func TestConstantTemplateNames(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	pages := `package pages
+
+type TplName string
+
+const Home TplName = "home.html"
+
+const Prefix = "admin/"
+`
+	src := `package main
+
+import "example.com/test/pages"
+
+type Context struct{}
+func (c *Context) Render(tpl pages.TplName, data map[string]interface{}) {}
+func Render(c *Context, tpl string, data map[string]interface{}) {}
+
+const settings pages.TplName = pages.Prefix + "settings.html"
+
+func handler(c *Context) {
+	c.Render(pages.Home, map[string]interface{}{})
+	c.Render(settings, map[string]interface{}{})
+	Render(c, string(pages.Home), map[string]interface{}{})
+	Render(c, pages.Prefix+"users.html", map[string]interface{}{})
+}
+`
+	writeTree(t, tmpDir, map[string]string{
+		"main.go":        src,
+		"pages/pages.go": pages,
+		"go.mod":         "module example.com/test\ngo 1.21\n",
+	})
+
+	result := AnalyzeDir(tmpDir, "", DefaultConfig)
+	if len(result.Errors) > 0 {
+		t.Fatalf("analysis errors: %v", result.Errors)
+	}
+	if len(result.UnresolvedRenderCalls) > 0 {
+		t.Fatalf("unexpected unresolved render calls: %+v", result.UnresolvedRenderCalls)
+	}
+
+	want := map[string]int{"home.html": 2, "admin/settings.html": 1, "admin/users.html": 1}
+	got := make(map[string]int)
+	for _, rc := range result.RenderCalls {
+		got[rc.Template]++
+		if rc.ResolvedVia != ResolvedViaConstant {
+			t.Errorf("%s (%s): expected via %s, got %s", rc.Template, rc.TemplateExpr, ResolvedViaConstant, rc.ResolvedVia)
+		}
+	}
+	for name, n := range want {
+		if got[name] != n {
+			t.Errorf("expected %d render calls for %s, got %d (all: %v)", n, name, got[name], got)
+		}
+	}
+}
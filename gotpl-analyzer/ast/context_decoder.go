@@ -0,0 +1,175 @@
+package ast
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/internal/textconf"
+)
+
+// contextDecoder unmarshals a context file into the shape used by context
+// enrichment: template name → variable name → type string.
+type contextDecoder func(data []byte) (map[string]map[string]string, error)
+
+// contextFormat pairs a human-readable format name with its decoder.
+type contextFormat struct {
+	name   string
+	decode contextDecoder
+}
+
+// contextFormats maps context file extensions to their decoders. Files with
+// any other extension are decoded as JSON, the original format.
+//
+// The YAML and TOML decoders are deliberately small: a context file is a
+// two-level table of strings, so they accept exactly that subset of each
+// format (plus comments) instead of pulling in full parsers as dependencies.
+var contextFormats = map[string]contextFormat{
+	".json": {name: "JSON", decode: decodeJSONContext},
+	".yaml": {name: "YAML", decode: decodeYAMLContext},
+	".yml":  {name: "YAML", decode: decodeYAMLContext},
+	".toml": {name: "TOML", decode: decodeTOMLContext},
+}
+
+// decodeContextFile detects the context file format from its extension and
+// decodes data. The detected format name is returned for error reporting.
+func decodeContextFile(path string, data []byte) (map[string]map[string]string, string, error) {
+	format, ok := contextFormats[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		format = contextFormats[".json"]
+	}
+	config, err := format.decode(data)
+	return config, format.name, err
+}
+
+func decodeJSONContext(data []byte) (map[string]map[string]string, error) {
+	var config map[string]map[string]string
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// decodeYAMLContext decodes a YAML mapping of mappings:
+//
+//	# comment
+//	global:
+//	  currentUser: models.User
+//	"views/users.html":
+//	  users: "[]models.User"
+//
+// Values are plain or quoted scalars; an empty template can be written as {}.
+func decodeYAMLContext(data []byte) (map[string]map[string]string, error) {
+	config := make(map[string]map[string]string)
+	var current map[string]string
+	childIndent := -1
+
+	lines, err := textconf.YAMLLines(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range lines {
+		key, value, err := splitYAMLPair(l.Text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", l.Num, err)
+		}
+
+		if l.Indent == 0 {
+			current = make(map[string]string)
+			config[key] = current
+			childIndent = -1
+			switch value {
+			case "", "{}":
+			default:
+				return nil, fmt.Errorf("line %d: template %q must map to variables, got %q", l.Num, key, value)
+			}
+			continue
+		}
+
+		if current == nil {
+			return nil, fmt.Errorf("line %d: variable %q is not inside a template", l.Num, key)
+		}
+		if childIndent == -1 {
+			childIndent = l.Indent
+		} else if l.Indent != childIndent {
+			return nil, fmt.Errorf("line %d: inconsistent indentation", l.Num)
+		}
+		if value == "" {
+			return nil, fmt.Errorf("line %d: variable %q has no type", l.Num, key)
+		}
+		current[key] = value
+	}
+
+	return config, nil
+}
+
+// splitYAMLPair splits "key: value" into its unquoted key and value.
+func splitYAMLPair(s string) (string, string, error) {
+	key, rest, err := textconf.ReadKey(s, ':')
+	if err != nil {
+		return "", "", err
+	}
+	value, err := textconf.Unquote(strings.TrimSpace(rest))
+	if err != nil {
+		return "", "", err
+	}
+	return key, value, nil
+}
+
+// decodeTOMLContext decodes TOML tables of string values:
+//
+//	# comment
+//	[global]
+//	currentUser = "models.User"
+//
+//	["views/users.html"]
+//	users = "[]models.User"
+func decodeTOMLContext(data []byte) (map[string]map[string]string, error) {
+	config := make(map[string]map[string]string)
+	var current map[string]string
+
+	for i, raw := range strings.Split(string(data), "\n") {
+		lineNum := i + 1
+		line := strings.TrimSpace(textconf.StripComment(raw))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") || strings.HasPrefix(line, "[[") {
+				return nil, fmt.Errorf("line %d: invalid table header %q", lineNum, line)
+			}
+			name, err := textconf.Unquote(strings.TrimSpace(line[1 : len(line)-1]))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", lineNum, err)
+			}
+			if name == "" {
+				return nil, fmt.Errorf("line %d: empty table name", lineNum)
+			}
+			current = make(map[string]string)
+			config[name] = current
+			continue
+		}
+
+		if current == nil {
+			return nil, fmt.Errorf("line %d: key outside of a [template] table", lineNum)
+		}
+
+		key, rest, err := textconf.ReadKey(line, '=')
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNum, err)
+		}
+		rest = strings.TrimSpace(rest)
+		if !strings.HasPrefix(rest, `"`) && !strings.HasPrefix(rest, "'") {
+			return nil, fmt.Errorf("line %d: value of %q must be a string", lineNum, key)
+		}
+		value, err := textconf.Unquote(rest)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNum, err)
+		}
+		current[key] = value
+	}
+
+	return config, nil
+}
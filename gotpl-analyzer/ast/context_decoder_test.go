@@ -0,0 +1,139 @@
+package ast
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestDecodeContextFileFormats verifies that YAML, TOML and JSON context files
+// decode to the same template → variable → type table.
+func TestDecodeContextFileFormats(t *testing.T) {
+	want := map[string]map[string]string{
+		"global":           {"currentUser": "models.User"},
+		"views/users.html": {"users": "[]models.User", "title": "string"},
+		"empty.html":       {},
+	}
+
+	cases := []struct {
+		path   string
+		format string
+		data   string
+	}{
+		{
+			path:   "ctx.json",
+			format: "JSON",
+			data: `{
+  "global": {"currentUser": "models.User"},
+  "views/users.html": {"users": "[]models.User", "title": "string"},
+  "empty.html": {}
+}`,
+		},
+		{
+			path:   "ctx.yaml",
+			format: "YAML",
+			data: `# shared context
+global:
+  currentUser: models.User
+"views/users.html":
+  users: "[]models.User" # quoted because of the brackets
+  title: 'string'
+empty.html: {}
+`,
+		},
+		{
+			path:   "ctx.TOML",
+			format: "TOML",
+			data: `# shared context
+[global]
+currentUser = "models.User"
+
+["views/users.html"]
+users = "[]models.User"
+title = 'string'
+
+["empty.html"]
+`,
+		},
+		{
+			path:   "ctx.conf",
+			format: "JSON",
+			data:   `{"global": {"currentUser": "models.User"}, "views/users.html": {"users": "[]models.User", "title": "string"}, "empty.html": {}}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.path, func(t *testing.T) {
+			got, format, err := decodeContextFile(tc.path, []byte(tc.data))
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if format != tc.format {
+				t.Errorf("expected format %s, got %s", tc.format, format)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("decoded %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestDecodeContextFileErrors(t *testing.T) {
+	cases := []struct {
+		path string
+		data string
+		msg  string
+	}{
+		{"ctx.yaml", "global:\n\tuser: models.User\n", "tabs"},
+		{"ctx.yaml", "global:\n  user: models.User\n    other: string\n", "inconsistent indentation"},
+		{"ctx.yaml", "  user: models.User\n", "not inside a template"},
+		{"ctx.yml", "global: models.User\n", "must map to variables"},
+		{"ctx.toml", "user = \"models.User\"\n", "outside of a [template] table"},
+		{"ctx.toml", "[global]\nuser = models.User\n", "must be a string"},
+	}
+
+	for _, tc := range cases {
+		_, _, err := decodeContextFile(tc.path, []byte(tc.data))
+		if err == nil || !strings.Contains(err.Error(), tc.msg) {
+			t.Errorf("%s %q: expected error containing %q, got %v", tc.path, tc.data, tc.msg, err)
+		}
+	}
+}
+
+// TestAnalyzeDirContextFileParseError verifies that a malformed context file
+// is reported in result.Errors with its detected format instead of aborting.
+func TestAnalyzeDirContextFileParseError(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	src := `package main
+
+type Context struct{}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+func handler(c *Context) {
+	c.Render("page.html", map[string]interface{}{"title": "Home"})
+}
+`
+	writeTestModule(t, tmpDir, src)
+	contextFile := filepath.Join(tmpDir, "gotpl.toml")
+	if err := os.WriteFile(contextFile, []byte("[global]\nuser = models.User\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	result := AnalyzeDir(tmpDir, contextFile, DefaultConfig)
+
+	found := false
+	for _, e := range result.Errors {
+		if strings.Contains(e, "as TOML") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected a TOML parse error in result.Errors, got %v", result.Errors)
+	}
+	if len(result.RenderCalls) != 1 || result.RenderCalls[0].Template != "page.html" {
+		t.Errorf("expected render calls to be left unchanged, got %+v", result.RenderCalls)
+	}
+}
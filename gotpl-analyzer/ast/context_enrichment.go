@@ -1,18 +1,26 @@
 package ast
 
 import (
-	"encoding/json"
+	"fmt"
 	"go/token"
 	"go/types"
 	"log"
 	"os"
+	"path"
+	"slices"
 	"strings"
 
 	"golang.org/x/tools/go/packages"
 )
 
+// ContextFileRenderCall is the File value given to synthetic RenderCall
+// entries created from the context file for templates that no Go render call
+// targets.
+const ContextFileRenderCall = "context-file"
+
 // enrichRenderCallsWithContext augments RenderCall entries with variables
-// defined in an external JSON context file.
+// defined in an external context file (JSON, YAML or TOML, by extension).
+// A file that fails to parse is reported as an error and leaves calls unchanged.
 func enrichRenderCallsWithContext(
 	calls []RenderCall,
 	contextFile string,
@@ -22,21 +30,22 @@ func enrichRenderCallsWithContext(
 	fset *token.FileSet,
 	config AnalysisConfig,
 	seenPool *seenMapPool,
-) []RenderCall {
+) ([]RenderCall, error) {
 	data, err := os.ReadFile(contextFile)
 	if err != nil {
 		log.Fatalf("context file not found: %v", contextFile)
 	}
 
-	var contextConfig map[string]map[string]string
-	if err := json.Unmarshal(data, &contextConfig); err != nil {
-		log.Fatalf("error parsing context file json: %v: %v", contextFile, err)
+	contextConfig, format, err := decodeContextFile(contextFile, data)
+	if err != nil {
+		return calls, fmt.Errorf("error parsing context file %s as %s: %v", contextFile, format, err)
 	}
 
+	contextConfig = normalizeContextKeys(contextConfig)
 	typeMap := buildTypeMap(pkgs)
 
 	globalVars := buildTemplateVarsOptimized(
-		contextConfig[config.GlobalTemplateName],
+		contextConfig[templateKey(config.GlobalTemplateName)],
 		typeMap,
 		structIndex,
 		fc,
@@ -48,7 +57,7 @@ func enrichRenderCallsWithContext(
 	calls = enrichExistingCalls(calls, contextConfig, globalVars, typeMap, structIndex, fc, fset, seenPool, seenTpls)
 	calls = addSyntheticCalls(calls, contextConfig, globalVars, typeMap, structIndex, fc, fset, config, seenPool, seenTpls)
 
-	return calls
+	return calls, nil
 }
 
 // isStdlibPkg reports whether a package ID looks like a standard library package
@@ -121,7 +130,10 @@ func buildTypeMap(pkgs []*packages.Package) map[string]*types.TypeName {
 	return typeMap
 }
 
-// enrichExistingCalls adds context-defined variables to existing render calls.
+// enrichExistingCalls adds context-defined variables to existing render calls
+// and records their templates in seenTpls. contextConfig and seenTpls are
+// keyed by templateKey, so "./page.html" in code picks up the variables of
+// "page.html" in the context file.
 func enrichExistingCalls(
 	calls []RenderCall,
 	contextConfig map[string]map[string]string,
@@ -134,12 +146,13 @@ func enrichExistingCalls(
 	seenTpls map[string]bool,
 ) []RenderCall {
 	for i, call := range calls {
-		seenTpls[call.Template] = true
+		key := templateKey(call.Template)
+		seenTpls[key] = true
 
 		base := make([]TemplateVar, 0, len(globalVars)+len(call.Vars)+8)
 		base = append(base, globalVars...)
 
-		if tplVars, ok := contextConfig[call.Template]; ok {
+		if tplVars, ok := contextConfig[key]; ok {
 			base = append(base, buildTemplateVarsOptimized(tplVars, typeMap, structIndex, fc, fset, seenPool)...)
 		}
 
@@ -151,7 +164,8 @@ func enrichExistingCalls(
 }
 
 // addSyntheticCalls creates RenderCall entries for templates defined in
-// context but not found in the codebase.
+// context but not found in the codebase. contextConfig and seenTpls are keyed
+// by templateKey.
 func addSyntheticCalls(
 	calls []RenderCall,
 	contextConfig map[string]map[string]string,
@@ -165,7 +179,7 @@ func addSyntheticCalls(
 	seenTpls map[string]bool,
 ) []RenderCall {
 	for tplName, tplVars := range contextConfig {
-		if tplName == config.GlobalTemplateName || seenTpls[tplName] {
+		if tplName == templateKey(config.GlobalTemplateName) || seenTpls[tplName] {
 			continue
 		}
 
@@ -174,7 +188,7 @@ func addSyntheticCalls(
 		newVars = append(newVars, buildTemplateVarsOptimized(tplVars, typeMap, structIndex, fc, fset, seenPool)...)
 
 		calls = append(calls, RenderCall{
-			File:     "context-file",
+			File:     ContextFileRenderCall,
 			Line:     1,
 			Template: tplName,
 			Vars:     newVars,
@@ -184,6 +198,47 @@ func addSyntheticCalls(
 	return calls
 }
 
+// templateKey normalizes a template name for matching render calls against
+// context file entries: backslashes become slashes and the path is cleaned, so
+// "./page.html", "page.html" and "views//page.html" compare by the file they
+// name.
+func templateKey(name string) string {
+	if name == "" {
+		return name
+	}
+	return path.Clean(strings.ReplaceAll(name, "\\", "/"))
+}
+
+// normalizeContextKeys rekeys contextConfig by templateKey. Sections naming
+// the same template are merged; on conflicting variables the section whose
+// name sorts first wins.
+func normalizeContextKeys(contextConfig map[string]map[string]string) map[string]map[string]string {
+	names := make([]string, 0, len(contextConfig))
+	for name := range contextConfig {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	normalized := make(map[string]map[string]string, len(contextConfig))
+	for _, name := range names {
+		key := templateKey(name)
+		existing, ok := normalized[key]
+		if !ok {
+			normalized[key] = contextConfig[name]
+			continue
+		}
+		merged := make(map[string]string, len(existing)+len(contextConfig[name]))
+		for v, typ := range contextConfig[name] {
+			merged[v] = typ
+		}
+		for v, typ := range existing {
+			merged[v] = typ
+		}
+		normalized[key] = merged
+	}
+	return normalized
+}
+
 // buildTemplateVarsOptimized constructs TemplateVar entries from type string
 // definitions in the context file.
 func buildTemplateVarsOptimized(
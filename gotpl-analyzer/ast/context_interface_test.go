@@ -0,0 +1,70 @@
+package ast
+
+import "testing"
+
+// TestContextInterfaceName verifies that Set and Render calls are found on
+// handler parameters typed as an interface the context satisfies, and on
+// other types implementing it, once ContextInterfaceName names it.
+func TestContextInterfaceName(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	src := `package main
+
+type Renderer interface {
+	Set(key string, val any)
+	Render(tpl string, data any) error
+}
+
+type appCtx struct{}
+func (c *appCtx) Set(key string, val any) {}
+func (c *appCtx) Render(tpl string, data any) error { return nil }
+
+type User struct{ Name string }
+
+func profile(r Renderer) {
+	r.Set("user", User{})
+	r.Render("profile.html", nil)
+}
+
+func home(c *appCtx) {
+	c.Set("title", "Home")
+	c.Render("home.html", nil)
+}
+`
+	writeTestModule(t, tmpDir, src)
+
+	varsByTemplate := func(result AnalysisResult) map[string][]string {
+		vars := make(map[string][]string)
+		for _, rc := range result.RenderCalls {
+			vars[rc.Template] = nil
+			for _, v := range rc.Vars {
+				vars[rc.Template] = append(vars[rc.Template], v.Name)
+			}
+		}
+		return vars
+	}
+
+	// Without the interface, neither receiver is the "Context" type.
+	result := AnalyzeDir(tmpDir, "", DefaultConfig)
+	if len(result.Errors) > 0 {
+		t.Fatalf("analysis errors: %v", result.Errors)
+	}
+	vars := varsByTemplate(result)
+	if len(vars) != 2 || len(vars["profile.html"]) != 0 || len(vars["home.html"]) != 0 {
+		t.Fatalf("expected both render calls without variables, got %v", vars)
+	}
+
+	config := DefaultConfig
+	config.ContextInterfaceName = "Renderer"
+	result = AnalyzeDir(tmpDir, "", config)
+	if len(result.Errors) > 0 {
+		t.Fatalf("analysis errors: %v", result.Errors)
+	}
+	vars = varsByTemplate(result)
+	if len(vars["profile.html"]) != 1 || vars["profile.html"][0] != "user" {
+		t.Errorf("expected user on profile.html, got %v", vars["profile.html"])
+	}
+	if len(vars["home.html"]) != 1 || vars["home.html"][0] != "title" {
+		t.Errorf("expected title on home.html, got %v", vars["home.html"])
+	}
+}
@@ -0,0 +1,70 @@
+package ast
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestContextFileTemplateKeysNormalized verifies that a context file entry
+// for "page.html" merges into a Go render call of "./page.html" instead of
+// producing a second, synthetic call.
+func TestContextFileTemplateKeysNormalized(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	src := `package main
+
+type Context struct{}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+func handler(c *Context) {
+	c.Render("./page.html", map[string]interface{}{"title": "Home"})
+}
+`
+	files := map[string]string{
+		"main.go":    src,
+		"go.mod":     "module example.com/test\ngo 1.21\n",
+		"gotpl.json": `{"page.html": {"user": "string"}, ".\\other.html": {"count": "int"}}`,
+	}
+	writeTree(t, tmpDir, files)
+
+	result := AnalyzeDir(tmpDir, filepath.Join(tmpDir, "gotpl.json"), DefaultConfig)
+
+	byTemplate := make(map[string][]RenderCall)
+	for _, rc := range result.RenderCalls {
+		byTemplate[rc.Template] = append(byTemplate[rc.Template], rc)
+	}
+	if len(byTemplate["page.html"]) != 0 {
+		t.Errorf("expected no synthetic call for page.html, got %+v", byTemplate["page.html"])
+	}
+	calls := byTemplate["./page.html"]
+	if len(calls) != 1 {
+		t.Fatalf("expected a single call for ./page.html, got %+v", result.RenderCalls)
+	}
+	names := make(map[string]bool)
+	for _, v := range calls[0].Vars {
+		names[v.Name] = true
+	}
+	if !names["title"] || !names["user"] {
+		t.Errorf("expected title and user to be merged into the call, got %+v", calls[0].Vars)
+	}
+
+	other := byTemplate["other.html"]
+	if len(other) != 1 || other[0].File != ContextFileRenderCall {
+		t.Errorf("expected a synthetic call for the normalized other.html, got %+v", result.RenderCalls)
+	}
+}
+
+func TestTemplateKey(t *testing.T) {
+	tests := map[string]string{
+		"page.html":          "page.html",
+		"./page.html":        "page.html",
+		`views\page.html`:    "views/page.html",
+		"views//a/../b.html": "views/b.html",
+		"":                   "",
+	}
+	for in, want := range tests {
+		if got := templateKey(in); got != want {
+			t.Errorf("templateKey(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package ast
+
+import "testing"
+
+// TestDataArgOffset verifies that DataArgOffsets locates the data argument
+// of a render method taking a status code between the name and the data,
+// that the status code is never read as data, and that render functions
+// not listed keep the data right after the name.
+func TestDataArgOffset(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	src := `package main
+
+const StatusOK = 200
+
+type Context struct{}
+func (c *Context) Render(tpl string, status int, data map[string]interface{}) {}
+
+type Template struct{}
+func (t *Template) ExecuteTemplate(w interface{}, name string, data map[string]interface{}) {}
+
+type User struct {
+	Name string
+}
+
+func handler(c *Context) {
+	c.Render("page.html", StatusOK, map[string]interface{}{
+		"user":  User{},
+		"title": "Home",
+	})
+}
+
+func mail(t *Template) {
+	t.ExecuteTemplate(nil, "mail.html", map[string]interface{}{"user": User{}})
+}
+`
+	writeTestModule(t, tmpDir, src)
+
+	varNames := func(config AnalysisConfig) map[string]map[string]bool {
+		result := AnalyzeDir(tmpDir, "", config)
+		if len(result.Errors) > 0 {
+			t.Fatalf("analysis errors: %v", result.Errors)
+		}
+		if len(result.RenderCalls) != 2 {
+			t.Fatalf("expected 2 render calls, got %d", len(result.RenderCalls))
+		}
+		names := make(map[string]map[string]bool)
+		for _, rc := range result.RenderCalls {
+			if rc.NilData {
+				t.Errorf("unexpected render call %+v", rc)
+			}
+			names[rc.Template] = make(map[string]bool)
+			for _, v := range rc.Vars {
+				names[rc.Template][v.Name] = true
+			}
+		}
+		return names
+	}
+
+	// The default offset lands on the status code, which is skipped.
+	if names := varNames(DefaultConfig); len(names["page.html"]) != 0 || !names["mail.html"]["user"] {
+		t.Errorf("expected no variables from the status code, got %v", names)
+	}
+
+	config := DefaultConfig
+	config.DataArgOffsets = map[string]int{"Render": 2}
+	names := varNames(config)
+	if !names["page.html"]["user"] || !names["page.html"]["title"] || len(names["page.html"]) != 2 {
+		t.Errorf("expected user and title from the third argument, got %v", names["page.html"])
+	}
+	if !names["mail.html"]["user"] || len(names["mail.html"]) != 1 {
+		t.Errorf("expected ExecuteTemplate to keep its data after the name, got %v", names["mail.html"])
+	}
+}
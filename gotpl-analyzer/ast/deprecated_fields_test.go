@@ -0,0 +1,70 @@
+package ast
+
+import "testing"
+
+// TestWarnDeprecatedFields verifies that the deprecation note from a field's
+// doc comment is recorded only when WarnDeprecated is set.
+func TestWarnDeprecatedFields(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	src := `package main
+
+type Context struct{}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+type User struct {
+	// Name is the display name.
+	Name string
+
+	// Deprecated: use Name instead.
+	OldName string
+
+	// deprecated:
+	Legacy string
+}
+
+func handler(c *Context) {
+	c.Render("user.html", map[string]interface{}{
+		"user": User{},
+	})
+}
+`
+	writeTestModule(t, tmpDir, src)
+
+	userFields := func(config AnalysisConfig) map[string]FieldInfo {
+		result := AnalyzeDir(tmpDir, "", config)
+		if len(result.Errors) > 0 {
+			t.Fatalf("analysis errors: %v", result.Errors)
+		}
+		if len(result.RenderCalls) != 1 {
+			t.Fatalf("expected 1 render call, got %d", len(result.RenderCalls))
+		}
+		fields := make(map[string]FieldInfo)
+		for _, v := range result.RenderCalls[0].Vars {
+			if v.Name == "user" {
+				for _, f := range v.Fields {
+					fields[f.Name] = f
+				}
+			}
+		}
+		return fields
+	}
+
+	fields := userFields(DefaultConfig)
+	if fields["OldName"].Deprecated != "" {
+		t.Errorf("expected no deprecation note by default, got %q", fields["OldName"].Deprecated)
+	}
+
+	config := DefaultConfig
+	config.WarnDeprecated = true
+	fields = userFields(config)
+	if got := fields["OldName"].Deprecated; got != "use Name instead." {
+		t.Errorf("expected OldName note %q, got %q", "use Name instead.", got)
+	}
+	if got := fields["Legacy"].Deprecated; got == "" {
+		t.Error("expected a bare lowercase marker to flag Legacy")
+	}
+	if got := fields["Name"].Deprecated; got != "" {
+		t.Errorf("expected Name not to be deprecated, got %q", got)
+	}
+}
@@ -0,0 +1,62 @@
+package ast
+
+import (
+	"fmt"
+	goast "go/ast"
+	"go/token"
+	"slices"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// LoadPackages loads the packages under dir with the same syntax and type
+// information the analysis entry points use, for callers such as
+// validator.DescribeType that work on already loaded packages.
+func LoadPackages(dir string, config AnalysisConfig) ([]*packages.Package, error) {
+	pkgs, err := packages.Load(newLoadConfig(dir, token.NewFileSet(), config), "./...")
+	if err != nil {
+		return nil, fmt.Errorf("load error: %v", err)
+	}
+	return pkgs, nil
+}
+
+// DescribeType returns the template-facing view of typeName in pkgs, written
+// as in a context file ("handlers.User", "[]*models.Post"). The result
+// carries the same field tree, docs, methods and definition position a render
+// call passing a value of that type would get, without requiring such a call
+// to exist.
+func DescribeType(pkgs []*packages.Package, typeName string, config AnalysisConfig) (TemplateVar, error) {
+	baseTypeStr, _ := parseTypeString(typeName)
+	typeMap := buildTypeMap(pkgs)
+	if _, ok := typeMap[baseTypeStr]; !ok {
+		return TemplateVar{}, fmt.Errorf("type %q not found in the loaded packages", baseTypeStr)
+	}
+
+	// Every package of a Load shares its file set; the module directory
+	// resolves the imported packages that were not loaded.
+	var (
+		fset  *token.FileSet
+		dir   string
+		files []*goast.File
+	)
+	for _, pkg := range pkgs {
+		if fset == nil {
+			fset = pkg.Fset
+		}
+		if dir == "" && pkg.Module != nil {
+			dir = pkg.Module.Dir
+		}
+		files = append(files, pkg.Syntax...)
+	}
+	indexFiles := append(slices.Clip(files), loadDependencySyntax(pkgs, fset, dir, config.BuildFlags)...)
+	structIndex := buildStructIndex(fset, buildFileMap(indexFiles, fset))
+
+	fc := newFieldCache()
+	fc.includeUnexported = config.IncludeUnexported
+	fc.warnDeprecated = config.WarnDeprecated
+
+	vars := buildTemplateVarsOptimized(
+		map[string]string{typeName: typeName}, typeMap, structIndex, fc, fset, newSeenMapPool(),
+	)
+	return vars[0], nil
+}
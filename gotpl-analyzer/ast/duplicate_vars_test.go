@@ -0,0 +1,65 @@
+package ast
+
+import "testing"
+
+// TestDuplicateSetVars verifies that a variable set twice in one handler
+// reaches the render call once, with the last value, and is reported at the
+// definition of that value.
+func TestDuplicateSetVars(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	src := `package main
+
+type Context struct{}
+func (c *Context) Set(key string, val any) {}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+type User struct{ Name string }
+type Admin struct{ Name, Role string }
+
+func middleware(c *Context) {
+	c.Set("site", "example")
+}
+
+func handler(c *Context) {
+	user := User{}
+	c.Set("user", user)
+	admin := Admin{}
+	c.Set("user", admin)
+	c.Set("site", "override")
+	c.Render("page.html", map[string]interface{}{"title": "Home"})
+}
+`
+	writeTestModule(t, tmpDir, src)
+
+	result := AnalyzeDir(tmpDir, "", DefaultConfig)
+	if len(result.Errors) > 0 {
+		t.Fatalf("analysis errors: %v", result.Errors)
+	}
+	if len(result.RenderCalls) != 1 {
+		t.Fatalf("expected 1 render call, got %d", len(result.RenderCalls))
+	}
+	rc := result.RenderCalls[0]
+
+	count := make(map[string]int)
+	for _, v := range rc.Vars {
+		count[v.Name]++
+		if v.Name == "user" && v.TypeStr != "main.Admin" {
+			t.Errorf("expected the last user value to win, got %s", v.TypeStr)
+		}
+	}
+	for _, name := range []string{"title", "user", "site"} {
+		if count[name] != 1 {
+			t.Errorf("expected %s once in the render call, got %d", name, count[name])
+		}
+	}
+
+	// Overriding the middleware's "site" is not a duplicate of the handler.
+	if len(rc.DuplicateVars) != 1 {
+		t.Fatalf("expected 1 duplicate variable, got %+v", rc.DuplicateVars)
+	}
+	d := rc.DuplicateVars[0]
+	if d.Name != "user" || d.DefFile != "main.go" || d.DefLine != 17 {
+		t.Errorf("expected user duplicate at main.go:17, got %+v", d)
+	}
+}
@@ -0,0 +1,48 @@
+package ast
+
+import "testing"
+
+// TestExecuteTemplateStdlibSignature verifies that the three-argument
+// html/template ExecuteTemplate(w, name, data) call is recognised, with the
+// template name taken after the writer and the data after the name.
+func TestExecuteTemplateStdlibSignature(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	src := `package main
+
+import (
+	"html/template"
+	"net/http"
+)
+
+var tmpl = template.Must(template.ParseGlob("views/*.html"))
+
+func handler(w http.ResponseWriter, r *http.Request) {
+	tmpl.ExecuteTemplate(w, "index.html", map[string]any{"Title": "Home"})
+}
+
+func main() {}
+`
+	writeTestModule(t, tmpDir, src)
+
+	result := AnalyzeDir(tmpDir, "", DefaultConfig)
+	if len(result.UnresolvedRenderCalls) != 0 {
+		t.Errorf("expected no unresolved calls, got %+v", result.UnresolvedRenderCalls)
+	}
+	if len(result.RenderCalls) != 1 {
+		t.Fatalf("expected 1 render call, got %+v", result.RenderCalls)
+	}
+	rc := result.RenderCalls[0]
+	if rc.Template != "index.html" || rc.ResolvedVia != ResolvedViaLiteral {
+		t.Errorf("expected literal index.html, got %q via %q", rc.Template, rc.ResolvedVia)
+	}
+	found := false
+	for _, v := range rc.Vars {
+		if v.Name == "Title" && v.TypeStr == "string" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected Title from the data argument, got %+v", rc.Vars)
+	}
+}
@@ -104,6 +104,7 @@ func extractFieldsUncachedDepth(
 	entry := structIndex[astKey]
 	fields := extractStructFieldsDepth(strct, entry, structIndex, fc, seen, fset, depth)
 	fields = append(fields, extractMethodFields(named, structIndex, fc, seen, fset, depth)...)
+	fields = dedupeFieldNames(fields, named, fc)
 	addMethodDocs(fields, entry)
 
 	return fields, entry.doc
@@ -122,7 +123,7 @@ func extractStructFieldsDepth(
 	fields := make([]FieldInfo, 0, strct.NumFields())
 
 	for field := range strct.Fields() {
-		if !field.Exported() {
+		if !field.Exported() && !fc.includeUnexported {
 			continue
 		}
 
@@ -137,6 +138,35 @@ func extractStructFieldsDepth(
 	return fields
 }
 
+// dedupeFieldNames drops repeated names from the fields of named. Embedded
+// types contribute their own fields and methods, so a promoted method can
+// collide with a direct field of the same name. As in Go, the field wins: it
+// replaces the method and is flagged with ShadowsMethod, and the collision is
+// recorded as an analysis warning. Other repeats keep their first entry.
+func dedupeFieldNames(fields []FieldInfo, named *types.Named, fc *fieldCache) []FieldInfo {
+	index := make(map[string]int, len(fields))
+	out := fields[:0]
+	for _, fi := range fields {
+		i, dup := index[fi.Name]
+		if !dup {
+			index[fi.Name] = len(out)
+			out = append(out, fi)
+			continue
+		}
+		prev := &out[i]
+		isMethod, prevIsMethod := fi.TypeStr == "method", prev.TypeStr == "method"
+		if isMethod == prevIsMethod {
+			continue
+		}
+		if prevIsMethod {
+			*prev = fi
+		}
+		prev.ShadowsMethod = true
+		fc.noteShadowedMethod(normalizeTypeStr(named), fi.Name)
+	}
+	return out
+}
+
 // buildFieldInfoDepth constructs a FieldInfo for a single struct field with depth tracking.
 //
 // OPTIMISATION: Only allocate a copySeenMap for slice/map branches where an
@@ -152,8 +182,9 @@ func buildFieldInfoDepth(
 	depth int,
 ) FieldInfo {
 	fi := FieldInfo{
-		Name:    field.Name(),
-		TypeStr: normalizeTypeStr(field.Type()),
+		Name:       field.Name(),
+		TypeStr:    normalizeTypeStr(field.Type()),
+		Unexported: !field.Exported(),
 	}
 
 	if pos := field.Pos(); pos.IsValid() && fset != nil {
@@ -168,12 +199,14 @@ func buildFieldInfoDepth(
 		ft = ptr.Elem()
 	}
 
-	if slice, ok := ft.(*types.Slice); ok {
+	// Named slice types such as `type UserList []User` are unwrapped like
+	// named maps, so ranging over the field sees the element's fields.
+	if elem := getElementType(ft); elem != nil {
 		fi.IsSlice = true
-		fi.ElemType = normalizeTypeStr(slice.Elem())
+		fi.ElemType = normalizeTypeStr(elem)
 		// Independent branch needs its own seen map copy to avoid cross-contamination.
 		elemSeen := copySeenMap(seen)
-		fi.Fields, _ = extractFieldsWithDocsDepth(slice.Elem(), structIndex, fc, elemSeen, fset, depth+1)
+		fi.Fields, _ = extractFieldsWithDocsDepth(elem, structIndex, fc, elemSeen, fset, depth+1)
 	} else if keyType, elemType := getMapTypes(ft); keyType != nil && elemType != nil {
 		fi.IsMap = true
 		fi.KeyType = normalizeTypeStr(keyType)
@@ -194,9 +227,29 @@ func buildFieldInfoDepth(
 		fi.Doc = pos.doc
 	}
 
+	if fc.warnDeprecated {
+		fi.Deprecated = deprecationNote(fi.Doc)
+	}
+
 	return fi
 }
 
+// deprecationNote returns the text after a leading "Deprecated:" marker in
+// doc (matched case-insensitively), or "" when the field is not deprecated.
+// A bare marker still flags the field with a placeholder note.
+func deprecationNote(doc string) string {
+	const marker = "deprecated:"
+	doc = strings.TrimSpace(doc)
+	if len(doc) < len(marker) || !strings.EqualFold(doc[:len(marker)], marker) {
+		return ""
+	}
+	note := strings.Join(strings.Fields(doc[len(marker):]), " ")
+	if note == "" {
+		return "no reason given"
+	}
+	return note
+}
+
 // extractMethodFields extracts exported methods as FieldInfo entries.
 func extractMethodFields(
 	named *types.Named,
@@ -234,6 +287,8 @@ func extractMethodFields(
 
 		if sig, ok := method.Type().(*types.Signature); ok {
 			fi.Params, fi.Returns, _ = extractSignatureInfoWithFields(sig, structIndex, fc, seen, fset, depth+1)
+			variadic := sig.Variadic()
+			fi.Variadic = &variadic
 
 			if recv := sig.Recv(); recv != nil {
 				recvType := unwrapType(recv.Type())
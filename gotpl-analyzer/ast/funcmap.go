@@ -7,6 +7,13 @@ import (
 	"strings"
 )
 
+// TemplateBuiltins are the functions text/template predefines. A FuncMap
+// entry with one of these names replaces the builtin.
+var TemplateBuiltins = []string{
+	"and", "call", "eq", "ge", "gt", "html", "index", "js", "le", "len",
+	"lt", "ne", "not", "or", "print", "printf", "println", "slice", "urlquery",
+}
+
 // processFuncMapIndexAssign handles assignments to FuncMap via index expression.
 // Example: myFuncMap["add"] = addFunc
 func processFuncMapIndexAssign(
@@ -45,6 +52,7 @@ func processFuncMapIndexAssign(
 			seen := seenPool.get()
 			fInfo.Params, fInfo.Returns, fInfo.Args = extractSignatureFromType(rtv.Type, structIndex, fc, seen, fset)
 			seenPool.put(seen)
+			fInfo.Variadic = isVariadicFunc(rtv.Type)
 			fInfo.ReturnTypeFields = extractFuncReturnFields(rtv.Type, structIndex, fc, seenPool, fset)
 		}
 	}
@@ -88,6 +96,7 @@ func extractFuncMaps(
 				seen := seenPool.get()
 				fInfo.Params, fInfo.Returns, fInfo.Args = extractSignatureFromType(tv.Type, structIndex, fc, seen, fset)
 				seenPool.put(seen)
+				fInfo.Variadic = isVariadicFunc(tv.Type)
 				fInfo.ReturnTypeFields = extractFuncReturnFields(tv.Type, structIndex, fc, seenPool, fset)
 			}
 		}
@@ -256,6 +265,16 @@ func extractSignatureFromType(
 	return extractSignatureInfoWithFields(sig, structIndex, fc, seen, fset, 0)
 }
 
+// isVariadicFunc reports whether t, or the type t points to, is a variadic
+// function signature.
+func isVariadicFunc(t types.Type) bool {
+	if ptr, ok := t.(*types.Pointer); ok {
+		t = ptr.Elem()
+	}
+	sig, ok := t.(*types.Signature)
+	return ok && sig.Variadic()
+}
+
 // extractSignatureInfo extracts detailed parameter and return type information
 // from a function signature.
 func extractSignatureInfo(sig *types.Signature) (params, returns []ParamInfo, args []string) {
@@ -0,0 +1,47 @@
+package ast
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+// TestIgnoreDirSkipsPackages verifies that directories rejected by
+// AnalysisConfig.IgnoreDir are left out of the package load set.
+func TestIgnoreDirSkipsPackages(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	handler := `package %s
+
+type Context struct{}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+func handler(c *Context) {
+	c.Render("%s.html", map[string]interface{}{"title": "x"})
+}
+`
+	files := map[string]string{
+		"go.mod":             "module example.com/test\ngo 1.21\n",
+		"main.go":            fmt.Sprintf(handler, "main", "index"),
+		"scratch/scratch.go": fmt.Sprintf(handler, "scratch", "scratch"),
+	}
+	writeTree(t, tmpDir, files)
+
+	templates := func(config AnalysisConfig) []string {
+		var names []string
+		for _, rc := range AnalyzeDir(tmpDir, "", config).RenderCalls {
+			names = append(names, rc.Template)
+		}
+		return names
+	}
+
+	if got := templates(DefaultConfig); len(got) != 2 {
+		t.Fatalf("expected both render calls without IgnoreDir, got %v", got)
+	}
+
+	config := DefaultConfig
+	config.IgnoreDir = func(path string) bool { return filepath.Base(path) == "scratch" }
+	if got := templates(config); len(got) != 1 || got[0] != "index.html" {
+		t.Errorf("expected only index.html with scratch ignored, got %v", got)
+	}
+}
@@ -0,0 +1,84 @@
+package ast
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestIncludeTestsFindsRenderCallsInTestFiles verifies that render calls in
+// _test.go files, in-package and external, are found with IncludeTests and
+// that the package's own calls are not duplicated by its test variant.
+func TestIncludeTestsFindsRenderCallsInTestFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	files := map[string]string{
+		"go.mod": "module example.com/test\ngo 1.21\n",
+		"main.go": `package main
+
+type Context struct{}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+func handler(c *Context) {
+	c.Render("index.html", map[string]interface{}{"title": "x"})
+}
+
+func main() {}
+`,
+		"main_test.go": `package main
+
+import "testing"
+
+func TestPage(t *testing.T) {
+	c := &Context{}
+	c.Render("internal.html", map[string]interface{}{"count": 1})
+}
+`,
+		"external_test.go": `package main_test
+
+import "testing"
+
+type Context struct{}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+func TestExternal(t *testing.T) {
+	c := &Context{}
+	c.Render("external.html", map[string]interface{}{"ok": true})
+}
+`,
+		"testdata/fixture/fixture.go": `package fixture
+
+type Context struct{}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+func handler(c *Context) {
+	c.Render("fixture.html", map[string]interface{}{"x": 1})
+}
+`,
+	}
+	writeTree(t, tmpDir, files)
+
+	templates := func(config AnalysisConfig) []string {
+		var names []string
+		for _, rc := range AnalyzeDir(tmpDir, "", config).RenderCalls {
+			names = append(names, rc.Template)
+		}
+		slices.Sort(names)
+		return names
+	}
+
+	if got := templates(DefaultConfig); !slices.Equal(got, []string{"index.html"}) {
+		t.Errorf("expected only index.html by default, got %v", got)
+	}
+
+	config := DefaultConfig
+	config.IncludeTests = true
+	if got, want := templates(config), []string{"external.html", "index.html", "internal.html"}; !slices.Equal(got, want) {
+		t.Errorf("expected %v with IncludeTests, got %v", want, got)
+	}
+
+	config = DefaultConfig
+	config.IncludeTestdata = true
+	if got, want := templates(config), []string{"fixture.html", "index.html"}; !slices.Equal(got, want) {
+		t.Errorf("expected %v with IncludeTestdata, got %v", want, got)
+	}
+}
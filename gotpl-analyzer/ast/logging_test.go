@@ -0,0 +1,46 @@
+package ast
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// TestAnalysisLogger verifies that each analysis phase is logged with its
+// counts when a logger is configured.
+func TestAnalysisLogger(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	src := `package main
+
+type Context struct{}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+func handler(c *Context) {
+	c.Render("home.html", map[string]interface{}{})
+}
+`
+	writeTestModule(t, tmpDir, src)
+
+	var buf bytes.Buffer
+	config := DefaultConfig
+	config.Logger = slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+	result := AnalyzeDir(tmpDir, "", config)
+	if len(result.Errors) > 0 {
+		t.Fatalf("analysis errors: %v", result.Errors)
+	}
+
+	logs := buf.String()
+	for _, want := range []string{
+		`msg="loaded packages" patterns=1 packages=1 files=1`,
+		`msg="indexed structs"`,
+		`msg="collected func scopes" scopes=`,
+		`msg="generated render calls" renderCalls=1 unresolved=0 funcMaps=0`,
+	} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("expected log containing %q, got:\n%s", want, logs)
+		}
+	}
+}
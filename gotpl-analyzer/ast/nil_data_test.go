@@ -0,0 +1,42 @@
+package ast
+
+import "testing"
+
+// TestRenderCallNilData verifies that a literal nil data argument is flagged
+// on the render call, while a shadowed identifier named nil is not.
+func TestRenderCallNilData(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	src := `package main
+
+type Context struct{}
+func (c *Context) Render(tpl string, data interface{}) {}
+
+func handler(c *Context) {
+	c.Render("x.html", nil)
+	c.Render("y.html", (nil))
+	c.Render("z.html", map[string]interface{}{"Foo": 1})
+}
+
+func shadowed(c *Context) {
+	nil := map[string]interface{}{"Foo": 1}
+	c.Render("s.html", nil)
+}
+`
+	writeTestModule(t, tmpDir, src)
+
+	result := AnalyzeDir(tmpDir, "", DefaultConfig)
+	if len(result.Errors) > 0 {
+		t.Fatalf("analysis errors: %v", result.Errors)
+	}
+
+	want := map[string]bool{"x.html": true, "y.html": true, "z.html": false, "s.html": false}
+	if len(result.RenderCalls) != len(want) {
+		t.Fatalf("expected %d render calls, got %+v", len(want), result.RenderCalls)
+	}
+	for _, rc := range result.RenderCalls {
+		if rc.NilData != want[rc.Template] {
+			t.Errorf("%s: expected NilData %v, got %v", rc.Template, want[rc.Template], rc.NilData)
+		}
+	}
+}
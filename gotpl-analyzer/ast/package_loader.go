@@ -11,6 +11,20 @@ import (
 	"golang.org/x/tools/go/packages"
 )
 
+// newLoadConfig returns the packages.Config shared by every analysis entry
+// point: syntax and full type information for the packages under dir.
+func newLoadConfig(dir string, fset *token.FileSet, config AnalysisConfig) *packages.Config {
+	return &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports |
+			packages.NeedModule,
+		Dir:        dir,
+		Fset:       fset,
+		Tests:      config.IncludeTests,
+		BuildFlags: config.BuildFlags,
+	}
+}
+
 // mergeTypeInfo consolidates type information from all loaded packages into
 // a single unified types.Info structure. This enables cross-package type
 // resolution during analysis.
@@ -18,12 +32,12 @@ import (
 // Also collects all AST files and non-import-related errors.
 //
 // Performance: Skips vendor and generated code directories to reduce processing time.
-func mergeTypeInfo(pkgs []*packages.Package, result *AnalysisResult) (*types.Info, []*goast.File) {
+func mergeTypeInfo(pkgs []*packages.Package, result *AnalysisResult, includeTests bool) (*types.Info, []*goast.File) {
 	// Pre-calculate total sizes to avoid map growth
 	totalTypes, totalDefs, totalUses := 0, 0, 0
 	for _, pkg := range pkgs {
 		// Skip vendor and generated code for performance
-		if shouldSkipPackage(pkg.PkgPath) {
+		if shouldSkipPackage(pkg.PkgPath, includeTests) || isTestMain(pkg) {
 			continue
 		}
 
@@ -47,7 +61,7 @@ func mergeTypeInfo(pkgs []*packages.Package, result *AnalysisResult) (*types.Inf
 	// Merge all package data
 	for _, pkg := range pkgs {
 		// Skip vendor and generated code
-		if shouldSkipPackage(pkg.PkgPath) {
+		if shouldSkipPackage(pkg.PkgPath, includeTests) || isTestMain(pkg) {
 			continue
 		}
 
@@ -73,8 +87,9 @@ func mergeTypeInfo(pkgs []*packages.Package, result *AnalysisResult) (*types.Inf
 }
 
 // shouldSkipPackage determines if a package should be skipped for performance reasons.
-// Skips vendor directories and common generated code patterns.
-func shouldSkipPackage(pkgPath string) bool {
+// Skips vendor directories and common generated code patterns, and external
+// test packages unless includeTests is set.
+func shouldSkipPackage(pkgPath string, includeTests bool) bool {
 	lower := strings.ToLower(pkgPath)
 
 	// Skip vendor directories
@@ -92,14 +107,79 @@ func shouldSkipPackage(pkgPath string) bool {
 		return true
 	}
 
-	// Skip test packages (already handled by Tests: false in config)
-	if strings.HasSuffix(lower, "_test") {
+	// Skip external test packages; they are only loaded with Tests: true.
+	if !includeTests && strings.HasSuffix(lower, "_test") {
 		return true
 	}
 
 	return false
 }
 
+// isTestMain reports whether pkg is the synthesized main package that runs a
+// package's tests, whose only file is generated by the go command.
+func isTestMain(pkg *packages.Package) bool {
+	return pkg.Name == "main" && strings.HasSuffix(pkg.ID, ".test")
+}
+
+// loadDependencySyntax parses the packages imported (transitively) by pkgs
+// that belong to the same module as pkgs but were not loaded themselves, so
+// their struct declarations can be indexed. When pkgs already covers the
+// whole module, as with AnalyzeDir, nothing is loaded.
+func loadDependencySyntax(pkgs []*packages.Package, fset *token.FileSet, dir string, buildFlags []string) []*goast.File {
+	modules := make(map[string]bool)
+	loaded := make(map[string]bool, len(pkgs))
+	for _, pkg := range pkgs {
+		loaded[pkg.PkgPath] = true
+		if pkg.Module != nil {
+			modules[pkg.Module.Path] = true
+		}
+	}
+
+	inModule := func(pkgPath string) bool {
+		for mod := range modules {
+			if pkgPath == mod || strings.HasPrefix(pkgPath, mod+"/") {
+				return true
+			}
+		}
+		return false
+	}
+
+	var files []*goast.File
+	frontier := pkgs
+	for len(frontier) > 0 {
+		var paths []string
+		for _, pkg := range frontier {
+			for _, imp := range pkg.Imports {
+				if loaded[imp.PkgPath] || !inModule(imp.PkgPath) || shouldSkipPackage(imp.PkgPath, false) {
+					continue
+				}
+				loaded[imp.PkgPath] = true
+				paths = append(paths, imp.PkgPath)
+			}
+		}
+		if len(paths) == 0 {
+			break
+		}
+
+		cfg := &packages.Config{
+			Mode:       packages.NeedName | packages.NeedFiles | packages.NeedSyntax | packages.NeedImports,
+			Dir:        dir,
+			Fset:       fset,
+			BuildFlags: buildFlags,
+		}
+		deps, err := packages.Load(cfg, paths...)
+		if err != nil {
+			break
+		}
+		for _, dep := range deps {
+			files = append(files, dep.Syntax...)
+		}
+		frontier = deps
+	}
+
+	return files
+}
+
 // buildFileMap creates a fast lookup map from filename to AST file.
 // This enables quick file resolution when processing type definitions.
 func buildFileMap(files []*goast.File, fset *token.FileSet) map[string]*goast.File {
@@ -121,6 +201,7 @@ func isImportRelatedError(msg string) bool {
 		"can't find import",
 		"cannot find package",
 		"no required module provides",
+		"build constraints exclude all go files",
 	}
 
 	for _, phrase := range importPhrases {
@@ -0,0 +1,64 @@
+package ast
+
+import "testing"
+
+// TestRenderAliasMethodValue verifies that calls made through a stored render
+// method value are recognized:
+//
+//	render := c.Render
+//	render("page.html", data)
+//
+// Unrelated function values called with a string first argument must not be
+// reported as render calls.
+func TestRenderAliasMethodValue(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	src := `package main
+
+type Context struct{}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+func (c *Context) Log(msg string, data map[string]interface{}) {}
+
+func handler(c *Context) {
+	render := c.Render
+	render("page.html", map[string]interface{}{
+		"title": "Home",
+	})
+
+	logf := c.Log
+	logf("not-a-template.html", map[string]interface{}{
+		"level": 1,
+	})
+
+	render = c.Log
+	render("reassigned.html", map[string]interface{}{
+		"level": 2,
+	})
+}
+`
+	writeTestModule(t, tmpDir, src)
+
+	result := AnalyzeDir(tmpDir, "", DefaultConfig)
+	if len(result.Errors) > 0 {
+		t.Fatalf("analysis errors: %v", result.Errors)
+	}
+
+	if len(result.RenderCalls) != 1 {
+		t.Fatalf("expected 1 render call, got %d: %+v", len(result.RenderCalls), result.RenderCalls)
+	}
+
+	rc := result.RenderCalls[0]
+	if rc.Template != "page.html" {
+		t.Errorf("expected template page.html, got %q", rc.Template)
+	}
+
+	hasTitle := false
+	for _, v := range rc.Vars {
+		if v.Name == "title" {
+			hasTitle = true
+		}
+	}
+	if !hasTitle {
+		t.Errorf("render call %q missing var 'title'", rc.Template)
+	}
+}
@@ -1,6 +1,7 @@
 package ast
 
 import (
+	"fmt"
 	goast "go/ast"
 	"go/token"
 	"go/types"
@@ -22,6 +23,7 @@ func generateRenderCalls(
 	structIndex map[string]structIndexEntry,
 	fc *fieldCache,
 	seenPool *seenMapPool,
+	config AnalysisConfig,
 ) []RenderCall {
 	// Pre-count total render calls for efficient allocation
 	totalRenders := 0
@@ -49,6 +51,21 @@ func generateRenderCalls(
 
 			templatePathExpr := call.Args[templateArgIdx]
 
+			templateExpr := exprSource(fset, templatePathExpr)
+
+			// A composed set is one call of its last template; the others
+			// only contribute named blocks. The name range covers the last
+			// element so that navigation lands on the executed template.
+			templatePaths := rr.TemplateNames
+			var composedWith []string
+			if rr.Composed {
+				last := len(templatePaths) - 1
+				templatePaths, composedWith = templatePaths[last:], templatePaths[:last]
+				if lit, ok := templatePathExpr.(*goast.CompositeLit); ok && len(lit.Elts) > 0 {
+					templatePathExpr = lit.Elts[len(lit.Elts)-1]
+				}
+			}
+
 			// Calculate precise column range for template name
 			tplNameStartCol, tplNameEndCol := getExprColumnRange(fset, templatePathExpr)
 
@@ -59,19 +76,25 @@ func generateRenderCalls(
 			}
 
 			// Process each template name (usually one, but can be multiple from variables)
-			for _, templatePath := range rr.TemplateNames {
+			for _, templatePath := range templatePaths {
 				if templatePath == "" {
 					continue
 				}
 
 				// Extract variables from data argument if present
-				dataArgIdx := templateArgIdx + 1
+				dataArgIdx := templateArgIdx + dataArgOffset(call, config)
 				var localVars []TemplateVar
+				nilData := false
 
-				if dataArgIdx < len(call.Args) {
+				if dataArgIdx < len(call.Args) && isNilExpr(call.Args[dataArgIdx], info) {
+					nilData = true
+				} else if dataArgIdx < len(call.Args) && !isScalarExpr(call.Args[dataArgIdx], info) {
 					dataArg := call.Args[dataArgIdx]
 					seen := seenPool.get()
 					localVars = extractMapVars(dataArg, info, fset, structIndex, fc, seen)
+					if config.StrictMapKeys {
+						attachMapKeys(localVars, dataArg)
+					}
 
 					// Fallback: data arg is an identifier — resolve it to a
 					// composite literal tracked during the assignment pass.
@@ -85,6 +108,9 @@ func generateRenderCalls(
 							if comp, found := scope.MapAssignments[ident.Name]; found {
 								clear(seen)
 								localVars = extractMapVars(comp, info, fset, structIndex, fc, seen)
+								if config.StrictMapKeys {
+									attachMapKeys(localVars, comp)
+								}
 							}
 						}
 					}
@@ -93,10 +119,7 @@ func generateRenderCalls(
 				}
 
 				// Combine all available variables: local + scope + global
-				allVars := make([]TemplateVar, 0, len(localVars)+len(scope.SetVars)+len(globalImplicitVars))
-				allVars = append(allVars, localVars...)
-				allVars = append(allVars, scope.SetVars...)
-				allVars = append(allVars, globalImplicitVars...)
+				allVars, duplicates := combineRenderVars(localVars, scope.SetVars, globalImplicitVars, dir)
 
 				// Resolve file path relative to analysis root
 				pos := fset.Position(call.Pos())
@@ -109,6 +132,11 @@ func generateRenderCalls(
 					TemplateNameStartCol: tplNameStartCol,
 					TemplateNameEndCol:   tplNameEndCol,
 					Vars:                 allVars,
+					TemplateExpr:         templateExpr,
+					ResolvedVia:          rr.ResolvedVia,
+					NilData:              nilData,
+					ComposedWith:         composedWith,
+					DuplicateVars:        duplicates,
 				})
 			}
 		}
@@ -117,6 +145,197 @@ func generateRenderCalls(
 	return renderCalls
 }
 
+// combineRenderVars combines the variables of a render call's data argument,
+// its handler's Set calls and the global (middleware) Set calls, keeping each
+// name once. Like rex, which copies the context locals over the data map, a
+// Set value wins over a data map key, and a handler's over a middleware's;
+// among Set calls of one function the last one wins. Names the handler itself
+// provides more than once are returned as duplicates. Each name keeps the
+// position of its first appearance in local, set, global order.
+func combineRenderVars(local, set, global []TemplateVar, dir string) ([]TemplateVar, []DuplicateVar) {
+	winner := make(map[string]TemplateVar, len(local)+len(set)+len(global))
+	fromHandler := make(map[string]bool, len(winner))
+	var duplicates []DuplicateVar
+	for _, group := range []struct {
+		vars    []TemplateVar
+		handler bool
+	}{{local, true}, {global, false}, {set, true}} {
+		for _, v := range group.vars {
+			if _, ok := winner[v.Name]; ok && fromHandler[v.Name] && group.handler {
+				d := DuplicateVar{Name: v.Name, DefLine: v.DefLine}
+				if v.DefFile != "" {
+					d.DefFile = resolveRelativePath(v.DefFile, dir)
+				}
+				duplicates = append(duplicates, d)
+			}
+			winner[v.Name] = v
+			fromHandler[v.Name] = fromHandler[v.Name] || group.handler
+		}
+	}
+
+	vars := make([]TemplateVar, 0, len(winner))
+	for _, group := range [][]TemplateVar{local, set, global} {
+		for _, v := range group {
+			if w, ok := winner[v.Name]; ok {
+				vars = append(vars, w)
+				delete(winner, v.Name)
+			}
+		}
+	}
+	return vars, duplicates
+}
+
+// dataArgOffset returns the position of call's data argument relative to
+// its template argument (see AnalysisConfig.DataArgOffsets).
+func dataArgOffset(call *goast.CallExpr, config AnalysisConfig) int {
+	var name string
+	switch fn := call.Fun.(type) {
+	case *goast.SelectorExpr:
+		name = fn.Sel.Name
+	case *goast.Ident:
+		name = fn.Name
+	}
+	if offset := config.DataArgOffsets[name]; offset > 0 {
+		return offset
+	}
+	return 1
+}
+
+// isScalarExpr reports whether the type checker gave expr a basic type, such
+// as the status code of c.Render("page.html", http.StatusOK, data) read as
+// the data argument, which holds no template variables.
+func isScalarExpr(expr goast.Expr, info *types.Info) bool {
+	if info == nil {
+		return false
+	}
+	tv, ok := info.Types[expr]
+	if !ok || tv.Type == nil {
+		return false
+	}
+	_, basic := tv.Type.Underlying().(*types.Basic)
+	return basic
+}
+
+// isNilExpr reports whether expr is the predeclared nil, possibly
+// parenthesized. A shadowed identifier named nil does not count.
+func isNilExpr(expr goast.Expr, info *types.Info) bool {
+	ident, ok := goast.Unparen(expr).(*goast.Ident)
+	if !ok || ident.Name != "nil" {
+		return false
+	}
+	if info == nil {
+		return true
+	}
+	obj := info.ObjectOf(ident)
+	if obj == nil {
+		return true
+	}
+	_, isNil := obj.(*types.Nil)
+	return isNil
+}
+
+// collectUnresolvedRenderCalls reports render calls whose template name could
+// not be resolved, so that a missing template can be traced back to the
+// argument expression the analyzer failed to evaluate.
+func collectUnresolvedRenderCalls(scopes []FuncScope, fset *token.FileSet, dir string) []RenderCall {
+	var calls []RenderCall
+	for _, scope := range scopes {
+		for _, rr := range scope.Unresolved {
+			pos := fset.Position(rr.Node.Pos())
+			rc := RenderCall{
+				File:        resolveRelativePath(pos.Filename, dir),
+				Line:        pos.Line,
+				ResolvedVia: ResolvedViaFailed,
+			}
+			if rr.TemplateArgIdx >= 0 && rr.TemplateArgIdx < len(rr.Node.Args) {
+				arg := rr.Node.Args[rr.TemplateArgIdx]
+				rc.TemplateExpr = exprSource(fset, arg)
+				rc.TemplateNameStartCol, rc.TemplateNameEndCol = getExprColumnRange(fset, arg)
+			}
+			calls = append(calls, rc)
+		}
+	}
+	return calls
+}
+
+// collectCappedNameNotes reports render calls whose template name variable
+// has more possible values than AnalysisConfig.MaxResolvedNames, each once.
+func collectCappedNameNotes(scopes []FuncScope, fset *token.FileSet, dir string, config AnalysisConfig) []string {
+	var notes []string
+	seen := make(map[string]bool)
+	limit := maxResolvedNames(config)
+	for _, scope := range scopes {
+		for _, rr := range scope.RenderNodes {
+			if rr.CappedVar == "" {
+				continue
+			}
+			pos := fset.Position(rr.Node.Pos())
+			note := fmt.Sprintf("%s:%d: Template name variable %q has more than %d possible values; validation limited to the first %d",
+				resolveRelativePath(pos.Filename, dir), pos.Line, rr.CappedVar, limit, limit)
+			if !seen[note] {
+				seen[note] = true
+				notes = append(notes, note)
+			}
+		}
+	}
+	return notes
+}
+
+// attachMapKeys records the literal key set on each map variable whose value
+// in the data literal is itself a composite literal with only constant string
+// keys, e.g. "config": map[string]string{"host": h, "port": p}. Maps with any
+// computed key are left without a key set and stay permissive in the validator.
+func attachMapKeys(vars []TemplateVar, dataArg goast.Expr) {
+	comp, ok := dataArg.(*goast.CompositeLit)
+	if !ok {
+		return
+	}
+
+	values := make(map[string]goast.Expr, len(comp.Elts))
+	for _, elt := range comp.Elts {
+		kv, ok := elt.(*goast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		if name := extractStringFast(kv.Key); name != "" {
+			values[name] = kv.Value
+		}
+	}
+
+	for i := range vars {
+		v := &vars[i]
+		if !v.IsMap || v.KeyType != "string" {
+			continue
+		}
+		lit, ok := values[v.Name].(*goast.CompositeLit)
+		if !ok {
+			continue
+		}
+		v.MapKeys = literalMapKeys(lit)
+	}
+}
+
+// literalMapKeys returns the string keys of a map composite literal, or nil
+// when any key is not a string literal.
+func literalMapKeys(lit *goast.CompositeLit) []string {
+	keys := make([]string, 0, len(lit.Elts))
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*goast.KeyValueExpr)
+		if !ok {
+			return nil
+		}
+		key := extractStringFast(kv.Key)
+		if key == "" {
+			return nil
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return keys
+}
+
 // resolveRelativePath attempts to convert an absolute path to a path
 // relative to the specified directory. Falls back to the original path
 // if conversion fails.
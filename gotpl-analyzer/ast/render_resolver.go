@@ -15,44 +15,76 @@ import (
 // 1. String literals: c.Render("template.html", data)
 // 2. Constants: c.Render(TemplateName, data)
 // 3. Variables: c.Render(tplName, data)
+// 4. Slices of names: c.Render([]string{"base.html", "page.html"}, data)
+//
+// Constants include typed constants, constants of other packages
+// (pages.Home) and constant expressions (Prefix + ".html").
+//
+// Slices of names are composed into one template set (see
+// ResolvedRender.Composed).
+//
+// Calls made through a render alias (`render := c.Render`) use the argument
+// index recorded for the alias.
+//
+// The result is never nil: a call whose template name cannot be determined is
+// returned with ResolvedVia set to ResolvedViaFailed so it can be reported.
 func resolveRenderCall(
 	call *goast.CallExpr,
 	info *types.Info,
 	stringAssignments map[string][]string,
+	renderAliases map[string]int,
+	config AnalysisConfig,
 ) *ResolvedRender {
 	resolved := &ResolvedRender{
 		Node:           call,
 		TemplateArgIdx: -1,
+		ResolvedVia:    ResolvedViaFailed,
 	}
 
 	// Determine expected position of template argument
-	templateArgIdx := inferTemplateArgIdx(call)
+	templateArgIdx := inferTemplateArgIdx(call, config)
+	if ident, ok := call.Fun.(*goast.Ident); ok {
+		if aliasIdx, isAlias := renderAliases[ident.Name]; isAlias {
+			templateArgIdx = aliasIdx
+		}
+	}
 
 	// Find actual template argument position
-	templateArgIdx = findTemplateArg(call, templateArgIdx, stringAssignments)
+	templateArgIdx = findTemplateArg(call, templateArgIdx, info, stringAssignments)
 
 	if templateArgIdx < 0 || templateArgIdx >= len(call.Args) {
-		return nil
+		return resolved
 	}
 
 	resolved.TemplateArgIdx = templateArgIdx
 	arg := call.Args[templateArgIdx]
 
 	// Resolve template name(s)
-	resolved.TemplateNames = resolveTemplateName(arg, info, stringAssignments)
+	names, via := resolveTemplateName(arg, info, stringAssignments)
+	if len(names) == 0 {
+		return resolved
+	}
 
-	if len(resolved.TemplateNames) == 0 {
-		return nil
+	if via == ResolvedViaVariable && len(names) > maxResolvedNames(config) {
+		names = names[:maxResolvedNames(config)]
+		resolved.CappedVar = arg.(*goast.Ident).Name
 	}
 
+	resolved.TemplateNames = names
+	resolved.ResolvedVia = via
+	resolved.Composed = via == ResolvedViaSlice
 	return resolved
 }
 
 // inferTemplateArgIdx determines the likely index of the template argument
 // based on the function call syntax.
-func inferTemplateArgIdx(call *goast.CallExpr) int {
-	switch call.Fun.(type) {
+func inferTemplateArgIdx(call *goast.CallExpr, config AnalysisConfig) int {
+	switch fn := call.Fun.(type) {
 	case *goast.SelectorExpr:
+		// Standard library: tmpl.ExecuteTemplate(w, template, data)
+		if fn.Sel.Name == config.ExecuteTemplateFunctionName && len(call.Args) >= 3 {
+			return 1
+		}
 		// Method call: obj.Render(template, ...)
 		return 0
 	case *goast.Ident:
@@ -68,6 +100,7 @@ func inferTemplateArgIdx(call *goast.CallExpr) int {
 func findTemplateArg(
 	call *goast.CallExpr,
 	initialIdx int,
+	info *types.Info,
 	stringAssignments map[string][]string,
 ) int {
 	if initialIdx >= 0 {
@@ -81,67 +114,119 @@ func findTemplateArg(
 			return i
 		}
 
+		// String constant or constant expression
+		if _, ok := stringConstant(arg, info); ok {
+			return i
+		}
+
 		// Variable with known string value
 		if ident, ok := arg.(*goast.Ident); ok {
 			if _, ok := stringAssignments[ident.Name]; ok {
 				return i
 			}
 		}
+
+		// Slice of names
+		if lit, ok := arg.(*goast.CompositeLit); ok && isStringSliceType(lit.Type) {
+			return i
+		}
 	}
 
 	return -1
 }
 
-// resolveTemplateName extracts template name(s) from an argument expression.
-// Handles string literals, constants, and variables.
+// resolveTemplateName extracts template name(s) from an argument expression
+// and reports how they were found. Handles string literals, constants,
+// variables, and []string literals whose elements are any of these with a
+// single value.
 func resolveTemplateName(
 	arg goast.Expr,
 	info *types.Info,
 	stringAssignments map[string][]string,
-) []string {
+) ([]string, string) {
 	// Try direct string extraction
 	if s := extractStringFast(arg); s != "" {
-		return []string{s}
+		return []string{s}, ResolvedViaLiteral
 	}
 
-	// Try identifier resolution
-	ident, ok := arg.(*goast.Ident)
-	if !ok {
-		return nil
+	if lit, ok := arg.(*goast.CompositeLit); ok {
+		if !isStringSliceType(lit.Type) || len(lit.Elts) == 0 {
+			return nil, ResolvedViaFailed
+		}
+		names := make([]string, 0, len(lit.Elts))
+		for _, elt := range lit.Elts {
+			elemNames, _ := resolveTemplateName(elt, info, stringAssignments)
+			if len(elemNames) != 1 {
+				return nil, ResolvedViaFailed
+			}
+			names = append(names, elemNames[0])
+		}
+		return names, ResolvedViaSlice
 	}
 
 	// Try constant resolution
-	if info != nil {
-		if obj := info.ObjectOf(ident); obj != nil {
-			if c, ok := obj.(*types.Const); ok {
-				val := c.Val()
-				if val.Kind() == constant.String {
-					return []string{constant.StringVal(val)}
-				}
-			}
-		}
+	if s, ok := stringConstant(arg, info); ok {
+		return []string{s}, ResolvedViaConstant
 	}
 
 	// Try variable resolution
+	ident, ok := arg.(*goast.Ident)
+	if !ok {
+		return nil, ResolvedViaFailed
+	}
 	if vals, ok := stringAssignments[ident.Name]; ok {
-		return vals
+		return vals, ResolvedViaVariable
+	}
+
+	return nil, ResolvedViaFailed
+}
+
+// stringConstant returns the value of expr when the type checker evaluated it
+// to a string constant: a constant of any string type, whether local or
+// selected from another package (pages.Home), a conversion of one, or a
+// constant expression such as Prefix + ".html".
+func stringConstant(expr goast.Expr, info *types.Info) (string, bool) {
+	if info == nil {
+		return "", false
+	}
+	tv, ok := info.Types[expr]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		return "", false
 	}
+	return constant.StringVal(tv.Value), true
+}
 
-	return nil
+// isStringSliceType reports whether expr is the type expression []string.
+func isStringSliceType(expr goast.Expr) bool {
+	arr, ok := expr.(*goast.ArrayType)
+	if !ok || arr.Len != nil {
+		return false
+	}
+	elt, ok := arr.Elt.(*goast.Ident)
+	return ok && elt.Name == "string"
 }
 
 // isRenderCall checks if a call expression is a template render call
-// based on configured function names.
-func isRenderCall(call *goast.CallExpr, config AnalysisConfig) bool {
-	funcName := ""
+// based on configured function names or a known render alias.
+func isRenderCall(call *goast.CallExpr, config AnalysisConfig, renderAliases map[string]int) bool {
+	if len(call.Args) < 2 {
+		return false
+	}
 
 	switch fn := call.Fun.(type) {
 	case *goast.SelectorExpr:
-		funcName = fn.Sel.Name
+		return isRenderFuncName(fn.Sel.Name, config)
 	case *goast.Ident:
-		funcName = fn.Name
+		if _, isAlias := renderAliases[fn.Name]; isAlias {
+			return true
+		}
+		return isRenderFuncName(fn.Name, config)
 	}
 
-	return (funcName == config.RenderFunctionName || funcName == config.ExecuteTemplateFunctionName) &&
-		len(call.Args) >= 2
+	return false
+}
+
+// isRenderFuncName reports whether name is one of the configured render functions.
+func isRenderFuncName(name string, config AnalysisConfig) bool {
+	return name == config.RenderFunctionName || name == config.ExecuteTemplateFunctionName
 }
@@ -470,6 +470,21 @@ func debugJSON(t *testing.T, v any) {
 	t.Logf("Debug JSON:\n%s", string(b))
 }
 
+// writeTree writes files, keyed by slash-separated path relative to dir,
+// creating the directories they need.
+func writeTree(t testing.TB, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
 // writeTestModule writes main.go + go.mod into tmpDir.
 func writeTestModule(t *testing.T, tmpDir, mainContent string) {
 	t.Helper()
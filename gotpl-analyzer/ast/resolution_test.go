@@ -0,0 +1,70 @@
+package ast
+
+import "testing"
+
+// TestRenderCallResolvedVia verifies that every render call records how its
+// template name was resolved and the argument expression it came from, and
+// that calls with unresolvable names are reported separately.
+func TestRenderCallResolvedVia(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	src := `package main
+
+type Context struct{}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+const usersPage = "users.html"
+
+func pageName() string { return "dynamic.html" }
+
+func handler(c *Context, admin bool) {
+	c.Render("home.html", map[string]interface{}{})
+	c.Render(usersPage, map[string]interface{}{})
+
+	tpl := "a.html"
+	if admin {
+		tpl = "b.html"
+	}
+	c.Render(tpl, map[string]interface{}{})
+
+	c.Render(pageName(), map[string]interface{}{})
+}
+`
+	writeTestModule(t, tmpDir, src)
+
+	result := AnalyzeDir(tmpDir, "", DefaultConfig)
+	if len(result.Errors) > 0 {
+		t.Fatalf("analysis errors: %v", result.Errors)
+	}
+
+	want := map[string]struct{ expr, via string }{
+		"home.html":  {`"home.html"`, ResolvedViaLiteral},
+		"users.html": {"usersPage", ResolvedViaConstant},
+		"a.html":     {"tpl", ResolvedViaVariable},
+		"b.html":     {"tpl", ResolvedViaVariable},
+	}
+	if len(result.RenderCalls) != len(want) {
+		t.Fatalf("expected %d render calls, got %d: %+v", len(want), len(result.RenderCalls), result.RenderCalls)
+	}
+	for _, rc := range result.RenderCalls {
+		w, ok := want[rc.Template]
+		if !ok {
+			t.Errorf("unexpected render call for %q", rc.Template)
+			continue
+		}
+		if rc.TemplateExpr != w.expr || rc.ResolvedVia != w.via {
+			t.Errorf("%s: expected expr %s via %s, got expr %s via %s", rc.Template, w.expr, w.via, rc.TemplateExpr, rc.ResolvedVia)
+		}
+	}
+
+	if len(result.UnresolvedRenderCalls) != 1 {
+		t.Fatalf("expected 1 unresolved render call, got %+v", result.UnresolvedRenderCalls)
+	}
+	failed := result.UnresolvedRenderCalls[0]
+	if failed.TemplateExpr != "pageName()" || failed.ResolvedVia != ResolvedViaFailed || failed.Template != "" {
+		t.Errorf("unexpected unresolved call: %+v", failed)
+	}
+	if failed.Line != 20 {
+		t.Errorf("expected unresolved call on line 20, got %d", failed.Line)
+	}
+}
@@ -0,0 +1,56 @@
+package ast
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestMaxResolvedNames verifies that a template name variable with more
+// possible values than the cap yields render calls for the first values only
+// and a note saying so.
+func TestMaxResolvedNames(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	src := `package main
+
+type Context struct{}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+func handler(c *Context, page int) {
+	tpl := "a.html"
+	switch page {
+	case 1:
+		tpl = "b.html"
+	case 2:
+		tpl = "c.html"
+	case 3:
+		tpl = "d.html"
+	}
+	c.Render(tpl, map[string]interface{}{"page": page})
+}
+`
+	writeTestModule(t, tmpDir, src)
+
+	config := DefaultConfig
+	config.MaxResolvedNames = 3
+	result := AnalyzeDir(tmpDir, "", config)
+
+	var names []string
+	for _, rc := range result.RenderCalls {
+		names = append(names, rc.Template)
+	}
+	if strings.Join(names, ",") != "a.html,b.html,c.html" {
+		t.Errorf("expected render calls for the first 3 names, got %v", names)
+	}
+
+	want := `main.go:16: Template name variable "tpl" has more than 3 possible values; validation limited to the first 3`
+	if len(result.Errors) != 1 || result.Errors[0] != want {
+		t.Errorf("expected note %q, got %v", want, result.Errors)
+	}
+
+	// At the default cap all four names are validated without a note.
+	result = AnalyzeDir(tmpDir, "", DefaultConfig)
+	if len(result.RenderCalls) != 4 || len(result.Errors) != 0 {
+		t.Errorf("expected 4 render calls and no errors, got %d and %v", len(result.RenderCalls), result.Errors)
+	}
+}
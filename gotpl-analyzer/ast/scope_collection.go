@@ -143,7 +143,7 @@ func processChunk(
 	localScopes := make([]FuncScope, 0, len(chunk)/2)
 	for _, unit := range chunk {
 		scope := processFunc(unit.node, info, fset, structIndex, fc, config, filesMap, seenPool, mutatorIndex, stringMapIndex)
-		if len(scope.RenderNodes) > 0 || len(scope.SetVars) > 0 || len(scope.FuncMaps) > 0 {
+		if len(scope.RenderNodes) > 0 || len(scope.Unresolved) > 0 || len(scope.SetVars) > 0 || len(scope.FuncMaps) > 0 {
 			localScopes = append(localScopes, scope)
 		}
 	}
@@ -7,9 +7,20 @@ import (
 	"strings"
 )
 
-// MaxAssignmentsPerVar is the maximum number of string assignments to track per variable
+// MaxAssignmentsPerVar is the default for AnalysisConfig.MaxResolvedNames,
+// the maximum number of string assignments to track per variable.
 const MaxAssignmentsPerVar = 10
 
+// maxResolvedNames returns the effective AnalysisConfig.MaxResolvedNames.
+// String assignments are collected up to one value past it, so that
+// resolveRenderCall can tell a variable that exceeds the cap.
+func maxResolvedNames(config AnalysisConfig) int {
+	if config.MaxResolvedNames <= 0 {
+		return MaxAssignmentsPerVar
+	}
+	return config.MaxResolvedNames
+}
+
 // processFunc analyzes a single function or declaration to extract:
 //  1. String literal assignments (for template name resolution)
 //  2. FuncMap assignments (template function definitions)
@@ -34,6 +45,7 @@ func processFunc(
 	}
 	stringAssignments := make(map[string][]string, 8)
 	funcMapAssignments := make(map[string]*goast.CompositeLit, 4)
+	renderAliases := make(map[string]int)
 
 	// Single fused walk: collect assignments AND find template operations together.
 	goast.Inspect(n, func(child goast.Node) bool {
@@ -46,21 +58,21 @@ func processFunc(
 
 		switch node := child.(type) {
 		case *goast.AssignStmt:
-			processAssignStmt(node, info, fset, filesMap, &scope, stringAssignments, funcMapAssignments, structIndex, fc, seenPool, stringMapIndex)
+			processAssignStmt(node, info, fset, filesMap, &scope, stringAssignments, funcMapAssignments, structIndex, fc, seenPool, stringMapIndex, config, renderAliases)
 			// Also check for render/set calls on the RHS.
 			for _, rhs := range node.Rhs {
 				if call, ok := rhs.(*goast.CallExpr); ok {
-					processCallExpr(call, info, fset, structIndex, fc, config, seenPool, &scope, stringAssignments)
+					processCallExpr(call, info, fset, structIndex, fc, config, seenPool, &scope, stringAssignments, renderAliases)
 				}
 			}
 
 		case *goast.GenDecl:
-			processGenDecl(node, info, fset, filesMap, &scope, stringAssignments, funcMapAssignments, structIndex, fc, seenPool)
+			processGenDecl(node, info, fset, filesMap, &scope, stringAssignments, funcMapAssignments, structIndex, fc, seenPool, config)
 
 		case *goast.CallExpr:
 			// Apply map mutator AND check for render/set in one step.
 			applyMapMutatorCall(node, &scope, mutatorIndex)
-			processCallExpr(node, info, fset, structIndex, fc, config, seenPool, &scope, stringAssignments)
+			processCallExpr(node, info, fset, structIndex, fc, config, seenPool, &scope, stringAssignments, renderAliases)
 
 		case *goast.CompositeLit:
 			// Inline FuncMap literals.
@@ -88,6 +100,8 @@ func processAssignStmt(
 	fc *fieldCache,
 	seenPool *seenMapPool,
 	stringMapIndex map[string][]string,
+	config AnalysisConfig,
+	renderAliases map[string]int,
 ) {
 	// ── Special case: map-index read  `v, ok := someMap[key]` ───────────────
 	if assign.Tok == token.DEFINE || assign.Tok == token.ASSIGN {
@@ -97,7 +111,7 @@ func processAssignStmt(
 					if vals, found := stringMapIndex[ident.Name]; found {
 						if len(assign.Lhs) >= 1 {
 							if lhsIdent, ok := assign.Lhs[0].(*goast.Ident); ok && lhsIdent.Name != "_" {
-								if len(stringAssignments[lhsIdent.Name]) < MaxAssignmentsPerVar {
+								if len(stringAssignments[lhsIdent.Name]) <= maxResolvedNames(config) {
 									stringAssignments[lhsIdent.Name] = append(
 										stringAssignments[lhsIdent.Name],
 										vals...,
@@ -123,6 +137,10 @@ func processAssignStmt(
 			if processFuncMapIndexAssign(indexExpr, rhs, info, fset, i, assign, scope, structIndex, fc, seenPool) {
 				continue
 			}
+			if setVar := extractSetIndexVar(indexExpr, rhs, info, fset, structIndex, fc, config, seenPool); setVar != nil {
+				scope.SetVars = append(scope.SetVars, *setVar)
+				continue
+			}
 			trackMapIndexAssign(indexExpr, rhs, scope)
 			continue
 		}
@@ -132,8 +150,10 @@ func processAssignStmt(
 			continue
 		}
 
+		trackRenderAlias(ident, rhs, info, config, renderAliases)
+
 		if s := extractStringFast(rhs); s != "" {
-			if len(stringAssignments[ident.Name]) < MaxAssignmentsPerVar {
+			if len(stringAssignments[ident.Name]) <= maxResolvedNames(config) {
 				stringAssignments[ident.Name] = append(stringAssignments[ident.Name], s)
 			}
 		}
@@ -150,6 +170,34 @@ func processAssignStmt(
 	}
 }
 
+// trackRenderAlias records ident as a render alias when rhs is a function
+// value whose selector matches a configured render function, e.g.
+// `render := c.Render`. Reassigning the identifier to anything else drops the
+// alias so unrelated function values are never treated as render calls.
+//
+// The stored value is the template argument index for calls made through the
+// alias: 0 when the receiver is bound by a method value, -1 (search the
+// arguments) for package-level functions such as `render := pkg.Render`.
+func trackRenderAlias(ident *goast.Ident, rhs goast.Expr, info *types.Info, config AnalysisConfig, renderAliases map[string]int) {
+	if ident.Name == "_" {
+		return
+	}
+
+	sel, ok := rhs.(*goast.SelectorExpr)
+	if !ok || !isRenderFuncName(sel.Sel.Name, config) {
+		delete(renderAliases, ident.Name)
+		return
+	}
+
+	argIdx := 0
+	if info != nil {
+		if selection, ok := info.Selections[sel]; !ok || selection.Kind() != types.MethodVal {
+			argIdx = -1
+		}
+	}
+	renderAliases[ident.Name] = argIdx
+}
+
 // trackMapIndexAssign records an index-assignment mutation on a map variable.
 func trackMapIndexAssign(indexExpr *goast.IndexExpr, rhs goast.Expr, scope *FuncScope) {
 	ident, ok := indexExpr.X.(*goast.Ident)
@@ -224,6 +272,7 @@ func processGenDecl(
 	structIndex map[string]structIndexEntry,
 	fc *fieldCache,
 	seenPool *seenMapPool,
+	config AnalysisConfig,
 ) {
 	if decl.Tok != token.VAR && decl.Tok != token.CONST {
 		return
@@ -242,7 +291,7 @@ func processGenDecl(
 			rhs := vspec.Values[i]
 
 			if s := extractStringFast(rhs); s != "" {
-				if len(stringAssignments[name.Name]) < MaxAssignmentsPerVar {
+				if len(stringAssignments[name.Name]) <= maxResolvedNames(config) {
 					stringAssignments[name.Name] = append(stringAssignments[name.Name], s)
 				}
 			}
@@ -279,9 +328,13 @@ func processCallExpr(
 	seenPool *seenMapPool,
 	scope *FuncScope,
 	stringAssignments map[string][]string,
+	renderAliases map[string]int,
 ) {
-	if isRenderCall(call, config) {
-		if resolved := resolveRenderCall(call, info, stringAssignments); resolved != nil {
+	if isRenderCall(call, config, renderAliases) {
+		resolved := resolveRenderCall(call, info, stringAssignments, renderAliases, config)
+		if resolved.ResolvedVia == ResolvedViaFailed {
+			scope.Unresolved = append(scope.Unresolved, *resolved)
+		} else {
 			scope.RenderNodes = append(scope.RenderNodes, *resolved)
 		}
 		return
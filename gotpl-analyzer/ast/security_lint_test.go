@@ -0,0 +1,51 @@
+package ast
+
+import "testing"
+
+// TestSecurityLintsMarkRawHTMLFuncs verifies that FuncMap entries named in
+// RawHTMLFuncs are flagged Unescaped only when SecurityLints is enabled.
+func TestSecurityLintsMarkRawHTMLFuncs(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	src := `package main
+
+import (
+	"html/template"
+	"strings"
+)
+
+var funcs = template.FuncMap{
+	"safeHTML": func(s string) template.HTML { return template.HTML(s) },
+	"upper":    strings.ToUpper,
+}
+
+func main() { _ = funcs }
+`
+	writeTestModule(t, tmpDir, src)
+
+	unescaped := func(config AnalysisConfig) map[string]bool {
+		result := AnalyzeDir(tmpDir, "", config)
+		if len(result.Errors) > 0 {
+			t.Fatalf("analysis errors: %v", result.Errors)
+		}
+		marks := make(map[string]bool)
+		for _, fm := range result.FuncMaps {
+			marks[fm.Name] = fm.Unescaped
+		}
+		return marks
+	}
+
+	if marks := unescaped(DefaultConfig); marks["safeHTML"] {
+		t.Errorf("safeHTML should not be marked without SecurityLints")
+	}
+
+	config := DefaultConfig
+	config.SecurityLints = true
+	marks := unescaped(config)
+	if !marks["safeHTML"] {
+		t.Errorf("expected safeHTML to be marked unescaped, got %v", marks)
+	}
+	if marks["upper"] {
+		t.Errorf("upper should not be marked unescaped")
+	}
+}
@@ -4,11 +4,13 @@ import (
 	goast "go/ast"
 	"go/token"
 	"go/types"
+	"slices"
 )
 
 // extractSetCallVarOptimized extracts template variable information from
-// a context.Set() call. Validates the receiver type and extracts comprehensive
-// type information including nested fields and documentation.
+// a context.Set() call, or a call to one of AnalysisConfig.SetFunctionNames.
+// Validates the receiver type and extracts comprehensive type information
+// including nested fields and documentation.
 //
 // Example: ctx.Set("user", user)
 // Extracts: name="user", type, fields, documentation
@@ -23,12 +25,12 @@ func extractSetCallVarOptimized(
 ) *TemplateVar {
 	// Must be method call
 	sel, ok := call.Fun.(*goast.SelectorExpr)
-	if !ok || sel.Sel.Name != config.SetFunctionName {
+	if !ok || !isSetFuncName(sel.Sel.Name, config) {
 		return nil
 	}
 
 	// Verify receiver type matches configured context type
-	if !isContextType(sel.X, info, config.ContextTypeName) {
+	if !isContextType(sel.X, info, config) {
 		return nil
 	}
 
@@ -42,9 +44,72 @@ func extractSetCallVarOptimized(
 		return nil
 	}
 
+	return buildSetVar(key, call.Args[1], info, fset, structIndex, fc, seenPool)
+}
+
+// extractSetIndexVar extracts template variable information from an
+// assignment to the map a context method returns, as frameworks exposing
+// their template data do.
+//
+// Example: ctx.Data()["user"] = user
+// Extracts: name="user", type, fields, documentation
+func extractSetIndexVar(
+	indexExpr *goast.IndexExpr,
+	valArg goast.Expr,
+	info *types.Info,
+	fset *token.FileSet,
+	structIndex map[string]structIndexEntry,
+	fc *fieldCache,
+	config AnalysisConfig,
+	seenPool *seenMapPool,
+) *TemplateVar {
+	call, ok := indexExpr.X.(*goast.CallExpr)
+	if !ok || info == nil {
+		return nil
+	}
+	sel, ok := call.Fun.(*goast.SelectorExpr)
+	if !ok || !isContextType(sel.X, info, config) {
+		return nil
+	}
+
+	// The method must return a map keyed by strings.
+	tv, ok := info.Types[indexExpr.X]
+	if !ok || tv.Type == nil {
+		return nil
+	}
+	m, ok := tv.Type.Underlying().(*types.Map)
+	if !ok {
+		return nil
+	}
+	if key, ok := m.Key().Underlying().(*types.Basic); !ok || key.Kind() != types.String {
+		return nil
+	}
+
+	key := extractStringFast(indexExpr.Index)
+	if key == "" {
+		return nil
+	}
+
+	return buildSetVar(key, valArg, info, fset, structIndex, fc, seenPool)
+}
+
+// isSetFuncName reports whether name is one of the configured setter methods.
+func isSetFuncName(name string, config AnalysisConfig) bool {
+	return name == config.SetFunctionName || slices.Contains(config.SetFunctionNames, name)
+}
+
+// buildSetVar builds the template variable key set to valArg.
+func buildSetVar(
+	key string,
+	valArg goast.Expr,
+	info *types.Info,
+	fset *token.FileSet,
+	structIndex map[string]structIndexEntry,
+	fc *fieldCache,
+	seenPool *seenMapPool,
+) *TemplateVar {
 	// Build template variable with full type information
 	tv := TemplateVar{Name: key}
-	valArg := call.Args[1]
 
 	// Extract type information if available
 	if typeInfo, ok := info.Types[valArg]; ok && typeInfo.Type != nil {
@@ -69,8 +134,9 @@ func extractSetCallVarOptimized(
 	return &tv
 }
 
-// isContextType verifies that an expression has the configured context type.
-func isContextType(expr goast.Expr, info *types.Info, contextTypeName string) bool {
+// isContextType verifies that an expression has the configured context type,
+// or a type implementing the configured context interface.
+func isContextType(expr goast.Expr, info *types.Info, config AnalysisConfig) bool {
 	if info == nil || expr == nil {
 		return false
 	}
@@ -81,6 +147,9 @@ func isContextType(expr goast.Expr, info *types.Info, contextTypeName string) bo
 	}
 
 	t := typeAndValue.Type
+	if config.contextInterface != nil && types.Implements(t, config.contextInterface) {
+		return true
+	}
 
 	// Dereference pointer
 	if ptr, ok := t.(*types.Pointer); ok {
@@ -89,7 +158,29 @@ func isContextType(expr goast.Expr, info *types.Info, contextTypeName string) bo
 
 	// Check named type
 	named, ok := t.(*types.Named)
-	return ok && named.Obj().Name() == contextTypeName
+	return ok && named.Obj().Name() == config.ContextTypeName
+}
+
+// lookupContextInterface returns the interface type named name among the
+// types declared in or used by the loaded packages, or nil when there is
+// none. An interface without methods is ignored since every type implements
+// it.
+func lookupContextInterface(info *types.Info, name string) *types.Interface {
+	if info == nil || name == "" {
+		return nil
+	}
+	for _, objs := range []map[*goast.Ident]types.Object{info.Defs, info.Uses} {
+		for _, obj := range objs {
+			tn, ok := obj.(*types.TypeName)
+			if !ok || tn.Name() != name {
+				continue
+			}
+			if iface, ok := tn.Type().Underlying().(*types.Interface); ok && iface.NumMethods() > 0 {
+				return iface
+			}
+		}
+	}
+	return nil
 }
 
 // checkSliceType determines if a type is a slice and extracts element type info.
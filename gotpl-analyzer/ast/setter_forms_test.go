@@ -0,0 +1,79 @@
+package ast
+
+import "testing"
+
+// TestSetterForms verifies that variables set through a configured setter
+// method other than Set, or assigned into the map a context method returns,
+// reach the render call with their type and fields.
+func TestSetterForms(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	src := `package main
+
+type Context struct{ data map[string]any }
+func (c *Context) With(key string, val any) *Context { return c }
+func (c *Context) Data() map[string]any { return c.data }
+func (c *Context) Labels() map[int]any { return nil }
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+type Address struct{ City string }
+type User struct {
+	Name    string
+	Address Address
+}
+
+func handler(c *Context) {
+	c.With("user", User{})
+	c.Data()["owner"] = &User{}
+	c.Labels()[1] = "ignored"
+	c.Render("page.html", nil)
+}
+`
+	writeTestModule(t, tmpDir, src)
+
+	config := DefaultConfig
+	config.SetFunctionNames = []string{"With"}
+	result := AnalyzeDir(tmpDir, "", config)
+	if len(result.Errors) > 0 {
+		t.Fatalf("analysis errors: %v", result.Errors)
+	}
+	if len(result.RenderCalls) != 1 {
+		t.Fatalf("expected 1 render call, got %+v", result.RenderCalls)
+	}
+
+	vars := make(map[string]TemplateVar)
+	for _, v := range result.RenderCalls[0].Vars {
+		vars[v.Name] = v
+	}
+	if len(vars) != 2 {
+		t.Fatalf("expected user and owner, got %+v", result.RenderCalls[0].Vars)
+	}
+
+	for name, typeStr := range map[string]string{"user": "main.User", "owner": "*main.User"} {
+		v, ok := vars[name]
+		if !ok {
+			t.Errorf("variable %q not found", name)
+			continue
+		}
+		if v.TypeStr != typeStr {
+			t.Errorf("%s: expected type %s, got %s", name, typeStr, v.TypeStr)
+		}
+		var address *FieldInfo
+		for i := range v.Fields {
+			if v.Fields[i].Name == "Address" {
+				address = &v.Fields[i]
+			}
+		}
+		if address == nil || len(address.Fields) != 1 || address.Fields[0].Name != "City" {
+			t.Errorf("%s: expected nested Address.City field, got %+v", name, v.Fields)
+		}
+	}
+
+	config.SetFunctionNames = nil
+	result = AnalyzeDir(tmpDir, "", config)
+	for _, v := range result.RenderCalls[0].Vars {
+		if v.Name == "user" {
+			t.Errorf("With is not a setter unless configured, got %+v", v)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package ast
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestFieldShadowsPromotedMethod verifies that a direct field hides a method
+// of the same name promoted from an embedded type: the field is kept once,
+// flagged, and the collision is reported as an analysis warning.
+func TestFieldShadowsPromotedMethod(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	src := `package main
+
+type Context struct{}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+type Base struct{}
+
+func (Base) Title() string { return "" }
+func (Base) Slug() string  { return "" }
+
+type Page struct {
+	Base
+	Title string
+}
+
+func handler(c *Context) {
+	c.Render("page.html", map[string]interface{}{
+		"page": Page{},
+	})
+}
+`
+	writeTestModule(t, tmpDir, src)
+
+	result := AnalyzeDir(tmpDir, "", DefaultConfig)
+	want := `Type main.Page has both a field and method named "Title"`
+	if !slices.Contains(result.Errors, want) {
+		t.Errorf("expected warning %q, got %v", want, result.Errors)
+	}
+	if len(result.RenderCalls) != 1 {
+		t.Fatalf("expected 1 render call, got %d", len(result.RenderCalls))
+	}
+
+	var titles []FieldInfo
+	hasSlug := false
+	for _, v := range result.RenderCalls[0].Vars {
+		if v.Name != "page" {
+			continue
+		}
+		for _, f := range v.Fields {
+			switch f.Name {
+			case "Title":
+				titles = append(titles, f)
+			case "Slug":
+				hasSlug = true
+			}
+		}
+	}
+
+	if len(titles) != 1 {
+		t.Fatalf("expected a single Title entry, got %d: %+v", len(titles), titles)
+	}
+	if titles[0].TypeStr != "string" {
+		t.Errorf("expected the Title field to win, got type %q", titles[0].TypeStr)
+	}
+	if !titles[0].ShadowsMethod {
+		t.Error("expected Title to be flagged as shadowing a method")
+	}
+	if !hasSlug {
+		t.Error("expected the promoted Slug method to be kept")
+	}
+}
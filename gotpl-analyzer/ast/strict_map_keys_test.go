@@ -0,0 +1,61 @@
+package ast
+
+import (
+	"slices"
+	"testing"
+)
+
+// TestStrictMapKeysRecordsLiteralKeys verifies that with StrictMapKeys enabled
+// a map passed as a composite literal carries its key set, while a map built
+// at runtime stays without one.
+func TestStrictMapKeysRecordsLiteralKeys(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	src := `package main
+
+type Context struct{}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+func handler(c *Context, dynamic map[string]string) {
+	c.Render("page.html", map[string]interface{}{
+		"config": map[string]string{
+			"host": "localhost",
+			"port": "5432",
+		},
+		"dynamic": dynamic,
+	})
+}
+`
+	writeTestModule(t, tmpDir, src)
+
+	config := DefaultConfig
+	config.StrictMapKeys = true
+	result := AnalyzeDir(tmpDir, "", config)
+	if len(result.Errors) > 0 {
+		t.Fatalf("analysis errors: %v", result.Errors)
+	}
+	if len(result.RenderCalls) != 1 {
+		t.Fatalf("expected 1 render call, got %d", len(result.RenderCalls))
+	}
+
+	for _, v := range result.RenderCalls[0].Vars {
+		switch v.Name {
+		case "config":
+			if !slices.Equal(v.MapKeys, []string{"host", "port"}) {
+				t.Errorf("config map keys = %v, want [host port]", v.MapKeys)
+			}
+		case "dynamic":
+			if len(v.MapKeys) != 0 {
+				t.Errorf("dynamic map should have no key set, got %v", v.MapKeys)
+			}
+		}
+	}
+
+	// Without the option no key sets are recorded.
+	result = AnalyzeDir(tmpDir, "", DefaultConfig)
+	for _, v := range result.RenderCalls[0].Vars {
+		if len(v.MapKeys) != 0 {
+			t.Errorf("%s: expected no key set without StrictMapKeys, got %v", v.Name, v.MapKeys)
+		}
+	}
+}
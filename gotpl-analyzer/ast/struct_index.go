@@ -12,7 +12,8 @@ import (
 //
 // OPTIMISATION: Pass 2 (attachMethodDocs) is now also parallelised.
 // Previously it was a sequential loop over all files; with many large files
-// this was a significant serial bottleneck.
+// this was a significant serial bottleneck. See attachMethodDocsConcurrent for
+// how updates to the same struct from different files are merged.
 func buildStructIndex(fset *token.FileSet, files map[string]*goast.File) map[string]structIndexEntry {
 	numWorkers := max(runtime.NumCPU(), 1)
 	fileChan := make(chan *goast.File, len(files))
@@ -122,73 +123,79 @@ func convertSyncMapToMap(sharedIndex *sync.Map, estimatedSize int) map[string]st
 	return finalIndex
 }
 
-// methodDocWork is a single method documentation attachment task.
-type methodDocWork struct {
-	file    *goast.File
-	pkgName string
+// methodDocUpdate is a method doc discovered by a Pass 2 worker, applied to
+// the index once all workers have finished.
+type methodDocUpdate struct {
+	key  string // struct index key (pkg.Type)
+	name string // method name
+	info fieldInfo
 }
 
 // attachMethodDocsConcurrent parallelises Pass 2 of buildStructIndex.
 //
-// OPTIMISATION: The original attachMethodDocs function was a sequential loop.
-// Each file is independent so we fan the work out across all CPU cores.
-// The finalIndex map is pre-built (no new keys are added here) so concurrent
-// reads of the map are safe; the writes are protected per-entry by a
-// fine-grained per-key approach: we read the entry, mutate it locally, and
-// write it back under a mutex.
+// Files are split into one chunk per CPU and scanned concurrently. Workers
+// never touch the index: each collects its (key, method, info) tuples into its
+// own slice, and the tuples are applied single-threaded after wg.Wait. Methods
+// of the same type declared in different files therefore cannot race or
+// overwrite each other's entries.
 func attachMethodDocsConcurrent(files map[string]*goast.File, fset *token.FileSet, index map[string]structIndexEntry) {
-	works := make([]methodDocWork, 0, len(files))
+	fileList := make([]*goast.File, 0, len(files))
 	for _, f := range files {
-		works = append(works, methodDocWork{file: f, pkgName: f.Name.Name})
+		fileList = append(fileList, f)
 	}
 
-	if len(works) == 0 {
+	if len(fileList) == 0 {
 		return
 	}
 
 	numWorkers := max(runtime.NumCPU(), 1)
-	chunkSize := (len(works) + numWorkers - 1) / numWorkers
+	chunkSize := (len(fileList) + numWorkers - 1) / numWorkers
+	results := make([][]methodDocUpdate, numWorkers)
 
-	var mu sync.Mutex // protects writes to index
 	var wg sync.WaitGroup
 
 	for w := range numWorkers {
 		start := w * chunkSize
-		if start >= len(works) {
+		if start >= len(fileList) {
 			break
 		}
-		end := min(start+chunkSize, len(works))
-		chunk := works[start:end]
+		end := min(start+chunkSize, len(fileList))
 
 		wg.Add(1)
-		go func(chunk []methodDocWork) {
+		go func(w int, chunk []*goast.File) {
 			defer wg.Done()
-			attachMethodDocsChunk(chunk, fset, index, &mu)
-		}(chunk)
+			results[w] = collectMethodDocs(chunk, fset)
+		}(w, fileList[start:end])
 	}
 
 	wg.Wait()
-}
 
-// attachMethodDocsChunk processes a slice of files and attaches method docs.
-func attachMethodDocsChunk(works []methodDocWork, fset *token.FileSet, index map[string]structIndexEntry, mu *sync.Mutex) {
-	// Accumulate updates locally to minimise lock contention.
-	type update struct {
-		key  string
-		name string
-		info fieldInfo
+	for _, updates := range results {
+		for _, u := range updates {
+			if entry, exists := index[u.key]; exists {
+				entry.fields[u.name] = u.info
+			}
+		}
 	}
-	updates := make([]update, 0, 16)
+}
+
+// collectMethodDocs returns the documented methods declared in files.
+// Methods are always top-level declarations, so only f.Decls is scanned
+// instead of walking every node with ast.Inspect.
+func collectMethodDocs(files []*goast.File, fset *token.FileSet) []methodDocUpdate {
+	var updates []methodDocUpdate
 
-	for _, w := range works {
-		goast.Inspect(w.file, func(n goast.Node) bool {
-			funcDecl, ok := n.(*goast.FuncDecl)
+	for _, f := range files {
+		pkgName := f.Name.Name
+
+		for _, decl := range f.Decls {
+			funcDecl, ok := decl.(*goast.FuncDecl)
 			if !ok || funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
-				return true
+				continue
 			}
 
 			if funcDecl.Doc == nil {
-				return true // no doc to attach
+				continue // no doc to attach
 			}
 
 			recvType := funcDecl.Recv.List[0].Type
@@ -207,35 +214,19 @@ func attachMethodDocsChunk(works []methodDocWork, fset *token.FileSet, index map
 			}
 
 			if ident == nil {
-				return true
+				continue
 			}
 
-			key := w.pkgName + "." + ident.Name
-			doc := funcDecl.Doc.Text()
 			pos := fset.Position(funcDecl.Pos())
-
-			updates = append(updates, update{
-				key:  key,
+			updates = append(updates, methodDocUpdate{
+				key:  pkgName + "." + ident.Name,
 				name: funcDecl.Name.Name,
-				info: fieldInfo{file: pos.Filename, line: pos.Line, col: pos.Column, doc: doc},
+				info: fieldInfo{file: pos.Filename, line: pos.Line, col: pos.Column, doc: funcDecl.Doc.Text()},
 			})
-
-			return true
-		})
-	}
-
-	if len(updates) == 0 {
-		return
-	}
-
-	mu.Lock()
-	for _, u := range updates {
-		if entry, exists := index[u.key]; exists {
-			entry.fields[u.name] = u.info
-			index[u.key] = entry
 		}
 	}
-	mu.Unlock()
+
+	return updates
 }
 
 // extractTypeDoc retrieves documentation from type declaration.
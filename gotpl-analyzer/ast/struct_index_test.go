@@ -0,0 +1,79 @@
+package ast
+
+import (
+	"fmt"
+	goast "go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+// parseStructIndexFiles parses sources into the map shape buildStructIndex expects.
+func parseStructIndexFiles(t testing.TB, sources map[string]string) (*token.FileSet, map[string]*goast.File) {
+	t.Helper()
+	fset := token.NewFileSet()
+	files := make(map[string]*goast.File, len(sources))
+	for name, src := range sources {
+		f, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[name] = f
+	}
+	return fset, files
+}
+
+// TestBuildStructIndexMergesMethodsAcrossFiles verifies that method docs for
+// one struct declared across many files all land in the index.
+func TestBuildStructIndexMergesMethodsAcrossFiles(t *testing.T) {
+	sources := map[string]string{
+		"user.go": "package app\n\n// User is a user.\ntype User struct {\n\t// Name is the display name.\n\tName string\n}\n",
+	}
+	const numFiles = 32
+	for i := range numFiles {
+		sources[fmt.Sprintf("user_m%d.go", i)] = fmt.Sprintf(
+			"package app\n\n// Method%d does thing %d.\nfunc (u *User) Method%d() string { return \"\" }\n", i, i, i)
+	}
+
+	fset, files := parseStructIndexFiles(t, sources)
+	index := buildStructIndex(fset, files)
+
+	entry, ok := index["app.User"]
+	if !ok {
+		t.Fatal("expected app.User in struct index")
+	}
+	if got := entry.fields["Name"].doc; !strings.Contains(got, "display name") {
+		t.Errorf("expected field doc for Name, got %q", got)
+	}
+	for i := range numFiles {
+		name := fmt.Sprintf("Method%d", i)
+		info, ok := entry.fields[name]
+		if !ok {
+			t.Errorf("missing method doc for %s", name)
+			continue
+		}
+		if want := fmt.Sprintf("does thing %d", i); !strings.Contains(info.doc, want) {
+			t.Errorf("%s doc = %q, want it to contain %q", name, info.doc, want)
+		}
+	}
+}
+
+// BenchmarkBuildStructIndex measures index construction for a large package
+// where most of the cost is in the method-doc pass.
+func BenchmarkBuildStructIndex(b *testing.B) {
+	sources := make(map[string]string, 400)
+	for i := range 400 {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "package app\n\n// Type%d doc.\ntype Type%d struct {\n\tA, B string\n\tC int\n}\n", i, i)
+		for m := range 25 {
+			fmt.Fprintf(&sb, "\n// M%d doc.\nfunc (t *Type%d) M%d() int {\n\tif t.C > %d {\n\t\treturn t.C\n\t}\n\treturn len(t.A) + len(t.B)\n}\n", m, i, m, m)
+		}
+		sources[fmt.Sprintf("file%d.go", i)] = sb.String()
+	}
+	fset, files := parseStructIndexFiles(b, sources)
+
+	for b.Loop() {
+		buildStructIndex(fset, files)
+	}
+}
@@ -0,0 +1,41 @@
+package ast
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// PerPackageTemplateRoot returns an AnalysisConfig.TemplateRootResolver that
+// resolves each render call against the nearest directory named dirName
+// (e.g. "templates") found by walking up from the directory of the call's Go
+// file. The walk stops at baseDir, which is searched too; a call with no such
+// directory in between falls back to baseDir and templateRoot.
+//
+// For a call in baseDir/serviceA/handlers/user.go, the resolver tries
+// serviceA/handlers/templates, then serviceA/templates, then templates below
+// baseDir, and returns the parent of the first match with dirName as the root.
+func PerPackageTemplateRoot(dirName, baseDir, templateRoot string) func(goFile string) (string, string) {
+	baseDir = filepath.Clean(baseDir)
+	return func(goFile string) (string, string) {
+		dir := filepath.Dir(goFile)
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(baseDir, dir)
+		}
+		for {
+			if info, err := os.Stat(filepath.Join(dir, dirName)); err == nil && info.IsDir() {
+				return dir, dirName
+			}
+			if dir == baseDir || !isWithin(baseDir, dir) {
+				return baseDir, templateRoot
+			}
+			dir = filepath.Dir(dir)
+		}
+	}
+}
+
+// isWithin reports whether path is base or lies below it.
+func isWithin(base, path string) bool {
+	rel, err := filepath.Rel(base, path)
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -1,6 +1,10 @@
 package ast
 
-import goast "go/ast"
+import (
+	goast "go/ast"
+	"go/types"
+	"log/slog"
+)
 
 // TemplateVar represents a variable available in a template context, including its type, fields, and definition location.
 type TemplateVar struct {
@@ -20,6 +24,9 @@ type TemplateVar struct {
 	KeyType string `json:"keyType,omitempty"`
 	// ElemType is the string representation of the slice's or map's element type, if IsSlice or IsMap is true.
 	ElemType string `json:"elemType,omitempty"`
+	// MapKeys is the finite set of keys of a map built from a composite literal.
+	// Only populated when AnalysisConfig.StrictMapKeys is enabled; empty for dynamic maps.
+	MapKeys []string `json:"mapKeys,omitempty"`
 
 	// DefFile is the Go file where the variable is defined.
 	DefFile string `json:"defFile,omitempty"`
@@ -54,6 +61,10 @@ type FieldInfo struct {
 	Params []ParamInfo `json:"params,omitempty"`
 	// Returns are the return values of the method, if this FieldInfo represents a method.
 	Returns []ParamInfo `json:"returns,omitempty"`
+	// Variadic reports whether the method's last parameter is variadic; its
+	// TypeStr is then the slice type, e.g. []string for ...string. Nil when
+	// unknown, as for methods described by hand in JSON.
+	Variadic *bool `json:"variadic,omitempty"`
 	// DefFile is the Go file where the field or method is defined.
 	DefFile string `json:"defFile,omitempty"`
 	// DefLine is the line number where the field or method is defined.
@@ -62,6 +73,16 @@ type FieldInfo struct {
 	DefCol int `json:"defCol,omitempty"`
 	// Doc is the documentation comment for the field or method.
 	Doc string `json:"doc,omitempty"`
+	// Unexported reports whether the field is unexported. Unexported fields
+	// are only extracted when AnalysisConfig.IncludeUnexported is set; fields
+	// from context files are always treated as exported.
+	Unexported bool `json:"unexported,omitempty"`
+	// Deprecated is the note following "Deprecated:" in the field's doc
+	// comment. Only set when AnalysisConfig.WarnDeprecated is enabled.
+	Deprecated string `json:"deprecated,omitempty"`
+	// ShadowsMethod reports that the type also has a promoted method of the
+	// same name, which this field hides.
+	ShadowsMethod bool `json:"shadowsMethod,omitempty"`
 }
 
 // RenderCall represents a detected template rendering invocation in Go source code.
@@ -78,8 +99,45 @@ type RenderCall struct {
 	TemplateNameEndCol int `json:"templateNameEndCol,omitempty"`
 	// Vars are the template variables explicitly passed to this render call.
 	Vars []TemplateVar `json:"vars"`
+	// TemplateExpr is the template name argument as written in the Go source.
+	TemplateExpr string `json:"templateExpr,omitempty"`
+	// ResolvedVia records how Template was resolved (one of the ResolvedVia* constants).
+	ResolvedVia string `json:"resolvedVia,omitempty"`
+	// NilData is true when the data argument is a literal nil, so the template
+	// receives no data beyond scope and global variables.
+	NilData bool `json:"nilData,omitempty"`
+	// ComposedWith lists the other templates of a call that renders a slice
+	// of names, as in c.Render([]string{"base.html", "page.html"}, data).
+	// Template is the last name, executed against the data; the templates
+	// listed here only contribute their named blocks.
+	ComposedWith []string `json:"composedWith,omitempty"`
+	// DuplicateVars lists the variables the handler sets more than once for
+	// this call, e.g. with two Set("user", ...) calls, at the definition of
+	// the value that wins. Vars holds each name once.
+	DuplicateVars []DuplicateVar `json:"duplicateVars,omitempty"`
 }
 
+// DuplicateVar locates the winning definition of a variable set more than
+// once for a render call.
+type DuplicateVar struct {
+	// Name is the variable name.
+	Name string `json:"name"`
+	// DefFile is the Go file of the winning definition, relative to the
+	// analysis root.
+	DefFile string `json:"defFile,omitempty"`
+	// DefLine is the line of the winning definition.
+	DefLine int `json:"defLine,omitempty"`
+}
+
+// Resolution provenance values for RenderCall.ResolvedVia.
+const (
+	ResolvedViaLiteral  = "literal"  // string literal argument
+	ResolvedViaConstant = "constant" // named string constant
+	ResolvedViaVariable = "variable" // variable with tracked string assignments
+	ResolvedViaSlice    = "slice"    // []string literal of names composed into one template set
+	ResolvedViaFailed   = "failed"   // template name could not be determined
+)
+
 // AnalysisResult is the top-level output structure containing all static analysis findings.
 type AnalysisResult struct {
 	// RenderCalls lists all identified template rendering invocations.
@@ -87,7 +145,10 @@ type AnalysisResult struct {
 	// FuncMaps lists all discovered template function map declarations.
 	FuncMaps []FuncMapInfo `json:"funcMaps"`
 	// Errors contains any non-fatal errors encountered during the analysis process.
-	Errors []string `json:"errors"`
+	Errors []string `json:"errors,omitempty"`
+	// UnresolvedRenderCalls lists render calls whose template name could not
+	// be resolved. Template is empty and ResolvedVia is ResolvedViaFailed.
+	UnresolvedRenderCalls []RenderCall `json:"unresolvedRenderCalls,omitempty"`
 
 	// Types is the global type registry mapping each named type to its direct
 	// (one-level-deep) fields. Populated by BuildTypeRegistry; consumers
@@ -107,6 +168,9 @@ type FuncMapInfo struct {
 	Args []string `json:"args"`
 	// Returns describes the return values of the function.
 	Returns []ParamInfo `json:"returns"`
+	// Variadic reports whether the function's last parameter is variadic; its
+	// TypeStr is then the slice type, e.g. []any for ...any.
+	Variadic bool `json:"variadic,omitempty"`
 	// Doc is the documentation comment for the function.
 	Doc string `json:"doc,omitempty"`
 	// DefFile is the Go file where the function is defined.
@@ -115,6 +179,9 @@ type FuncMapInfo struct {
 	DefLine int `json:"defLine,omitempty"`
 	// DefCol is the column number where the function is defined.
 	DefCol int `json:"defCol,omitempty"`
+	// Unescaped marks a function whose output bypasses auto-escaping.
+	// Only set when AnalysisConfig.SecurityLints is enabled.
+	Unescaped bool `json:"unescaped,omitempty"`
 
 	// Fields of the primary return type after unwrapping pointer and slice.
 	// e.g. func() *[]MgtHints → fields of MgtHints.
@@ -142,15 +209,149 @@ type ParamInfo struct {
 // AnalysisConfig defines customizable function and type names used by the analyzer to identify template-related constructs.
 type AnalysisConfig struct {
 	// RenderFunctionName is the name of the function or method used to render templates (default: "Render").
-	RenderFunctionName string
+	RenderFunctionName string `json:"renderFunctionName"`
+	// DataArgOffsets maps a render function or method name to the position
+	// of its data argument relative to its template argument, for render
+	// functions that take other arguments in between, e.g. {"Render": 2} for
+	// c.Render("page.html", http.StatusOK, data). Functions not listed, or
+	// listed with a non-positive offset, take the data right after the
+	// template argument (default: none).
+	DataArgOffsets map[string]int `json:"dataArgOffsets,omitempty"`
 	// ExecuteTemplateFunctionName is an alternative function name for rendering templates (default: "ExecuteTemplate").
-	ExecuteTemplateFunctionName string
+	ExecuteTemplateFunctionName string `json:"executeTemplateFunctionName"`
 	// SetFunctionName is the name of the method used to explicitly set context variables within a template (default: "Set").
-	SetFunctionName string
+	SetFunctionName string `json:"setFunctionName"`
+	// SetFunctionNames lists further methods of the context type that take a
+	// key and a value like SetFunctionName, e.g. "With" for c.With("user", u).
+	SetFunctionNames []string `json:"setFunctionNames,omitempty"`
 	// ContextTypeName is the name of the Go type that represents the template execution context (default: "Context").
-	ContextTypeName string
+	ContextTypeName string `json:"contextTypeName"`
+	// ContextInterfaceName is the name of an interface the context type
+	// satisfies, e.g. "Renderer", for handlers written against the interface
+	// rather than the concrete type. Set calls on any expression whose type
+	// implements it are recognized as well. Empty disables it (default: "").
+	ContextInterfaceName string `json:"contextInterfaceName,omitempty"`
 	// GlobalTemplateName is the special key used in the context file to define global template variables (default: "global").
-	GlobalTemplateName string
+	GlobalTemplateName string `json:"globalTemplateName"`
+	// StrictMapKeys records the literal key set of maps built from composite literals
+	// so the validator can warn about accesses to unknown keys (default: false).
+	StrictMapKeys bool `json:"strictMapKeys"`
+	// SecurityLints enables escaping lints: plain strings rendered through a raw-output
+	// function, and safe HTML values escaped again with html (default: false).
+	// The first lint applies to the FuncMap entries named in RawHTMLFuncs.
+	SecurityLints bool `json:"securityLints"`
+	// RawHTMLFuncs names the template functions whose output bypasses html/template
+	// auto-escaping (default: "safeHTML", "noescape").
+	RawHTMLFuncs []string `json:"rawHTMLFuncs"`
+	// IncludeUnexported extracts unexported struct fields as well, for template engines
+	// that can reach them (default: false, matching text/template semantics).
+	IncludeUnexported bool `json:"includeUnexported"`
+	// DefineIsFileEntry treats a rendered file consisting solely of
+	// {{define "X"}}, where X is the file's base name without extension, as an
+	// entry point whose effective content is the define's body. This models
+	// renderers that execute the define named after the file instead of the
+	// file's (empty) top-level template (default: false).
+	DefineIsFileEntry bool `json:"defineIsFileEntry"`
+	// DynamicTemplateNameSeverity is the severity ("info", "warning" or "error")
+	// of the note emitted for {{template $name .}} includes whose target cannot
+	// be verified statically. Empty disables the note (default).
+	DynamicTemplateNameSeverity string `json:"dynamicTemplateNameSeverity"`
+	// WarnDeprecated warns when a template references a struct field whose doc
+	// comment starts with "Deprecated:" (default: false).
+	WarnDeprecated bool `json:"warnDeprecated"`
+	// MaxTemplateBytes skips template files larger than this many bytes, such
+	// as huge generated HTML that is not a real template. Skipped files are
+	// reported as non-fatal errors and contribute no named blocks
+	// (default: 0, unlimited).
+	MaxTemplateBytes int `json:"maxTemplateBytes"`
+	// MaxErrors caps the number of validation results. Validation stops
+	// early once the cap is reached and a final result says how many more
+	// were suppressed (default: 0, unlimited).
+	MaxErrors int `json:"maxErrors"`
+	// MergeContexts warns about variables that only some of the render calls
+	// targeting a template provide. Each template is always validated once
+	// against the union of its callers' variables; this additionally checks
+	// it against the variables every caller provides (default: false).
+	MergeContexts bool `json:"mergeContexts"`
+	// BuildFlags are passed to the go command when loading packages, e.g.
+	// []string{"-tags=prod"}. Build tags change which files are parsed and
+	// therefore which render calls are found (default: none).
+	BuildFlags []string `json:"buildFlags"`
+	// Logger receives per-phase timings and counts (package load, scope
+	// collection, render call generation). Nil discards them (default).
+	// Not serialized.
+	Logger *slog.Logger `json:"-"`
+	// AllowBlockOverride validates {{template}} and {{block}} calls against a
+	// {{define}} that overrides a {{block}} of the same name instead of the
+	// block's default body, following html/template inheritance (default: false).
+	AllowBlockOverride bool `json:"allowBlockOverride"`
+	// IgnoreDir reports whether AnalyzeDir should leave a directory, given as
+	// an absolute path, out of the package load set, e.g. because .gitignore
+	// excludes it. go/packages loads whole packages, so individually ignored
+	// Go files are still analyzed. Nil loads every directory (default).
+	// Not serialized.
+	IgnoreDir func(path string) bool `json:"-"`
+	// TemplateRootResolver returns the template base directory and root that
+	// a render call in goFile resolves its template name against, for
+	// repositories where each service keeps its templates beside its
+	// handlers. See PerPackageTemplateRoot. Nil resolves every render call
+	// against the -template-base-dir and -template-root values (default).
+	// Not serialized.
+	TemplateRootResolver func(goFile string) (baseDir, templateRoot string) `json:"-"`
+	// ValidateBlockBodies validates each {{define}} and {{block}} body on its
+	// own against the union of the contexts its {{template}} call sites pass,
+	// so that blocks reached only with an untracked context, or not at all,
+	// get checked too. Blocks without a resolvable call site are checked
+	// against the variables every render call provides, with warnings instead
+	// of errors (default: false).
+	ValidateBlockBodies bool `json:"validateBlockBodies"`
+	// IncludeTests loads _test.go files too, for render calls made from
+	// integration tests. Calls found in both a package and its test variant
+	// are reported once (default: false).
+	IncludeTests bool `json:"includeTests"`
+	// IncludeTestdata analyzes Go packages under testdata directories, which
+	// AnalyzeDir skips like the go command does (default: false).
+	IncludeTestdata bool `json:"includeTestdata"`
+	// StrictParse also parses each template file with text/template, whose
+	// parser rejects malformed actions the validator's scanner tolerates,
+	// such as {{if}} without a condition, and reports the first parse error
+	// as a syntax result (default: false).
+	StrictParse bool `json:"strictParse"`
+	// WarnEmptyTemplates warns when a render call passes variables to a
+	// template file without a single action, which usually means the name
+	// resolved to the wrong file, such as a minified asset (default: false).
+	WarnEmptyTemplates bool `json:"warnEmptyTemplates"`
+	// ComponentProps is the marker of a props annotation declaring the
+	// variables a {{define}} or {{block}} component requires, e.g. "props"
+	// for {{/* props: Title string, Count int */}} as the first action of
+	// the body. {{template}} calls of an annotated component must provide
+	// every declared prop. Empty disables the annotation (default: "").
+	ComponentProps string `json:"componentProps,omitempty"`
+	// MaxResolvedNames caps the template names a variable passed as the
+	// template argument can resolve to, such as one assigned in every case
+	// of a switch or in a loop. A render call whose variable has more
+	// possible values is validated against the first MaxResolvedNames only
+	// and reported as a non-fatal error. Non-positive uses
+	// MaxAssignmentsPerVar (default: 10).
+	MaxResolvedNames int `json:"maxResolvedNames"`
+	// WarnShadowing warns when a reference inside a range or with block,
+	// such as .Users in {{range .Users}}, resolves to a field of the element
+	// that has the same name as a root variable, and suggests $.Users if the
+	// root was intended (default: false).
+	WarnShadowing bool `json:"warnShadowing"`
+	// WarnStructOutput warns when an output action such as {{.}} or
+	// {{.User}} prints a struct without a String method as a whole, which
+	// renders a Go-syntax dump of its fields (default: false).
+	WarnStructOutput bool `json:"warnStructOutput"`
+	// CustomRules are the reference validation rules to run on every
+	// template, each written as "rule:argument" with an optional
+	// ":severity", e.g. "require-variable:CSRFToken" or
+	// "forbid-field-path:User.Password:warning" (see validator.ParseRules).
+	CustomRules []string `json:"customRules,omitempty"`
+
+	// contextInterface is the interface ContextInterfaceName names, looked
+	// up in the loaded packages at the start of an analysis.
+	contextInterface *types.Interface
 }
 
 // DefaultConfig provides the default configuration for the go template LSP,
@@ -161,6 +362,8 @@ var DefaultConfig = AnalysisConfig{
 	SetFunctionName:             "Set",
 	ContextTypeName:             "Context",
 	GlobalTemplateName:          "global",
+	RawHTMLFuncs:                []string{"safeHTML", "noescape"},
+	MaxResolvedNames:            MaxAssignmentsPerVar,
 }
 
 // FuncScope encapsulates all template-related operations within a single
@@ -168,6 +371,7 @@ var DefaultConfig = AnalysisConfig{
 type FuncScope struct {
 	SetVars        []TemplateVar                  // Template variables set via context.Set()
 	RenderNodes    []ResolvedRender               // Template render calls found
+	Unresolved     []ResolvedRender               // Render calls whose template name could not be resolved
 	FuncMaps       []FuncMapInfo                  // Function map definitions
 	MapAssignments map[string]*goast.CompositeLit // Map variable name → composite literal
 }
@@ -178,6 +382,9 @@ type ResolvedRender struct {
 	Node           *goast.CallExpr // The actual call expression
 	TemplateNames  []string        // Resolved template name(s)
 	TemplateArgIdx int             // Index of template name argument
+	ResolvedVia    string          // How TemplateNames were resolved (ResolvedVia* constant)
+	Composed       bool            // TemplateNames form one template set executed by its last name, not alternatives
+	CappedVar      string          // Variable whose values were cut to MaxResolvedNames, if any
 }
 
 // funcWorkUnit wraps an AST node for concurrent processing.
@@ -0,0 +1,66 @@
+package ast
+
+import "testing"
+
+// TestIncludeUnexportedFields verifies that unexported struct fields are only
+// extracted when IncludeUnexported is set, and that fields are tagged with
+// their exported status.
+func TestIncludeUnexportedFields(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	src := `package main
+
+type Context struct{}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+type Account struct {
+	Name    string
+	balance int
+}
+
+func handler(c *Context) {
+	c.Render("account.html", map[string]interface{}{
+		"account": Account{},
+	})
+}
+`
+	writeTestModule(t, tmpDir, src)
+
+	accountFields := func(config AnalysisConfig) map[string]FieldInfo {
+		result := AnalyzeDir(tmpDir, "", config)
+		if len(result.Errors) > 0 {
+			t.Fatalf("analysis errors: %v", result.Errors)
+		}
+		if len(result.RenderCalls) != 1 {
+			t.Fatalf("expected 1 render call, got %d", len(result.RenderCalls))
+		}
+		fields := make(map[string]FieldInfo)
+		for _, v := range result.RenderCalls[0].Vars {
+			if v.Name == "account" {
+				for _, f := range v.Fields {
+					fields[f.Name] = f
+				}
+			}
+		}
+		return fields
+	}
+
+	fields := accountFields(DefaultConfig)
+	if _, ok := fields["balance"]; ok {
+		t.Error("unexported field balance should be skipped by default")
+	}
+	if fields["Name"].Unexported {
+		t.Error("expected Name not to be tagged unexported")
+	}
+
+	config := DefaultConfig
+	config.IncludeUnexported = true
+	fields = accountFields(config)
+	balance, ok := fields["balance"]
+	if !ok {
+		t.Fatal("expected unexported field balance with IncludeUnexported")
+	}
+	if !balance.Unexported {
+		t.Error("expected balance to be tagged unexported")
+	}
+}
@@ -2,7 +2,9 @@ package ast
 
 import (
 	goast "go/ast"
+	"go/printer"
 	"go/token"
+	"strings"
 )
 
 // extractStringFast efficiently extracts string value from a BasicLit.
@@ -21,3 +23,12 @@ func extractStringFast(expr goast.Expr) string {
 	// Slice to remove surrounding quotes
 	return lit.Value[1 : len(lit.Value)-1]
 }
+
+// exprSource reconstructs the Go source text of expr.
+func exprSource(fset *token.FileSet, expr goast.Expr) string {
+	var sb strings.Builder
+	if err := printer.Fprint(&sb, fset, expr); err != nil {
+		return ""
+	}
+	return sb.String()
+}
@@ -0,0 +1,72 @@
+package ast
+
+import "testing"
+
+// TestVariadicSignatures verifies that FuncMap functions and context methods
+// record whether their last parameter is variadic.
+func TestVariadicSignatures(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	src := `package main
+
+import (
+	"fmt"
+	"html/template"
+)
+
+type Context struct{}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+type User struct{}
+func (u User) InAny(groups ...string) bool { return false }
+func (u User) HasAll(groups []string) bool { return false }
+
+func join(sep string, parts ...string) string { return "" }
+func upper(s string) string { return s }
+
+var funcs = template.FuncMap{
+	"join":   join,
+	"upper":  upper,
+	"printf": fmt.Sprintf,
+}
+
+func handler(c *Context) {
+	c.Render("home.html", map[string]interface{}{"User": User{}})
+}
+`
+	writeTestModule(t, tmpDir, src)
+
+	result := AnalyzeDir(tmpDir, "", DefaultConfig)
+	if len(result.Errors) > 0 {
+		t.Fatalf("analysis errors: %v", result.Errors)
+	}
+
+	want := map[string]bool{"join": true, "upper": false, "printf": true}
+	for _, fm := range result.FuncMaps {
+		if v, ok := want[fm.Name]; ok {
+			if fm.Variadic != v {
+				t.Errorf("func %s: expected Variadic=%v, got %v", fm.Name, v, fm.Variadic)
+			}
+			delete(want, fm.Name)
+		}
+	}
+	if len(want) > 0 {
+		t.Errorf("functions not found: %v", want)
+	}
+
+	if len(result.RenderCalls) != 1 || len(result.RenderCalls[0].Vars) != 1 {
+		t.Fatalf("expected one render call with one variable, got %+v", result.RenderCalls)
+	}
+	methods := map[string]bool{"InAny": true, "HasAll": false}
+	for _, f := range result.RenderCalls[0].Vars[0].Fields {
+		if v, ok := methods[f.Name]; ok {
+			if f.Variadic == nil || *f.Variadic != v {
+				t.Errorf("method %s: expected Variadic=%v, got %v", f.Name, v, f.Variadic)
+			}
+			delete(methods, f.Name)
+		}
+	}
+	if len(methods) > 0 {
+		t.Errorf("methods not found: %v", methods)
+	}
+}
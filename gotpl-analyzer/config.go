@@ -0,0 +1,359 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/internal/textconf"
+)
+
+// configFileNames are the settings files loadConfig looks for, in order of
+// preference within a directory.
+var configFileNames = []string{".rexvalidate.json", ".rexvalidate.yaml", ".rexvalidate.yml"}
+
+// configEnvPrefix prefixes the environment variables loadConfig reads. The
+// rest of the name is the setting's key in upper snake case, so
+// templateRoot is REX_TEMPLATE_ROOT and rawHTMLFuncs is REX_RAW_HTML_FUNCS.
+const configEnvPrefix = "REX_"
+
+// cliOptions are the command-line settings that are not part of
+// ast.AnalysisConfig. Their JSON keys double as settings file keys.
+type cliOptions struct {
+	// TemplateBaseDir is the directory TemplateRoot is relative to.
+	TemplateBaseDir string `json:"templateBaseDir"`
+
+	// TemplateRoot is the template root directory, relative to TemplateBaseDir.
+	TemplateRoot string `json:"templateRoot"`
+
+	// ExtraTemplateRoots are further template roots, relative to
+	// TemplateBaseDir, validated alongside TemplateRoot (see
+	// validator.ValidateOptions.ExtraTemplateRoots).
+	ExtraTemplateRoots []string `json:"extraTemplateRoots,omitempty"`
+
+	// TemplateRootMode is "single" or "per-package".
+	TemplateRootMode string `json:"templateRootMode"`
+
+	// ContextFile is the context file path, if any.
+	ContextFile string `json:"contextFile,omitempty"`
+
+	// Packages are the package patterns to analyze instead of the whole tree.
+	Packages []string `json:"packages,omitempty"`
+
+	// RespectGitignore skips files and directories excluded by .gitignore.
+	RespectGitignore bool `json:"respectGitignore"`
+
+	// TemplateOnly skips Go analysis and validates against ContextFile alone.
+	TemplateOnly bool `json:"templateOnly"`
+
+	// WarningsAsErrors reports every warning as an error.
+	WarningsAsErrors bool `json:"warningsAsErrors"`
+
+	// FailOn is the least severity of a validation result that makes the
+	// run exit with status 1: "error", "warning" or "none".
+	FailOn string `json:"failOn"`
+
+	// ConfigFile is the settings file loadConfig read, if any. It is set by
+	// loadConfig, not read from the file.
+	ConfigFile string `json:"configFile,omitempty"`
+}
+
+// loadConfig resolves the settings for analyzing dir from, in increasing
+// order of precedence, the defaults, the nearest settings file and the
+// environment. Command-line flags are applied on top by the caller.
+//
+// The settings file is the first of configFileNames found in dir or one of
+// its parents. Its keys are the JSON names of the ast.AnalysisConfig and
+// cliOptions fields, as printed by -print-config, and unknown keys are
+// errors. Relative templateBaseDir and contextFile paths are resolved against
+// the file's directory, which is also the base directory when the file sets
+// templateRoot alone. Environment variables are named after the same keys
+// (see configEnvPrefix); lists are comma-separated there.
+func loadConfig(dir string) (ast.AnalysisConfig, cliOptions, error) {
+	config := ast.DefaultConfig
+	config.RawHTMLFuncs = slices.Clone(config.RawHTMLFuncs)
+	opts := cliOptions{TemplateRootMode: "single", FailOn: "error"}
+	fields := configFields(&config, &opts)
+
+	if path := findConfigFile(dir); path != "" {
+		if err := loadConfigFile(path, &config, &opts, fields); err != nil {
+			return config, opts, fmt.Errorf("config file %s: %v", path, err)
+		}
+		base := filepath.Dir(path)
+		if opts.TemplateBaseDir == "" && opts.TemplateRoot != "" {
+			opts.TemplateBaseDir = base
+		}
+		for _, p := range []*string{&opts.TemplateBaseDir, &opts.ContextFile} {
+			if *p != "" && !filepath.IsAbs(*p) {
+				*p = filepath.Join(base, *p)
+			}
+		}
+		opts.ConfigFile = path
+	}
+
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		name := configEnvPrefix + envName(key)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		if err := setConfigField(fields[key], value); err != nil {
+			return config, opts, fmt.Errorf("%s: %v", name, err)
+		}
+	}
+
+	return config, opts, nil
+}
+
+// findConfigFile returns the first settings file found walking up from dir,
+// or "".
+func findConfigFile(dir string) string {
+	for {
+		for _, name := range configFileNames {
+			path := filepath.Join(dir, name)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				return path
+			}
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// loadConfigFile decodes the settings file at path into config and opts.
+func loadConfigFile(path string, config *ast.AnalysisConfig, opts *cliOptions, fields map[string]reflect.Value) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	if filepath.Ext(path) == ".json" {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		return dec.Decode(&struct {
+			*ast.AnalysisConfig
+			*cliOptions
+		}{config, opts})
+	}
+
+	values, err := decodeFlatYAML(data)
+	if err != nil {
+		return err
+	}
+	for key, value := range values {
+		field, ok := fields[key]
+		if !ok {
+			return fmt.Errorf("unknown key %q", key)
+		}
+		if err := setConfigField(field, value); err != nil {
+			return fmt.Errorf("%s: %v", key, err)
+		}
+	}
+	return nil
+}
+
+// configFields maps the JSON key of every serializable field of config and
+// opts to the field.
+func configFields(config *ast.AnalysisConfig, opts *cliOptions) map[string]reflect.Value {
+	fields := make(map[string]reflect.Value)
+	for _, v := range []reflect.Value{reflect.ValueOf(config).Elem(), reflect.ValueOf(opts).Elem()} {
+		for i := range v.NumField() {
+			key, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("json"), ",")
+			if key == "" || key == "-" || key == "configFile" {
+				continue
+			}
+			fields[key] = v.Field(i)
+		}
+	}
+	return fields
+}
+
+// decodeFlatYAML decodes a YAML mapping of scalars and lists of scalars, the
+// subset used by settings files:
+//
+//	# comment
+//	templateRoot: templates
+//	strictMapKeys: true
+//	rawHTMLFuncs: [safeHTML, "noescape"]
+//	buildFlags:
+//	  - -tags=prod
+//
+// Each value is a string or a []string; scalars are returned unquoted and
+// untyped, leaving their interpretation to setConfigField.
+func decodeFlatYAML(data []byte) (map[string]any, error) {
+	values := make(map[string]any)
+	listKey := ""
+
+	lines, err := textconf.YAMLLines(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range lines {
+		if l.Indent > 0 {
+			item, ok := strings.CutPrefix(l.Text, "-")
+			if !ok || listKey == "" || (item != "" && item[0] != ' ') {
+				return nil, fmt.Errorf("line %d: unexpected indentation", l.Num)
+			}
+			value, err := textconf.Unquote(strings.TrimSpace(item))
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", l.Num, err)
+			}
+			values[listKey] = append(values[listKey].([]string), value)
+			continue
+		}
+
+		key, rest, ok := strings.Cut(l.Text, ":")
+		if key = strings.TrimSpace(key); !ok || key == "" {
+			return nil, fmt.Errorf("line %d: expected \"key: value\", got %q", l.Num, l.Text)
+		}
+		if _, dup := values[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", l.Num, key)
+		}
+		rest = strings.TrimSpace(rest)
+		listKey = ""
+
+		switch {
+		case rest == "":
+			// A block list follows, or the value is empty.
+			values[key] = []string{}
+			listKey = key
+		case strings.HasPrefix(rest, "["):
+			if !strings.HasSuffix(rest, "]") {
+				return nil, fmt.Errorf("line %d: unterminated list", l.Num)
+			}
+			list := []string{}
+			for item := range strings.SplitSeq(rest[1:len(rest)-1], ",") {
+				if item = strings.TrimSpace(item); item == "" {
+					continue
+				}
+				value, err := textconf.Unquote(item)
+				if err != nil {
+					return nil, fmt.Errorf("line %d: %v", l.Num, err)
+				}
+				list = append(list, value)
+			}
+			values[key] = list
+		default:
+			value, err := textconf.Unquote(rest)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", l.Num, err)
+			}
+			values[key] = value
+		}
+	}
+
+	return values, nil
+}
+
+// setConfigField stores value, a string or a []string, in field. A string
+// given for a list or a map is split on commas; map entries are written as
+// name=offset (see parseArgOffset).
+func setConfigField(field reflect.Value, value any) error {
+	s, isString := value.(string)
+	list, _ := value.([]string)
+	if !isString {
+		// "key:" with nothing after it in YAML.
+		if len(list) > 0 && field.Kind() != reflect.Slice && field.Kind() != reflect.Map {
+			return fmt.Errorf("expected a single value, got a list")
+		}
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", s)
+		}
+		field.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", s)
+		}
+		field.SetInt(int64(n))
+	case reflect.Slice:
+		if isString {
+			list = nil
+			for item := range strings.SplitSeq(s, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					list = append(list, item)
+				}
+			}
+		}
+		field.Set(reflect.ValueOf(slices.Clone(list)))
+	case reflect.Map:
+		if field.Type() != reflect.TypeFor[map[string]int]() {
+			return fmt.Errorf("unsupported setting type %s", field.Type())
+		}
+		if isString {
+			list = strings.Split(s, ",")
+		}
+		offsets := make(map[string]int, len(list))
+		for _, entry := range list {
+			if entry = strings.TrimSpace(entry); entry == "" {
+				continue
+			}
+			name, offset, err := parseArgOffset(entry)
+			if err != nil {
+				return err
+			}
+			offsets[name] = offset
+		}
+		field.Set(reflect.ValueOf(offsets))
+	default:
+		return fmt.Errorf("unsupported setting type %s", field.Type())
+	}
+	return nil
+}
+
+// parseArgOffset parses a "name=offset" entry of
+// ast.AnalysisConfig.DataArgOffsets, such as "Render=2".
+func parseArgOffset(entry string) (string, int, error) {
+	name, value, ok := strings.Cut(entry, "=")
+	name = strings.TrimSpace(name)
+	offset, err := strconv.Atoi(strings.TrimSpace(value))
+	if !ok || name == "" || err != nil || offset <= 0 {
+		return "", 0, fmt.Errorf("invalid data argument offset %q, expected name=offset", entry)
+	}
+	return name, offset, nil
+}
+
+// envName converts a camelCase key to upper snake case, keeping acronyms
+// together: rawHTMLFuncs becomes RAW_HTML_FUNCS.
+func envName(key string) string {
+	var b strings.Builder
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		if i > 0 && isUpper(c) {
+			prev := key[i-1]
+			nextLower := i+1 < len(key) && !isUpper(key[i+1])
+			if !isUpper(prev) || nextLower {
+				b.WriteByte('_')
+			}
+		}
+		b.WriteString(strings.ToUpper(string(c)))
+	}
+	return b.String()
+}
+
+// isUpper reports whether c is an ASCII upper-case letter.
+func isUpper(c byte) bool {
+	return c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,174 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeTree creates files, keyed by slash-separated paths relative to dir.
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestLoadConfigYAML(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		".rexvalidate.yaml": `# team settings
+templateRoot: views
+extraTemplateRoots: [emails]
+contextFile: "config/gotpl.json"
+strictMapKeys: true
+maxTemplateBytes: 1024
+rawHTMLFuncs: [safeHTML, "raw"]
+customRules:
+  - require-variable:CSRFToken
+dataArgOffsets: [Render=2, HTML=3]
+buildFlags:
+  - -tags=prod
+`,
+		"cmd/server/main.go": "package main\n",
+	})
+
+	config, opts, err := loadConfig(filepath.Join(dir, "cmd", "server"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.ConfigFile != filepath.Join(dir, ".rexvalidate.yaml") {
+		t.Errorf("expected the file in the parent directory to be found, got %q", opts.ConfigFile)
+	}
+	if !config.StrictMapKeys || config.MaxTemplateBytes != 1024 {
+		t.Errorf("expected scalar settings to be applied, got %+v", config)
+	}
+	if !reflect.DeepEqual(config.RawHTMLFuncs, []string{"safeHTML", "raw"}) || !reflect.DeepEqual(config.BuildFlags, []string{"-tags=prod"}) || !reflect.DeepEqual(config.CustomRules, []string{"require-variable:CSRFToken"}) {
+		t.Errorf("expected list settings to be applied, got %v, %v and %v", config.RawHTMLFuncs, config.BuildFlags, config.CustomRules)
+	}
+	if !reflect.DeepEqual(config.DataArgOffsets, map[string]int{"Render": 2, "HTML": 3}) {
+		t.Errorf("expected data argument offsets per render function, got %v", config.DataArgOffsets)
+	}
+	if config.RenderFunctionName != "Render" || opts.TemplateRootMode != "single" {
+		t.Errorf("expected unset settings to keep their defaults, got %q and %q", config.RenderFunctionName, opts.TemplateRootMode)
+	}
+	// Relative paths are relative to the config file, which also becomes
+	// the base of a templateRoot given without templateBaseDir.
+	if opts.TemplateBaseDir != dir || opts.TemplateRoot != "views" || !reflect.DeepEqual(opts.ExtraTemplateRoots, []string{"emails"}) {
+		t.Errorf("expected templates under %s/views and emails, got %q/%q and %v", dir, opts.TemplateBaseDir, opts.TemplateRoot, opts.ExtraTemplateRoots)
+	}
+	if opts.ContextFile != filepath.Join(dir, "config", "gotpl.json") {
+		t.Errorf("expected the context file to be resolved, got %q", opts.ContextFile)
+	}
+}
+
+func TestLoadConfigEnvOverridesFile(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		".rexvalidate.json": `{"renderFunctionName": "HTML", "mergeContexts": true, "templateRoot": "views", "packages": ["./web/..."], "dataArgOffsets": {"HTML": 3}}`,
+	})
+	t.Setenv("REX_DATA_ARG_OFFSETS", "HTML=2")
+	t.Setenv("REX_MERGE_CONTEXTS", "false")
+	t.Setenv("REX_RAW_HTML_FUNCS", "safeHTML, unsafe")
+	t.Setenv("REX_TEMPLATE_ROOT_MODE", "per-package")
+
+	config, opts, err := loadConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.RenderFunctionName != "HTML" || config.MergeContexts {
+		t.Errorf("expected the file's renderFunctionName and the environment's mergeContexts, got %+v", config)
+	}
+	if !reflect.DeepEqual(config.RawHTMLFuncs, []string{"safeHTML", "unsafe"}) {
+		t.Errorf("expected a comma-separated list from the environment, got %v", config.RawHTMLFuncs)
+	}
+	if !reflect.DeepEqual(config.DataArgOffsets, map[string]int{"HTML": 2}) {
+		t.Errorf("expected the environment's data argument offsets, got %v", config.DataArgOffsets)
+	}
+	if opts.TemplateRootMode != "per-package" || !reflect.DeepEqual(opts.Packages, []string{"./web/..."}) {
+		t.Errorf("unexpected CLI options %+v", opts)
+	}
+}
+
+func TestLoadConfigWithoutFile(t *testing.T) {
+	config, opts, err := loadConfig(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if opts.ConfigFile != "" || config.GlobalTemplateName != "global" || len(config.RawHTMLFuncs) != 2 {
+		t.Errorf("expected the defaults, got %+v and %+v", config, opts)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		file, content, env, msg string
+	}{
+		{file: ".rexvalidate.json", content: `{"templateRot": "views"}`, msg: `unknown field "templateRot"`},
+		{file: ".rexvalidate.yml", content: "templateRot: views\n", msg: `unknown key "templateRot"`},
+		{file: ".rexvalidate.yml", content: "strictMapKeys: sometimes\n", msg: `strictMapKeys: invalid boolean "sometimes"`},
+		{file: ".rexvalidate.yml", content: "templateRoot:\n  - a\n", msg: "expected a single value"},
+		{file: ".rexvalidate.yml", content: "dataArgOffsets: [Render]\n", msg: `dataArgOffsets: invalid data argument offset "Render"`},
+		{env: "lots", msg: `REX_MAX_TEMPLATE_BYTES: invalid integer "lots"`},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		if tt.file != "" {
+			writeTree(t, dir, map[string]string{tt.file: tt.content})
+		}
+		if tt.env != "" {
+			t.Setenv("REX_MAX_TEMPLATE_BYTES", tt.env)
+		}
+		_, _, err := loadConfig(dir)
+		if err == nil || !strings.Contains(err.Error(), tt.msg) {
+			t.Errorf("%s %q: expected error containing %q, got %v", tt.file, tt.content, tt.msg, err)
+		}
+	}
+}
+
+func TestDecodeFlatYAML(t *testing.T) {
+	data := `# settings
+templateRoot: "views"   # quoted
+strictMapKeys: true
+empty:
+rawHTMLFuncs: [safeHTML, 'raw']
+buildFlags:
+  - -tags=prod
+  - "-race"
+`
+	got, err := decodeFlatYAML([]byte(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"templateRoot":  "views",
+		"strictMapKeys": "true",
+		"empty":         []string{},
+		"rawHTMLFuncs":  []string{"safeHTML", "raw"},
+		"buildFlags":    []string{"-tags=prod", "-race"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	for data, msg := range map[string]string{
+		"  - orphan\n":          "line 1: unexpected indentation",
+		"a: 1\na: 2\n":          `line 2: duplicate key "a"`,
+		"a: [x, y\n":            "line 1: unterminated list",
+		"a:\n  nested: value\n": "line 2: unexpected indentation",
+		"a: 'x\n":               "line 1: malformed quoted string 'x",
+		"just text\n":           `line 1: expected "key: value"`,
+	} {
+		if _, err := decodeFlatYAML([]byte(data)); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("%q: expected error containing %q, got %v", data, msg, err)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -35,11 +36,36 @@ type rpcError struct {
 }
 
 type daemonAnalyzeParams struct {
-	Dir             string `json:"dir"`
-	TemplateRoot    string `json:"templateRoot"`
-	TemplateBaseDir string `json:"templateBaseDir"`
-	ContextFile     string `json:"contextFile"`
-	Validate        bool   `json:"validate"`
+	Dir                         string         `json:"dir"`
+	TemplateRoot                string         `json:"templateRoot"`
+	TemplateBaseDir             string         `json:"templateBaseDir"`
+	ContextFile                 string         `json:"contextFile"`
+	Validate                    bool           `json:"validate"`
+	StrictMapKeys               bool           `json:"strictMapKeys"`
+	SecurityLints               bool           `json:"securityLints"`
+	IncludeUnexported           bool           `json:"includeUnexported"`
+	IncludeTests                bool           `json:"includeTests"`
+	IncludeTestdata             bool           `json:"includeTestdata"`
+	DefineIsFileEntry           bool           `json:"defineIsFileEntry"`
+	DynamicTemplateNameSeverity string         `json:"dynamicTemplateNameSeverity"`
+	WarnDeprecated              bool           `json:"warnDeprecated"`
+	MaxTemplateBytes            int            `json:"maxTemplateBytes"`
+	MaxErrors                   int            `json:"maxErrors"`
+	MergeContexts               bool           `json:"mergeContexts"`
+	Tags                        string         `json:"tags"`
+	TemplateOnly                bool           `json:"templateOnly"`
+	AllowBlockOverride          bool           `json:"allowBlockOverride"`
+	ValidateBlockBodies         bool           `json:"validateBlockBodies"`
+	StrictParse                 bool           `json:"strictParse"`
+	WarnEmptyTemplates          bool           `json:"warnEmptyTemplates"`
+	WarnShadowing               bool           `json:"warnShadowing"`
+	WarnStructOutput            bool           `json:"warnStructOutput"`
+	ComponentProps              string         `json:"componentProps"`
+	MaxResolvedNames            int            `json:"maxResolvedNames"`
+	DataArgOffsets              map[string]int `json:"dataArgOffsets"`
+	RespectGitignore            bool           `json:"respectGitignore"`
+	WarningsAsErrors            bool           `json:"warningsAsErrors"`
+	CustomRules                 []string       `json:"customRules"`
 }
 
 type daemonValidateTemplateParams struct {
@@ -70,6 +96,12 @@ type daemonGetHoverInfoParams struct {
 	Content      string `json:"content"`
 }
 
+type daemonGetCompletionsParams struct {
+	AbsolutePath string `json:"absolutePath"`
+	Offset       int    `json:"offset"` // byte offset of the cursor in content
+	Content      string `json:"content"`
+}
+
 type daemonValidateTemplateResult struct {
 	ValidationErrors []validator.ValidationResult `json:"validationErrors"`
 	HasContext       bool                         `json:"hasContext"`
@@ -77,8 +109,9 @@ type daemonValidateTemplateResult struct {
 
 // daemonState is the immutable snapshot of analysis results shared by all
 // concurrent read-only operations (validateTemplate, inferExpressionType,
-// getHoverInfo).  The pointer is replaced atomically on each analyze call so
-// readers always see a consistent snapshot without acquiring a write lock.
+// getHoverInfo, getCompletions).  The pointer is replaced atomically on each
+// analyze call so readers always see a consistent snapshot without acquiring a
+// write lock.
 //
 // OPTIMISATION: Previously every read-only handler performed deep clones of
 // renderVarsByTemplate, funcMaps, typeRegistry, namedBlocks, and
@@ -94,7 +127,27 @@ type daemonState struct {
 	validate     bool
 	output       ValidationOutput
 
+	// defineIsFileEntry and dynamicTemplateNameSeverity mirror the matching
+	// ast.AnalysisConfig fields for live validation.
+	defineIsFileEntry           bool
+	dynamicTemplateNameSeverity string
+	// warningsAsErrors mirrors daemonAnalyzeParams.WarningsAsErrors.
+	warningsAsErrors bool
+	// strictParse mirrors daemonAnalyzeParams.StrictParse.
+	strictParse bool
+	// warnShadowing mirrors daemonAnalyzeParams.WarnShadowing.
+	warnShadowing bool
+	// securityLints mirrors daemonAnalyzeParams.SecurityLints.
+	securityLints bool
+	// warnStructOutput mirrors daemonAnalyzeParams.WarnStructOutput.
+	warnStructOutput bool
+	// allowBlockOverride and componentProps mirror the matching
+	// daemonAnalyzeParams fields, to mark the entries of unsaved templates.
+	allowBlockOverride bool
+	componentProps     string
+
 	renderVarsByTemplate map[string][]ast.TemplateVar
+	nilDataTemplates     map[string]bool
 	funcMaps             validator.FuncMapRegistry
 	typeRegistry         map[string][]ast.FieldInfo
 	namedBlocks          map[string][]validator.NamedBlockEntry
@@ -258,6 +311,20 @@ func (d *analyzerDaemon) handle(req rpcRequest) rpcResponse {
 		resp.Result = result
 		return resp
 
+	case "getCompletions":
+		var params daemonGetCompletionsParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			resp.Error = &rpcError{Code: -32602, Message: fmt.Sprintf("invalid getCompletions params: %v", err)}
+			return resp
+		}
+		result, err := d.getCompletions(params)
+		if err != nil {
+			resp.Error = &rpcError{Code: -32000, Message: err.Error()}
+			return resp
+		}
+		resp.Result = result
+		return resp
+
 	case "shutdown":
 		resp.Result = map[string]bool{"ok": true}
 		return resp
@@ -269,23 +336,95 @@ func (d *analyzerDaemon) handle(req rpcRequest) rpcResponse {
 }
 
 func (d *analyzerDaemon) analyze(params daemonAnalyzeParams) (ValidationOutput, error) {
+	if !validator.ValidDynamicTemplateNameSeverity(params.DynamicTemplateNameSeverity) {
+		return ValidationOutput{}, fmt.Errorf("invalid dynamicTemplateNameSeverity %q: want info, warning or error", params.DynamicTemplateNameSeverity)
+	}
+	rules, err := validator.ParseRules(params.CustomRules)
+	if err != nil {
+		return ValidationOutput{}, fmt.Errorf("invalid customRules: %v", err)
+	}
+
 	baseDir := params.Dir
 	if params.TemplateBaseDir != "" {
 		baseDir = params.TemplateBaseDir
 	}
 
-	result := ast.AnalyzeDir(params.Dir, params.ContextFile, ast.DefaultConfig)
+	config := ast.DefaultConfig
+	config.StrictMapKeys = params.StrictMapKeys
+	config.SecurityLints = params.SecurityLints
+	config.IncludeUnexported = params.IncludeUnexported
+	config.IncludeTests = params.IncludeTests
+	config.IncludeTestdata = params.IncludeTestdata
+	config.DefineIsFileEntry = params.DefineIsFileEntry
+	config.DynamicTemplateNameSeverity = params.DynamicTemplateNameSeverity
+	config.WarnDeprecated = params.WarnDeprecated
+	config.MaxTemplateBytes = params.MaxTemplateBytes
+	config.MaxErrors = params.MaxErrors
+	config.MaxResolvedNames = params.MaxResolvedNames
+	config.DataArgOffsets = params.DataArgOffsets
+	config.MergeContexts = params.MergeContexts
+	config.AllowBlockOverride = params.AllowBlockOverride
+	config.ValidateBlockBodies = params.ValidateBlockBodies
+	config.StrictParse = params.StrictParse
+	config.WarnEmptyTemplates = params.WarnEmptyTemplates
+	config.WarnShadowing = params.WarnShadowing
+	config.WarnStructOutput = params.WarnStructOutput
+	config.ComponentProps = params.ComponentProps
+	config.CustomRules = params.CustomRules
+	if params.Tags != "" {
+		config.BuildFlags = []string{"-tags=" + params.Tags}
+	}
+	var ignore *validator.GitignoreMatcher
+	if params.RespectGitignore {
+		m, err := validator.NewGitignoreMatcher(params.Dir)
+		if err != nil {
+			return ValidationOutput{}, err
+		}
+		ignore = m
+		config.IgnoreDir = func(path string) bool { return m.Match(path, true) }
+	}
+
+	var result ast.AnalysisResult
+	if params.TemplateOnly {
+		if params.ContextFile == "" {
+			return ValidationOutput{}, fmt.Errorf("templateOnly requires contextFile")
+		}
+		result = ast.AnalyzeContextFile(params.ContextFile, config)
+	} else {
+		result = ast.AnalyzeDir(params.Dir, params.ContextFile, config)
+	}
 	result.Errors = filterImportErrors(result.Errors)
 
-	validationErrors, namedBlocks, namedBlockErrors := validator.ValidateTemplates(
+	validationErrors, namedBlocks, namedBlockErrors, skipped := validator.ValidateTemplatesWithOptions(
 		result.RenderCalls,
 		result.FuncMaps,
 		baseDir,
 		params.TemplateRoot,
+		validator.ValidateOptions{
+			MaxTemplateBytes:    config.MaxTemplateBytes,
+			MaxErrors:           config.MaxErrors,
+			WarningsAsErrors:    params.WarningsAsErrors,
+			AllowBlockOverride:  config.AllowBlockOverride,
+			ValidateBlockBodies: config.ValidateBlockBodies,
+			StrictParse:         config.StrictParse,
+			WarnEmptyTemplates:  config.WarnEmptyTemplates,
+			MergeContexts:       config.MergeContexts,
+			DefineIsFileEntry:   config.DefineIsFileEntry,
+			ComponentProps:      config.ComponentProps,
+			WarnShadowing:       config.WarnShadowing,
+			SecurityLints:       config.SecurityLints,
+			WarnStructOutput:    config.WarnStructOutput,
+			Rules:               rules,
+			Ignore:              ignore,
+			WarnMissingContext:  params.TemplateOnly,
+
+			DynamicTemplateNameSeverity: config.DynamicTemplateNameSeverity,
+		},
 	)
+	result.Errors = append(result.Errors, skipped...)
 
 	// Build the render-var index BEFORE Flatten() so field trees are intact.
-	renderVarIndex := buildRenderVarIndex(result.RenderCalls)
+	renderVarIndex := validator.MergeCallContexts(result.RenderCalls)
 
 	result.Flatten()
 
@@ -304,17 +443,27 @@ func (d *analyzerDaemon) analyze(params daemonAnalyzeParams) (ValidationOutput,
 
 	// Build immutable snapshot — no cloning needed by readers.
 	snap := &daemonState{
-		dir:                  params.Dir,
-		baseDir:              baseDir,
-		templateRoot:         params.TemplateRoot,
-		contextFile:          params.ContextFile,
-		validate:             params.Validate,
-		output:               output,
-		renderVarsByTemplate: renderVarIndex,
-		funcMaps:             validator.BuildFuncMapRegistry(result.FuncMaps),
-		typeRegistry:         result.Types,
-		namedBlocks:          namedBlocks,
-		partialTargets:       validator.FindPartialTargets(baseDir, params.TemplateRoot),
+		dir:                         params.Dir,
+		baseDir:                     baseDir,
+		templateRoot:                params.TemplateRoot,
+		contextFile:                 params.ContextFile,
+		validate:                    params.Validate,
+		defineIsFileEntry:           params.DefineIsFileEntry,
+		dynamicTemplateNameSeverity: params.DynamicTemplateNameSeverity,
+		warningsAsErrors:            params.WarningsAsErrors,
+		strictParse:                 params.StrictParse,
+		warnShadowing:               params.WarnShadowing,
+		securityLints:               params.SecurityLints,
+		warnStructOutput:            params.WarnStructOutput,
+		allowBlockOverride:          params.AllowBlockOverride,
+		componentProps:              params.ComponentProps,
+		output:                      output,
+		renderVarsByTemplate:        renderVarIndex,
+		nilDataTemplates:            validator.NilDataTemplates(result.RenderCalls),
+		funcMaps:                    validator.BuildFuncMapRegistry(result.FuncMaps),
+		typeRegistry:                result.Types,
+		namedBlocks:                 namedBlocks,
+		partialTargets:              validator.FindPartialTargets(baseDir, params.TemplateRoot),
 	}
 
 	// Atomic swap: readers instantly see the new state without waiting.
@@ -369,29 +518,59 @@ func (d *analyzerDaemon) validateTemplate(params daemonValidateTemplateParams) (
 
 	overlays[absPath] = params.Content
 
-	// Build a per-request registry copy only when overlays change the shape.
-	// For the common case (no overlays beyond the current file), we reuse the
-	// shared namedBlocks snapshot directly — only clone when we must mutate.
-	registry := snap.namedBlocks
-	if len(overlays) > 0 {
-		registry = cloneRegistry(snap.namedBlocks)
-		applyTemplateOverlays(registry, overlays, snap.baseDir, snap.templateRoot)
-	}
+	registry := snap.overlayRegistry(overlays)
 
 	var errors []validator.ValidationResult
 	hasContext := false
+	opts := validator.ValidateOptions{
+		DynamicTemplateNameSeverity: snap.dynamicTemplateNameSeverity,
+		WarnShadowing:               snap.warnShadowing,
+		SecurityLints:               snap.securityLints,
+	}
 
-	if _, vars, ok := findRenderVarsForTemplate(snap.renderVarsByTemplate, absPath, snap.baseDir, snap.templateRoot); ok {
+	if key, vars, ok := findRenderVarsForTemplate(snap.renderVarsByTemplate, absPath, snap.baseDir, snap.templateRoot); ok {
 		hasContext = true
-		errors = append(errors, validator.ValidateTemplateFileStr(
-			params.Content,
-			vars,
-			rel,
-			snap.baseDir,
-			snap.templateRoot,
-			registry,
-			snap.funcMaps,
-		)...)
+		nilData := snap.nilDataTemplates[key]
+		var body string
+		var line int
+		fileEntry := false
+		if snap.defineIsFileEntry {
+			body, line, fileEntry = validator.FileEntryDefine(params.Content, rel)
+		}
+		if fileEntry {
+			errors = append(errors, validator.ValidateContentWithOptions(
+				body,
+				vars,
+				nilData,
+				rel,
+				snap.baseDir,
+				snap.templateRoot,
+				line,
+				registry,
+				snap.funcMaps,
+				opts,
+			)...)
+		} else {
+			errors = append(errors, validator.ValidateContentWithOptions(
+				params.Content,
+				vars,
+				nilData,
+				rel,
+				snap.baseDir,
+				snap.templateRoot,
+				1,
+				registry,
+				snap.funcMaps,
+				opts,
+			)...)
+		}
+		if snap.warnStructOutput {
+			content, offset := params.Content, 1
+			if fileEntry {
+				content, offset = body, line
+			}
+			errors = append(errors, validator.StructOutputWarnings(content, vars, rel, offset, registry, snap.funcMaps)...)
+		}
 	}
 
 	for _, entry := range registryEntriesForFile(registry, absPath) {
@@ -406,18 +585,29 @@ func (d *analyzerDaemon) validateTemplate(params daemonValidateTemplateParams) (
 			continue
 		}
 		hasContext = true
-		errors = append(errors, validator.ValidateNamedBlockContent(
+		errors = append(errors, validator.ValidateContentWithOptions(
 			entry.Content,
 			vars,
+			snap.nilDataTemplates[entry.Name],
 			entry.TemplatePath,
 			snap.baseDir,
 			snap.templateRoot,
 			entry.Line,
 			registry,
 			snap.funcMaps,
+			opts,
 		)...)
 	}
 
+	if snap.strictParse && !slices.ContainsFunc(errors, func(r validator.ValidationResult) bool {
+		return r.Severity == validator.SeveritySyntax && r.Template == rel
+	}) {
+		errors = append(errors, validator.StrictParseTemplate(params.Content, rel, snap.funcMaps)...)
+	}
+
+	if snap.warningsAsErrors {
+		errors = validator.EscalateWarnings(errors)
+	}
 	return daemonValidateTemplateResult{
 		ValidationErrors: dedupeValidationErrors(errors),
 		HasContext:       hasContext,
@@ -499,11 +689,7 @@ func (d *analyzerDaemon) getHoverInfo(params daemonGetHoverInfoParams) (*validat
 	}
 	rel = filepath.ToSlash(rel)
 
-	registry := snap.namedBlocks
-	if len(overlays) > 0 {
-		registry = cloneRegistry(snap.namedBlocks)
-		applyTemplateOverlays(registry, overlays, snap.baseDir, snap.templateRoot)
-	}
+	registry := snap.overlayRegistry(overlays)
 
 	_, vars, ok := findRenderVarsForTemplate(snap.renderVarsByTemplate, absPath, snap.baseDir, snap.templateRoot)
 	if !ok {
@@ -524,6 +710,34 @@ func (d *analyzerDaemon) getHoverInfo(params daemonGetHoverInfoParams) (*validat
 	return result, nil
 }
 
+func (d *analyzerDaemon) getCompletions(params daemonGetCompletionsParams) ([]validator.CompletionItem, error) {
+	snap := d.state.Load()
+	if snap == nil {
+		return nil, fmt.Errorf("daemon not initialized")
+	}
+
+	absPath, err := filepath.Abs(params.AbsolutePath)
+	if err != nil {
+		return nil, err
+	}
+
+	content := params.Content
+	if content == "" {
+		d.overlayMu.RLock()
+		content = d.templateOverlays[absPath]
+		d.overlayMu.RUnlock()
+	}
+	if content == "" {
+		return nil, fmt.Errorf("no content for %s", absPath)
+	}
+
+	_, vars, ok := findRenderVarsForTemplate(snap.renderVarsByTemplate, absPath, snap.baseDir, snap.templateRoot)
+	if !ok {
+		return nil, nil
+	}
+	return validator.CompletionsAt(content, vars, params.Offset), nil
+}
+
 // ── Helpers ──────────────────────────────────────────────────────────────────
 
 func findRenderVarsForTemplate(
@@ -561,26 +775,6 @@ func findRenderVarsForTemplate(
 	return "", nil, false
 }
 
-func buildRenderVarIndex(renderCalls []ast.RenderCall) map[string][]ast.TemplateVar {
-	idx := make(map[string][]ast.TemplateVar, len(renderCalls))
-	seen := make(map[string]map[string]bool, len(renderCalls))
-
-	for _, rc := range renderCalls {
-		if _, ok := idx[rc.Template]; !ok {
-			idx[rc.Template] = nil
-			seen[rc.Template] = make(map[string]bool)
-		}
-		for _, v := range rc.Vars {
-			if !seen[rc.Template][v.Name] {
-				seen[rc.Template][v.Name] = true
-				idx[rc.Template] = append(idx[rc.Template], v)
-			}
-		}
-	}
-
-	return idx
-}
-
 func cloneRegistry(in map[string][]validator.NamedBlockEntry) map[string][]validator.NamedBlockEntry {
 	out := make(map[string][]validator.NamedBlockEntry, len(in))
 	for key, entries := range in {
@@ -597,6 +791,23 @@ func cloneTemplateOverlays(in map[string]string) map[string]string {
 	return out
 }
 
+// overlayRegistry returns the named blocks of the snapshot with the entries
+// of overlaid files replaced by their unsaved content. Without overlays the
+// shared registry is returned as is; otherwise it is cloned before being
+// changed, and the replaced entries are marked as analyze marked the others.
+func (snap *daemonState) overlayRegistry(overlays map[string]string) map[string][]validator.NamedBlockEntry {
+	if len(overlays) == 0 {
+		return snap.namedBlocks
+	}
+	registry := cloneRegistry(snap.namedBlocks)
+	applyTemplateOverlays(registry, overlays, snap.baseDir, snap.templateRoot)
+	validator.ValidateOptions{
+		AllowBlockOverride: snap.allowBlockOverride,
+		ComponentProps:     snap.componentProps,
+	}.MarkRegistry(registry)
+	return registry
+}
+
 func applyTemplateOverlays(registry map[string][]validator.NamedBlockEntry, overlays map[string]string, baseDir, templateRoot string) {
 	templateBase := filepath.Join(baseDir, templateRoot)
 	for absolutePath, content := range overlays {
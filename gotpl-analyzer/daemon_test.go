@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
 )
 
 func TestFindRenderVarsForTemplateMatchesBySuffix(t *testing.T) {
@@ -49,3 +50,39 @@ func TestFindRenderVarsForTemplateMatchesByAbsolutePath(t *testing.T) {
 		t.Fatalf("expected original key to be preserved, got %q", matchedKey)
 	}
 }
+
+func TestOverlayRegistryMarksEntries(t *testing.T) {
+	baseDir := filepath.Join(string(filepath.Separator), "workspace")
+	snap := &daemonState{
+		baseDir:            baseDir,
+		templateRoot:       "templates",
+		allowBlockOverride: true,
+		componentProps:     "props",
+		namedBlocks: map[string][]validator.NamedBlockEntry{
+			"content": {{Name: "content", IsBlock: true, TemplatePath: "base.html", AbsolutePath: filepath.Join(baseDir, "templates", "base.html")}},
+		},
+	}
+	page := filepath.Join(baseDir, "templates", "page.html")
+	registry := snap.overlayRegistry(map[string]string{
+		page: `{{define "content"}}{{/* props: Title string */}}{{.Title}}{{end}}`,
+	})
+
+	var override *validator.NamedBlockEntry
+	for i, e := range registry["content"] {
+		if e.TemplatePath == "page.html" {
+			override = &registry["content"][i]
+		}
+	}
+	if override == nil {
+		t.Fatalf("expected the overlay's define in the registry, got %#v", registry["content"])
+	}
+	if !override.OverridesBlock {
+		t.Error("expected the overlay's define to override the block")
+	}
+	if len(override.Props) != 1 || override.Props[0].Name != "Title" {
+		t.Errorf("expected the overlay's props, got %#v", override.Props)
+	}
+	if snap.namedBlocks["content"][0].OverridesBlock || len(snap.namedBlocks["content"]) != 1 {
+		t.Error("expected the snapshot registry to be left unchanged")
+	}
+}
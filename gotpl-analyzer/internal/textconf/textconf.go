@@ -0,0 +1,127 @@
+// Package textconf reads the small subsets of YAML and TOML that context
+// files and settings files are written in: comments, bare and quoted
+// scalars, keys, and indented YAML lines. The readers built on it accept
+// only the shape of their own files instead of pulling in full parsers as
+// dependencies.
+package textconf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Line is a line of a YAML document that carries content.
+type Line struct {
+	// Num is the 1-based line number.
+	Num int
+	// Indent is the number of spaces the line is indented by.
+	Indent int
+	// Text is the line without its indentation and trailing comment.
+	Text string
+}
+
+// YAMLLines returns the lines of data that carry content, with comments
+// removed. Blank lines and "---" document markers are skipped; indenting
+// with tabs is an error.
+func YAMLLines(data []byte) ([]Line, error) {
+	var lines []Line
+	for i, raw := range strings.Split(string(data), "\n") {
+		line := strings.TrimRight(StripComment(raw), " \r")
+		if strings.TrimSpace(line) == "" || line == "---" {
+			continue
+		}
+		text := strings.TrimLeft(line, " ")
+		if strings.HasPrefix(text, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", i+1)
+		}
+		lines = append(lines, Line{Num: i + 1, Indent: len(line) - len(text), Text: text})
+	}
+	return lines, nil
+}
+
+// ReadKey reads a bare or quoted key from s up to sep and returns the key and
+// the remainder after sep.
+func ReadKey(s string, sep byte) (string, string, error) {
+	if strings.HasPrefix(s, `"`) || strings.HasPrefix(s, "'") {
+		end := closingQuote(s)
+		if end == -1 {
+			return "", "", fmt.Errorf("unterminated quoted key")
+		}
+		key, err := Unquote(s[:end+1])
+		if err != nil {
+			return "", "", err
+		}
+		rest := strings.TrimLeft(s[end+1:], " ")
+		if rest == "" || rest[0] != sep {
+			return "", "", fmt.Errorf("expected %q after key %q", sep, key)
+		}
+		return key, rest[1:], nil
+	}
+
+	idx := strings.IndexByte(s, sep)
+	if idx == -1 {
+		return "", "", fmt.Errorf("expected %q in %q", sep, s)
+	}
+	key := strings.TrimSpace(s[:idx])
+	if key == "" {
+		return "", "", fmt.Errorf("empty key")
+	}
+	return key, s[idx+1:], nil
+}
+
+// Unquote removes double (with Go/TOML escapes) or single quotes from s.
+// Unquoted values are returned unchanged.
+func Unquote(s string) (string, error) {
+	if len(s) == 0 || (s[0] != '"' && s[0] != '\'') {
+		return s, nil
+	}
+	if closingQuote(s) != len(s)-1 {
+		return "", fmt.Errorf("malformed quoted string %s", s)
+	}
+	if s[0] == '\'' {
+		return s[1 : len(s)-1], nil
+	}
+	value, err := strconv.Unquote(s)
+	if err != nil {
+		return "", fmt.Errorf("malformed quoted string %s", s)
+	}
+	return value, nil
+}
+
+// closingQuote returns the index of the quote closing the one at s[0], or -1.
+func closingQuote(s string) int {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if quote == '"' {
+				i++
+			}
+		case quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// StripComment removes a trailing # comment that is not inside quotes.
+func StripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,68 @@
+package textconf
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUnquote(t *testing.T) {
+	for in, want := range map[string]string{
+		`plain`:      "plain",
+		`"a\tb"`:     "a\tb",
+		`'a\tb'`:     `a\tb`,
+		`"it's"`:     "it's",
+		`'say "hi"'`: `say "hi"`,
+		``:           "",
+	} {
+		if got, err := Unquote(in); err != nil || got != want {
+			t.Errorf("Unquote(%s) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{`"open`, `'open`, `'a\'b'`, `"a" b`, `"\q"`} {
+		if _, err := Unquote(in); err == nil || !strings.Contains(err.Error(), "malformed quoted string") {
+			t.Errorf("Unquote(%s): expected a malformed quoted string error, got %v", in, err)
+		}
+	}
+}
+
+func TestStripComment(t *testing.T) {
+	for in, want := range map[string]string{
+		"a: b # note":     "a: b ",
+		"# note":          "",
+		`a: "b # c" # d`:  `a: "b # c" `,
+		`a: 'b \' # c`:    `a: 'b \' `,
+		"a: b#c":          "a: b#c",
+		`a: "b \" # c"`:   `a: "b \" # c"`,
+		"no comment here": "no comment here",
+	} {
+		if got := StripComment(in); got != want {
+			t.Errorf("StripComment(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestYAMLLines(t *testing.T) {
+	got, err := YAMLLines([]byte("---\n# top\na: 1\n\n  - x # item\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Line{{Num: 3, Text: "a: 1"}, {Num: 5, Indent: 2, Text: "- x"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	if _, err := YAMLLines([]byte("a:\n\tb: 1\n")); err == nil || !strings.Contains(err.Error(), "line 2: tabs") {
+		t.Errorf("expected a tab indentation error on line 2, got %v", err)
+	}
+}
+
+func TestReadKey(t *testing.T) {
+	key, rest, err := ReadKey(`"views/a.html": x`, ':')
+	if err != nil || key != "views/a.html" || rest != " x" {
+		t.Errorf("got %q, %q, %v", key, rest, err)
+	}
+	if _, _, err := ReadKey("no separator", '='); err == nil {
+		t.Error("expected an error without a separator")
+	}
+}
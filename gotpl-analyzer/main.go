@@ -15,9 +15,16 @@ import (
 	"compress/gzip"
 	"encoding/json"
 	"flag"
+	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
+	"sync/atomic"
 
 	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
 	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
@@ -36,9 +43,15 @@ type ValidationOutput struct {
 	// FuncMaps contains discovered template function maps.
 	FuncMaps []ast.FuncMapInfo `json:"funcMaps"`
 
-	// ValidationErrors contains template-to-render-call mismatches.
+	// ValidationErrors contains template-to-render-call mismatches. It is
+	// empty with -group-by=gofile.
 	ValidationErrors []validator.ValidationResult `json:"validationErrors"`
 
+	// ValidationErrorsByGoFile contains the same results keyed by the Go file
+	// of the render call that introduced them, with -group-by=gofile (see
+	// validator.GroupByGoFile).
+	ValidationErrorsByGoFile map[string][]validator.ValidationResult `json:"validationErrorsByGoFile,omitempty"`
+
 	// Errors contains non-fatal analysis errors (optional).
 	Errors []string `json:"errors,omitempty"`
 
@@ -52,22 +65,131 @@ type ValidationOutput struct {
 	// its direct fields. Consumers reconstruct the full type hierarchy by
 	// recursively looking up each field's TypeStr in this map.
 	Types map[string][]ast.FieldInfo `json:"types,omitempty"`
+
+	// Config is the effective configuration of the run, included with -v.
+	Config *EffectiveConfig `json:"config,omitempty"`
+}
+
+// AnalysisOutput is the JSON structure emitted without -validate when -v
+// adds the effective configuration to the analysis result.
+type AnalysisOutput struct {
+	ast.AnalysisResult
+
+	// Config is the effective configuration of the run.
+	Config *EffectiveConfig `json:"config"`
+}
+
+// EffectiveConfig is the fully resolved configuration of a run, emitted by
+// -print-config and included in the output with -v so that a reproduction
+// can use exactly the same settings. Its keys are those of the config file
+// (see loadConfig). The CLI options stand in for the
+// AnalysisConfig hooks that cannot be serialized.
+type EffectiveConfig struct {
+	ast.AnalysisConfig
+	cliOptions
+
+	// Dir is the absolute Go source directory.
+	Dir string `json:"dir"`
+}
+
+// ErrorsOutput is the JSON structure emitted by -errors-only.
+type ErrorsOutput struct {
+	// Errors contains every non-fatal analysis error, including the
+	// import-related ones that are normally filtered out, since those are
+	// often the reason no render calls were found.
+	Errors []string `json:"errors"`
 }
 
 // main is the CLI entry point for the template analyzer.
 func main() {
 	// Command-line flags
 	dir := flag.String("dir", ".", "Go source directory to analyze")
-	templateRoot := flag.String("template-root", "", "Root directory for templates")
+	var templateRoots stringList
+	flag.Var(&templateRoots, "template-root", "Root directory for templates (repeatable; further roots are validated alongside the first)")
 	templateBaseDir := flag.String("template-base-dir", "", "Base directory for template-root")
+	templateRootMode := flag.String("template-root-mode", "single", "How render calls locate templates: single (-template-root for all) or per-package (nearest templates/ directory above the Go file)")
 	validate := flag.Bool("validate", false, "Validate templates against render calls")
-	contextFile := flag.String("context-file", "", "Path to JSON file with additional context variables")
+	contextFile := flag.String("context-file", "", "Path to JSON, YAML or TOML file with additional context variables")
 	compress := flag.Bool("compress", false, "Output gzip-compressed JSON")
+	pretty := flag.Bool("pretty", false, "Indent JSON output for reading by hand (ignored with -compress and -format=ndjson)")
+	groupBy := flag.String("group-by", "template", "Key of -validate results: template, or gofile to group them by the Go file of the render call that introduced them")
+	format := flag.String("format", "json", "Output format of -validate: json, or ndjson to stream one JSON record per line as results are found")
 	daemon := flag.Bool("daemon", false, "Run as a long-lived JSON-RPC daemon over stdio")
 	showNamedTemplates := flag.Bool("named-templates", false, "Return all named template as JSON")
+	listTemplates := flag.Bool("list-templates", false, "Output every template file, named block and render call target with whether it is rendered or included")
+	funcSignatures := flag.Bool("func-signatures", false, "Output the normalized signatures of the discovered template functions")
+	funcUsage := flag.Bool("func-usage", false, "Output the custom, builtin and unknown functions each template file calls")
 	viewContext := flag.String("view-context", "", "Show context for a specific template")
+	explain := flag.String("explain", "", "Output the scope at the line:col given as argument in this template, e.g. -explain users.html 12:5")
+	xref := flag.Bool("xref", false, "Output a template-to-Go cross-reference index")
+	resolve := flag.Bool("resolve", false, "Output how each render call's template name was resolved")
+	quiet := flag.Bool("quiet", false, "Omit non-fatal analysis errors from the output")
+	errorsOnly := flag.Bool("errors-only", false, "Output only the non-fatal analysis errors")
+	strictMapKeys := flag.Bool("strict-map-keys", false, "Warn on map keys missing from literal-built maps")
+	securityLints := flag.Bool("security-lints", false, "Warn on unescaped string output and redundant escaping")
+	includeTests := flag.Bool("include-tests", false, "Also analyze _test.go files for render calls")
+	includeTestdata := flag.Bool("include-testdata", false, "Also analyze Go packages under testdata directories")
+	includeUnexported := flag.Bool("include-unexported", false, "Include unexported struct fields in the context")
+	dynamicTemplateNames := flag.String("dynamic-template-names", "", "Severity of notes for dynamic {{template}} names: info, warning or error (default off)")
+	warnDeprecated := flag.Bool("warn-deprecated", false, "Warn on references to fields documented as Deprecated")
+	defineIsFileEntry := flag.Bool("define-is-file-entry", false, "Validate a define-only file by the define named after the file")
+	verbose := flag.Bool("v", false, "Log analysis phase timings and counts to stderr, and include the effective configuration in the output")
+	veryVerbose := flag.Bool("vv", false, "Like -v, with additional debug details")
+	tags := flag.String("tags", "", "Comma-separated build tags to apply when loading packages (may change which render calls are found)")
+	allowBlockOverride := flag.Bool("allow-block-override", false, "Validate block calls against a define overriding the block's default body")
+	mergeContexts := flag.Bool("merge-contexts", false, "Warn on variables only some render calls of a template provide")
+	deps := flag.Bool("deps", false, "Output the templates each template file includes via {{template}}")
+	describeType := flag.String("describe-type", "", "Output the template field tree of a named type, e.g. handlers.User")
+	respectGitignore := flag.Bool("respect-gitignore", false, "Skip template files and package directories excluded by .gitignore files under -dir")
+	warningsAsErrors := flag.Bool("warnings-as-errors", false, "Report every warning-severity validation result as an error")
+	failOn := flag.String("fail-on", "error", "Exit with status 1 when -validate reports a result of this severity or worse: error, warning or none")
+	validateBlockBodies := flag.Bool("validate-block-bodies", false, "Also validate each define and block body against the union of its call sites' contexts")
+	templateOnly := flag.Bool("template-only", false, "Skip Go analysis and validate templates against -context-file alone")
+	strictParse := flag.Bool("strict-parse", false, "Also report template files that text/template fails to parse")
+	only := flag.String("only", "", "Validate only the render calls whose template matches this glob, e.g. \"users/*.html\"; also filters -view-context, -xref and -resolve")
+	warnShadowing := flag.Bool("warn-shadowing", false, "Warn on references inside range and with blocks to element fields named like a root variable")
+	warnStructOutput := flag.Bool("warn-struct-output", false, "Warn on actions such as {{.User}} that print a struct without a String method as a whole")
+	warnEmptyTemplates := flag.Bool("warn-empty-templates", false, "Warn on rendered templates that receive variables but contain no actions")
+	componentProps := flag.String("component-props", "", "Marker of {{/* props: Name Type, ... */}} annotations whose props {{template}} calls of the component must provide, e.g. \"props\" (default off)")
+	printConfig := flag.Bool("print-config", false, "Output the effective configuration as JSON and exit")
+	maxErrors := flag.Int("max-errors", 0, "Stop after this many validation results and report how many more were suppressed (default unlimited)")
+	var dataArgOffsets argOffsets
+	flag.Var(&dataArgOffsets, "data-arg-offset", "Position of a render function's data argument relative to its template argument as name=offset, e.g. Render=2 for c.Render(name, status, data) (repeatable; default 1)")
+	maxResolvedNames := flag.Int("max-resolved-names", ast.MaxAssignmentsPerVar, "Validate at most this many possible template names of a variable passed to a render call")
+	var maxTemplateSize byteSize
+	flag.Var(&maxTemplateSize, "max-template-size", "Skip template files larger than this size, e.g. 10MB (default unlimited)")
+	var customRules stringList
+	flag.Var(&customRules, "rule", "Custom rule to run on every template, e.g. require-variable:CSRFToken or forbid-field-path:User.Password:warning (repeatable)")
+	var pkgPatterns stringList
+	flag.Var(&pkgPatterns, "pkg", "Package pattern to analyze instead of the whole -dir tree, relative to -dir (repeatable)")
 	flag.Parse()
 
+	if *quiet && *errorsOnly {
+		fmt.Fprintln(os.Stderr, "-quiet and -errors-only are mutually exclusive")
+		os.Exit(2)
+	}
+	if *format != "json" && *format != "ndjson" {
+		fmt.Fprintf(os.Stderr, "invalid -format %q: want json or ndjson\n", *format)
+		os.Exit(2)
+	}
+	var explainLine, explainCol int
+	if *explain != "" {
+		if flag.NArg() != 1 {
+			fmt.Fprintln(os.Stderr, "-explain requires a line:col argument")
+			os.Exit(2)
+		}
+		l, c, ok := parseLineCol(flag.Arg(0))
+		if !ok {
+			fmt.Fprintf(os.Stderr, "invalid -explain position %q: want line:col\n", flag.Arg(0))
+			os.Exit(2)
+		}
+		explainLine, explainCol = l, c
+	}
+	if _, err := path.Match(*only, ""); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -only pattern %q: %v\n", *only, err)
+		os.Exit(2)
+	}
+
 	if *daemon {
 		if err := runDaemon(os.Stdin, os.Stdout); err != nil {
 			panic("daemon failed: " + err.Error())
@@ -78,88 +200,464 @@ func main() {
 	// Resolve absolute paths
 	absDir := mustAbs(*dir)
 
+	// Settings from the config file and REX_* environment variables apply to
+	// every flag not given on the command line.
+	config, opts, err := loadConfig(absDir)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	explicit := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	defaultFlag(explicit, "template-root", &templateRoots, append(stringList{opts.TemplateRoot}, opts.ExtraTemplateRoots...))
+	defaultFlag(explicit, "template-base-dir", templateBaseDir, opts.TemplateBaseDir)
+	defaultFlag(explicit, "template-root-mode", templateRootMode, opts.TemplateRootMode)
+	defaultFlag(explicit, "context-file", contextFile, opts.ContextFile)
+	defaultFlag(explicit, "pkg", &pkgPatterns, stringList(opts.Packages))
+	defaultFlag(explicit, "respect-gitignore", respectGitignore, opts.RespectGitignore)
+	defaultFlag(explicit, "template-only", templateOnly, opts.TemplateOnly)
+	defaultFlag(explicit, "warnings-as-errors", warningsAsErrors, opts.WarningsAsErrors)
+	defaultFlag(explicit, "fail-on", failOn, opts.FailOn)
+	defaultFlag(explicit, "strict-map-keys", strictMapKeys, config.StrictMapKeys)
+	defaultFlag(explicit, "security-lints", securityLints, config.SecurityLints)
+	defaultFlag(explicit, "include-unexported", includeUnexported, config.IncludeUnexported)
+	defaultFlag(explicit, "include-tests", includeTests, config.IncludeTests)
+	defaultFlag(explicit, "include-testdata", includeTestdata, config.IncludeTestdata)
+	defaultFlag(explicit, "define-is-file-entry", defineIsFileEntry, config.DefineIsFileEntry)
+	defaultFlag(explicit, "dynamic-template-names", dynamicTemplateNames, config.DynamicTemplateNameSeverity)
+	defaultFlag(explicit, "warn-deprecated", warnDeprecated, config.WarnDeprecated)
+	defaultFlag(explicit, "max-template-size", &maxTemplateSize, byteSize(config.MaxTemplateBytes))
+	defaultFlag(explicit, "max-errors", maxErrors, config.MaxErrors)
+	defaultFlag(explicit, "max-resolved-names", maxResolvedNames, config.MaxResolvedNames)
+	defaultFlag(explicit, "data-arg-offset", &dataArgOffsets, argOffsets(config.DataArgOffsets))
+	defaultFlag(explicit, "merge-contexts", mergeContexts, config.MergeContexts)
+	defaultFlag(explicit, "allow-block-override", allowBlockOverride, config.AllowBlockOverride)
+	defaultFlag(explicit, "validate-block-bodies", validateBlockBodies, config.ValidateBlockBodies)
+	defaultFlag(explicit, "strict-parse", strictParse, config.StrictParse)
+	defaultFlag(explicit, "warn-empty-templates", warnEmptyTemplates, config.WarnEmptyTemplates)
+	defaultFlag(explicit, "warn-shadowing", warnShadowing, config.WarnShadowing)
+	defaultFlag(explicit, "warn-struct-output", warnStructOutput, config.WarnStructOutput)
+	defaultFlag(explicit, "component-props", componentProps, config.ComponentProps)
+	defaultFlag(explicit, "rule", &customRules, stringList(config.CustomRules))
+
+	// The first -template-root is the main one; modes that report on a single
+	// template tree accept no other.
+	templateRoot := templateRoots[0]
+	if len(templateRoots) > 1 {
+		singleRoot := []struct {
+			name string
+			set  bool
+		}{{"deps", *deps}, {"list-templates", *listTemplates}, {"func-usage", *funcUsage}}
+		for _, mode := range singleRoot {
+			if mode.set {
+				fmt.Fprintf(os.Stderr, "-%s supports a single -template-root, got %d\n", mode.name, len(templateRoots))
+				os.Exit(2)
+			}
+		}
+	}
+
+	if *templateRootMode != "single" && *templateRootMode != "per-package" {
+		fmt.Fprintf(os.Stderr, "invalid -template-root-mode %q: want single or per-package\n", *templateRootMode)
+		os.Exit(2)
+	}
+
+	if *failOn != "error" && *failOn != "warning" && *failOn != "none" {
+		fmt.Fprintf(os.Stderr, "invalid -fail-on %q: want error, warning or none\n", *failOn)
+		os.Exit(2)
+	}
+
+	if !validator.ValidDynamicTemplateNameSeverity(*dynamicTemplateNames) {
+		fmt.Fprintf(os.Stderr, "invalid -dynamic-template-names %q: want info, warning or error\n", *dynamicTemplateNames)
+		os.Exit(2)
+	}
+
+	rules, err := validator.ParseRules(customRules)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid -rule: %v\n", err)
+		os.Exit(2)
+	}
+
+	if *groupBy != "template" && *groupBy != "gofile" {
+		fmt.Fprintf(os.Stderr, "invalid -group-by %q: want template or gofile\n", *groupBy)
+		os.Exit(2)
+	}
+	if *groupBy == "gofile" && *format == "ndjson" {
+		fmt.Fprintln(os.Stderr, "-group-by=gofile cannot be used with -format=ndjson")
+		os.Exit(2)
+	}
+
+	if *format == "ndjson" && (!*validate && !*templateOnly || *showNamedTemplates) {
+		fmt.Fprintln(os.Stderr, "-format=ndjson requires -validate")
+		os.Exit(2)
+	}
+
+	if *templateOnly {
+		if *contextFile == "" {
+			fmt.Fprintln(os.Stderr, "-template-only requires -context-file")
+			os.Exit(2)
+		}
+		*validate = true
+	}
+
 	templateBase := absDir
 	if *templateBaseDir != "" {
 		templateBase = mustAbs(*templateBaseDir)
 	}
 
-	// Run static analysis on the source directory.
-	result := ast.AnalyzeDir(absDir, *contextFile, ast.DefaultConfig)
+	config.StrictMapKeys = *strictMapKeys
+	config.SecurityLints = *securityLints
+	config.IncludeUnexported = *includeUnexported
+	config.IncludeTests = *includeTests
+	config.IncludeTestdata = *includeTestdata
+	config.DefineIsFileEntry = *defineIsFileEntry
+	config.DynamicTemplateNameSeverity = *dynamicTemplateNames
+	config.WarnDeprecated = *warnDeprecated
+	config.MaxTemplateBytes = int(maxTemplateSize)
+	config.MaxErrors = *maxErrors
+	config.MaxResolvedNames = *maxResolvedNames
+	config.DataArgOffsets = dataArgOffsets
+	config.MergeContexts = *mergeContexts
+	config.AllowBlockOverride = *allowBlockOverride
+	config.ValidateBlockBodies = *validateBlockBodies
+	config.StrictParse = *strictParse
+	config.WarnEmptyTemplates = *warnEmptyTemplates
+	config.WarnShadowing = *warnShadowing
+	config.WarnStructOutput = *warnStructOutput
+	config.ComponentProps = *componentProps
+	config.CustomRules = customRules
+	config.Logger = newLogger(*verbose, *veryVerbose)
+	if config.Logger != nil && opts.ConfigFile != "" {
+		config.Logger.Info("loaded config file", "path", opts.ConfigFile)
+	}
+	if *templateRootMode == "per-package" {
+		config.TemplateRootResolver = ast.PerPackageTemplateRoot("templates", templateBase, templateRoot)
+	}
+	if *tags != "" {
+		config.BuildFlags = []string{"-tags=" + *tags}
+	}
+	var ignore *validator.GitignoreMatcher
+	if *respectGitignore {
+		m, err := validator.NewGitignoreMatcher(absDir)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		ignore = m
+		config.IgnoreDir = func(path string) bool { return m.Match(path, true) }
+	}
+
+	effectiveContextFile := *contextFile
+	if effectiveContextFile != "" {
+		effectiveContextFile = mustAbs(effectiveContextFile)
+	}
+	effective := &EffectiveConfig{
+		AnalysisConfig: config,
+		cliOptions: cliOptions{
+			TemplateBaseDir:    templateBase,
+			TemplateRoot:       templateRoot,
+			ExtraTemplateRoots: templateRoots[1:],
+			TemplateRootMode:   *templateRootMode,
+			ContextFile:        effectiveContextFile,
+			Packages:           pkgPatterns,
+			RespectGitignore:   *respectGitignore,
+			TemplateOnly:       *templateOnly,
+			WarningsAsErrors:   *warningsAsErrors,
+			FailOn:             *failOn,
+			ConfigFile:         opts.ConfigFile,
+		},
+		Dir: absDir,
+	}
+	if *printConfig {
+		encodeJSON(effective, *compress, *pretty)
+		return
+	}
+	if !*verbose && !*veryVerbose {
+		effective = nil
+	}
+
+	// deps only reads the template tree; no Go analysis is needed.
+	if *deps {
+		encodeJSON(validator.TemplateDeps(templateBase, templateRoot), *compress, *pretty)
+		return
+	}
+
+	// describe-type introspects a single type; no render calls are involved.
+	if *describeType != "" {
+		pkgs, err := ast.LoadPackages(absDir, config)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		tv, err := validator.DescribeTypeWithConfig(pkgs, *describeType, config)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		encodeJSON(tv, *compress, *pretty)
+		return
+	}
+
+	// Run static analysis on the source directory, or only on the requested
+	// packages. Patterns are resolved relative to -dir like `go list` would.
+	var result ast.AnalysisResult
+	if *templateOnly {
+		result = ast.AnalyzeContextFile(mustAbs(*contextFile), config)
+	} else if len(pkgPatterns) > 0 {
+		ctxFile := *contextFile
+		if ctxFile != "" {
+			ctxFile = mustAbs(ctxFile)
+		}
+		result = ast.AnalyzePackagesIn(absDir, pkgPatterns, ctxFile, config)
+	} else {
+		result = ast.AnalyzeDir(absDir, *contextFile, config)
+	}
+
+	if *errorsOnly {
+		errs := result.Errors
+		if errs == nil {
+			errs = []string{}
+		}
+		encodeJSON(ErrorsOutput{Errors: errs}, *compress, *pretty)
+		return
+	}
+
+	// -only narrows the render calls every remaining mode reports on. Validation
+	// filters on its own, since the other calls still contribute context.
+	if !*validate && !*showNamedTemplates {
+		result.RenderCalls = validator.FilterRenderCalls(result.RenderCalls, *only)
+		result.UnresolvedRenderCalls = validator.FilterRenderCalls(result.UnresolvedRenderCalls, *only)
+	}
 
 	// view-context outputs the full variable context (including inline field
 	// trees) for a single template so the editor extension can render hover
 	// and autocomplete information. Do NOT flatten before this call.
 	if *viewContext != "" {
-		handleViewContext(result, *viewContext, *compress)
+		handleViewContext(result, *viewContext, *compress, *pretty)
+		return
+	}
+
+	// explain replays the scopes of one template up to a position; like
+	// view-context it needs the inline field trees.
+	if *explain != "" {
+		handleExplain(result, templateBase, templateRoots, *explain, explainLine, explainCol, *compress, *pretty)
+		return
+	}
+
+	// xref only reshapes the render calls; no validation or flattening needed.
+	if *xref {
+		encodeJSON(validator.BuildXRef(result.RenderCalls), *compress, *pretty)
+		return
+	}
+
+	// list-templates is a coverage-style inventory of the template tree
+	// against the render calls; no validation needed.
+	if *listTemplates {
+		encodeJSON(validator.Inventory(result.RenderCalls, templateBase, templateRoot), *compress, *pretty)
+		return
+	}
+
+	// func-signatures only reshapes the discovered FuncMaps.
+	if *funcSignatures {
+		encodeJSON(validator.FuncMapSignatures(result.FuncMaps), *compress, *pretty)
+		return
+	}
+
+	// func-usage scans the template tree for function calls and classifies
+	// them against the discovered FuncMaps; no validation needed.
+	if *funcUsage {
+		encodeJSON(validator.FunctionUsageByTemplate(templateBase, templateRoot, result.FuncMaps), *compress, *pretty)
+		return
+	}
+
+	// resolve is a dry run of template-name resolution for debugging
+	// "template not found" reports, including calls that failed to resolve.
+	if *resolve {
+		calls := append(result.RenderCalls, result.UnresolvedRenderCalls...)
+		encodeJSON(validator.DescribeResolution(calls), *compress, *pretty)
 		return
 	}
 
 	// Filter out import-related noise
 	result.Errors = filterImportErrors(result.Errors)
+	if *quiet {
+		result.Errors = nil
+	}
 
-	// Prepare output payload
-	var output any
-
-	if *validate || *showNamedTemplates {
-		// Validation reads inline field trees from render call variables to
-		// build per-template variable maps. Flatten AFTER validation completes
-		// so those trees are available throughout the validation pass.
-		ve, namedBlocks, namedBlockErrors := validator.ValidateTemplates(
-			result.RenderCalls,
-			result.FuncMaps,
-			templateBase,
-			*templateRoot,
-		)
-
-		// Build the type registry and strip inline field trees before
-		// serialization to keep the JSON payload small.
+	// Without -validate the raw analysis is the output; nothing below is
+	// needed, so no validation options, workers or template walks are set up.
+	if !*validate && !*showNamedTemplates {
 		result.Flatten()
+		var output any = result
+		if effective != nil {
+			output = AnalysisOutput{AnalysisResult: result, Config: effective}
+		}
+		encodeJSON(output, *compress, *pretty)
+		return
+	}
+
+	validateOpts := validator.ValidateOptions{
+		MaxTemplateBytes:            config.MaxTemplateBytes,
+		MaxErrors:                   config.MaxErrors,
+		WarningsAsErrors:            *warningsAsErrors,
+		Logger:                      config.Logger,
+		DynamicTemplateNameSeverity: config.DynamicTemplateNameSeverity,
+		AllowBlockOverride:          config.AllowBlockOverride,
+		Ignore:                      ignore,
+		TemplateRootResolver:        config.TemplateRootResolver,
+		ExtraTemplateRoots:          templateRoots[1:],
+		ValidateBlockBodies:         config.ValidateBlockBodies,
+		StrictParse:                 config.StrictParse,
+		WarnEmptyTemplates:          config.WarnEmptyTemplates,
+		MergeContexts:               config.MergeContexts,
+		DefineIsFileEntry:           config.DefineIsFileEntry,
+		ComponentProps:              config.ComponentProps,
+		WarnShadowing:               config.WarnShadowing,
+		SecurityLints:               config.SecurityLints,
+		WarnStructOutput:            config.WarnStructOutput,
+		Rules:                       rules,
+		Only:                        *only,
+		WarnMissingContext:          *templateOnly,
+	}
 
-		if *showNamedTemplates {
-			keys := make([]string, 0, len(namedBlocks))
-			for k := range namedBlocks {
-				keys = append(keys, k)
+	// ndjson writes each result as soon as a validation worker finds it.
+	if *format == "ndjson" {
+		w := newNDJSONWriter(*compress)
+		for _, e := range result.Errors {
+			w.analysisError(e)
+		}
+		// The stream only emits results that passed the filters, escalation
+		// and -max-errors cap of validateOpts, so they are written as is.
+		var failed atomic.Bool
+		emit := func(r validator.ValidationResult) {
+			if failsOn(r, *failOn) {
+				failed.Store(true)
 			}
-			output = keys
-		} else {
-			// Produce extended output with validation results.
-			output = ValidationOutput{
-				RenderCalls:      result.RenderCalls,
-				FuncMaps:         result.FuncMaps,
-				ValidationErrors: ve,
-				Errors:           result.Errors,
-				NamedBlocks:      namedBlocks,
-				NamedBlockErrors: namedBlockErrors,
-				Types:            result.Types,
+			w.result(r)
+		}
+		_, namedBlockErrors, skipped := validator.ValidateTemplatesStream(
+			result.RenderCalls, result.FuncMaps, templateBase, templateRoot, validateOpts, emit)
+		for _, e := range namedBlockErrors {
+			w.namedBlockError(e)
+		}
+		if !*quiet {
+			for _, e := range skipped {
+				w.analysisError(e)
 			}
 		}
+		w.Close()
+		if failed.Load() {
+			os.Exit(1)
+		}
+		return
+	}
+
+	// Prepare output payload
+	var output any
+
+	// Validation reads inline field trees from render call variables to
+	// build per-template variable maps. Flatten AFTER validation completes
+	// so those trees are available throughout the validation pass.
+	ve, namedBlocks, namedBlockErrors, skipped := validator.ValidateTemplatesWithOptions(
+		result.RenderCalls,
+		result.FuncMaps,
+		templateBase,
+		templateRoot,
+		validateOpts,
+	)
+	if !*quiet {
+		result.Errors = append(result.Errors, skipped...)
+	}
+
+	// Build the type registry and strip inline field trees before
+	// serialization to keep the JSON payload small.
+	result.Flatten()
+
+	if *showNamedTemplates {
+		keys := make([]string, 0, len(namedBlocks))
+		for k := range namedBlocks {
+			keys = append(keys, k)
+		}
+		output = keys
 	} else {
-		// Raw analysis output: build the registry and flatten before encoding.
-		result.Flatten()
-		output = result
+		// Produce extended output with validation results.
+		vo := ValidationOutput{
+			RenderCalls:      validator.FilterRenderCalls(result.RenderCalls, *only),
+			FuncMaps:         result.FuncMaps,
+			ValidationErrors: ve,
+			Errors:           result.Errors,
+			NamedBlocks:      namedBlocks,
+			NamedBlockErrors: namedBlockErrors,
+			Types:            result.Types,
+			Config:           effective,
+		}
+		if *groupBy == "gofile" {
+			vo.ValidationErrorsByGoFile = validator.GroupByGoFile(ve)
+			vo.ValidationErrors = []validator.ValidationResult{}
+		}
+		output = vo
 	}
 
 	// Encode and write JSON output
-	encodeJSON(output, *compress)
+	encodeJSON(output, *compress, *pretty)
+
+	if !*showNamedTemplates {
+		os.Exit(exitStatus(ve, *failOn))
+	}
+}
+
+// exitStatus returns the exit status of a -validate run: 1 when one of
+// results fails the -fail-on threshold failOn, 0 otherwise. Results are
+// checked as output, after -warnings-as-errors, so combined with
+// -fail-on=error that flag fails the run on any warning as well.
+func exitStatus(results []validator.ValidationResult, failOn string) int {
+	for _, r := range results {
+		if failsOn(r, failOn) {
+			return 1
+		}
+	}
+	return 0
+}
+
+// failsOn reports whether r is at least as severe as failOn: "error" fails
+// on errors and syntax errors, "warning" on warnings as well and "none" on
+// nothing.
+func failsOn(r validator.ValidationResult, failOn string) bool {
+	switch r.Severity {
+	case "error", validator.SeveritySyntax:
+		return failOn == "error" || failOn == "warning"
+	case "warning":
+		return failOn == "warning"
+	}
+	return false
 }
 
 // encodeJSON serializes output as JSON and writes it to stdout.
 //
-// If compress is true, the output is gzip-compressed.
-func encodeJSON(output any, compress bool) {
+// If compress is true, the output is gzip-compressed. Otherwise pretty
+// indents it by two spaces.
+func encodeJSON(output any, compress, pretty bool) {
 	if compress {
 		writeGzipJSON(output)
 		return
 	}
 
-	enc := json.NewEncoder(os.Stdout)
-	enc.SetIndent("", "") // disable indent (reduces size by > 2x)
-
-	if err := enc.Encode(output); err != nil {
+	if err := writeJSON(os.Stdout, output, pretty); err != nil {
 		panic("failed to encode JSON: " + err.Error())
 	}
 }
 
+// writeJSON writes output to w as a single line of JSON, or indented by two
+// spaces when pretty is true.
+func writeJSON(w io.Writer, output any, pretty bool) error {
+	enc := json.NewEncoder(w)
+	if pretty {
+		enc.SetIndent("", "  ")
+	} else {
+		enc.SetIndent("", "") // disable indent (reduces size by > 2x)
+	}
+	return enc.Encode(output)
+}
+
 // writeGzipJSON writes gzip-compressed JSON to stdout.
 func writeGzipJSON(output any) {
 	gzWriter := gzip.NewWriter(os.Stdout)
@@ -177,6 +675,87 @@ func writeGzipJSON(output any) {
 	}
 }
 
+// defaultFlag sets the flag variable p to value unless the flag named name
+// was given on the command line.
+func defaultFlag[T any](explicit map[string]bool, name string, p *T, value T) {
+	if !explicit[name] {
+		*p = value
+	}
+}
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(v string) error {
+	*l = append(*l, v)
+	return nil
+}
+
+// argOffsets is a repeatable name=offset flag.
+type argOffsets map[string]int
+
+func (m *argOffsets) String() string {
+	entries := make([]string, 0, len(*m))
+	for name, offset := range *m {
+		entries = append(entries, name+"="+strconv.Itoa(offset))
+	}
+	slices.Sort(entries)
+	return strings.Join(entries, ",")
+}
+
+func (m *argOffsets) Set(v string) error {
+	name, offset, err := parseArgOffset(v)
+	if err != nil {
+		return err
+	}
+	if *m == nil {
+		*m = make(argOffsets)
+	}
+	(*m)[name] = offset
+	return nil
+}
+
+// newLogger returns a stderr logger for -v (info) or -vv (debug), or nil when
+// neither is set. Stdout is reserved for the JSON output.
+func newLogger(verbose, veryVerbose bool) *slog.Logger {
+	level := slog.LevelInfo
+	switch {
+	case veryVerbose:
+		level = slog.LevelDebug
+	case !verbose:
+		return nil
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
+}
+
+// byteSize is a flag accepting a byte count with an optional KB, MB or GB
+// suffix (powers of 1024), e.g. "512KB" or "10MB".
+type byteSize int
+
+func (b *byteSize) String() string { return strconv.Itoa(int(*b)) }
+
+func (b *byteSize) Set(v string) error {
+	s := strings.ToUpper(strings.TrimSpace(v))
+	mult := 1
+	for _, unit := range []struct {
+		suffix string
+		mult   int
+	}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}} {
+		if strings.HasSuffix(s, unit.suffix) {
+			s, mult = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix)), unit.mult
+			break
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return fmt.Errorf("invalid size %q", v)
+	}
+	*b = byteSize(n * mult)
+	return nil
+}
+
 // mustAbs resolves path to an absolute path.
 //
 // The program panics if resolution fails, since relative paths
@@ -223,39 +802,100 @@ func isImportError(e string) bool {
 	return false
 }
 
+// ViewContext is the variable context of a single render call, as output by
+// -view-context.
+type ViewContext struct {
+	// Template is the full template path of the render call, so results from
+	// a base-name query can be told apart.
+	Template string            `json:"template"`
+	File     string            `json:"file"`
+	Line     int               `json:"line"`
+	Vars     []ast.TemplateVar `json:"vars"`
+}
+
 // handleViewContext filters render calls for a specific template and outputs
 // the full variable context including inline field trees. This endpoint is
 // intentionally not flattened so the caller receives complete type information
 // for hover and autocomplete features.
-func handleViewContext(result ast.AnalysisResult, templateName string, compress bool) {
-	type ContextInfo struct {
-		File string            `json:"file"`
-		Line int               `json:"line"`
-		Vars []ast.TemplateVar `json:"vars"`
+func handleViewContext(result ast.AnalysisResult, templateName string, compress, pretty bool) {
+	encodeJSON(findViewContexts(result.RenderCalls, templateName), compress, pretty)
+}
+
+// handleExplain outputs the scope at line:col in templateName, read from the
+// first template root that has it, against the merged context of the render
+// calls targeting it (matched as for -view-context).
+func handleExplain(result ast.AnalysisResult, templateBase string, templateRoots []string, templateName string, line, col int, compress, pretty bool) {
+	var content []byte
+	var err error
+	for _, root := range templateRoots {
+		if content, err = os.ReadFile(filepath.Join(templateBase, root, templateName)); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	var contexts [][]ast.TemplateVar
+	for _, vc := range findViewContexts(result.RenderCalls, templateName) {
+		contexts = append(contexts, vc.Vars)
+	}
+	explanation := validator.ExplainAt(string(content), validator.MergeContexts(contexts...), line, col)
+	if len(explanation.Scopes) == 0 {
+		fmt.Fprintf(os.Stderr, "%s has no position %d:%d\n", templateName, line, col)
+		os.Exit(1)
 	}
+	encodeJSON(explanation, compress, pretty)
+}
 
-	foundContexts := []ContextInfo{}
+// parseLineCol parses a 1-based "line:col" position.
+func parseLineCol(s string) (line, col int, ok bool) {
+	l, c, found := strings.Cut(s, ":")
+	if !found {
+		return 0, 0, false
+	}
+	line, err1 := strconv.Atoi(l)
+	col, err2 := strconv.Atoi(c)
+	if err1 != nil || err2 != nil || line < 1 || col < 1 {
+		return 0, 0, false
+	}
+	return line, col, true
+}
 
-	for _, rc := range result.RenderCalls {
-		// Check for exact match or suffix match (to handle relative paths vs absolute/partial paths)
-		if rc.Template == templateName || strings.HasSuffix(rc.Template, "/"+templateName) || strings.HasSuffix(rc.Template, "\\"+templateName) {
-			// Avoid NULLs
-			if rc.Vars == nil {
-				rc.Vars = []ast.TemplateVar{}
-			}
-			foundContexts = append(foundContexts, ContextInfo{
-				File: rc.File,
-				Line: rc.Line,
-				Vars: rc.Vars,
-			})
+// findViewContexts returns the contexts of all render calls whose template
+// matches templateName, either exactly or by path suffix. A bare file name
+// such as "user.html" therefore matches that file in every directory.
+// Separators are normalized on both sides so "views\user.html" and
+// "views/user.html" are equivalent regardless of platform.
+func findViewContexts(renderCalls []ast.RenderCall, templateName string) []ViewContext {
+	query := slashTemplatePath(templateName)
+	foundContexts := []ViewContext{}
+
+	for _, rc := range renderCalls {
+		template := slashTemplatePath(rc.Template)
+		if template != query && !strings.HasSuffix(template, "/"+query) {
+			continue
 		}
-	}
 
-	if len(foundContexts) == 0 {
-		// Output empty list to indicate no context found
-		encodeJSON([]ContextInfo{}, compress)
-		return
+		// Avoid NULLs
+		if rc.Vars == nil {
+			rc.Vars = []ast.TemplateVar{}
+		}
+		foundContexts = append(foundContexts, ViewContext{
+			Template: rc.Template,
+			File:     rc.File,
+			Line:     rc.Line,
+			Vars:     rc.Vars,
+		})
 	}
 
-	encodeJSON(foundContexts, compress)
+	return foundContexts
+}
+
+// slashTemplatePath converts a template path to forward slashes. Backslashes
+// are replaced explicitly because filepath.ToSlash only rewrites the host
+// separator, and template names written on Windows may reach us anywhere.
+func slashTemplatePath(name string) string {
+	name = strings.ReplaceAll(filepath.ToSlash(name), `\`, "/")
+	return strings.TrimPrefix(name, "./")
 }
@@ -1,6 +1,15 @@
 package main
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
@@ -48,3 +57,201 @@ func BenchmarkWarmStart(b *testing.B) {
 // View flame graphs
 // go tool pprof -http=:8080 cpu_warm.prof
 // go tool pprof -http=:8081 mem_warm.prof
+
+func TestFindViewContextsNormalizesSeparators(t *testing.T) {
+	calls := []ast.RenderCall{
+		{File: "handlers/user.go", Line: 10, Template: "views/user.html"},
+		{File: "handlers/admin.go", Line: 20, Template: `views\admin.html`},
+	}
+
+	for _, query := range []string{"views/user.html", `views\user.html`} {
+		got := findViewContexts(calls, query)
+		if len(got) != 1 || got[0].Template != "views/user.html" {
+			t.Errorf("query %q: expected views/user.html, got %#v", query, got)
+		}
+	}
+
+	got := findViewContexts(calls, "views/admin.html")
+	if len(got) != 1 || got[0].File != "handlers/admin.go" {
+		t.Errorf("expected backslash template to match forward-slash query, got %#v", got)
+	}
+}
+
+func TestFindViewContextsMatchesBaseName(t *testing.T) {
+	calls := []ast.RenderCall{
+		{File: "handlers/user.go", Line: 10, Template: "views/user.html"},
+		{File: "handlers/admin.go", Line: 20, Template: "admin/user.html"},
+		{File: "handlers/other.go", Line: 30, Template: "views/superuser.html"},
+	}
+
+	got := findViewContexts(calls, "user.html")
+	if len(got) != 2 {
+		t.Fatalf("expected 2 contexts, got %#v", got)
+	}
+	if got[0].Template != "views/user.html" || got[1].Template != "admin/user.html" {
+		t.Errorf("expected contexts annotated with full paths, got %q and %q", got[0].Template, got[1].Template)
+	}
+	for _, ctx := range got {
+		if ctx.Vars == nil {
+			t.Errorf("expected non-nil vars for %s", ctx.Template)
+		}
+	}
+}
+
+func TestByteSizeFlag(t *testing.T) {
+	cases := map[string]int{"512": 512, "2KB": 2 << 10, "10MB": 10 << 20, "1gb": 1 << 30, "8 MB": 8 << 20, "100B": 100}
+	for in, want := range cases {
+		var b byteSize
+		if err := b.Set(in); err != nil || int(b) != want {
+			t.Errorf("Set(%q) = %d, %v; want %d", in, b, err, want)
+		}
+	}
+	for _, in := range []string{"", "MB", "-1KB", "ten"} {
+		var b byteSize
+		if err := b.Set(in); err == nil {
+			t.Errorf("Set(%q) should fail", in)
+		}
+	}
+}
+
+func TestParseLineCol(t *testing.T) {
+	if line, col, ok := parseLineCol("12:5"); !ok || line != 12 || col != 5 {
+		t.Errorf("parseLineCol(12:5) = %d, %d, %v", line, col, ok)
+	}
+	for _, in := range []string{"", "12", "12:", ":5", "0:1", "1:0", "a:b"} {
+		if _, _, ok := parseLineCol(in); ok {
+			t.Errorf("parseLineCol(%q) should fail", in)
+		}
+	}
+}
+
+func TestWriteJSONPretty(t *testing.T) {
+	output := ErrorsOutput{Errors: []string{"load error: boom", "type error: bang"}}
+
+	var compact, pretty bytes.Buffer
+	if err := writeJSON(&compact, output, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeJSON(&pretty, output, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Count(compact.String(), "\n") != 1 {
+		t.Errorf("default output should be a single line, got %q", compact.String())
+	}
+	if !strings.Contains(pretty.String(), "{\n  \"errors\": [\n    \"load error: boom\",\n") {
+		t.Errorf("pretty output should be indented by two spaces, got %q", pretty.String())
+	}
+
+	var a, b ErrorsOutput
+	if err := json.Unmarshal(compact.Bytes(), &a); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(pretty.Bytes(), &b); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(a, b) {
+		t.Errorf("pretty output decodes to %+v, want %+v", b, a)
+	}
+}
+
+func TestEffectiveConfigJSON(t *testing.T) {
+	config := ast.DefaultConfig
+	config.BuildFlags = []string{"-tags=prod"}
+	config.Logger = slog.New(slog.DiscardHandler)
+	config.IgnoreDir = func(string) bool { return false }
+	config.TemplateRootResolver = ast.PerPackageTemplateRoot("templates", "/src", "")
+
+	data, err := json.Marshal(EffectiveConfig{AnalysisConfig: config, cliOptions: cliOptions{TemplateRootMode: "per-package"}, Dir: "/src"})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]any{
+		"renderFunctionName": "Render",
+		"buildFlags":         []any{"-tags=prod"},
+		"dir":                "/src",
+		"templateRootMode":   "per-package",
+	} {
+		if !reflect.DeepEqual(got[key], want) {
+			t.Errorf("%s: expected %v, got %v", key, want, got[key])
+		}
+	}
+	for _, key := range []string{"Logger", "IgnoreDir", "TemplateRootResolver", "AnalysisConfig", "cliOptions"} {
+		if _, ok := got[key]; ok {
+			t.Errorf("expected %s to be left out, got %s", key, data)
+		}
+	}
+}
+
+// runMain runs main with args in a subprocess, discarding its output, and
+// returns its exit status.
+func runMain(t *testing.T, args ...string) int {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], "-test.run=^TestRunMain$")
+	cmd.Env = append(os.Environ(), "GOTPL_ANALYZER_ARGS="+strings.Join(args, "\n"))
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return 0
+}
+
+// TestRunMain is the subprocess of runMain.
+func TestRunMain(t *testing.T) {
+	args := os.Getenv("GOTPL_ANALYZER_ARGS")
+	if args == "" {
+		t.Skip("only run by runMain")
+	}
+	os.Args = append([]string{"gotpl-analyzer"}, strings.Split(args, "\n")...)
+	main()
+}
+
+func TestExitStatus(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"context.json":     `{"index.html": {"title": "string"}, "broken.html": {"title": "string"}}`,
+		"warn/index.html":  `{{.title}}`,
+		"warn/orphan.html": `{{.anything}}`,
+		"fail/index.html":  `{{.title}}`,
+		"fail/broken.html": `{{.missing}}`,
+		"dyn/index.html":   `{{template .title .}}`,
+	})
+	base := []string{"-dir", dir, "-template-only", "-context-file", filepath.Join(dir, "context.json")}
+
+	cases := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{"warnings only", []string{"-template-root", "warn"}, 0},
+		{"warnings as errors", []string{"-template-root", "warn", "-warnings-as-errors"}, 1},
+		{"fail on warning", []string{"-template-root", "warn", "-fail-on", "warning"}, 1},
+		{"errors", []string{"-template-root", "fail"}, 1},
+		{"errors streamed", []string{"-template-root", "fail", "-format", "ndjson"}, 1},
+		{"fail on none", []string{"-template-root", "fail", "-fail-on", "none"}, 0},
+		{"invalid fail-on", []string{"-template-root", "fail", "-fail-on", "warnings"}, 2},
+		{"dynamic template name", []string{"-template-root", "dyn"}, 0},
+		{"dynamic template name error", []string{"-template-root", "dyn", "-dynamic-template-names", "error"}, 1},
+		{"custom rule", []string{"-template-root", "warn", "-rule", "require-variable:csrf"}, 1},
+		{"custom rule warning", []string{"-template-root", "warn", "-rule", "require-variable:csrf:warning"}, 0},
+		{"invalid custom rule", []string{"-template-root", "warn", "-rule", "require-csrf"}, 2},
+		{"invalid data argument offset", []string{"-template-root", "warn", "-data-arg-offset", "Render"}, 2},
+		{"single-root mode with two roots", []string{"-template-root", "warn", "-template-root", "fail", "-deps"}, 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := runMain(t, append(base, c.args...)...); got != c.want {
+				t.Errorf("exit status %d, want %d", got, c.want)
+			}
+		})
+	}
+}
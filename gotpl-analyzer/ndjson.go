@@ -0,0 +1,82 @@
+package main
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// StreamRecord is one line of -format=ndjson output. Kind says which of the
+// other fields is set: "result" for a validation result, whose fields are
+// inlined, "namedBlockError" for a duplicate named block and "error" for a
+// non-fatal analysis error.
+type StreamRecord struct {
+	Kind string `json:"kind"`
+
+	*validator.ValidationResult
+
+	// NamedBlockError is set for "namedBlockError" records.
+	NamedBlockError *validator.NamedBlockDuplicateError `json:"namedBlockError,omitempty"`
+
+	// Error is set for "error" records.
+	Error string `json:"error,omitempty"`
+}
+
+// ndjsonWriter writes StreamRecords to stdout one per line. It is safe for
+// concurrent use by the validation workers.
+type ndjsonWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	gz  *gzip.Writer
+}
+
+// newNDJSONWriter returns a writer to stdout, gzip-compressed if compress is
+// true. Close must be called once all records are written.
+func newNDJSONWriter(compress bool) *ndjsonWriter {
+	w := &ndjsonWriter{}
+	var out io.Writer = os.Stdout
+	if compress {
+		w.gz = gzip.NewWriter(os.Stdout)
+		out = w.gz
+	}
+	w.enc = json.NewEncoder(out)
+	return w
+}
+
+// write encodes rec as a single line.
+func (w *ndjsonWriter) write(rec StreamRecord) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if err := w.enc.Encode(rec); err != nil {
+		panic("failed to encode JSON: " + err.Error())
+	}
+}
+
+// result writes a "result" record.
+func (w *ndjsonWriter) result(r validator.ValidationResult) {
+	w.write(StreamRecord{Kind: "result", ValidationResult: &r})
+}
+
+// namedBlockError writes a "namedBlockError" record.
+func (w *ndjsonWriter) namedBlockError(e validator.NamedBlockDuplicateError) {
+	w.write(StreamRecord{Kind: "namedBlockError", NamedBlockError: &e})
+}
+
+// analysisError writes an "error" record.
+func (w *ndjsonWriter) analysisError(msg string) {
+	w.write(StreamRecord{Kind: "error", Error: msg})
+}
+
+// Close flushes the gzip stream, if any.
+func (w *ndjsonWriter) Close() {
+	if w.gz == nil {
+		return
+	}
+	if err := w.gz.Close(); err != nil {
+		panic("failed to close gzip writer: " + err.Error())
+	}
+}
@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"strconv"
 	"strings"
 )
 
@@ -27,14 +28,13 @@ func parseTemplateAction(action string) []string {
 	remaining := rest
 
 	if len(rest) > 0 && (rest[0] == '"' || rest[0] == '`') {
-		// Quoted template name — find the closing quote
-		quoteChar := rest[0]
-		endIdx := strings.IndexByte(rest[1:], quoteChar)
-		if endIdx == -1 {
+		// Quoted template name — unquote it, honouring escapes
+		name, after, ok := splitQuotedName(rest)
+		if !ok {
 			return []string{rest} // malformed, return as-is
 		}
-		tmplName = rest[1 : endIdx+1]
-		remaining = strings.TrimSpace(rest[endIdx+2:])
+		tmplName = name
+		remaining = strings.TrimSpace(after)
 	} else {
 		// Unquoted template name — take until whitespace
 		idx := strings.IndexAny(rest, " \t\n\r")
@@ -53,6 +53,44 @@ func parseTemplateAction(action string) []string {
 	return []string{tmplName, remaining}
 }
 
+// splitQuotedName splits the Go string literal at the start of s off the
+// rest of s and returns its unquoted value. Both interpreted ("a \"b\"") and
+// raw (`a "b"`) literals are accepted, so names containing spaces or quotes
+// come out exactly as text/template would register them.
+func splitQuotedName(s string) (name, rest string, ok bool) {
+	prefix, err := strconv.QuotedPrefix(s)
+	if err != nil || prefix[0] == '\'' {
+		return "", s, false
+	}
+	name, err = strconv.Unquote(prefix)
+	if err != nil {
+		return "", s, false
+	}
+	return name, s[len(prefix):], true
+}
+
+// unquotedTemplateName returns the name token of a {{template}}, {{block}} or
+// {{define}} action, and its offset in action, when it is not a quoted string
+// as text/template requires. A $variable or .Field {{template}} name is left
+// to the dynamic-name note, and a missing name is not reported here.
+func unquotedTemplateName(action, keyword string) (string, int) {
+	if keyword != "template" && keyword != "block" && keyword != "define" {
+		return "", -1
+	}
+	rest := strings.TrimLeft(strings.TrimPrefix(action, keyword), " \t\r\n")
+	if rest == "" || rest[0] == '"' || rest[0] == '`' {
+		return "", -1
+	}
+	name := rest
+	if idx := strings.IndexAny(rest, " \t\r\n"); idx != -1 {
+		name = rest[:idx]
+	}
+	if keyword == "template" && isDynamicTemplateName(name) {
+		return "", -1
+	}
+	return name, len(action) - len(rest)
+}
+
 // extractVariablesFromAction extracts all variable references from a template
 // action string.
 //
@@ -65,15 +103,25 @@ func parseTemplateAction(action string) []string {
 //   - String literals (quoted content)
 //   - Operators and delimiters
 //   - Keywords
+//   - Field chains on a parenthesized expression, e.g. the .Address.City in
+//     (index .Users 0).Address.City; see extractParenChains
 //
-// Calls onVar callback for each valid variable found.
+// Calls onVar callback for each valid variable found, together with its byte
+// offset within action.
 //
 // Thread-safety: No shared state, safe for concurrent calls.
-func extractVariablesFromAction(action string, onVar func(string)) {
+func extractVariablesFromAction(action string, onVar func(v string, offset int)) {
 	start := -1
 	inString := false
 	stringChar := rune(0)
 
+	emit := func(end int) {
+		if start > 0 && action[start-1] == ')' {
+			return
+		}
+		emitVar(action[start:end], start, onVar)
+	}
+
 	for i, r := range action {
 		if inString {
 			// Inside string literal: skip until closing quote
@@ -87,7 +135,7 @@ func extractVariablesFromAction(action string, onVar func(string)) {
 		case '"', '`':
 			// Start of string literal
 			if start != -1 {
-				emitVar(action[start:i], onVar)
+				emit(i)
 				start = -1
 			}
 			inString = true
@@ -96,7 +144,7 @@ func extractVariablesFromAction(action string, onVar func(string)) {
 		case ' ', '\n', '\r', '\t', '(', ')', '|', '=', ',', '+', '-', '*', '/', '!', '<', '>', '%', '&':
 			// Delimiter: emit pending variable
 			if start != -1 {
-				emitVar(action[start:i], onVar)
+				emit(i)
 				start = -1
 			}
 
@@ -110,7 +158,48 @@ func extractVariablesFromAction(action string, onVar func(string)) {
 
 	// Emit any remaining variable
 	if start != -1 {
-		emitVar(action[start:], onVar)
+		emit(len(action))
+	}
+}
+
+// extractParenChains finds field chains applied to a parenthesized expression,
+// such as (index .Users 0).Address.City, and calls onChain with the
+// expression inside the parentheses, the chain segments ("Address", "City")
+// and the byte offset of the opening parenthesis within action.
+func extractParenChains(action string, onChain func(inner string, chain []string, offset int)) {
+	var opens []int
+	inString := false
+	stringChar := byte(0)
+
+	for i := 0; i < len(action); i++ {
+		c := action[i]
+		if inString {
+			if c == stringChar {
+				inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"', '`':
+			inString = true
+			stringChar = c
+		case '(':
+			opens = append(opens, i)
+		case ')':
+			if len(opens) == 0 {
+				continue
+			}
+			open := opens[len(opens)-1]
+			opens = opens[:len(opens)-1]
+
+			end := i + 1
+			for end < len(action) && !strings.ContainsRune(" \n\r\t()|=,", rune(action[end])) {
+				end++
+			}
+			if end > i+2 && action[i+1] == '.' {
+				onChain(action[open+1:i], strings.Split(action[i+2:end], "."), open)
+			}
+		}
 	}
 }
 
@@ -120,18 +209,18 @@ func extractVariablesFromAction(action string, onVar func(string)) {
 //   - Start with . or $.
 //   - Not exactly . or $ (these are special variables)
 //   - Not starting with .. (invalid)
-func emitVar(v string, onVar func(string)) {
+func emitVar(v string, offset int, onVar func(string, int)) {
 	v = strings.TrimSpace(v)
 	if v == "." || v == "$" || strings.HasPrefix(v, "..") {
 		return
 	}
 
 	if strings.HasPrefix(v, ".") || strings.HasPrefix(v, "$.") {
-		onVar(v)
+		onVar(v, offset)
 		return
 	}
 
 	if strings.HasPrefix(v, "$") && len(v) > 1 && v[1] != '.' && v[1] != '$' {
-		onVar(v)
+		onVar(v, offset)
 	}
 }
@@ -0,0 +1,190 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+)
+
+// validateBlockBodies validates the body of every registered {{define}} or
+// {{block}} that no render call targets directly, independently of the call
+// sites that include it (see ValidateOptions.ValidateBlockBodies).
+//
+// A block is checked against the union of the variables its {{template}}
+// call sites pass, as collected by collectBlockContexts. A block with no call
+// site whose context resolves is checked against the global context instead,
+// approximated as the variables every render call provides, and everything it
+// reports is downgraded to a warning. Syntax errors are left out since the
+// template tree and orphaned-block passes already report them.
+func validateBlockBodies(
+	renderCalls []ast.RenderCall,
+	namedBlocks map[string][]NamedBlockEntry,
+	renderVarsByTemplate map[string][]ast.TemplateVar,
+	baseDir string,
+	templateRoot string,
+	funcMaps FuncMapRegistry,
+	opts contentOptions,
+	sink *resultSink,
+) []ValidationResult {
+	contexts := collectBlockContexts(namedBlocks, renderVarsByTemplate, baseDir, templateRoot, funcMaps)
+	global := commonRenderVars(renderCalls)
+
+	type workItem struct {
+		entry    NamedBlockEntry
+		varMap   map[string]ast.TemplateVar
+		fallback bool
+	}
+
+	names := make([]string, 0, len(namedBlocks))
+	for name := range namedBlocks {
+		if _, covered := renderVarsByTemplate[name]; !covered {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	var items []workItem
+	for _, name := range names {
+		varMap, ok := contexts[name]
+		if !ok {
+			varMap = global
+		}
+		for _, entry := range namedBlocks[name] {
+			if entry.foreign {
+				continue
+			}
+			items = append(items, workItem{entry: entry, varMap: varMap, fallback: !ok})
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	return runWorkers(len(items), sink, func(chunk []int) []ValidationResult {
+		var errs []ValidationResult
+		for _, i := range chunk {
+			item := items[i]
+			for _, r := range validateTemplateContent(
+				item.entry.Content,
+				item.varMap,
+				false,
+				item.entry.TemplatePath,
+				baseDir,
+				templateRoot,
+				item.entry.Line,
+				namedBlocks,
+				funcMaps,
+				opts,
+				nil,
+			) {
+				if r.Severity == SeveritySyntax {
+					continue
+				}
+				if item.fallback && r.Severity == "error" {
+					r.Severity = "warning"
+				}
+				errs = append(errs, r)
+			}
+		}
+		return errs
+	})
+}
+
+// collectBlockContexts maps each registered block name to the union of the
+// variables passed by its {{template}} call sites. The search starts at the
+// templates targeted by render calls, whose context is known, and follows
+// includes breadth-first: a block becomes a caller itself, with its union so
+// far, once one of its call sites resolves. Call sites whose context argument
+// cannot be resolved, or that pass no data, contribute nothing.
+func collectBlockContexts(
+	namedBlocks map[string][]NamedBlockEntry,
+	renderVarsByTemplate map[string][]ast.TemplateVar,
+	baseDir string,
+	templateRoot string,
+	funcMaps FuncMapRegistry,
+) map[string]map[string]ast.TemplateVar {
+	type caller struct {
+		name    string
+		content string
+		varMap  map[string]ast.TemplateVar
+	}
+
+	var queue []caller
+	for name, vars := range renderVarsByTemplate {
+		content, err := os.ReadFile(filepath.Join(baseDir, templateRoot, name))
+		if err != nil {
+			if entries := namedBlocks[name]; len(entries) > 0 {
+				queue = append(queue, caller{name, entries[0].Content, buildVarMap(vars)})
+			}
+			continue
+		}
+		queue = append(queue, caller{name, string(content), buildVarMap(vars)})
+	}
+
+	contexts := make(map[string]map[string]ast.TemplateVar)
+	visited := make(map[string]bool)
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+
+		for _, ref := range ExtractTemplateRefs(c.content) {
+			if _, ok := namedBlocks[ref.Name]; !ok || ref.Context == "" {
+				continue
+			}
+			ps := buildScopeAtPosition(c.content, c.varMap, c.name, 0, ref.Line, ref.Column, namedBlocks, funcMaps)
+			if ps == nil {
+				continue
+			}
+			partialScope := resolvePartialScope(ref.Context, ps.ScopeStack, c.varMap, funcMaps)
+			passed := buildPartialVarMap(ref.Context, partialScope, ps.ScopeStack, c.varMap)
+			if len(passed) == 0 {
+				continue
+			}
+
+			union := contexts[ref.Name]
+			if union == nil {
+				union = make(map[string]ast.TemplateVar, len(passed))
+				contexts[ref.Name] = union
+			}
+			for k, v := range passed {
+				if _, ok := union[k]; !ok {
+					union[k] = v
+				}
+			}
+		}
+
+		// A block is enqueued once, the first time one of its call sites
+		// resolves. The union map is shared, so call sites found before the
+		// block is dequeued still reach its callees.
+		for name, union := range contexts {
+			if visited[name] {
+				continue
+			}
+			visited[name] = true
+			for _, entry := range namedBlocks[name] {
+				queue = append(queue, caller{name, entry.Content, union})
+			}
+		}
+	}
+	return contexts
+}
+
+// commonRenderVars returns the variables every render call provides, such as
+// the context file's global variables, which are merged into each call.
+func commonRenderVars(renderCalls []ast.RenderCall) map[string]ast.TemplateVar {
+	if len(renderCalls) == 0 {
+		return nil
+	}
+	common := buildVarMap(renderCalls[0].Vars)
+	for _, rc := range renderCalls[1:] {
+		vars := buildVarMap(rc.Vars)
+		for name := range common {
+			if _, ok := vars[name]; !ok {
+				delete(common, name)
+			}
+		}
+	}
+	return common
+}
@@ -0,0 +1,52 @@
+package validator
+
+// markBlockOverrides flags every {{define}} entry in registry that shares its
+// name with a {{block}}. With html/template inheritance the define, loaded
+// into the same set as the block, replaces the block's default body.
+func markBlockOverrides(registry map[string][]NamedBlockEntry) {
+	for _, entries := range registry {
+		hasBlock := false
+		for _, e := range entries {
+			hasBlock = hasBlock || e.IsBlock
+		}
+		for i := range entries {
+			entries[i].OverridesBlock = hasBlock && !entries[i].IsBlock
+		}
+	}
+}
+
+// resolveBlockOverride returns the entries a {{template}} or {{block}} call
+// resolves to: the overriding defines when markBlockOverrides found any, and
+// every entry otherwise.
+func resolveBlockOverride(entries []NamedBlockEntry) []NamedBlockEntry {
+	var overrides []NamedBlockEntry
+	for _, e := range entries {
+		if e.OverridesBlock {
+			overrides = append(overrides, e)
+		}
+	}
+	if len(overrides) == 0 {
+		return entries
+	}
+	return overrides
+}
+
+// dropBlockOverrideDuplicates removes duplicate reports for names declared
+// exactly once as a block and once as a define, which is an override rather
+// than a conflict.
+func dropBlockOverrideDuplicates(dups []NamedBlockDuplicateError) []NamedBlockDuplicateError {
+	out := dups[:0]
+	for _, d := range dups {
+		blocks := 0
+		for _, e := range d.Entries {
+			if e.IsBlock {
+				blocks++
+			}
+		}
+		if len(d.Entries) == 2 && blocks == 1 {
+			continue
+		}
+		out = append(out, d)
+	}
+	return out
+}
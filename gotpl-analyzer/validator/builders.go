@@ -0,0 +1,75 @@
+package validator
+
+import (
+	"strings"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+)
+
+// Field returns a field of type typeName without nested fields. A slice or
+// map type string such as []string or map[string]int marks the field as a
+// collection with its key and element types.
+//
+//	validator.Field("CreatedAt", "time.Time")
+func Field(name, typeName string) ast.FieldInfo {
+	f := ast.FieldInfo{Name: name, TypeStr: typeName}
+	base := strings.TrimLeft(typeName, "*")
+	switch {
+	case strings.HasPrefix(base, "[]"):
+		f.IsSlice = true
+		f.ElemType = unwrapCollectionElemType(base)
+	case strings.HasPrefix(base, "map["):
+		f.IsMap = true
+		f.KeyType = unwrapMapKeyType(base)
+		f.ElemType = unwrapCollectionElemType(base)
+	}
+	return f
+}
+
+// Struct returns a variable of the struct type typeName with fields.
+//
+// Struct, Field, Slice and Map build the variable trees the analyzer
+// produces, with the collection flags it sets, for tests of packages built
+// on this one:
+//
+//	vars := []ast.TemplateVar{
+//		validator.Struct("user", "User",
+//			validator.Field("Name", "string"),
+//			validator.Field("Tags", "[]string"),
+//		),
+//		validator.Slice("orders", "Order", validator.Field("Total", "float64")),
+//		validator.Map("config", "string", "string"),
+//	}
+//	errs := validator.ValidateWith(content, vars, "page.html", nil)
+func Struct(name, typeName string, fields ...ast.FieldInfo) ast.TemplateVar {
+	return ast.TemplateVar{Name: name, TypeStr: typeName, Fields: fields}
+}
+
+// Slice returns a variable of type []elemType. As in the analyzer's output,
+// elemFields are the fields of the element type.
+//
+//	validator.Slice("users", "User", validator.Field("Name", "string"))
+func Slice(name, elemType string, elemFields ...ast.FieldInfo) ast.TemplateVar {
+	return ast.TemplateVar{
+		Name:     name,
+		TypeStr:  "[]" + elemType,
+		IsSlice:  true,
+		ElemType: elemType,
+		Fields:   elemFields,
+	}
+}
+
+// Map returns a variable of type map[keyType]elemType. As in the analyzer's
+// output, valueFields are the fields of the value type.
+//
+//	validator.Map("settings", "string", "Setting", validator.Field("Value", "string"))
+func Map(name, keyType, elemType string, valueFields ...ast.FieldInfo) ast.TemplateVar {
+	return ast.TemplateVar{
+		Name:     name,
+		TypeStr:  "map[" + keyType + "]" + elemType,
+		IsMap:    true,
+		KeyType:  keyType,
+		ElemType: elemType,
+		Fields:   valueFields,
+	}
+}
@@ -0,0 +1,152 @@
+package validator
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+)
+
+// CompletionItem is a single completion candidate for the cursor position
+// passed to CompletionsAt.
+type CompletionItem struct {
+	// Name is the text to insert: a field, method or root variable name, or a
+	// local variable including its leading "$".
+	Name string `json:"name"`
+
+	// Type is the string representation of the candidate's type.
+	Type string `json:"type"`
+
+	// Doc is the documentation comment of the candidate, if known.
+	Doc string `json:"doc,omitempty"`
+
+	// IsMethod reports whether the candidate is a method.
+	IsMethod bool `json:"isMethod,omitempty"`
+}
+
+// CompletionsAt returns the candidates for the field path that ends at the
+// byte offset in content, which must lie inside an action. The action does
+// not need to be closed yet, as is usual while typing.
+//
+// The scope at offset is replayed from the start of content the way
+// validation builds it. The path before the cursor is then split into a base
+// and a partial name: in {{ .User.Na }} the base .User is resolved and its
+// fields and methods starting with "Na" are returned. A bare "." lists the
+// fields of the current scope, or the root variables at the top level, and a
+// "$" prefix lists the local variables in scope. Items are sorted by name.
+func CompletionsAt(content string, vars []ast.TemplateVar, offset int) []CompletionItem {
+	if offset < 0 || offset > len(content) {
+		return nil
+	}
+
+	// Closing the action right after the cursor ignores everything past it and
+	// lets an unterminated action be completed.
+	truncated := content[:offset] + "}}"
+	line, col := newLineIndex(truncated).position(offset)
+	varMap := buildVarMap(vars)
+	ps := buildScopeAtPosition(truncated, varMap, "", 0, line, col, nil, nil)
+	if ps == nil {
+		return nil
+	}
+
+	path := completionPathBefore(ps.RawAction, ps.CursorOffset)
+	if path == "" {
+		return nil
+	}
+
+	dot := strings.LastIndexByte(path, '.')
+	if dot == -1 {
+		// "$" or "$na": a local variable name.
+		return filterCompletions(localCompletions(ps.Locals), path)
+	}
+	base, prefix := path[:dot], path[dot+1:]
+
+	_, dotContext := varMap["."]
+	atRoot := ps.ScopeStack[len(ps.ScopeStack)-1].IsRoot && !dotContext
+
+	var items []CompletionItem
+	switch {
+	case base == "" && atRoot:
+		items = rootCompletions(varMap)
+	case base == "":
+		items = fieldCompletions(createScopeFromExpression(".", ps.ScopeStack, varMap).Fields)
+	case base == "$":
+		items = rootCompletions(varMap)
+	case strings.HasPrefix(base, "$."):
+		scope := createScopeFromExpression(base[1:], ps.ScopeStack[:1], varMap)
+		if !scope.IsSlice && !scope.IsMap {
+			items = fieldCompletions(scope.Fields)
+		}
+	default:
+		result := InferExpressionType(base, ps.Vars, ps.ScopeStack, ps.Locals, nil, nil)
+		if result != nil && !result.IsSlice && !result.IsMap {
+			items = fieldCompletions(result.Fields)
+		}
+	}
+	return filterCompletions(items, prefix)
+}
+
+// completionPathBefore returns the field or variable path that ends at
+// offset in action, e.g. ".User.Na" for "if .User.Na" with the cursor at the
+// end.
+func completionPathBefore(action string, offset int) string {
+	start := offset
+	for start > 0 {
+		c := action[start-1]
+		if c != '.' && c != '$' && c != '_' && !isAlphaNum(c) {
+			break
+		}
+		start--
+	}
+	return action[start:offset]
+}
+
+// isAlphaNum reports whether c is an ASCII letter or digit.
+func isAlphaNum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+// rootCompletions lists the template's root variables.
+func rootCompletions(varMap map[string]ast.TemplateVar) []CompletionItem {
+	items := make([]CompletionItem, 0, len(varMap))
+	for name, v := range varMap {
+		items = append(items, CompletionItem{Name: name, Type: v.TypeStr, Doc: v.Doc})
+	}
+	return items
+}
+
+// fieldCompletions lists the fields and methods of a type.
+func fieldCompletions(fields []ast.FieldInfo) []CompletionItem {
+	items := make([]CompletionItem, 0, len(fields))
+	for _, f := range fields {
+		items = append(items, CompletionItem{
+			Name:     f.Name,
+			Type:     f.TypeStr,
+			Doc:      f.Doc,
+			IsMethod: strings.HasPrefix(f.TypeStr, "func(") || len(f.Params) > 0 || len(f.Returns) > 0,
+		})
+	}
+	return items
+}
+
+// localCompletions lists the $variables in scope.
+func localCompletions(locals map[string]ast.TemplateVar) []CompletionItem {
+	items := make([]CompletionItem, 0, len(locals))
+	for name, v := range locals {
+		if !strings.HasPrefix(name, "$") {
+			name = "$" + name
+		}
+		items = append(items, CompletionItem{Name: name, Type: v.TypeStr, Doc: v.Doc})
+	}
+	return items
+}
+
+// filterCompletions keeps the items whose name starts with prefix, sorted by
+// name.
+func filterCompletions(items []CompletionItem, prefix string) []CompletionItem {
+	items = slices.DeleteFunc(items, func(item CompletionItem) bool {
+		return !strings.HasPrefix(item.Name, prefix)
+	})
+	slices.SortFunc(items, func(a, b CompletionItem) int { return strings.Compare(a.Name, b.Name) })
+	return items
+}
@@ -0,0 +1,112 @@
+package validator
+
+import (
+	"strings"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+)
+
+// ComponentProp is a variable a component block declares it requires in a
+// props annotation such as {{/* props: Title string, Count int */}}.
+type ComponentProp struct {
+	// Name is the variable name, without a leading dot.
+	Name string `json:"name"`
+
+	// Type is the declared type, empty when omitted. It documents the prop
+	// and is not checked.
+	Type string `json:"type,omitempty"`
+}
+
+// markComponentProps sets the Props of every entry in registry whose body
+// starts with a props annotation using marker.
+func markComponentProps(registry map[string][]NamedBlockEntry, marker string) {
+	for _, entries := range registry {
+		for i := range entries {
+			entries[i].Props = parseComponentProps(entries[i].Content, marker)
+		}
+	}
+}
+
+// parseComponentProps parses the props annotation that is the first action of
+// a block body: a comment whose text starts with marker and a colon, followed
+// by comma-separated "Name Type" pairs. The type may be omitted.
+func parseComponentProps(content, marker string) []ComponentProp {
+	body := strings.TrimSpace(content)
+	if !strings.HasPrefix(body, "{{") {
+		return nil
+	}
+	end := strings.Index(body, "}}")
+	if end == -1 {
+		return nil
+	}
+	action := strings.TrimSpace(strings.Trim(body[2:end], "-"))
+	if !strings.HasPrefix(action, "/*") || !strings.HasSuffix(action, "*/") {
+		return nil
+	}
+	text := strings.TrimSpace(action[2 : len(action)-2])
+	rest, ok := strings.CutPrefix(text, marker)
+	if !ok {
+		return nil
+	}
+	rest, ok = strings.CutPrefix(strings.TrimSpace(rest), ":")
+	if !ok {
+		return nil
+	}
+
+	var props []ComponentProp
+	for decl := range strings.SplitSeq(rest, ",") {
+		fields := strings.Fields(decl)
+		if len(fields) == 0 {
+			continue
+		}
+		prop := ComponentProp{Name: strings.TrimPrefix(fields[0], ".")}
+		if len(fields) > 1 {
+			prop.Type = strings.Join(fields[1:], " ")
+		}
+		props = append(props, prop)
+	}
+	return props
+}
+
+// declaredProps returns the props of the first annotated entry. Entries of
+// the same name from the file being validated are merged in again without
+// props, so the annotation is looked up rather than taken from entries[0].
+func declaredProps(entries []NamedBlockEntry) []ComponentProp {
+	for _, e := range entries {
+		if len(e.Props) > 0 {
+			return e.Props
+		}
+	}
+	return nil
+}
+
+// missingProps returns the props that the context of a {{template}} call,
+// given as the var map the component is validated with, does not provide.
+// A context of unknown shape, such as a map without known keys, provides
+// every prop, while nil data provides none.
+func missingProps(props []ComponentProp, partialVarMap map[string]ast.TemplateVar, nilData bool) []ComponentProp {
+	provided := make(map[string]bool)
+	switch dot, ok := partialVarMap["."]; {
+	case nilData:
+		// Called without a context: nothing is provided.
+	case ok:
+		if len(dot.Fields) == 0 {
+			return nil
+		}
+		for _, f := range dot.Fields {
+			provided[f.Name] = true
+		}
+	default:
+		for name := range partialVarMap {
+			provided[name] = true
+		}
+	}
+
+	var missing []ComponentProp
+	for _, p := range props {
+		if !provided[p.Name] {
+			missing = append(missing, p)
+		}
+	}
+	return missing
+}
@@ -3,6 +3,7 @@ package validator
 import (
 	"fmt"
 	"maps"
+	"slices"
 	"strings"
 
 	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
@@ -52,40 +53,137 @@ func ValidateTemplateContent(
 	registry map[string][]NamedBlockEntry,
 	funcMaps ...FuncMapRegistry,
 ) []ValidationResult {
-	effectiveFuncMaps := optionalFuncMapRegistry(funcMaps...)
+	return validateTemplateContent(content, varMap, false, templateName, baseDir, templateRoot, lineOffset, registry, optionalFuncMapRegistry(funcMaps...), contentOptions{}, nil)
+}
+
+// validateTemplateContent is ValidateTemplateContent with the settings of
+// ValidateOptions that apply to a single template and nilData set when the
+// template is executed with nil data. onAction is passed on to
+// validateTemplateContentWithRegistry.
+func validateTemplateContent(
+	content string,
+	varMap map[string]ast.TemplateVar,
+	nilData bool,
+	templateName string,
+	baseDir, templateRoot string,
+	lineOffset int,
+	registry map[string][]NamedBlockEntry,
+	funcMaps FuncMapRegistry,
+	opts contentOptions,
+	onAction func(RuleAction),
+) []ValidationResult {
 	// Merge once at the entry point. All recursive calls receive this merged
 	// registry directly and skip the merge entirely.
 	effectiveRegistry := mergeNamedBlockRegistry(registry, content, templateName)
-	return validateTemplateContentWithRegistry(content, varMap, templateName, baseDir, templateRoot, lineOffset, effectiveRegistry, effectiveFuncMaps)
+	return validateTemplateContentWithRegistry(content, varMap, nilData, templateName, baseDir, templateRoot, lineOffset, effectiveRegistry, funcMaps, opts, []includeStep{{name: templateName}}, onAction)
+}
+
+// contentOptions are the settings of ValidateOptions that change the results
+// of validating a single template. The zero value is what the content
+// validation functions without options use.
+type contentOptions struct {
+	// dynamicNameSeverity is ValidateOptions.DynamicTemplateNameSeverity.
+	dynamicNameSeverity string
+
+	// warnShadowing is ValidateOptions.WarnShadowing.
+	warnShadowing bool
+
+	// securityLints is ValidateOptions.SecurityLints.
+	securityLints bool
+
+	// defineIsFileEntry is ValidateOptions.DefineIsFileEntry.
+	defineIsFileEntry bool
+
+	// actions, when non-nil, records the actions of every template file and
+	// named block validated on its own, for the rules run after validation.
+	actions *ruleActions
+}
+
+// contentOptions returns the settings of o that apply to a single template.
+func (o ValidateOptions) contentOptions() contentOptions {
+	return contentOptions{
+		dynamicNameSeverity: o.DynamicTemplateNameSeverity,
+		warnShadowing:       o.WarnShadowing,
+		securityLints:       o.SecurityLints,
+		defineIsFileEntry:   o.DefineIsFileEntry,
+	}
 }
 
 // validateTemplateContentWithRegistry is the internal implementation that
 // accepts a pre-merged registry. validateTemplateCall passes this registry
 // directly to recursive ValidateTemplateContent calls, avoiding the
 // O(registry + content) re-merge cost on every partial invocation.
+//
+// nilData is set when the template is executed with nil data, so every
+// reference to a root field is reported; see NilDataTemplates.
+//
+// includePath lists the templates currently being expanded, outermost first,
+// and is used to detect {{template}} inclusion cycles.
+//
+// onAction, when non-nil, is called with the scope stack in effect for every
+// action other than {{end}} and comments outside {{define}} and {{block}}
+// bodies; see ruleActions.
 func validateTemplateContentWithRegistry(
 	content string,
 	varMap map[string]ast.TemplateVar,
+	nilData bool,
 	templateName string,
 	baseDir, templateRoot string,
 	lineOffset int,
 	effectiveRegistry map[string][]NamedBlockEntry,
 	effectiveFuncMaps FuncMapRegistry,
+	opts contentOptions,
+	includePath []includeStep,
+	onAction func(RuleAction),
 ) []ValidationResult {
 	var errors []ValidationResult
 
 	// Initialize scope stack with root scope
 	var scopeStack []ScopeType
 	rootScope := buildRootScope(varMap)
+	rootScope.NilData = nilData
+	rootScope.suggestions = &suggestionCandidates{}
+	// Below the render target, nil data only comes from a {{template}} call
+	// without a context argument, which included the last template on the
+	// path.
+	if nilData && len(includePath) > 1 {
+		rootScope.nilInclude = includePath[len(includePath)-1].name
+	}
 	scopeStack = append(scopeStack, rootScope)
 
 	defineSkipDepth := 0
 	openingActions := []string{"root"}
+	// openingLines holds the line of the action that opened each entry of
+	// openingActions; {{else}} branches keep the line of the original opener.
+	openingLines := []int{0}
+
+	// Unbalanced delimiters are reported up front; the actions between them
+	// are still validated.
+	balance, unclosed := delimiterBalance(content, leftDelim, rightDelim, lineOffset)
+	for _, r := range balance {
+		r.Template = templateName
+		errors = append(errors, r)
+	}
 
+	lines := newLineIndex(content)
 	cur := 0
 	lineNum := 0
 
+	// A rex:ignore comment arms the suppression of ignoreRules; the results
+	// of the next action, appended from ignoreFrom on, are filtered once it
+	// has been processed.
+	var ignoreRules []string
+	ignoreArmed, ignoring := false, false
+	ignoreFrom := 0
+	flushIgnore := func() {
+		if ignoring {
+			errors = append(errors[:ignoreFrom], suppressResults(errors[ignoreFrom:], ignoreRules)...)
+			ignoring = false
+		}
+	}
+
 	for cur < len(content) {
+		flushIgnore()
 		openRel := strings.Index(content[cur:], "{{")
 		if openRel == -1 {
 			break
@@ -95,15 +193,14 @@ func validateTemplateContentWithRegistry(
 		lineNum += strings.Count(content[cur:openIdx], "\n")
 		actualLineNum := lineNum + lineOffset
 
+		if unclosed[openIdx] {
+			lineNum += strings.Count(content[openIdx:openIdx+2], "\n")
+			cur = openIdx + 2
+			continue
+		}
+
 		closeRel := strings.Index(content[openIdx:], "}}")
 		if closeRel == -1 {
-			errors = append(errors, ValidationResult{
-				Template: templateName,
-				Line:     actualLineNum,
-				Column:   0,
-				Message:  fmt.Sprintf("Unclosed action tag '{{' at line %d — add the closing '}}'", actualLineNum),
-				Severity: "error",
-			})
 			break
 		}
 		closeIdx := openIdx + closeRel
@@ -124,8 +221,7 @@ func validateTemplateContentWithRegistry(
 			contentEnd--
 		}
 
-		lastNewline := strings.LastIndexByte(content[:openIdx], '\n')
-		col := contentStart - lastNewline
+		col := contentStart - lines.lineStart(openIdx) + 1
 
 		var action string
 		if contentStart < contentEnd {
@@ -136,9 +232,15 @@ func validateTemplateContentWithRegistry(
 		cur = closeIdx + 2
 
 		if strings.Contains(action, "/*") && strings.Contains(action, "*/") {
+			if rules, ok := parseIgnoreDirective(action); ok {
+				ignoreRules, ignoreArmed = rules, true
+			}
 			lineNum += lineNumInside
 			continue
 		}
+		if ignoreArmed {
+			ignoreArmed, ignoring, ignoreFrom = false, true, len(errors)
+		}
 
 		words := strings.Fields(action)
 		first := ""
@@ -162,20 +264,39 @@ func validateTemplateContentWithRegistry(
 
 		isElse := first == "else"
 		var elseAction string
+		openLine := actualLineNum
 
 		if isElse {
 			if len(scopeStack) <= 1 {
 				errors = append(errors, ValidationResult{
 					Template: templateName,
 					Line:     actualLineNum,
-					Column:   0,
+					Column:   col,
 					Message:  fmt.Sprintf("{{else}} at line %d has no matching opening block", actualLineNum),
-					Severity: "error",
+					Severity: SeveritySyntax,
+					Rule:     "syntax",
+				})
+				lineNum += lineNumInside
+				continue
+			}
+			// {{else}} is only meaningful after if/with/range. Anything else
+			// (typically a second {{else}} in the same chain) is rejected by
+			// Go's parser, so surface it here instead of at render time.
+			opener := openingActions[len(openingActions)-1]
+			openLine = openingLines[len(openingLines)-1]
+			if opener != "if" && opener != "with" && opener != "range" {
+				errors = append(errors, ValidationResult{
+					Template: templateName,
+					Line:     actualLineNum,
+					Column:   col,
+					Message:  fmt.Sprintf("{{else}} is not valid after {{%s}} (block opened at line %d)", opener, openLine),
+					Severity: SeveritySyntax,
+					Rule:     "syntax",
 				})
-				break
 			}
 			scopeStack = scopeStack[:len(scopeStack)-1]
 			openingActions = openingActions[:len(openingActions)-1]
+			openingLines = openingLines[:len(openingLines)-1]
 			if len(words) > 1 {
 				elseAction = words[1]
 				if idx := strings.IndexByte(elseAction, '('); idx != -1 {
@@ -187,43 +308,74 @@ func validateTemplateContentWithRegistry(
 				errors = append(errors, ValidationResult{
 					Template: templateName,
 					Line:     actualLineNum,
-					Column:   0,
+					Column:   col,
 					Message:  fmt.Sprintf("unexpected {{end}} at line %d — no open block to close", actualLineNum),
-					Severity: "error",
+					Severity: SeveritySyntax,
+					Rule:     "syntax",
 				})
-				break
+				lineNum += lineNumInside
+				continue
 			}
 			scopeStack = scopeStack[:len(scopeStack)-1]
 			openingActions = openingActions[:len(openingActions)-1]
+			openingLines = openingLines[:len(openingLines)-1]
 			lineNum += lineNumInside
 			continue
 		}
 
+		if onAction != nil {
+			onAction(RuleAction{Text: action, Line: actualLineNum, Column: col, ScopeStack: slices.Clone(scopeStack)})
+		}
+
 		assignmentTargets := assignmentTargetSet(action)
 		errors = append(errors, validateActionFunctions(action, first, templateName, actualLineNum, col, effectiveFuncMaps)...)
-		extractVariablesFromAction(action, func(v string) {
-			if assignmentTargets[v] {
+		errors = append(errors, validateMethodArity(action, first, templateName, actualLineNum, col, scopeStack, varMap, effectiveFuncMaps)...)
+		errors = append(errors, validateEscaping(action, first, templateName, actualLineNum, col, scopeStack, varMap, effectiveFuncMaps, opts.securityLints)...)
+		// A variable repeated within one action, as in {{if or .A (and .A .B)}},
+		// is validated once so a bad reference is reported only once.
+		seenVars := make(map[string]bool)
+		extractVariablesFromAction(action, func(v string, offset int) {
+			if assignmentTargets[v] || seenVars[v] {
 				return
 			}
-			if err := validateVariableInScope(v, scopeStack, varMap); err != nil {
+			seenVars[v] = true
+			if err := validateVariableInScope(v, scopeStack, varMap, opts.warnShadowing); err != nil {
 				err.Template = templateName
-				err.Line = actualLineNum
-				err.Column = col + strings.Index(action, v)
-				if err.Column < col {
-					err.Column = col
-				}
+				err.setRange(action, offset, actualLineNum, col)
 				errors = append(errors, *err)
 			}
 		})
+		extractParenChains(action, func(inner string, chain []string, offset int) {
+			if err := validateParenChain(inner, chain, scopeStack, varMap, effectiveFuncMaps); err != nil {
+				err.Template = templateName
+				err.setRange(action, offset, actualLineNum, col)
+				errors = append(errors, *err)
+			}
+		})
+
+		// An unquoted name is rejected by Go's parser; report it instead of
+		// looking up a template by that name.
+		name, nameOffset := unquotedTemplateName(action, first)
+		if name != "" {
+			errors = append(errors, ValidationResult{
+				Template: templateName,
+				Line:     actualLineNum,
+				Column:   col + nameOffset,
+				Variable: name,
+				Message:  fmt.Sprintf("Template/block name must be a quoted string, got %q", name),
+				Severity: SeveritySyntax,
+				Rule:     "syntax",
+			})
+		}
 
-		if first == "block" {
+		if first == "block" && name == "" {
 			syntheticAction := "template " + strings.TrimSpace(strings.TrimPrefix(action, "block"))
 			parts := parseTemplateAction(syntheticAction)
 			if len(parts) >= 2 {
 				blockName := parts[0]
 				if !hasTemplateCallForBlock(content, blockName) {
 					// Pass effectiveRegistry directly — no re-merge.
-					partialErrs := validateTemplateCallWithRegistry(syntheticAction, scopeStack, varMap, actualLineNum, col, templateName, baseDir, templateRoot, effectiveRegistry, effectiveFuncMaps)
+					partialErrs := validateTemplateCallWithRegistry(syntheticAction, scopeStack, varMap, actualLineNum, col, templateName, baseDir, templateRoot, effectiveRegistry, effectiveFuncMaps, opts, includePath)
 					errors = append(errors, partialErrs...)
 				}
 			}
@@ -256,6 +408,7 @@ func validateTemplateContentWithRegistry(
 				}
 				scopeStack = append(scopeStack, top)
 				openingActions = append(openingActions, "else")
+				openingLines = append(openingLines, openLine)
 				lineNum += lineNumInside
 				continue
 			}
@@ -274,6 +427,7 @@ func validateTemplateContentWithRegistry(
 			}
 			scopeStack = append(scopeStack, newScope)
 			openingActions = append(openingActions, "range")
+			openingLines = append(openingLines, openLine)
 
 		case "with":
 			withExpr := strings.TrimSpace(strings.TrimPrefix(exprToParse, "with"))
@@ -287,6 +441,7 @@ func validateTemplateContentWithRegistry(
 			}
 			scopeStack = append(scopeStack, newScope)
 			openingActions = append(openingActions, "with")
+			openingLines = append(openingLines, openLine)
 
 		case "if":
 			top := ScopeType{}
@@ -300,28 +455,31 @@ func validateTemplateContentWithRegistry(
 			}
 			scopeStack = append(scopeStack, top)
 			openingActions = append(openingActions, "if")
+			openingLines = append(openingLines, openLine)
 		}
 
 		// Pass effectiveRegistry directly to avoid re-merge inside the recursive call.
-		if first == "template" {
-			partialErrs := validateTemplateCallWithRegistry(action, scopeStack, varMap, actualLineNum, col, templateName, baseDir, templateRoot, effectiveRegistry, effectiveFuncMaps)
+		if first == "template" && name == "" {
+			partialErrs := validateTemplateCallWithRegistry(action, scopeStack, varMap, actualLineNum, col, templateName, baseDir, templateRoot, effectiveRegistry, effectiveFuncMaps, opts, includePath)
 			errors = append(errors, partialErrs...)
 		}
 
 		lineNum += lineNumInside
 	}
+	flushIgnore()
 
 	if len(scopeStack) > 1 {
 		unclosed := make([]string, 0, len(openingActions)-1)
-		for _, a := range openingActions[1:] {
-			unclosed = append(unclosed, "{{"+a+"}}")
+		for i, a := range openingActions[1:] {
+			unclosed = append(unclosed, fmt.Sprintf("{{%s}} (line %d)", a, openingLines[i+1]))
 		}
 		errors = append(errors, ValidationResult{
 			Template: templateName,
 			Line:     lineNum + lineOffset,
 			Column:   0,
 			Message:  fmt.Sprintf("%d unclosed scope block(s) at end of template — missing {{end}} for: %s", len(scopeStack)-1, strings.Join(unclosed, ", ")),
-			Severity: "error",
+			Severity: SeveritySyntax,
+			Rule:     "syntax",
 		})
 	}
 
@@ -332,7 +490,6 @@ func validateTemplateContentWithRegistry(
 // {{template "name" ...}} call (not a {{block}}) for the given block name.
 func hasTemplateCallForBlock(content, blockName string) bool {
 	// Look for {{ template "blockName" ... }} patterns, excluding {{ block "blockName" ... }}.
-	searchQuoted := `"` + blockName + `"`
 	idx := 0
 	for idx < len(content) {
 		pos := strings.Index(content[idx:], "{{")
@@ -352,7 +509,7 @@ func hasTemplateCallForBlock(content, blockName string) bool {
 		inner = strings.TrimSuffix(inner, "-")
 		inner = strings.TrimSpace(inner)
 
-		if strings.HasPrefix(inner, "template ") && strings.Contains(inner, searchQuoted) {
+		if strings.HasPrefix(inner, "template ") && parseTemplateAction(inner)[0] == blockName {
 			return true
 		}
 		idx = closePos + 2
@@ -379,6 +536,10 @@ func optionalFuncMapRegistry(funcMaps ...FuncMapRegistry) FuncMapRegistry {
 // copy of the registry; entries from the incoming registry are referenced, not
 // deep-copied, which keeps allocation proportional to the number of NEW blocks
 // rather than the total registry size.
+//
+// This is the per-call overlay that makes blocks an entry template defines
+// for its own use resolve even when the file was not part of the scanned
+// registry, e.g. because an ignore rule excluded it.
 func mergeNamedBlockRegistry(registry map[string][]NamedBlockEntry, content, templateName string) map[string][]NamedBlockEntry {
 	// Fast path: content has no define/block actions — return registry as-is.
 	// This avoids the O(registry) clone for the vast majority of templates.
@@ -405,7 +566,16 @@ func mergeNamedBlockRegistry(registry map[string][]NamedBlockEntry, content, tem
 		} else {
 			combined := make([]NamedBlockEntry, len(existing), len(existing)+len(entries))
 			copy(combined, existing)
-			merged[name] = append(combined, entries...)
+			for _, e := range entries {
+				// A block the scan already found in this same file is kept
+				// once, with the flags the scan set on it.
+				if !slices.ContainsFunc(existing, func(x NamedBlockEntry) bool {
+					return x.TemplatePath == e.TemplatePath && x.Line == e.Line && x.Col == e.Col && x.Content == e.Content
+				}) {
+					combined = append(combined, e)
+				}
+			}
+			merged[name] = combined
 		}
 	}
 	return merged
@@ -551,55 +721,51 @@ func registerRangeLocals(frame *ScopeType, names []string, rangeExpr string, sco
 	}
 }
 
-var templateBuiltins = map[string]bool{
-	"and":      true,
-	"or":       true,
-	"not":      true,
-	"eq":       true,
-	"ne":       true,
-	"lt":       true,
-	"le":       true,
-	"gt":       true,
-	"ge":       true,
-	"index":    true,
-	"slice":    true,
-	"len":      true,
-	"print":    true,
-	"printf":   true,
-	"println":  true,
-	"html":     true,
-	"js":       true,
-	"urlquery": true,
-	"dict":     true,
-	"add":      true,
-	"sub":      true,
-	"mul":      true,
-	"div":      true,
-	"mod":      true,
-	"call":     true,
-}
+// templateBuiltins are the functions accepted without a FuncMap entry: the
+// text/template builtins plus common helpers assumed to be registered.
+var templateBuiltins = func() map[string]bool {
+	m := map[string]bool{
+		"dict": true,
+		"add":  true,
+		"sub":  true,
+		"mul":  true,
+		"div":  true,
+		"mod":  true,
+	}
+	for _, name := range ast.TemplateBuiltins {
+		m[name] = true
+	}
+	return m
+}()
 
 func validateActionFunctions(action, first, templateName string, line, col int, funcMaps FuncMapRegistry) []ValidationResult {
+	expr, ok := actionPipeline(action, first)
+	if !ok {
+		return nil
+	}
+	return validateExpressionFunctions(expr, templateName, line, col, funcMaps)
+}
+
+// actionPipeline strips the control keyword from an action and returns the
+// pipeline it evaluates. It reports false for actions without a pipeline to
+// check ({{template}}, {{define}}, {{end}}, bare {{else}}, ...).
+func actionPipeline(action, first string) (string, bool) {
 	trimmed := strings.TrimSpace(action)
 	if first == "template" || first == "block" || first == "define" || first == "end" {
-		return nil
+		return "", false
 	}
 	if first == "else" {
-		switch {
-		case strings.HasPrefix(trimmed, "else if "):
-			return validateExpressionFunctions(strings.TrimSpace(strings.TrimPrefix(trimmed, "else if")), templateName, line, col, funcMaps)
-		case strings.HasPrefix(trimmed, "else with "):
-			return validateExpressionFunctions(strings.TrimSpace(strings.TrimPrefix(trimmed, "else with")), templateName, line, col, funcMaps)
-		case strings.HasPrefix(trimmed, "else range "):
-			return validateExpressionFunctions(strings.TrimSpace(strings.TrimPrefix(trimmed, "else range")), templateName, line, col, funcMaps)
-		default:
-			return nil
+		for _, keyword := range []string{"if", "with", "range"} {
+			if strings.HasPrefix(trimmed, "else "+keyword+" ") {
+				return strings.TrimSpace(strings.TrimPrefix(trimmed, "else "+keyword)), true
+			}
 		}
+		return "", false
 	}
 	if first == "if" || first == "with" || first == "range" {
-		return validateExpressionFunctions(strings.TrimSpace(strings.TrimPrefix(trimmed, first)), templateName, line, col, funcMaps)
+		return strings.TrimSpace(strings.TrimPrefix(trimmed, first)), true
 	}
-	return validateExpressionFunctions(trimmed, templateName, line, col, funcMaps)
+	return trimmed, true
 }
 
 func validateExpressionFunctions(expr, templateName string, line, col int, funcMaps FuncMapRegistry) []ValidationResult {
@@ -621,6 +787,7 @@ func validateExpressionFunctions(expr, templateName string, line, col int, funcM
 			Variable: candidate.name,
 			Message:  fmt.Sprintf("Template function %q is not defined in the current FuncMap", candidate.name),
 			Severity: "error",
+			Rule:     "undefined-function",
 		})
 	}
 	return errors
@@ -0,0 +1,125 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+)
+
+// leftDelim and rightDelim are the action delimiters validation scans
+// templates for. They are text/template's defaults, since templates using
+// Delims are not supported.
+const (
+	leftDelim  = "{{"
+	rightDelim = "}}"
+)
+
+// CheckDelimiterBalance reports every open delimiter of content that is not
+// closed before the next one, or at all, as syntax results with 1-based lines
+// and columns. Delimiters inside quoted strings and comments of an action do
+// not count. A close delimiter outside an action is plain text to
+// text/template, as in inline JavaScript or CSS, and is not reported.
+// Template is left empty for the caller to set.
+func CheckDelimiterBalance(content, open, close string) []ValidationResult {
+	results, _ := delimiterBalance(content, open, close, 1)
+	return results
+}
+
+// delimiterBalance is CheckDelimiterBalance for content starting at line
+// lineOffset that also returns the offsets of the unclosed open delimiters,
+// so that validation can skip them and go on with the well-formed actions
+// after them.
+func delimiterBalance(content, open, close string, lineOffset int) ([]ValidationResult, map[int]bool) {
+	if open == "" || close == "" {
+		return nil, nil
+	}
+
+	var results []ValidationResult
+	var unclosed map[int]bool
+	var lines lineIndex
+	report := func(offset int, message func(line int) string) {
+		if lines == nil {
+			lines = newLineIndex(content)
+		}
+		line, col := lines.position(offset)
+		line += lineOffset - 1
+		results = append(results, ValidationResult{
+			Line:     line,
+			Column:   col,
+			Message:  message(line),
+			Severity: SeveritySyntax,
+			Rule:     "syntax",
+		})
+	}
+
+	cur := 0
+	for cur < len(content) {
+		openRel := strings.Index(content[cur:], open)
+		if openRel == -1 {
+			break
+		}
+
+		openIdx := cur + openRel
+		end := actionEnd(content, openIdx+len(open), open, close)
+		if end == -1 {
+			report(openIdx, func(line int) string {
+				return fmt.Sprintf("Unclosed action tag '%s' at line %d — add the closing '%s'", open, line, close)
+			})
+			if unclosed == nil {
+				unclosed = make(map[int]bool)
+			}
+			unclosed[openIdx] = true
+			cur = openIdx + len(open)
+			continue
+		}
+		cur = end + len(close)
+	}
+	return results, unclosed
+}
+
+// actionEnd returns the offset of the close delimiter ending the action whose
+// text starts at start, or -1 when another open delimiter or the end of
+// content comes first. Quoted strings and a comment are skipped.
+func actionEnd(content string, start int, open, close string) int {
+	i := start
+	if i < len(content) && content[i] == '-' {
+		i++
+	}
+	for i < len(content) && isWhitespace(content[i]) {
+		i++
+	}
+	if strings.HasPrefix(content[i:], "/*") {
+		endRel := strings.Index(content[i+2:], "*/")
+		if endRel == -1 {
+			return -1
+		}
+		i += 2 + endRel + 2
+	}
+
+	for i < len(content) {
+		switch {
+		case strings.HasPrefix(content[i:], close):
+			return i
+		case strings.HasPrefix(content[i:], open):
+			return -1
+		}
+		switch c := content[i]; c {
+		case '"', '\'':
+			// Interpreted strings and characters end at the line.
+			i++
+			for i < len(content) && content[i] != c && content[i] != '\n' {
+				if content[i] == '\\' {
+					i++
+				}
+				i++
+			}
+		case '`':
+			endRel := strings.IndexByte(content[i+1:], '`')
+			if endRel == -1 {
+				return -1
+			}
+			i += endRel + 1
+		}
+		i++
+	}
+	return -1
+}
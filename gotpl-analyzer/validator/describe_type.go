@@ -0,0 +1,20 @@
+package validator
+
+import (
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"golang.org/x/tools/go/packages"
+)
+
+// DescribeType returns the template field tree of typeName, written as in a
+// context file ("handlers.User", "[]*models.Post"), as the packages loaded by
+// ast.LoadPackages expose it. The type is resolved like render call context
+// types, so no render call has to pass a value of it.
+func DescribeType(pkgs []*packages.Package, typeName string) (ast.TemplateVar, error) {
+	return DescribeTypeWithConfig(pkgs, typeName, ast.DefaultConfig)
+}
+
+// DescribeTypeWithConfig is DescribeType honouring config's IncludeUnexported,
+// WarnDeprecated and BuildFlags, as the analysis of render calls does.
+func DescribeTypeWithConfig(pkgs []*packages.Package, typeName string, config ast.AnalysisConfig) (ast.TemplateVar, error) {
+	return ast.DescribeType(pkgs, typeName, config)
+}
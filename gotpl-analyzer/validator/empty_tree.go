@@ -0,0 +1,75 @@
+package validator
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+)
+
+// hasTemplateFiles reports whether the tree under root contains at least one
+// template file not excluded by ignore. It stops at the first one found.
+func hasTemplateFiles(root string, ignore *GitignoreMatcher) bool {
+	found := false
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if ignore.Match(path, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && IsFileBasedPartial(path) {
+			found = true
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	return found
+}
+
+// anyRenderTargetExists reports whether a file exists under root for the
+// template of any of renderCalls, whatever its extension.
+func anyRenderTargetExists(renderCalls []ast.RenderCall, root string) bool {
+	for _, rc := range renderCalls {
+		if _, err := os.Stat(filepath.Join(root, rc.Template)); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// validateWithoutTemplates is validateWithRegistry for a template root with
+// no template files that no render call targets, as in a project that has
+// none yet. There is nothing to parse or walk, so only the render calls are
+// checked, one by one, without starting any workers.
+func validateWithoutTemplates(
+	renderCalls []ast.RenderCall,
+	funcMapRegistry FuncMapRegistry,
+	baseDir string,
+	templateRoot string,
+	opts ValidateOptions,
+) []ValidationResult {
+	renderCalls = FilterRenderCalls(renderCalls, opts.Only)
+	renderVarsByTemplate := MergeCallContexts(renderCalls)
+	nilDataTemplates := NilDataTemplates(renderCalls)
+	registry := map[string][]NamedBlockEntry{}
+
+	var results []ValidationResult
+	seen := make(map[string]bool)
+	for _, rc := range renderCalls {
+		if seen[rc.Template] || opts.sink.full() {
+			continue
+		}
+		seen[rc.Template] = true
+		errs := validateSafely(rc.Template, func() []ValidationResult {
+			return validateRenderTarget(rc, renderVarsByTemplate[rc.Template], nilDataTemplates[rc.Template], baseDir, templateRoot, registry, funcMapRegistry, opts.contentOptions())
+		})
+		results = append(results, opts.sink.add(linkRenderCall(errs, rc))...)
+	}
+	results = append(results, opts.sink.add(missingComposedTemplates(renderCalls, baseDir, templateRoot, registry))...)
+	return append(results, opts.sink.add(duplicateVarWarnings(renderCalls))...)
+}
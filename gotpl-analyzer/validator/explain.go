@@ -0,0 +1,115 @@
+package validator
+
+import (
+	"strings"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+)
+
+// ScopeExplanation describes the scope at a position in a template, as
+// returned by ExplainAt.
+type ScopeExplanation struct {
+	// Line and Column are the 1-based position that was explained. A position
+	// inside an action is explained at the action's opening {{, where the
+	// action is evaluated.
+	Line   int `json:"line"`
+	Column int `json:"column"`
+
+	// DotType is the type of "." at the position. It is empty at the top
+	// level of the render data, whose fields are the root variables.
+	DotType string `json:"dotType"`
+
+	// DotFields are the fields and methods of ".".
+	DotFields []ast.FieldInfo `json:"dotFields"`
+
+	// Scopes is the scope stack from the root down to the current scope.
+	Scopes []ScopeFrame `json:"scopes"`
+
+	// Locals are the $variables in scope, including those declared by range
+	// and with assignments.
+	Locals map[string]ast.TemplateVar `json:"locals"`
+}
+
+// ScopeFrame is one entry of ScopeExplanation.Scopes.
+type ScopeFrame struct {
+	// Opener is the action keyword that opened the frame, e.g. "range",
+	// "with" or "else if", or "root" for the template's top level.
+	Opener string `json:"opener"`
+
+	// TypeStr is the type of "." inside the frame, empty at the root.
+	TypeStr string `json:"type,omitempty"`
+
+	// IsSlice and IsMap report a frame whose "." is a collection, such as
+	// the {{else}} of a range.
+	IsSlice bool `json:"isSlice,omitempty"`
+	IsMap   bool `json:"isMap,omitempty"`
+
+	// ElemType and KeyType describe a collection "." further.
+	ElemType string `json:"elemType,omitempty"`
+	KeyType  string `json:"keyType,omitempty"`
+}
+
+// ExplainAt replays the scopes of content up to the 1-based line and col the
+// way validation builds them and describes what "." is there: its type and
+// fields, the scope stack that led to it and the local variables in scope.
+// It answers "why is .X invalid here" the way CompletionsAt answers "what
+// can I write here". A position outside content yields the zero value,
+// without any scope.
+func ExplainAt(content string, vars []ast.TemplateVar, line, col int) ScopeExplanation {
+	lines := newLineIndex(content)
+	if line < 1 || line > len(lines) || col < 1 {
+		return ScopeExplanation{}
+	}
+	offset := lines[line-1] + col - 1
+	lineEnd := len(content)
+	if line < len(lines) {
+		lineEnd = lines[line] - 1
+	}
+	if offset > lineEnd {
+		return ScopeExplanation{}
+	}
+
+	// An action is evaluated in the scope before it, so a position inside one
+	// moves to its {{.
+	if open := strings.LastIndex(content[:offset], "{{"); open != -1 && !strings.Contains(content[open:offset], "}}") {
+		offset = open
+	}
+	line, col = lines.position(offset)
+
+	// Probing with {{.}} at the position captures the scope there; everything
+	// after it is irrelevant.
+	varMap := buildVarMap(vars)
+	ps := buildScopeAtPosition(content[:offset]+"{{.}}", varMap, "", 0, line, col, nil, nil)
+	if ps == nil {
+		return ScopeExplanation{}
+	}
+
+	explanation := ScopeExplanation{
+		Line:   line,
+		Column: col,
+		Scopes: make([]ScopeFrame, 0, len(ps.ScopeStack)),
+		Locals: ps.Locals,
+	}
+	for i, frame := range ps.ScopeStack {
+		opener := frame.opener
+		if i == 0 {
+			opener = "root"
+		}
+		explanation.Scopes = append(explanation.Scopes, ScopeFrame{
+			Opener:   opener,
+			TypeStr:  frame.TypeStr,
+			IsSlice:  frame.IsSlice,
+			IsMap:    frame.IsMap,
+			ElemType: frame.ElemType,
+			KeyType:  frame.KeyType,
+		})
+	}
+
+	dot := ps.ScopeStack[len(ps.ScopeStack)-1]
+	explanation.DotType = dot.TypeStr
+	explanation.DotFields = dot.Fields
+	if explanation.DotFields == nil {
+		explanation.DotFields = []ast.FieldInfo{}
+	}
+	return explanation
+}
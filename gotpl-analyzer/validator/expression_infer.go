@@ -372,8 +372,20 @@ func (i expressionInferencer) inferFunctionCall(name string, rawArgs []templatep
 		return &ExpressionTypeResult{TypeStr: "int"}
 	case "print", "printf", "println", "html", "js", "urlquery":
 		return &ExpressionTypeResult{TypeStr: "string"}
-	case "eq", "ne", "lt", "le", "gt", "ge", "and", "or", "not":
+	case "eq", "ne", "lt", "le", "gt", "ge", "not":
 		return &ExpressionTypeResult{TypeStr: "bool"}
+	case "and", "or":
+		// and and or return one of their arguments, so the result type is
+		// only known when all of them agree.
+		if len(args) == 0 || args[0] == nil {
+			return nil
+		}
+		for _, arg := range args[1:] {
+			if arg == nil || arg.TypeStr != args[0].TypeStr {
+				return nil
+			}
+		}
+		return i.hydrateResult(args[0])
 	case "add", "sub", "mul", "div", "mod":
 		return inferArithmeticResult(args)
 	case "dict":
@@ -470,9 +482,15 @@ func (i expressionInferencer) collectionElementResult(current *ExpressionTypeRes
 		return nil
 	}
 	elemType = strings.TrimSpace(elemType)
+	// A slice's Fields describe its element type, so they carry over to the
+	// element (and to the inner slice of a [][]T).
+	var elemFields []ast.FieldInfo
+	if result.IsSlice {
+		elemFields = result.Fields
+	}
 	return i.hydrateResult(&ExpressionTypeResult{
 		TypeStr:  elemType,
-		Fields:   i.fieldsForType(elemType, nil),
+		Fields:   i.fieldsForType(elemType, elemFields),
 		IsSlice:  strings.HasPrefix(strings.TrimLeft(elemType, "*"), "[]"),
 		IsMap:    strings.HasPrefix(strings.TrimLeft(elemType, "*"), "map["),
 		ElemType: unwrapCollectionElemType(elemType),
@@ -0,0 +1,92 @@
+package validator
+
+import (
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+)
+
+// commentActionRe matches a whole {{/* ... */}} comment action.
+var commentActionRe = regexp.MustCompile(`(?s)\{\{-?\s*/\*.*?\*/\s*-?\}\}`)
+
+// FileEntryDefine reports whether content is a "define-only" template file
+// whose single top-level {{define "X"}} is named after the file's base name
+// without extension, e.g. {{define "layout"}} in layout.html. It returns the
+// define's body and the line of its {{define}} tag for use as a line offset.
+//
+// With html/template, executing such a file renders nothing because the file's
+// own top-level template is empty; renderers that follow the "file entry"
+// convention instead execute the define matching the file's base name. See
+// ast.AnalysisConfig.DefineIsFileEntry.
+//
+// Content outside the define may only be whitespace and comment actions.
+func FileEntryDefine(content, templateName string) (string, int, bool) {
+	registry := make(map[string][]NamedBlockEntry)
+	extractNamedTemplatesFromContent(content, "", templateName, registry)
+	if len(registry) != 1 {
+		return "", 0, false
+	}
+
+	base := path.Base(templateName)
+	entries, ok := registry[strings.TrimSuffix(base, path.Ext(base))]
+	if !ok || len(entries) != 1 {
+		return "", 0, false
+	}
+	entry := entries[0]
+
+	// Locate the {{define}} tag from its 1-based line and column.
+	start := 0
+	for range entry.Line - 1 {
+		start += strings.IndexByte(content[start:], '\n') + 1
+	}
+	start += entry.Col - 1
+
+	tagEnd := start + strings.Index(content[start:], "}}") + 2
+	tag := strings.TrimLeft(content[start+2:tagEnd-2], "- \t\r\n")
+	if !strings.HasPrefix(tag, "define") {
+		return "", 0, false // a top-level {{block}} renders on its own
+	}
+
+	bodyEnd := tagEnd + len(entry.Content)
+	endTagEnd := bodyEnd + strings.Index(content[bodyEnd:], "}}") + 2
+
+	outside := content[:start] + content[endTagEnd:]
+	if strings.TrimSpace(commentActionRe.ReplaceAllString(outside, "")) != "" {
+		return "", 0, false
+	}
+
+	return entry.Content, entry.Line, true
+}
+
+// validateDefineFileEntry validates the body of a define-only template file
+// as the file's effective content. ok is false when the file does not match
+// the convention and should be validated normally.
+func validateDefineFileEntry(
+	templatePath string,
+	vars []ast.TemplateVar,
+	nilData bool,
+	templateName string,
+	baseDir, templateRoot string,
+	registry map[string][]NamedBlockEntry,
+	funcMaps FuncMapRegistry,
+	opts contentOptions,
+) ([]ValidationResult, bool) {
+	content, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, false
+	}
+
+	body, line, ok := FileEntryDefine(string(content), templateName)
+	if !ok {
+		return nil, false
+	}
+
+	effectiveRegistry := mergeNamedBlockRegistry(registry, string(content), templateName)
+	return validateTemplateContentWithRegistry(
+		body, buildVarMap(vars), nilData, templateName,
+		baseDir, templateRoot, line, effectiveRegistry, funcMaps, opts, []includeStep{{name: templateName}}, nil,
+	), true
+}
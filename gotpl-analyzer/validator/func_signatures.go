@@ -0,0 +1,119 @@
+package validator
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+)
+
+// SignatureParam is a parameter or result of a FuncSignature.
+type SignatureParam struct {
+	// Name is the parameter name, empty when the Go signature omits it.
+	Name string `json:"name,omitempty"`
+
+	// Type is the parameter type. A variadic parameter has its slice type,
+	// e.g. []any for ...any.
+	Type string `json:"type"`
+}
+
+// FuncSignature is the normalized signature of a template function, for
+// tooling that generates template.FuncMap stubs or documentation.
+type FuncSignature struct {
+	// Name is the name the function is registered under in the FuncMap.
+	Name string `json:"name"`
+
+	// Params are the parameters in declaration order.
+	Params []SignatureParam `json:"params"`
+
+	// Returns are the results in declaration order.
+	Returns []SignatureParam `json:"returns"`
+
+	// Variadic reports whether the last parameter is variadic.
+	Variadic bool `json:"variadic"`
+
+	// Signature is the Go function type, e.g. func(format string, args ...any) string.
+	Signature string `json:"signature"`
+
+	// Doc is the documentation comment of the function.
+	Doc string `json:"doc,omitempty"`
+
+	// DefFile and DefLine locate the function definition, if known.
+	DefFile string `json:"defFile,omitempty"`
+	DefLine int    `json:"defLine,omitempty"`
+}
+
+// FuncMapSignatures returns the signatures of the discovered template
+// functions sorted by name. A function registered more than once, for
+// example in several FuncMaps, is listed once with its first definition.
+func FuncMapSignatures(funcMaps []ast.FuncMapInfo) []FuncSignature {
+	seen := make(map[string]bool, len(funcMaps))
+	sigs := make([]FuncSignature, 0, len(funcMaps))
+	for _, fm := range funcMaps {
+		if seen[fm.Name] {
+			continue
+		}
+		seen[fm.Name] = true
+
+		sig := FuncSignature{
+			Name:     fm.Name,
+			Params:   signatureParams(fm.Params),
+			Returns:  signatureParams(fm.Returns),
+			Variadic: fm.Variadic && len(fm.Params) > 0,
+			Doc:      fm.Doc,
+			DefFile:  fm.DefFile,
+			DefLine:  fm.DefLine,
+		}
+		sig.Signature = sig.goSignature()
+		sigs = append(sigs, sig)
+	}
+	slices.SortFunc(sigs, func(a, b FuncSignature) int { return strings.Compare(a.Name, b.Name) })
+	return sigs
+}
+
+// signatureParams converts ast parameters, dropping their field trees.
+func signatureParams(params []ast.ParamInfo) []SignatureParam {
+	out := make([]SignatureParam, len(params))
+	for i, p := range params {
+		out[i] = SignatureParam{Name: p.Name, Type: p.TypeStr}
+	}
+	return out
+}
+
+// goSignature renders s as a Go function type.
+func (s FuncSignature) goSignature() string {
+	var b strings.Builder
+	b.WriteString("func(")
+	for i, p := range s.Params {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		typ := p.Type
+		if s.Variadic && i == len(s.Params)-1 {
+			typ = "..." + strings.TrimPrefix(typ, "[]")
+		}
+		if p.Name != "" {
+			b.WriteString(p.Name + " ")
+		}
+		b.WriteString(typ)
+	}
+	b.WriteString(")")
+
+	switch {
+	case len(s.Returns) == 1 && s.Returns[0].Name == "":
+		b.WriteString(" " + s.Returns[0].Type)
+	case len(s.Returns) > 0:
+		b.WriteString(" (")
+		for i, r := range s.Returns {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			if r.Name != "" {
+				b.WriteString(r.Name + " ")
+			}
+			b.WriteString(r.Type)
+		}
+		b.WriteString(")")
+	}
+	return b.String()
+}
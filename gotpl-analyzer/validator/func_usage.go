@@ -0,0 +1,90 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+)
+
+// FunctionUsage is the functions a template calls, split by where they come
+// from. Every list is sorted.
+type FunctionUsage struct {
+	// Custom are the functions registered in a discovered FuncMap.
+	Custom []string `json:"custom"`
+
+	// Builtin are the text/template builtins and the common helpers the
+	// validator accepts without a FuncMap entry, such as dict.
+	Builtin []string `json:"builtin"`
+
+	// Unknown are the functions neither registered nor builtin, which
+	// validation reports as undefined.
+	Unknown []string `json:"unknown"`
+}
+
+// TemplateFunctionUsage returns the distinct, sorted names of the functions
+// called in content. Like the undefined-function check, it only looks at the
+// identifiers in function position of each action's pipeline, so field and
+// method accesses, variables and literals are never reported. Functions in
+// {{template}} context arguments are not reported either.
+func TemplateFunctionUsage(content string) []string {
+	var names []string
+	forEachAction(content, func(action string, _ int) {
+		expr, ok := actionPipeline(action, firstWord(action))
+		if !ok {
+			return
+		}
+		if _, rhs, ok := splitAssignment(expr); ok {
+			expr = rhs
+		}
+		for _, candidate := range functionCandidates(expr) {
+			names = append(names, candidate.name)
+		}
+	})
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// ClassifyFunctions splits names, as returned by TemplateFunctionUsage, into
+// custom, builtin and unknown functions. A FuncMap entry wins over a builtin
+// of the same name, as it does in text/template.
+func ClassifyFunctions(names []string, funcMaps []ast.FuncMapInfo) FunctionUsage {
+	registry := BuildFuncMapRegistry(funcMaps)
+	usage := FunctionUsage{Custom: []string{}, Builtin: []string{}, Unknown: []string{}}
+	for _, name := range names {
+		if _, ok := registry[name]; ok {
+			usage.Custom = append(usage.Custom, name)
+		} else if templateBuiltins[name] {
+			usage.Builtin = append(usage.Builtin, name)
+		} else {
+			usage.Unknown = append(usage.Unknown, name)
+		}
+	}
+	return usage
+}
+
+// FunctionUsageByTemplate maps every template file under baseDir/templateRoot,
+// keyed by its slash-separated path relative to the root, to the functions it
+// calls, classified against funcMaps. Files calling no function map to empty
+// lists so that every template appears, like in TemplateDeps.
+func FunctionUsageByTemplate(baseDir, templateRoot string, funcMaps []ast.FuncMapInfo) map[string]FunctionUsage {
+	usage := make(map[string]FunctionUsage)
+	root := filepath.Join(baseDir, templateRoot)
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !IsFileBasedPartial(path) {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			rel = path
+		}
+		usage[filepath.ToSlash(rel)] = ClassifyFunctions(TemplateFunctionUsage(string(content)), funcMaps)
+		return nil
+	})
+	return usage
+}
@@ -0,0 +1,211 @@
+package validator
+
+import (
+	"bufio"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// GitignoreMatcher reports whether paths are excluded by the .gitignore files
+// that apply to them. It supports the common subset of the gitignore syntax:
+// "*", "?", "**", character classes, negation with "!", directory-only
+// patterns ending in "/" and patterns anchored by a leading or inner "/".
+//
+// A nil *GitignoreMatcher matches nothing.
+type GitignoreMatcher struct {
+	rules []gitignoreRule
+}
+
+// gitignoreRule is a single compiled pattern. base is the absolute directory
+// of the .gitignore file it came from; the pattern only applies below it.
+type gitignoreRule struct {
+	base    string
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// NewGitignoreMatcher loads the .gitignore files that apply to root: those in
+// root's ancestors up to the enclosing git work tree, then every .gitignore
+// below root, each applying to its own subtree. Rules are kept in order from
+// the outermost file to the innermost so that later, more specific rules win.
+func NewGitignoreMatcher(root string) (*GitignoreMatcher, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+	m := &GitignoreMatcher{}
+
+	// Ancestors of root up to the work tree root, innermost first. Outside
+	// a work tree no ancestor .gitignore applies.
+	var ancestors []string
+	if !fileExists(filepath.Join(root, ".git")) {
+		for dir := filepath.Dir(root); ; dir = filepath.Dir(dir) {
+			ancestors = append(ancestors, dir)
+			if fileExists(filepath.Join(dir, ".git")) {
+				break
+			}
+			if dir == filepath.Dir(dir) {
+				ancestors = nil
+				break
+			}
+		}
+	}
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		if err := m.load(ancestors[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if d.Name() == ".git" || (path != root && m.Match(path, true)) {
+			return filepath.SkipDir
+		}
+		return m.load(path)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// fileExists reports whether path can be stat'ed.
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// load appends the rules of dir/.gitignore, if present.
+func (m *GitignoreMatcher) load(dir string) error {
+	f, err := os.Open(filepath.Join(dir, ".gitignore"))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if rule, ok := compileGitignoreLine(dir, scanner.Text()); ok {
+			m.rules = append(m.rules, rule)
+		}
+	}
+	return scanner.Err()
+}
+
+// compileGitignoreLine turns one .gitignore line into a rule. Blank lines and
+// comments yield ok == false.
+func compileGitignoreLine(base, line string) (gitignoreRule, bool) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return gitignoreRule{}, false
+	}
+
+	rule := gitignoreRule{base: base}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\`) {
+		line = line[1:] // escaped leading "#" or "!"
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimSuffix(line, "/")
+	}
+	if line == "" {
+		return gitignoreRule{}, false
+	}
+
+	// A slash anywhere but at the end anchors the pattern to base; otherwise
+	// it matches a name at any depth.
+	anchored := strings.Contains(line, "/")
+	line = strings.TrimPrefix(line, "/")
+
+	var sb strings.Builder
+	sb.WriteString("^")
+	if !anchored {
+		sb.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case strings.HasPrefix(line[i:], "**/"):
+			sb.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(line[i:], "**"):
+			sb.WriteString(".*")
+			i++
+		case c == '*':
+			sb.WriteString("[^/]*")
+		case c == '?':
+			sb.WriteString("[^/]")
+		case c == '[':
+			if end := strings.IndexByte(line[i+1:], ']'); end != -1 {
+				class := line[i+1 : i+1+end]
+				if strings.HasPrefix(class, "!") {
+					class = "^" + class[1:]
+				}
+				sb.WriteString("[" + class + "]")
+				i += end + 1
+			} else {
+				sb.WriteString(`\[`)
+			}
+		case c == '\\' && i+1 < len(line):
+			i++
+			sb.WriteString(regexp.QuoteMeta(line[i : i+1]))
+		default:
+			sb.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	sb.WriteString("$")
+
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return gitignoreRule{}, false
+	}
+	rule.re = re
+	return rule, true
+}
+
+// Match reports whether the absolute path is ignored. isDir tells whether the
+// path is a directory, for patterns ending in "/". As in git, a path inside an
+// ignored directory is ignored too and cannot be re-included by a negation.
+func (m *GitignoreMatcher) Match(path string, isDir bool) bool {
+	if m == nil || len(m.rules) == 0 {
+		return false
+	}
+	path = filepath.Clean(path)
+	for dir := filepath.Dir(path); dir != filepath.Dir(dir); dir = filepath.Dir(dir) {
+		if m.matchOne(dir, true) {
+			return true
+		}
+	}
+	return m.matchOne(path, isDir)
+}
+
+// matchOne applies every rule to path alone, ignoring its parents. The last
+// matching rule decides.
+func (m *GitignoreMatcher) matchOne(path string, isDir bool) bool {
+	ignored := false
+	for _, r := range m.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		rel, err := filepath.Rel(r.base, path)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			continue
+		}
+		if r.re.MatchString(filepath.ToSlash(rel)) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
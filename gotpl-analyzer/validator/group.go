@@ -0,0 +1,32 @@
+package validator
+
+import "github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+
+// ContextFileGroup is the GroupByGoFile key of the results of the synthetic
+// render calls of a context file (see ast.ContextFileRenderCall).
+const ContextFileGroup = "<context-file>"
+
+// TemplateTreeGroup is the GroupByGoFile key of results no render call
+// introduced, such as those of templates validated as part of the tree and
+// of named blocks validated on their own.
+const TemplateTreeGroup = "<template-tree>"
+
+// GroupByGoFile groups results by the Go file of the render call that
+// introduced them, for tools that annotate handlers rather than templates.
+// Results of context file render calls are grouped under ContextFileGroup
+// and results without a GoFile under TemplateTreeGroup. Each group keeps the
+// order of results.
+func GroupByGoFile(results []ValidationResult) map[string][]ValidationResult {
+	groups := make(map[string][]ValidationResult)
+	for _, r := range results {
+		key := r.GoFile
+		switch key {
+		case ast.ContextFileRenderCall:
+			key = ContextFileGroup
+		case "":
+			key = TemplateTreeGroup
+		}
+		groups[key] = append(groups[key], r)
+	}
+	return groups
+}
@@ -0,0 +1,88 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+)
+
+// InventoryEntry describes one template known from the template tree or the
+// render calls.
+type InventoryEntry struct {
+	// Template is the file path relative to the template root, or the name of
+	// a {{define}} or {{block}}.
+	Template string `json:"template"`
+
+	// IsNamedBlock reports whether a {{define}} or {{block}} declares the name.
+	IsNamedBlock bool `json:"isNamedBlock"`
+
+	// IsFile reports whether the name is a template file under the root.
+	IsFile bool `json:"isFile"`
+
+	// Included reports whether a {{template}} action in the tree refers to
+	// the name.
+	Included bool `json:"included"`
+
+	// Rendered reports whether at least one render call targets the template.
+	Rendered bool `json:"rendered"`
+
+	// RenderCallCount is the number of render calls targeting the template.
+	RenderCallCount int `json:"renderCallCount"`
+}
+
+// Inventory lists every template file under baseDir/templateRoot, every named
+// block and every render call target, sorted by name, so that files never
+// rendered, blocks never included and calls targeting templates that do not
+// exist (neither IsFile nor IsNamedBlock) show up in one view.
+func Inventory(calls []ast.RenderCall, baseDir, templateRoot string) []InventoryEntry {
+	entries := make(map[string]*InventoryEntry)
+	entry := func(name string) *InventoryEntry {
+		e, ok := entries[name]
+		if !ok {
+			e = &InventoryEntry{Template: name}
+			entries[name] = e
+		}
+		return e
+	}
+
+	root := filepath.Join(baseDir, templateRoot)
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !IsFileBasedPartial(path) {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			rel = path
+		}
+		entry(filepath.ToSlash(rel)).IsFile = true
+		if content, err := os.ReadFile(path); err == nil {
+			for _, ref := range ExtractTemplateRefs(string(content)) {
+				entry(ref.Name).Included = true
+			}
+		}
+		return nil
+	})
+
+	namedBlocks, _ := ParseAllNamedTemplates(baseDir, templateRoot)
+	for name := range namedBlocks {
+		entry(name).IsNamedBlock = true
+	}
+	for _, rc := range calls {
+		if rc.Template == "" {
+			continue
+		}
+		e := entry(rc.Template)
+		e.Rendered = true
+		e.RenderCallCount++
+	}
+
+	list := make([]InventoryEntry, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, *e)
+	}
+	slices.SortFunc(list, func(a, b InventoryEntry) int { return strings.Compare(a.Template, b.Template) })
+	return list
+}
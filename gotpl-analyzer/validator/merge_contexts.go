@@ -0,0 +1,96 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+)
+
+// sharedCallContexts inspects templates targeted by more than one render call.
+// For each template where the calls disagree it returns the variables every
+// call provides (shared) and the names provided by only some calls (partial).
+// Templates whose callers all pass the same variables are omitted.
+func sharedCallContexts(renderCalls []ast.RenderCall) (map[string][]ast.TemplateVar, map[string]map[string]bool) {
+	callCount := make(map[string]int)
+	providers := make(map[string]map[string]int)
+	union := MergeCallContexts(renderCalls)
+
+	for _, rc := range renderCalls {
+		callCount[rc.Template]++
+		if providers[rc.Template] == nil {
+			providers[rc.Template] = make(map[string]int)
+		}
+		seen := make(map[string]bool, len(rc.Vars))
+		for _, v := range rc.Vars {
+			if !seen[v.Name] {
+				seen[v.Name] = true
+				providers[rc.Template][v.Name]++
+			}
+		}
+	}
+
+	shared := make(map[string][]ast.TemplateVar)
+	partial := make(map[string]map[string]bool)
+	for name, n := range callCount {
+		if n < 2 {
+			continue
+		}
+		var common []ast.TemplateVar
+		some := make(map[string]bool)
+		for _, v := range union[name] {
+			if providers[name][v.Name] == n {
+				common = append(common, v)
+			} else {
+				some[v.Name] = true
+			}
+		}
+		if len(some) == 0 {
+			continue
+		}
+		shared[name] = common
+		partial[name] = some
+	}
+
+	return shared, partial
+}
+
+// partialContextWarnings validates the template targeted by rc against the
+// variables shared by all of its render calls and reports each reference to
+// a variable in partial as a warning, since some callers do not provide it.
+// Of opts, only defineIsFileEntry applies, since it selects the content.
+func partialContextWarnings(
+	rc ast.RenderCall,
+	shared []ast.TemplateVar,
+	partial map[string]bool,
+	baseDir, templateRoot string,
+	namedBlocks map[string][]NamedBlockEntry,
+	funcMaps FuncMapRegistry,
+	opts contentOptions,
+) []ValidationResult {
+	var warnings []ValidationResult
+	// Without shared variables the template is validated as if executed with
+	// nil data, which keeps the root scope strict so references to the
+	// partial variables are reported instead of silently accepted.
+	for _, r := range validateRenderTarget(rc, shared, len(shared) == 0, baseDir, templateRoot, namedBlocks, funcMaps, contentOptions{defineIsFileEntry: opts.defineIsFileEntry}) {
+		if r.Severity != "error" || !partial[rootVarName(r.Variable)] {
+			continue
+		}
+		r.Message = fmt.Sprintf("Variable %q is only provided by some render calls", r.Variable)
+		r.Rule = "partial-context"
+		r.Severity = "warning"
+		warnings = append(warnings, r)
+	}
+	return warnings
+}
+
+// rootVarName returns the root variable of a field chain such as ".User.Name"
+// or "$.User.Name", or "" for anything else.
+func rootVarName(varExpr string) string {
+	expr := strings.TrimPrefix(varExpr, "$")
+	if !strings.HasPrefix(expr, ".") {
+		return ""
+	}
+	name, _, _ := strings.Cut(expr[1:], ".")
+	return name
+}
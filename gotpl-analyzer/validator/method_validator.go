@@ -0,0 +1,154 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	templateparse "text/template/parse"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+)
+
+// validateMethodArity checks that every method invoked in an action's
+// pipeline receives the number of arguments declared in its signature
+// (FieldInfo.Params), e.g. {{if .User.HasRole "admin"}}.
+//
+// A method used as an argument to another command is invoked with no
+// arguments, and a method in a later pipeline stage also receives the piped
+// value as its final argument, mirroring text/template's evaluation rules.
+// A method that requires arguments cannot be used as such a value, e.g.
+// {{printf "%s" .User.HasRole}}, which gets a dedicated message.
+//
+// Actions that cannot be parsed are skipped; the other validators already
+// report the problems that make them unparseable.
+func validateMethodArity(
+	action, first, templateName string,
+	line, col int,
+	scopeStack []ScopeType,
+	varMap map[string]ast.TemplateVar,
+	funcMaps FuncMapRegistry,
+) []ValidationResult {
+	expr, ok := actionPipeline(action, first)
+	if !ok || !strings.Contains(expr, ".") {
+		return nil
+	}
+	if _, pipeline, hasAssignment := splitAssignment(expr); hasAssignment {
+		expr = pipeline
+	}
+
+	inferencer := expressionInferencer{vars: varMap, scopeStack: scopeStack, funcMaps: funcMaps}
+	tree, err := parseExpressionTree(expr, funcMaps, inferencer.collectLocalVarNames())
+	if err != nil || len(tree.Root.Nodes) == 0 {
+		return nil
+	}
+	actionNode, ok := tree.Root.Nodes[len(tree.Root.Nodes)-1].(*templateparse.ActionNode)
+	if !ok {
+		return nil
+	}
+
+	var errors []ValidationResult
+	inferencer.checkPipeArity(actionNode.Pipe, func(node templateparse.Node, method ast.FieldInfo, got int, asValue bool) {
+		if methodAcceptsArgs(method, got) {
+			return
+		}
+		message := fmt.Sprintf("Method %q expects %d arguments but got %d", method.Name, len(method.Params), got)
+		if isVariadicMethod(method) {
+			message = fmt.Sprintf("Method %q expects at least %d arguments but got %d", method.Name, len(method.Params)-1, got)
+		}
+		if asValue {
+			message = fmt.Sprintf("Method %q requires arguments and cannot be used as a value", method.Name)
+		}
+		varExpr := node.String()
+		errors = append(errors, ValidationResult{
+			Template: templateName,
+			Line:     line,
+			Column:   col + max(strings.Index(action, varExpr), 0),
+			Variable: varExpr,
+			Message:  message,
+			Severity: "error",
+			Rule:     "method-arity",
+		})
+	})
+	return errors
+}
+
+// checkPipeArity reports every method invocation in pipe, including those in
+// parenthesized sub-pipelines, together with the number of arguments it is
+// called with and whether it is used as a value, i.e. as an argument to
+// another command rather than at the head of its own.
+func (i expressionInferencer) checkPipeArity(pipe *templateparse.PipeNode, report func(node templateparse.Node, method ast.FieldInfo, got int, asValue bool)) {
+	if pipe == nil {
+		return
+	}
+	for idx, cmd := range pipe.Cmds {
+		for argIdx, arg := range cmd.Args {
+			if sub, ok := arg.(*templateparse.PipeNode); ok {
+				i.checkPipeArity(sub, report)
+				continue
+			}
+			method, ok := i.resolveMethod(arg)
+			if !ok {
+				continue
+			}
+			got := 0
+			if argIdx == 0 {
+				got = len(cmd.Args) - 1
+				if idx > 0 {
+					got++
+				}
+			}
+			report(arg, method, got, argIdx > 0)
+		}
+	}
+}
+
+// resolveMethod returns the method FieldInfo that a field or variable chain
+// such as .User.HasRole or $user.HasRole refers to.
+func (i expressionInferencer) resolveMethod(node templateparse.Node) (ast.FieldInfo, bool) {
+	var parent *ExpressionTypeResult
+	var name string
+
+	switch typed := node.(type) {
+	case *templateparse.FieldNode:
+		last := len(typed.Ident) - 1
+		parent = i.resolveFieldPath(append([]string{"."}, typed.Ident[:last]...))
+		name = typed.Ident[last]
+	case *templateparse.VariableNode:
+		last := len(typed.Ident) - 1
+		if last < 1 {
+			return ast.FieldInfo{}, false
+		}
+		parent = i.resolveVariablePath(typed.Ident[:last])
+		name = typed.Ident[last]
+	default:
+		return ast.FieldInfo{}, false
+	}
+
+	if parent == nil || parent.IsMap {
+		return ast.FieldInfo{}, false
+	}
+	field := findFieldInfo(parent.Fields, name)
+	if field == nil || field.TypeStr != "method" {
+		return ast.FieldInfo{}, false
+	}
+	return *field, true
+}
+
+// methodAcceptsArgs reports whether method can be called with got arguments.
+func methodAcceptsArgs(method ast.FieldInfo, got int) bool {
+	want := len(method.Params)
+	if want > 0 && isVariadicMethod(method) {
+		return got >= want-1
+	}
+	return got == want
+}
+
+// isVariadicMethod reports whether the last parameter of method is variadic.
+// When method does not record it, a trailing slice parameter is treated as
+// variadic to avoid false positives.
+func isVariadicMethod(method ast.FieldInfo) bool {
+	if method.Variadic != nil {
+		return *method.Variadic
+	}
+	n := len(method.Params)
+	return n > 0 && strings.HasPrefix(method.Params[n-1].TypeStr, "[]")
+}
@@ -0,0 +1,60 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+)
+
+// MissingContextWarnings reports every template file under templateRoot that
+// no render call targets and no other template includes. It is meant for
+// -template-only runs, where the context file is the only source of render
+// calls and a template missing from it is validated with an empty context.
+func MissingContextWarnings(renderCalls []ast.RenderCall, baseDir, templateRoot string) []ValidationResult {
+	renderVarsByTemplate := MergeCallContexts(renderCalls)
+	partialTargets := FindPartialTargets(baseDir, templateRoot)
+	root := filepath.Join(baseDir, templateRoot)
+
+	var warnings []ValidationResult
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info == nil || info.IsDir() || !IsFileBasedPartial(path) {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			rel = path
+		}
+		rel = filepath.ToSlash(rel)
+		if isCoveredByRenderCall(rel, renderVarsByTemplate) || partialTargets[rel] {
+			return nil
+		}
+		warnings = append(warnings, ValidationResult{
+			Template: rel,
+			Line:     1,
+			Message:  fmt.Sprintf("No context defined for template %q", rel),
+			Severity: "warning",
+			Rule:     "missing-context",
+		})
+		return nil
+	})
+
+	sort.Slice(warnings, func(i, j int) bool { return warnings[i].Template < warnings[j].Template })
+	return warnings
+}
+
+// missingContextWarnings is MissingContextWarnings for templateRoot and every
+// extra root. Templates of the extra roots are named by their path relative
+// to baseDir, like their validation results.
+func missingContextWarnings(renderCalls []ast.RenderCall, baseDir, templateRoot string, extraRoots []string) []ValidationResult {
+	warnings := MissingContextWarnings(renderCalls, baseDir, templateRoot)
+	for _, root := range extraRoots {
+		for _, r := range MissingContextWarnings(renderCalls, baseDir, root) {
+			r.Template = relativeTemplateName(baseDir, filepath.Join(baseDir, root), r.Template)
+			warnings = append(warnings, r)
+		}
+	}
+	return warnings
+}
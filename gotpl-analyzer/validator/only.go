@@ -0,0 +1,33 @@
+package validator
+
+import (
+	"path"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+)
+
+// MatchTemplate reports whether the template name matches glob, a
+// path.Match pattern such as "users/*.html". An empty glob matches every
+// name; a malformed one matches none (check it with path.Match first).
+func MatchTemplate(glob, name string) bool {
+	if glob == "" {
+		return true
+	}
+	ok, _ := path.Match(glob, name)
+	return ok
+}
+
+// FilterRenderCalls returns the render calls whose template matches glob
+// (see MatchTemplate). calls itself is returned when glob is empty.
+func FilterRenderCalls(calls []ast.RenderCall, glob string) []ast.RenderCall {
+	if glob == "" {
+		return calls
+	}
+	var matched []ast.RenderCall
+	for _, rc := range calls {
+		if MatchTemplate(glob, rc.Template) {
+			matched = append(matched, rc)
+		}
+	}
+	return matched
+}
@@ -4,11 +4,22 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
 )
 
+// includeStep is a template being expanded on an inclusion path.
+type includeStep struct {
+	name string
+
+	// conditional is set when the {{template}} call that included the
+	// template sits inside an if, range or with, so that at execution time
+	// the inclusion may not happen.
+	conditional bool
+}
+
 // validateTemplateCallWithRegistry is the hot-path implementation. It accepts
 // an already-merged registry and passes it directly into recursive
 // ValidateTemplateContent calls, breaking the re-merge cycle.
@@ -28,6 +39,8 @@ func validateTemplateCallWithRegistry(
 	templateRoot string,
 	registry map[string][]NamedBlockEntry,
 	funcMaps FuncMapRegistry,
+	opts contentOptions,
+	includePath []includeStep,
 ) []ValidationResult {
 	var errors []ValidationResult
 	parts := parseTemplateAction(action)
@@ -43,15 +56,33 @@ func validateTemplateCallWithRegistry(
 	}
 
 	if contextArg != "" && contextArg != "." {
-		if err := validateContextArg(contextArg, scopeStack, varMap, funcMaps); err != nil {
+		if err := validateContextArg(contextArg, scopeStack, varMap, funcMaps, opts.warnShadowing); err != nil {
 			err.Template = templateName
-			err.Line = actualLineNum
-			err.Column = max(col+strings.Index(action, contextArg), col)
+			err.setRange(action, max(strings.Index(action, contextArg), 0), actualLineNum, col)
 			errors = append(errors, *err)
 			return errors
 		}
 	}
 
+	// A $variable or .Field name is resolved at execution time by engines that
+	// allow dynamic includes. The name variable itself is checked like any
+	// other variable reference in the action, so only note that the target
+	// cannot be verified instead of reporting a missing block.
+	if isDynamicTemplateName(tmplName) {
+		if opts.dynamicNameSeverity != "" {
+			errors = append(errors, ValidationResult{
+				Template: templateName,
+				Line:     actualLineNum,
+				Column:   max(col+strings.Index(action, tmplName), col),
+				Variable: tmplName,
+				Message:  fmt.Sprintf("Dynamic template name %q cannot be statically verified", tmplName),
+				Severity: opts.dynamicNameSeverity,
+				Rule:     "dynamic-template-name",
+			})
+		}
+		return errors
+	}
+
 	pinCallSite := func(inner []ValidationResult) []ValidationResult {
 		for i := range inner {
 			e := &inner[i]
@@ -68,7 +99,58 @@ func validateTemplateCallWithRegistry(
 		return inner
 	}
 
+	// A template that is already being expanded would recurse forever, so
+	// stop descending. The cycle is only reported when no call in it sits
+	// inside an if, range or with, as those end recursive templates such as
+	// trees at execution time. A file including itself by path is named as
+	// such, since the cycle would only repeat the file name.
+	conditional := len(scopeStack) > 1
+	if idx := slices.IndexFunc(includePath, func(s includeStep) bool { return s.name == tmplName }); idx != -1 {
+		cycle := []string{tmplName}
+		for _, step := range includePath[idx+1:] {
+			conditional = conditional || step.conditional
+			cycle = append(cycle, step.name)
+		}
+		if conditional {
+			return errors
+		}
+		cycle = append(cycle, tmplName)
+		message := fmt.Sprintf("Template inclusion cycle detected: %s", strings.Join(cycle, " > "))
+		if len(cycle) == 2 && len(registry[tmplName]) == 0 && IsFileBasedPartial(tmplName) {
+			message = fmt.Sprintf("Template %q includes itself", tmplName)
+		}
+		errors = append(errors, ValidationResult{
+			Template: templateName,
+			Line:     actualLineNum,
+			Column:   col,
+			Variable: tmplName,
+			Message:  message,
+			Severity: "warning",
+			Rule:     "inclusion-cycle",
+		})
+		return errors
+	}
+	nextPath := append(slices.Clone(includePath), includeStep{name: tmplName, conditional: conditional})
+
+	// The registry is consulted before the file-based heuristic so that a
+	// block declared as {{define "content.html"}} wins over a disk lookup.
 	if entries, ok := registry[tmplName]; ok && len(entries) > 0 {
+		entries = resolveBlockOverride(entries)
+		if props := declaredProps(entries); len(props) > 0 {
+			partialScope := resolvePartialScope(contextArg, scopeStack, varMap, funcMaps)
+			partialVarMap := buildPartialVarMap(contextArg, partialScope, scopeStack, varMap)
+			for _, prop := range missingProps(props, partialVarMap, contextArg == "") {
+				errors = append(errors, ValidationResult{
+					Template: templateName,
+					Line:     actualLineNum,
+					Column:   col,
+					Variable: tmplName,
+					Message:  fmt.Sprintf("Component %q requires prop %q which is not provided", tmplName, prop.Name),
+					Severity: "error",
+					Rule:     "missing-prop",
+				})
+			}
+		}
 		anyValid := false
 		allErrors := make([]ValidationResult, 0)
 		for _, nt := range entries {
@@ -78,12 +160,16 @@ func validateTemplateCallWithRegistry(
 			partialErrors := validateTemplateContentWithRegistry(
 				nt.Content,
 				partialVarMap,
+				contextArg == "",
 				nt.TemplatePath,
 				baseDir,
 				templateRoot,
 				nt.Line,
 				registry, // pass through unchanged
 				funcMaps,
+				opts,
+				nextPath,
+				nil,
 			)
 			if len(partialErrors) == 0 {
 				anyValid = true
@@ -104,6 +190,7 @@ func validateTemplateCallWithRegistry(
 				Variable: tmplName,
 				Message:  fmt.Sprintf(`Partial template "%s" could not be found at %s`, tmplName, fullPath),
 				Severity: "error",
+				Rule:     "missing-template",
 			})
 			return errors
 		}
@@ -111,17 +198,26 @@ func validateTemplateCallWithRegistry(
 		partialScope := resolvePartialScope(contextArg, scopeStack, varMap, funcMaps)
 		partialVarMap := buildPartialVarMap(contextArg, partialScope, scopeStack, varMap)
 
-		partialErrors := ValidateTemplateFile(
+		partialErrors := validateTemplateFile(
 			fullPath,
 			scopeVarsToTemplateVars(partialVarMap),
+			contextArg == "",
 			tmplName,
 			baseDir,
 			templateRoot,
-			registry, // pass through — ValidateTemplateFile already handles merge
+			registry, // pass through — validateTemplateFile already handles merge
 			funcMaps,
+			opts,
+			nextPath,
 		)
 		errors = append(errors, pinCallSite(partialErrors)...)
 	}
 
 	return errors
 }
+
+// isDynamicTemplateName reports whether a {{template}} name token is a
+// $variable or .Field expression rather than a literal block name.
+func isDynamicTemplateName(name string) bool {
+	return strings.HasPrefix(name, "$") || (strings.HasPrefix(name, ".") && len(name) > 1)
+}
@@ -0,0 +1,24 @@
+package validator
+
+import "fmt"
+
+// validateSafely runs validate, the validation of template, and turns a
+// panic in it into a single SeveritySyntax result naming the template. The
+// validation workers validate one template after another, so an input that
+// trips up the parser must not take the worker, and the results of the
+// templates after it in the same chunk, down with it.
+func validateSafely(template string, validate func() []ValidationResult) (results []ValidationResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			results = []ValidationResult{{
+				Template: template,
+				Line:     1,
+				Column:   1,
+				Message:  fmt.Sprintf("internal error validating %q: %v", template, r),
+				Severity: SeveritySyntax,
+				Rule:     "syntax",
+			}}
+		}
+	}()
+	return validate()
+}
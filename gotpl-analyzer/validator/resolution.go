@@ -0,0 +1,76 @@
+package validator
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+)
+
+// Resolution describes how the template name of one render call site was
+// resolved.
+type Resolution struct {
+	// File is the Go file containing the render call, relative to the analyzed directory.
+	File string `json:"file"`
+
+	// Line is the line number of the render call in File.
+	Line int `json:"line"`
+
+	// Expr is the template name argument as written in the Go source.
+	Expr string `json:"expr"`
+
+	// Templates lists the resolved template names; empty when resolution failed.
+	Templates []string `json:"templates"`
+
+	// ResolvedVia is one of the ast.ResolvedVia* values, or
+	// ast.ContextFileRenderCall for calls synthesized from the context file.
+	ResolvedVia string `json:"resolvedVia"`
+}
+
+// DescribeResolution summarizes how each render call's template name was
+// resolved. Calls sharing a call site (a variable with several possible
+// values yields one RenderCall per value) are merged into a single entry.
+// Entries are sorted by file then line.
+func DescribeResolution(calls []ast.RenderCall) []Resolution {
+	type siteKey struct {
+		file string
+		line int
+		expr string
+	}
+
+	index := make(map[siteKey]int)
+	out := make([]Resolution, 0, len(calls))
+
+	for _, rc := range calls {
+		via := rc.ResolvedVia
+		if rc.File == ast.ContextFileRenderCall {
+			via = ast.ContextFileRenderCall
+		}
+
+		key := siteKey{rc.File, rc.Line, rc.TemplateExpr}
+		i, ok := index[key]
+		if !ok {
+			i = len(out)
+			index[key] = i
+			out = append(out, Resolution{
+				File:        rc.File,
+				Line:        rc.Line,
+				Expr:        rc.TemplateExpr,
+				Templates:   []string{},
+				ResolvedVia: via,
+			})
+		}
+		if rc.Template != "" && !slices.Contains(out[i].Templates, rc.Template) {
+			out[i].Templates = append(out[i].Templates, rc.Template)
+		}
+	}
+
+	for i := range out {
+		slices.Sort(out[i].Templates)
+	}
+	slices.SortStableFunc(out, func(a, b Resolution) int {
+		return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Line, b.Line))
+	})
+
+	return out
+}
@@ -0,0 +1,376 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+)
+
+// Rule is a project-specific check, such as "every page references
+// .CSRFToken", run on every template and named block by
+// ValidateTemplatesWithOptions (see ValidateOptions.Rules and ParseRules).
+//
+// Check is called from the validation worker pool, concurrently for
+// different templates, so implementations must be safe for concurrent use.
+// The maps and slices in ctx are shared and must not be modified.
+type Rule interface {
+	Check(ctx RuleContext) []ValidationResult
+}
+
+// RuleContext is what a Rule sees of one template file or named block.
+type RuleContext struct {
+	// Template is the file path relative to the template root, or the path
+	// of the file declaring the named block.
+	Template string
+
+	// Block is the name of the {{define}} or {{block}} when the context
+	// covers a named block body rather than a whole file.
+	Block string
+
+	// Content is the raw template text. Action lines are relative to the
+	// file, not to Content, when Block is set.
+	Content string
+
+	// Vars are the variables merged from the render calls targeting the
+	// template. Empty when no render call targets it.
+	Vars map[string]ast.TemplateVar
+
+	// Rendered reports whether a render call targets the template directly.
+	Rendered bool
+
+	// Actions lists the template's actions in source order. Actions inside
+	// nested {{define}} and {{block}} bodies belong to the block's own
+	// context instead.
+	Actions []RuleAction
+}
+
+// RuleAction is a single action of a template together with its scope.
+type RuleAction struct {
+	// Text is the action without its delimiters and trim markers.
+	Text string
+
+	// Line and Column locate the start of Text.
+	Line, Column int
+
+	// ScopeStack is the scope chain in effect for the action, root first.
+	ScopeStack []ScopeType
+}
+
+// ruleActions records the actions validation visits in each template file and
+// named block body it validates on its own, so that rules can be run without
+// validating them again. It is safe for concurrent use.
+type ruleActions struct {
+	mu      sync.Mutex
+	actions map[ruleActionsKey][]RuleAction
+}
+
+// ruleActionsKey names a template file, with an empty block, or a named block
+// body, by the file declaring it, its name and the line it starts at.
+type ruleActionsKey struct {
+	template, block string
+	line            int
+}
+
+func newRuleActions() *ruleActions {
+	return &ruleActions{actions: make(map[ruleActionsKey][]RuleAction)}
+}
+
+// recorder returns the onAction callback of validateTemplateContentWithRegistry
+// recording the actions of the named template or block. It returns nil when r
+// is nil or the template was already recorded, since the first validation is
+// as good as any other.
+func (r *ruleActions) recorder(template, block string, line int) func(RuleAction) {
+	if r == nil {
+		return nil
+	}
+	key := ruleActionsKey{template, block, line}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.actions[key]; ok {
+		return nil
+	}
+	r.actions[key] = []RuleAction{}
+	return func(a RuleAction) {
+		r.mu.Lock()
+		r.actions[key] = append(r.actions[key], a)
+		r.mu.Unlock()
+	}
+}
+
+// get returns the recorded actions of the named template or block.
+func (r *ruleActions) get(template, block string, line int) ([]RuleAction, bool) {
+	if r == nil {
+		return nil, false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	actions, ok := r.actions[ruleActionsKey{template, block, line}]
+	return actions, ok
+}
+
+// collectRuleActions replays content the way validation does and returns its
+// actions with their scopes, for templates validation did not record.
+func collectRuleActions(
+	content string,
+	varMap map[string]ast.TemplateVar,
+	templateName string,
+	baseDir, templateRoot string,
+	lineOffset int,
+	registry map[string][]NamedBlockEntry,
+	funcMaps FuncMapRegistry,
+) []RuleAction {
+	var actions []RuleAction
+	effectiveRegistry := mergeNamedBlockRegistry(registry, content, templateName)
+	validateTemplateContentWithRegistry(
+		content, varMap, false, templateName, baseDir, templateRoot, lineOffset,
+		effectiveRegistry, funcMaps, contentOptions{}, []includeStep{{name: templateName}},
+		func(a RuleAction) { actions = append(actions, a) },
+	)
+	return actions
+}
+
+// runRules runs rules on every template file in the tree that was validated
+// and on every named block body, with the actions recorded in actions.
+// Templates only validated through {{template}} calls, such as partials, are
+// replayed with collectRuleActions instead.
+func runRules(
+	rules []Rule,
+	actions *ruleActions,
+	baseDir string,
+	templateRoot string,
+	namedBlocks map[string][]NamedBlockEntry,
+	renderVarsByTemplate map[string][]ast.TemplateVar,
+	skippedFiles map[string]bool,
+	ignore *GitignoreMatcher,
+	funcMaps FuncMapRegistry,
+	sink *resultSink,
+) []ValidationResult {
+	type workItem struct {
+		ctx        RuleContext
+		lineOffset int
+	}
+
+	var items []workItem
+	root := filepath.Join(baseDir, templateRoot)
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !IsFileBasedPartial(path) || ignore.Match(path, false) {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			rel = path
+		}
+		rel = filepath.ToSlash(rel)
+		if skippedFiles[rel] {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		vars, rendered := renderVarsByTemplate[rel]
+		items = append(items, workItem{
+			ctx:        RuleContext{Template: rel, Content: string(content), Vars: buildVarMap(vars), Rendered: rendered},
+			lineOffset: 1,
+		})
+		return nil
+	})
+	for name, entries := range namedBlocks {
+		vars, rendered := renderVarsByTemplate[name]
+		for _, entry := range entries {
+			if entry.foreign {
+				continue
+			}
+			items = append(items, workItem{
+				ctx:        RuleContext{Template: entry.TemplatePath, Block: name, Content: entry.Content, Vars: buildVarMap(vars), Rendered: rendered},
+				lineOffset: entry.Line,
+			})
+		}
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	return runWorkers(len(items), sink, func(chunk []int) []ValidationResult {
+		var results []ValidationResult
+		for _, i := range chunk {
+			item := items[i]
+			ctx := item.ctx
+			results = append(results, validateSafely(ctx.Template, func() []ValidationResult {
+				var results []ValidationResult
+				var ok bool
+				if ctx.Actions, ok = actions.get(ctx.Template, ctx.Block, item.lineOffset); !ok {
+					ctx.Actions = collectRuleActions(ctx.Content, ctx.Vars, ctx.Template, baseDir, templateRoot, item.lineOffset, namedBlocks, funcMaps)
+				}
+				for _, rule := range rules {
+					for _, r := range rule.Check(ctx) {
+						if r.Template == "" {
+							r.Template = ctx.Template
+						}
+						if r.Severity == "" {
+							r.Severity = "error"
+						}
+						results = append(results, r)
+					}
+				}
+				return results
+			})...)
+		}
+		return results
+	})
+}
+
+// ParseRules returns the rules described by specs, as written in
+// ast.AnalysisConfig.CustomRules: "require-variable:Name" for RequireVariable
+// and "forbid-field-path:Path" for ForbidFieldPath, each with an optional
+// ":severity" suffix.
+func ParseRules(specs []string) ([]Rule, error) {
+	var rules []Rule
+	for _, spec := range specs {
+		kind, arg, _ := strings.Cut(spec, ":")
+		arg, severity, _ := strings.Cut(arg, ":")
+		if arg == "" {
+			return nil, fmt.Errorf("rule %q: missing argument", spec)
+		}
+		if severity != "" && severity != "info" && severity != "warning" && severity != "error" {
+			return nil, fmt.Errorf("rule %q: invalid severity %q: want info, warning or error", spec, severity)
+		}
+		switch kind {
+		case "require-variable":
+			rules = append(rules, RequireVariable{Name: arg, Severity: severity})
+		case "forbid-field-path":
+			rules = append(rules, ForbidFieldPath{Path: arg, Severity: severity})
+		default:
+			return nil, fmt.Errorf("rule %q: unknown rule %q: want require-variable or forbid-field-path", spec, kind)
+		}
+	}
+	return rules, nil
+}
+
+// RequireVariable is a Rule reporting rendered templates that never
+// reference the root variable Name, e.g. a CSRF token every page's forms
+// need. A reference is .Name or $.Name, or any field path below them, at any
+// depth of the template. Named blocks and templates that are only included
+// are not checked.
+type RequireVariable struct {
+	// Name is the root variable, without the leading dot.
+	Name string
+
+	// Severity of the result; "error" when empty.
+	Severity string
+}
+
+// Check implements Rule.
+func (r RequireVariable) Check(ctx RuleContext) []ValidationResult {
+	if !ctx.Rendered || ctx.Block != "" {
+		return nil
+	}
+	for _, a := range ctx.Actions {
+		found := false
+		extractVariablesFromAction(a.Text, func(v string, _ int) {
+			if path, ok := rootFieldPath(v, a.ScopeStack); ok && hasPathPrefix(path, r.Name) {
+				found = true
+			}
+		})
+		if found {
+			return nil
+		}
+	}
+	return []ValidationResult{{
+		Template: ctx.Template,
+		Line:     1,
+		Column:   1,
+		Variable: "." + r.Name,
+		Message:  fmt.Sprintf("Template must reference .%s", r.Name),
+		Severity: r.Severity,
+		Rule:     "require-variable",
+	}}
+}
+
+// ForbidFieldPath is a Rule reporting every reference to the field path Path
+// below the root, e.g. "User.Password", or to anything below it. References
+// through {{with}} and {{range}} are resolved, so {{with .User}}{{.Password}}
+// matches too; a range element is addressed through its collection, as in
+// "Users.Password" for {{range .Users}}{{.Password}}. References through
+// $variables are not followed.
+type ForbidFieldPath struct {
+	// Path is the dot-separated field path, without the leading dot.
+	Path string
+
+	// Severity of each result; "error" when empty.
+	Severity string
+}
+
+// Check implements Rule.
+func (r ForbidFieldPath) Check(ctx RuleContext) []ValidationResult {
+	var results []ValidationResult
+	for _, a := range ctx.Actions {
+		extractVariablesFromAction(a.Text, func(v string, offset int) {
+			path, ok := rootFieldPath(v, a.ScopeStack)
+			if !ok || !hasPathPrefix(path, r.Path) {
+				return
+			}
+			result := ValidationResult{
+				Template: ctx.Template,
+				Variable: v,
+				Message:  fmt.Sprintf("Access to .%s is forbidden", r.Path),
+				Severity: r.Severity,
+				Rule:     "forbid-field-path",
+			}
+			result.setRange(a.Text, offset, a.Line, a.Column)
+			results = append(results, result)
+		})
+	}
+	return results
+}
+
+// rootFieldPath returns the field path below the root that the variable
+// reference v resolves to in scopeStack, without the leading dot: "User.Name"
+// for .Name inside {{with .User}}. ok is false for $variables and for dots
+// established by expressions other than field paths.
+func rootFieldPath(v string, scopeStack []ScopeType) (string, bool) {
+	if rest, ok := strings.CutPrefix(v, "$."); ok {
+		return rest, true
+	}
+	if !strings.HasPrefix(v, ".") {
+		return "", false
+	}
+
+	path := v[1:]
+	for i := len(scopeStack) - 1; i > 0; i-- {
+		name := scopeStack[i].VarName
+		// {{if}} and {{else}} frames copy the enclosing frame.
+		if name == scopeStack[i-1].VarName {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(name, "$."); ok && isFieldPath(rest) {
+			return rest + "." + path, true
+		}
+		if !strings.HasPrefix(name, ".") || !isFieldPath(name[1:]) {
+			return "", false
+		}
+		path = name[1:] + "." + path
+	}
+	return path, true
+}
+
+// isFieldPath reports whether s is a non-empty dot-separated list of names.
+func isFieldPath(s string) bool {
+	for name := range strings.SplitSeq(s, ".") {
+		if name == "" || strings.ContainsFunc(name, func(r rune) bool {
+			return r != '_' && (r > 0x7f || !isAlphaNum(byte(r)))
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasPathPrefix reports whether path is prefix or lies below it.
+func hasPathPrefix(path, prefix string) bool {
+	return path == prefix || strings.HasPrefix(path, prefix+".")
+}
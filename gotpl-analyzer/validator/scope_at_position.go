@@ -130,6 +130,7 @@ func buildScopeAtPosition(content string, varMap map[string]ast.TemplateVar, tem
 	rootScope := buildRootScope(varMap)
 	scopeStack = append(scopeStack, rootScope)
 
+	lines := newLineIndex(content)
 	cur := 0
 	lineNum := 0
 
@@ -164,8 +165,7 @@ func buildScopeAtPosition(content string, varMap map[string]ast.TemplateVar, tem
 			contentEnd--
 		}
 
-		lastNewline := strings.LastIndexByte(content[:openIdx], '\n')
-		col := openIdx - lastNewline // 1-based col of {{
+		col := openIdx - lines.lineStart(openIdx) + 1 // 1-based col of {{
 
 		var action string
 		if contentStart < contentEnd {
@@ -201,8 +201,7 @@ func buildScopeAtPosition(content string, varMap map[string]ast.TemplateVar, tem
 			actionEndCol = col + (closeIdx + 2 - openIdx)
 		} else {
 			// Multi-line: end col is from last newline to closeIdx+2
-			lastNL := strings.LastIndexByte(content[:closeIdx+2], '\n')
-			actionEndCol = closeIdx + 2 - lastNL
+			actionEndCol = closeIdx + 2 - lines.lineStart(closeIdx+1) + 1
 		}
 
 		// If the target is on this action's line range, check column bounds
@@ -294,6 +293,7 @@ func buildScopeAtPosition(content string, varMap map[string]ast.TemplateVar, tem
 				if blockScope.IsRoot {
 					newScope.IsRoot = true
 				}
+				newScope.opener = "block"
 				scopeStack = append(scopeStack, newScope)
 			} else {
 				// No context arg — inherit current scope
@@ -301,6 +301,7 @@ func buildScopeAtPosition(content string, varMap map[string]ast.TemplateVar, tem
 				if len(scopeStack) > 0 {
 					top = childScope(scopeStack[len(scopeStack)-1])
 				}
+				top.opener = "block"
 				scopeStack = append(scopeStack, top)
 			}
 			lineNum += lineNumInside
@@ -312,14 +313,13 @@ func buildScopeAtPosition(content string, varMap map[string]ast.TemplateVar, tem
 			// their context from the template/block call site. For position-scope
 			// purposes, look up the registry to see if there is a matching render
 			// call that provides vars, otherwise use an empty scope.
-			if len(words) >= 2 {
-				defName := strings.Trim(words[1], `"`)
-				var _ string = defName
+			if _, _, ok := splitQuotedName(strings.TrimSpace(strings.TrimPrefix(action, "define"))); ok {
 				varMapForDefine := findDefineVars(effectiveRegistry, varMap, scopeStack, effectiveFuncMaps)
 				newScope := buildRootScope(varMapForDefine)
+				newScope.opener = "define"
 				scopeStack = append(scopeStack, newScope)
 			} else {
-				scopeStack = append(scopeStack, ScopeType{})
+				scopeStack = append(scopeStack, ScopeType{opener: "define"})
 			}
 			lineNum += lineNumInside
 			continue
@@ -341,12 +341,18 @@ func buildScopeAtPosition(content string, varMap map[string]ast.TemplateVar, tem
 				if len(scopeStack) > 0 {
 					top = childScope(scopeStack[len(scopeStack)-1])
 				}
+				top.opener = "else"
 				scopeStack = append(scopeStack, top)
 				lineNum += lineNumInside
 				continue
 			}
 		}
 
+		opener := actionToPush
+		if isElse {
+			opener = "else " + actionToPush
+		}
+
 		switch actionToPush {
 		case "range":
 			rangeExpr := strings.TrimSpace(strings.TrimPrefix(exprToParse, "range"))
@@ -358,6 +364,7 @@ func buildScopeAtPosition(content string, varMap map[string]ast.TemplateVar, tem
 			if hasAssignment {
 				registerRangeLocalsSafe(&newScope, assignmentNames, rangeExpr, scopeStack, varMap, effectiveFuncMaps)
 			}
+			newScope.opener = opener
 			scopeStack = append(scopeStack, newScope)
 
 		case "with":
@@ -370,6 +377,7 @@ func buildScopeAtPosition(content string, varMap map[string]ast.TemplateVar, tem
 			if hasAssignment {
 				registerAssignedLocalsSafe(&newScope, assignmentNames, withExpr, scopeStack, varMap, effectiveFuncMaps)
 			}
+			newScope.opener = opener
 			scopeStack = append(scopeStack, newScope)
 
 		case "if":
@@ -382,6 +390,7 @@ func buildScopeAtPosition(content string, varMap map[string]ast.TemplateVar, tem
 			if hasAssignment {
 				registerAssignedLocalsSafe(&top, assignmentNames, ifPipeline, scopeStack, varMap, effectiveFuncMaps)
 			}
+			top.opener = opener
 			scopeStack = append(scopeStack, top)
 		}
 
@@ -41,8 +41,6 @@ func resolvePartialScope(
 	return resolveScopeFromExpression(contextArg, scopeStack, varMap, funcMaps)
 }
 
-// buildPartialVarMap constructs the variable map available to a nested template
-// based on the context argument.
 // buildPartialVarMap constructs the variable map available to a nested template
 // based on the context argument.
 func buildPartialVarMap(
@@ -53,6 +51,12 @@ func buildPartialVarMap(
 ) map[string]ast.TemplateVar {
 	result := make(map[string]ast.TemplateVar)
 
+	// {{template "name"}} without an argument executes the template with nil
+	// data, not the caller's dot; see validateTemplateCallWithRegistry.
+	if contextArg == "" {
+		return result
+	}
+
 	if contextArg == "$" {
 		maps.Copy(result, varMap)
 		return result
@@ -153,7 +157,7 @@ func createScopeFromRange(
 		newIsMap := false
 		newIsSlice := false
 		newElemType := ""
-		// KeyType logic omitted for now as it's not critical for IsMap determination
+		newKeyType := ""
 
 		if strings.HasPrefix(baseType, "map[") {
 			// Logic to parse map[Key]Value
@@ -177,21 +181,26 @@ func createScopeFromRange(
 				valType := baseType[splitIdx+1:]
 				newIsMap = true
 				newElemType = strings.TrimSpace(valType)
+				newKeyType = strings.TrimSpace(baseType[4:splitIdx])
 			}
 		} else if strings.HasPrefix(baseType, "[]") {
 			newIsSlice = true
 			newElemType = baseType[2:]
 		}
 
-		// Return updated scope representing the element
+		// Return updated scope representing the element, typed without the
+		// pointer so []*User iterates over User. Fields are carried through
+		// unchanged: the ast extractor attaches the innermost element's fields
+		// to the collection, so map[string][]User keeps User's fields across
+		// both range levels.
 		return ScopeType{
 			IsRoot:   false,
 			VarName:  expr, // or original varExpr
-			TypeStr:  collectionScope.ElemType,
+			TypeStr:  baseType,
 			Fields:   collectionScope.Fields,
 			IsSlice:  newIsSlice,
 			IsMap:    newIsMap,
-			KeyType:  "",          // Not currently parsed
+			KeyType:  newKeyType,
 			ElemType: newElemType, // Derived from ElemType string
 		}
 	}
@@ -199,6 +208,34 @@ func createScopeFromRange(
 	return collectionScope
 }
 
+// methodResultField returns what a template sees when it evaluates f: f
+// itself for a field, and the single result for a method, marked as a slice
+// or map when it is one so that {{range .User.Orders}} iterates the
+// elements. A method with several results, or none recorded, yields a field
+// with no fields, which accepts any access.
+func methodResultField(f ast.FieldInfo) ast.FieldInfo {
+	if f.TypeStr != "method" {
+		return f
+	}
+	if len(f.Returns) != 1 {
+		return ast.FieldInfo{Name: f.Name}
+	}
+
+	ret := f.Returns[0]
+	result := ast.FieldInfo{Name: f.Name, TypeStr: ret.TypeStr, Fields: ret.Fields, Doc: ret.Doc}
+	base := strings.TrimLeft(ret.TypeStr, "*")
+	switch {
+	case strings.HasPrefix(base, "[]"):
+		result.IsSlice = true
+		result.ElemType = base[2:]
+	case strings.HasPrefix(base, "map["):
+		result.IsMap = true
+		result.KeyType = unwrapMapKeyType(base)
+		result.ElemType = unwrapCollectionElemType(base)
+	}
+	return result
+}
+
 // createScopeFromWith creates a new scope for a {{with}} block.
 //
 // With syntax: {{with .Variable}}
@@ -254,14 +291,16 @@ func resolveScopeFromExpression(
 
 func childScope(scope ScopeType) ScopeType {
 	return ScopeType{
-		IsRoot:   scope.IsRoot,
-		VarName:  scope.VarName,
-		TypeStr:  scope.TypeStr,
-		ElemType: scope.ElemType,
-		KeyType:  scope.KeyType,
-		Fields:   scope.Fields,
-		IsSlice:  scope.IsSlice,
-		IsMap:    scope.IsMap,
+		IsRoot:     scope.IsRoot,
+		VarName:    scope.VarName,
+		TypeStr:    scope.TypeStr,
+		ElemType:   scope.ElemType,
+		KeyType:    scope.KeyType,
+		Fields:     scope.Fields,
+		IsSlice:    scope.IsSlice,
+		IsMap:      scope.IsMap,
+		NilData:    scope.NilData,
+		nilInclude: scope.nilInclude,
 	}
 }
 
@@ -358,7 +397,7 @@ func elementScopeFromCollection(scope ScopeType) ScopeType {
 	newScope := childScope(scope)
 	newScope.IsRoot = false
 	newScope.VarName = scope.VarName
-	newScope.TypeStr = scope.ElemType
+	newScope.TypeStr = baseType
 	newScope.KeyType = ""
 	newScope.IsMap = false
 	newScope.IsSlice = false
@@ -390,6 +429,27 @@ func elementScopeFromCollection(scope ScopeType) ScopeType {
 	return newScope
 }
 
+// mapValueField returns the value type of the map field f as the field
+// reached by indexing it with key, as in .Config.database.
+func mapValueField(key string, f ast.FieldInfo) ast.FieldInfo {
+	elem := elementScopeFromCollection(ScopeType{
+		TypeStr:  f.TypeStr,
+		Fields:   f.Fields,
+		IsMap:    f.IsMap,
+		KeyType:  f.KeyType,
+		ElemType: f.ElemType,
+	})
+	return ast.FieldInfo{
+		Name:     key,
+		TypeStr:  elem.TypeStr,
+		Fields:   elem.Fields,
+		IsSlice:  elem.IsSlice,
+		IsMap:    elem.IsMap,
+		KeyType:  elem.KeyType,
+		ElemType: elem.ElemType,
+	}
+}
+
 func walkScopePath(scope ScopeType, parts []string) ScopeType {
 	current := childScope(scope)
 	for _, part := range parts {
@@ -553,14 +613,25 @@ func createScopeFromExpression(
 	var currentField *ast.FieldInfo
 	firstPart := parts[1]
 
-	// Look in current scope first
+	// Look in current scope first; a map scope yields its value type for any key
 	if len(scopeStack) > 0 {
 		currentScope := scopeStack[len(scopeStack)-1]
-		for _, f := range currentScope.Fields {
-			if f.Name == firstPart {
-				fCopy := f
-				currentField = &fCopy
-				break
+		if currentScope.IsMap && !currentScope.IsRoot {
+			elem := mapValueField(firstPart, ast.FieldInfo{
+				TypeStr:  currentScope.TypeStr,
+				Fields:   currentScope.Fields,
+				IsMap:    true,
+				KeyType:  currentScope.KeyType,
+				ElemType: currentScope.ElemType,
+			})
+			currentField = &elem
+		} else {
+			for _, f := range currentScope.Fields {
+				if f.Name == firstPart {
+					fCopy := methodResultField(f)
+					currentField = &fCopy
+					break
+				}
 			}
 		}
 	}
@@ -587,10 +658,15 @@ func createScopeFromExpression(
 
 	// Traverse remaining path segments
 	for _, part := range parts[2:] {
+		if currentField.IsMap {
+			elem := mapValueField(part, *currentField)
+			currentField = &elem
+			continue
+		}
 		found := false
 		for _, f := range currentField.Fields {
 			if f.Name == part {
-				fCopy := f
+				fCopy := methodResultField(f)
 				currentField = &fCopy
 				found = true
 				break
@@ -0,0 +1,117 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	templateparse "text/template/parse"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+)
+
+// validateEscaping reports escaping mistakes in an action's pipeline:
+//
+//   - a plain string passed to a function whose output bypasses
+//     auto-escaping (ast.FuncMapInfo.Unescaped), e.g. {{.Bio | safeHTML}};
+//   - a template.HTML value escaped again with the html builtin, which is
+//     redundant and mangles the markup.
+//
+// The lints are opt-in (ValidateOptions.SecurityLints); enabled turns them on.
+func validateEscaping(
+	action, first, templateName string,
+	line, col int,
+	scopeStack []ScopeType,
+	varMap map[string]ast.TemplateVar,
+	funcMaps FuncMapRegistry,
+	enabled bool,
+) []ValidationResult {
+	if !enabled {
+		return nil
+	}
+	expr, ok := actionPipeline(action, first)
+	if !ok || !strings.Contains(expr, ".") {
+		return nil
+	}
+	if _, pipeline, hasAssignment := splitAssignment(expr); hasAssignment {
+		expr = pipeline
+	}
+
+	inferencer := expressionInferencer{vars: varMap, scopeStack: scopeStack, funcMaps: funcMaps}
+	tree, err := parseExpressionTree(expr, funcMaps, inferencer.collectLocalVarNames())
+	if err != nil || len(tree.Root.Nodes) == 0 {
+		return nil
+	}
+	actionNode, ok := tree.Root.Nodes[len(tree.Root.Nodes)-1].(*templateparse.ActionNode)
+	if !ok {
+		return nil
+	}
+
+	var errors []ValidationResult
+	report := func(node templateparse.Node, message string) {
+		varExpr := node.String()
+		errors = append(errors, ValidationResult{
+			Template: templateName,
+			Line:     line,
+			Column:   col + max(strings.Index(action, varExpr), 0),
+			Variable: varExpr,
+			Message:  message,
+			Severity: "warning",
+			Rule:     "escaping",
+		})
+	}
+	inferencer.checkPipeEscaping(actionNode.Pipe, funcMaps, report)
+	return errors
+}
+
+// checkPipeEscaping inspects every function call in pipe, including
+// parenthesized sub-pipelines, and reports the field arguments (or piped
+// field) whose type is wrong for that function.
+func (i expressionInferencer) checkPipeEscaping(pipe *templateparse.PipeNode, funcMaps FuncMapRegistry, report func(templateparse.Node, string)) {
+	if pipe == nil {
+		return
+	}
+	for idx, cmd := range pipe.Cmds {
+		for _, arg := range cmd.Args {
+			if sub, ok := arg.(*templateparse.PipeNode); ok {
+				i.checkPipeEscaping(sub, funcMaps, report)
+			}
+		}
+
+		ident, ok := cmd.Args[0].(*templateparse.IdentifierNode)
+		if !ok {
+			continue
+		}
+
+		inputs := cmd.Args[1:]
+		if idx > 0 && len(pipe.Cmds[idx-1].Args) == 1 {
+			inputs = append(inputs[:len(inputs):len(inputs)], pipe.Cmds[idx-1].Args[0])
+		}
+
+		for _, input := range inputs {
+			typeStr := i.fieldTypeOf(input)
+			if typeStr == "" {
+				continue
+			}
+			switch {
+			case funcMaps[ident.Ident].Unescaped && typeStr == "string":
+				report(input, fmt.Sprintf("Field %q (%s) is rendered unescaped — potential XSS", input.String(), typeStr))
+			case ident.Ident == "html" && typeStr == "template.HTML":
+				report(input, fmt.Sprintf("Field %q (%s) is already safe HTML; escaping it with html is redundant", input.String(), typeStr))
+			}
+		}
+	}
+}
+
+// fieldTypeOf returns the type of a field or variable reference, or "" for
+// any other node (literals, function calls, ...).
+func (i expressionInferencer) fieldTypeOf(node templateparse.Node) string {
+	switch node.(type) {
+	case *templateparse.FieldNode, *templateparse.VariableNode, *templateparse.ChainNode:
+	default:
+		return ""
+	}
+	result := i.inferNode(node)
+	if result == nil {
+		return ""
+	}
+	return strings.TrimPrefix(result.TypeStr, "*")
+}
@@ -0,0 +1,172 @@
+package validator
+
+import (
+	"fmt"
+	"sync/atomic"
+)
+
+// resultSink is what every validation result passes through on its way out
+// of ValidateTemplatesWithOptions or ValidateTemplatesStream: it drops the
+// results ValidateOptions.Only excludes and those covered by a rex:ignore
+// directive, escalates warnings under ValidateOptions.WarningsAsErrors, names
+// templates of other trees relative to the base directory, enforces
+// ValidateOptions.MaxErrors and streams the kept results to emit. Filtering
+// and escalation happen before the results are counted against MaxErrors. A
+// nil *resultSink passes every result through unchanged.
+//
+// A sink is used from the concurrent validation workers; copies made by
+// withIgnore and withRename share its counts.
+type resultSink struct {
+	max      int64
+	only     string
+	escalate bool
+	ignore   *ignoreIndex
+	rename   func(string) string
+	emit     func(ValidationResult)
+	counts   *sinkCounts
+}
+
+// sinkCounts are the counts shared by a sink and its copies, including which
+// severities the results cut by MaxErrors had.
+type sinkCounts struct {
+	found      atomic.Int64
+	cutError   atomic.Bool
+	cutWarning atomic.Bool
+	cutInfo    atomic.Bool
+}
+
+// newResultSink returns the sink for opts, streaming to emit when it is not
+// nil.
+func newResultSink(opts ValidateOptions, emit func(ValidationResult)) *resultSink {
+	return &resultSink{
+		max:      int64(max(opts.MaxErrors, 0)),
+		only:     opts.Only,
+		escalate: opts.WarningsAsErrors,
+		emit:     emit,
+		counts:   &sinkCounts{},
+	}
+}
+
+// withIgnore returns a copy of s that drops the results suppressed by the
+// rex:ignore directives of ignore before anything else.
+func (s *resultSink) withIgnore(ignore *ignoreIndex) *resultSink {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	c.ignore = ignore
+	return &c
+}
+
+// withRename returns a copy of s that renames each result's template with
+// rename after filtering and before emitting it.
+func (s *resultSink) withRename(rename func(string) string) *resultSink {
+	if s == nil {
+		return nil
+	}
+	c := *s
+	c.rename = rename
+	return &c
+}
+
+// add passes results through the sink and returns those kept, which have
+// already been emitted.
+func (s *resultSink) add(results []ValidationResult) []ValidationResult {
+	if s == nil || len(results) == 0 {
+		return results
+	}
+
+	kept := results[:0]
+	for _, r := range results {
+		if !MatchTemplate(s.only, r.Template) || s.ignore.suppressed(r) {
+			continue
+		}
+		if s.rename != nil {
+			r.Template = s.rename(r.Template)
+		}
+		kept = append(kept, r)
+	}
+	if s.escalate {
+		EscalateWarnings(kept)
+	}
+
+	n := int64(len(kept))
+	found := s.counts.found.Add(n)
+	if s.max > 0 && found > s.max {
+		room := max(s.max-(found-n), 0)
+		for _, r := range kept[room:] {
+			switch r.Severity {
+			case "error", SeveritySyntax:
+				s.counts.cutError.Store(true)
+			case "warning":
+				s.counts.cutWarning.Store(true)
+			default:
+				s.counts.cutInfo.Store(true)
+			}
+		}
+		kept = kept[:room]
+	}
+
+	if s.emit != nil {
+		for _, r := range kept {
+			s.emit(r)
+		}
+	}
+	return kept
+}
+
+// full reports whether MaxErrors results have been kept, so workers can stop
+// taking new items.
+func (s *resultSink) full() bool {
+	return s != nil && s.max > 0 && s.counts.found.Load() >= s.max
+}
+
+// finish returns, and emits, the note saying how many results MaxErrors cut,
+// or nil when none were. The count only covers the results found before the
+// workers stopped. The note is an error when any of the cut results is one,
+// and names the cut results by their severity when they share one.
+func (s *resultSink) finish() *ValidationResult {
+	if s == nil || s.max <= 0 {
+		return nil
+	}
+	cut := s.counts.found.Load() - s.max
+	if cut <= 0 {
+		return nil
+	}
+	cutError, cutWarning, cutInfo := s.counts.cutError.Load(), s.counts.cutWarning.Load(), s.counts.cutInfo.Load()
+	severity := "warning"
+	if cutError {
+		severity = "error"
+	}
+	kind := "results"
+	switch {
+	case cutError && !cutWarning && !cutInfo:
+		kind = "errors"
+	case cutWarning && !cutError && !cutInfo:
+		kind = "warnings"
+	case cutInfo && !cutError && !cutWarning:
+		kind = "notes"
+	}
+	note := &ValidationResult{
+		Message:  fmt.Sprintf("… %d additional %s suppressed (limit %d)", cut, kind, s.max),
+		Severity: severity,
+	}
+	if s.emit != nil {
+		s.emit(*note)
+	}
+	return note
+}
+
+// EscalateWarnings rewrites every warning-severity result in results to an
+// error, for runs that tolerate no warnings. It modifies the elements of
+// results in place and returns the same slice; all other fields are kept
+// and results of any other severity are left unchanged, so applying it twice
+// is a no-op.
+func EscalateWarnings(results []ValidationResult) []ValidationResult {
+	for i := range results {
+		if results[i].Severity == "warning" {
+			results[i].Severity = "error"
+		}
+	}
+	return results
+}
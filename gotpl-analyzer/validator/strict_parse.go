@@ -0,0 +1,147 @@
+package validator
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"text/template"
+)
+
+// strictParseName is the name templates are parsed under by strictParse, so
+// that errors can be taken apart regardless of the file name.
+const strictParseName = "strict"
+
+var (
+	// strictParseError matches the "template: NAME:LINE: message" form of
+	// text/template parse errors.
+	strictParseError = regexp.MustCompile(`^template: ` + strictParseName + `:(\d+): (.*)$`)
+
+	// undefinedFuncError matches the parse error for an unknown function.
+	undefinedFuncError = regexp.MustCompile(`^function "([^"]+)" not defined$`)
+)
+
+// strictParse runs content through text/template's parser, which rejects
+// malformed actions the validation scanner tolerates, such as {{if}} without
+// a condition. Functions of funcMaps and the builtins are registered as
+// stubs; any other function is stubbed too and the parse retried, since
+// unknown functions are reported by the FuncMap check instead. The first
+// parse error, if any, is returned as a syntax result.
+func strictParse(content, templateName string, funcMaps FuncMapRegistry) *ValidationResult {
+	stub := func(...any) any { return nil }
+	funcs := make(template.FuncMap, len(templateBuiltins)+len(funcMaps))
+	for name := range templateBuiltins {
+		funcs[name] = stub
+	}
+	for name := range funcMaps {
+		funcs[name] = stub
+	}
+
+	for {
+		_, err := template.New(strictParseName).Funcs(funcs).Parse(content)
+		if err == nil {
+			return nil
+		}
+
+		line, msg := 1, err.Error()
+		if m := strictParseError.FindStringSubmatch(msg); m != nil {
+			line, _ = strconv.Atoi(m[1])
+			msg = m[2]
+		}
+		if m := undefinedFuncError.FindStringSubmatch(msg); m != nil {
+			if _, stubbed := funcs[m[1]]; !stubbed {
+				funcs[m[1]] = stub
+				continue
+			}
+		}
+
+		return &ValidationResult{
+			Template: templateName,
+			Line:     line,
+			Column:   0,
+			Message:  fmt.Sprintf("Template does not parse: %s", msg),
+			Severity: SeveritySyntax,
+			Rule:     "syntax",
+		}
+	}
+}
+
+// validateStrictParse runs strictParse on every template file in the tree
+// that was validated and has no syntax result yet in results.
+func validateStrictParse(
+	results []ValidationResult,
+	baseDir string,
+	templateRoot string,
+	skippedFiles map[string]bool,
+	ignore *GitignoreMatcher,
+	funcMaps FuncMapRegistry,
+	sink *resultSink,
+) []ValidationResult {
+	hasSyntaxError := make(map[string]bool)
+	for _, r := range results {
+		if r.Severity == SeveritySyntax {
+			hasSyntaxError[r.Template] = true
+		}
+	}
+
+	type workItem struct {
+		absPath string
+		relName string
+	}
+
+	var items []workItem
+	root := filepath.Join(baseDir, templateRoot)
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info == nil {
+			return nil
+		}
+		if ignore.Match(path, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() || !IsFileBasedPartial(path) {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			rel = path
+		}
+		rel = filepath.ToSlash(rel)
+		if skippedFiles[rel] || hasSyntaxError[rel] {
+			return nil
+		}
+		items = append(items, workItem{absPath: path, relName: rel})
+		return nil
+	})
+	if len(items) == 0 {
+		return nil
+	}
+
+	return runWorkers(len(items), sink, func(chunk []int) []ValidationResult {
+		var errs []ValidationResult
+		for _, i := range chunk {
+			item := items[i]
+			content, err := os.ReadFile(item.absPath)
+			if err != nil {
+				continue
+			}
+			if r := strictParse(string(content), item.relName, funcMaps); r != nil {
+				errs = append(errs, *r)
+			}
+		}
+		return errs
+	})
+}
+
+// StrictParseTemplate runs the strict parse of ValidateOptions.StrictParse on
+// a single template's content, as the daemon does for live validation. An
+// error covered by a rex:ignore comment of content is left out.
+func StrictParseTemplate(content, templateName string, funcMaps FuncMapRegistry) []ValidationResult {
+	if r := strictParse(content, templateName, funcMaps); r != nil {
+		return suppressIgnored([]ValidationResult{*r}, content, 1)
+	}
+	return nil
+}
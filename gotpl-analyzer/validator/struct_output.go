@@ -0,0 +1,105 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+)
+
+// structOutputRule warns when an output action emits a struct as a whole,
+// as in {{.}} inside {{with .User}} or {{.User}}: text/template then prints a
+// Go-syntax dump such as {Alice 42}, which is rarely what a page should show.
+// It runs as a Rule so that it only applies with
+// ValidateOptions.WarnStructOutput.
+type structOutputRule struct{}
+
+func (structOutputRule) Check(ctx RuleContext) []ValidationResult {
+	var results []ValidationResult
+	for _, a := range ctx.Actions {
+		if w := structOutputWarning(a, ctx.Template, ctx.Vars); w != nil {
+			results = append(results, *w)
+		}
+	}
+	return results
+}
+
+// StructOutputWarnings runs the check of ValidateOptions.WarnStructOutput on
+// a single template's content, as the daemon does for live validation.
+// lineOffset is the line content starts at in the file. Warnings covered by
+// a rex:ignore comment of content are left out.
+func StructOutputWarnings(
+	content string,
+	vars []ast.TemplateVar,
+	templateName string,
+	lineOffset int,
+	registry map[string][]NamedBlockEntry,
+	funcMaps FuncMapRegistry,
+) []ValidationResult {
+	varMap := buildVarMap(vars)
+	actions := collectRuleActions(content, varMap, templateName, "", "", lineOffset, registry, funcMaps)
+	results := structOutputRule{}.Check(RuleContext{Template: templateName, Content: content, Vars: varMap, Actions: actions})
+	return suppressIgnored(results, content, lineOffset)
+}
+
+// structOutputWarning returns the warning for a, or nil when a is not a bare
+// reference to a struct. A struct with a String or Error method prints
+// through it and is not reported.
+func structOutputWarning(a RuleAction, templateName string, varMap map[string]ast.TemplateVar) *ValidationResult {
+	action := a.Text
+	if !isOutputReference(action) {
+		return nil
+	}
+
+	inferencer := expressionInferencer{vars: varMap, scopeStack: a.ScopeStack}
+	var result *ExpressionTypeResult
+	if strings.HasPrefix(action, "$") {
+		result = inferencer.resolveVariablePath(strings.Split(action, "."))
+	} else {
+		result = inferencer.resolveFieldPath(append([]string{"."}, strings.Split(action[1:], ".")...))
+	}
+	if !isPlainStruct(result) {
+		return nil
+	}
+
+	warning := &ValidationResult{
+		Template: templateName,
+		Variable: action,
+		Message:  fmt.Sprintf("Rendering %q directly prints a struct; did you mean a specific field?", action),
+		Severity: "warning",
+		Rule:     "struct-output",
+	}
+	warning.setRange(action, 0, a.Line, a.Column)
+	return warning
+}
+
+// isOutputReference reports whether action only prints a variable or field
+// reference such as ., .User.Address or $user: a single token that is not a
+// keyword, function call or declaration.
+func isOutputReference(action string) bool {
+	if strings.ContainsAny(action, " \t\r\n()|=") {
+		return false
+	}
+	return action == "." || strings.HasPrefix(action, ".") && !strings.HasPrefix(action, "..") ||
+		strings.HasPrefix(action, "$") && action != "$"
+}
+
+// isPlainStruct reports whether result is a struct, as far as its fields
+// tell, without a String or Error method to format it. The root context and
+// collections are not structs.
+func isPlainStruct(result *ExpressionTypeResult) bool {
+	if result == nil || result.TypeStr == "context" || result.IsSlice || result.IsMap {
+		return false
+	}
+	hasField := false
+	for _, f := range result.Fields {
+		if f.TypeStr != "method" {
+			hasField = true
+			continue
+		}
+		if (f.Name == "String" || f.Name == "Error") && len(f.Params) == 0 {
+			return false
+		}
+	}
+	return hasField
+}
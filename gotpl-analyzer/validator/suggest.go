@@ -0,0 +1,108 @@
+package validator
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+)
+
+// maxSuggestionDistance is the largest edit distance between an unknown root
+// variable and a provided one for the latter to be offered as a suggestion.
+const maxSuggestionDistance = 2
+
+// maxSuggestionCandidates bounds how many provided names, in sorted order,
+// are compared against an unknown one on very large contexts.
+const maxSuggestionCandidates = 1000
+
+// suggestionCandidates is the sorted list of names offered as suggestions
+// for an unknown root variable of a template: the variables provided to it
+// and the fields of its root scope. It is built on the first undefined root
+// variable of a template and reused for the later ones.
+type suggestionCandidates struct {
+	names []string
+	built bool
+}
+
+// list returns the candidates for varMap and the root scope fields. A nil c
+// builds them without keeping them.
+func (c *suggestionCandidates) list(varMap map[string]ast.TemplateVar, fields []ast.FieldInfo) []string {
+	if c != nil && c.built {
+		return c.names
+	}
+
+	// Candidates are compared in sorted order so that, past the cap, the
+	// same names are checked on every run despite map iteration order.
+	names := make([]string, 0, len(varMap)+len(fields))
+	for name := range varMap {
+		names = append(names, name)
+	}
+	for _, f := range fields {
+		if _, ok := varMap[f.Name]; !ok {
+			names = append(names, f.Name)
+		}
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+	names = names[:min(len(names), maxSuggestionCandidates)]
+
+	if c != nil {
+		c.names, c.built = names, true
+	}
+	return names
+}
+
+// withRootSuggestion appends a "did you mean" hint to err when exactly one of
+// candidates is within maxSuggestionDistance of name. Nil-data errors are
+// left alone since no variables were provided at all.
+func withRootSuggestion(err *ValidationResult, name string, candidates []string) *ValidationResult {
+	if err == nil || err.nilData {
+		return err
+	}
+
+	var match string
+	matches := 0
+	for _, candidate := range candidates {
+		if abs(len(candidate)-len(name)) > maxSuggestionDistance || editDistance(name, candidate) > maxSuggestionDistance {
+			continue
+		}
+		match = candidate
+		if matches++; matches > 1 {
+			break
+		}
+	}
+
+	if matches == 1 {
+		err.Message += fmt.Sprintf(" — did you mean %q?", match)
+	}
+	return err
+}
+
+// editDistance returns the Levenshtein distance between a and b, counting
+// single-byte insertions, deletions and substitutions.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
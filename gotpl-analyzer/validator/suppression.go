@@ -0,0 +1,233 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// ignoreDirective is the marker of a template comment that suppresses
+// results of the action following it:
+//
+//	{{/* rex:ignore undefined-variable */}}
+//	{{.InjectedByMiddleware}}
+//
+// Rule ids (see RuleID) are separated by spaces or commas; without any, every
+// result of the action is suppressed. Results located in Go code, such as
+// duplicate-var and builtin-shadow warnings, are suppressed by a
+// "// rex:ignore" line comment on their line or the line before it.
+const ignoreDirective = "rex:ignore"
+
+// RuleID returns the id of the check that produced r, such as
+// "undefined-variable" or "missing-template" (see ValidationResult.Rule), for
+// suppression comments and other consumers that filter results by category.
+// Results of custom rules and other notes have no id.
+func RuleID(r ValidationResult) string {
+	return r.Rule
+}
+
+// parseIgnoreDirective returns the rule ids of a comment action carrying
+// ignoreDirective, and whether it carries it.
+func parseIgnoreDirective(action string) ([]string, bool) {
+	text := strings.TrimSpace(action)
+	text = strings.TrimPrefix(text, "/*")
+	text = strings.TrimSuffix(text, "*/")
+	text = strings.TrimSpace(text)
+	rest, ok := strings.CutPrefix(text, ignoreDirective)
+	if !ok || rest != "" && !isWhitespace(rest[0]) {
+		return nil, false
+	}
+	return strings.FieldsFunc(rest, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\t' || r == '\n' || r == '\r'
+	}), true
+}
+
+// suppressResults removes the results matching rules, or all of them when
+// rules is empty.
+func suppressResults(results []ValidationResult, rules []string) []ValidationResult {
+	return slices.DeleteFunc(results, func(r ValidationResult) bool {
+		return len(rules) == 0 || slices.Contains(rules, RuleID(r))
+	})
+}
+
+// ignoreIndex applies the rex:ignore directives of a template tree to
+// results once every pass has produced them, so that the results of rules
+// and other whole-tree checks are suppressed like those of
+// ValidateTemplateContent. Files are read once, when a result first points
+// into them; an index is safe for concurrent use.
+type ignoreIndex struct {
+	baseDir string
+	root    string
+	mu      sync.Mutex
+	// spans are keyed by template name, or by Go file for goSpans.
+	spans   map[string][]ignoreSpan
+	goSpans map[string][]ignoreSpan
+}
+
+// ignoreSpan is the part of a file a directive applies to, from the start
+// position up to, but excluding, the end position. Zero columns compare by
+// line only.
+type ignoreSpan struct {
+	rules              []string
+	startLine, endLine int
+	startCol, endCol   int
+}
+
+// newIgnoreIndex returns the index of the tree at baseDir/templateRoot.
+func newIgnoreIndex(baseDir, templateRoot string) *ignoreIndex {
+	return &ignoreIndex{
+		baseDir: baseDir,
+		root:    filepath.Join(baseDir, templateRoot),
+		spans:   make(map[string][]ignoreSpan),
+		goSpans: make(map[string][]ignoreSpan),
+	}
+}
+
+// suppressed reports whether a directive covers r: a template comment before
+// the action at r's position or, for results located in Go code only, a line
+// comment on or above r's Go line.
+func (x *ignoreIndex) suppressed(r ValidationResult) bool {
+	if x == nil {
+		return false
+	}
+	if r.Template != "" && r.Line > 0 {
+		x.mu.Lock()
+		spans, ok := x.spans[r.Template]
+		if !ok {
+			// Templates of other trees are named relative to the base
+			// directory.
+			content, err := os.ReadFile(filepath.Join(x.root, r.Template))
+			if err != nil {
+				content, err = os.ReadFile(filepath.Join(x.baseDir, r.Template))
+			}
+			if err == nil {
+				spans = templateIgnoreSpans(string(content))
+			}
+			x.spans[r.Template] = spans
+		}
+		x.mu.Unlock()
+		return covers(spans, r.Line, r.Column, r.Rule)
+	}
+	if r.GoFile != "" && r.GoLine > 0 {
+		x.mu.Lock()
+		spans, ok := x.goSpans[r.GoFile]
+		if !ok {
+			path := r.GoFile
+			if !filepath.IsAbs(path) {
+				path = filepath.Join(x.baseDir, path)
+			}
+			if content, err := os.ReadFile(path); err == nil {
+				spans = goIgnoreSpans(string(content))
+			}
+			x.goSpans[r.GoFile] = spans
+		}
+		x.mu.Unlock()
+		return covers(spans, r.GoLine, 0, r.Rule)
+	}
+	return false
+}
+
+// covers reports whether one of spans covers line and col for rule.
+func covers(spans []ignoreSpan, line, col int, rule string) bool {
+	for _, s := range spans {
+		if len(s.rules) > 0 && !slices.Contains(s.rules, rule) {
+			continue
+		}
+		if line < s.startLine || line > s.endLine {
+			continue
+		}
+		if col > 0 && s.startCol > 0 {
+			if line == s.startLine && col < s.startCol || line == s.endLine && col >= s.endCol {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// suppressIgnored removes the results located in content, which starts at
+// lineOffset in its file, that a rex:ignore comment of content covers. It
+// applies the directives to the single-template helpers the daemon calls
+// after ValidateContentWithOptions.
+func suppressIgnored(results []ValidationResult, content string, lineOffset int) []ValidationResult {
+	spans := templateIgnoreSpans(content)
+	if len(spans) == 0 {
+		return results
+	}
+	return slices.DeleteFunc(results, func(r ValidationResult) bool {
+		return covers(spans, r.Line-lineOffset+1, r.Column, r.Rule)
+	})
+}
+
+// templateIgnoreSpans returns the actions of content that follow a
+// rex:ignore comment.
+func templateIgnoreSpans(content string) []ignoreSpan {
+	var spans []ignoreSpan
+	lines := newLineIndex(content)
+	var rules []string
+	armed := false
+	cur := 0
+	for cur < len(content) {
+		openRel := strings.Index(content[cur:], "{{")
+		if openRel == -1 {
+			break
+		}
+		openIdx := cur + openRel
+
+		start := openIdx + 2
+		if start < len(content) && content[start] == '-' {
+			start++
+		}
+		for start < len(content) && isWhitespace(content[start]) {
+			start++
+		}
+		searchFrom := start
+		if strings.HasPrefix(content[start:], "/*") {
+			endComment := strings.Index(content[start+2:], "*/")
+			if endComment == -1 {
+				break
+			}
+			searchFrom = start + 2 + endComment + 2
+		}
+		closeRel := strings.Index(content[searchFrom:], "}}")
+		if closeRel == -1 {
+			break
+		}
+		cur = searchFrom + closeRel + 2
+
+		if searchFrom != start {
+			if r, ok := parseIgnoreDirective(content[start:searchFrom]); ok {
+				rules, armed = r, true
+			}
+			continue
+		}
+		if armed {
+			span := ignoreSpan{rules: rules}
+			span.startLine, span.startCol = lines.position(openIdx)
+			span.endLine, span.endCol = lines.position(cur - 1)
+			span.endCol++
+			spans = append(spans, span)
+			armed = false
+		}
+	}
+	return spans
+}
+
+// goIgnoreSpans returns the lines of Go source content covered by a
+// "// rex:ignore" comment: its own line and the next one.
+func goIgnoreSpans(content string) []ignoreSpan {
+	var spans []ignoreSpan
+	for i, line := range strings.Split(content, "\n") {
+		idx := strings.Index(line, "//")
+		if idx == -1 {
+			continue
+		}
+		if rules, ok := parseIgnoreDirective(line[idx+2:]); ok {
+			spans = append(spans, ignoreSpan{rules: rules, startLine: i + 1, endLine: i + 2})
+		}
+	}
+	return spans
+}
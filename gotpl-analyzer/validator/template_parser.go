@@ -2,26 +2,41 @@ package validator
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
-	"regexp"
 	"runtime"
+	"sort"
 	"strings"
 	"sync"
 )
 
-// defineOrBlockNameRe extracts the quoted name from a define or block action.
-// Still used for the name-extraction sub-step (cheap single match per action).
-var defineOrBlockNameRe = regexp.MustCompile(`^(?:define|block)\s+"([^"]+)"`)
-
 // parseAllNamedTemplates extracts all {{define}} and {{block}} declarations
 // from template files in the specified directory tree.
 func parseAllNamedTemplates(baseDir, templateRoot string) (map[string][]NamedBlockEntry, []NamedBlockDuplicateError) {
+	registry, errors, _ := parseAllNamedTemplatesWithMaxSize(baseDir, templateRoot, 0, nil, slog.New(slog.DiscardHandler))
+	return registry, errors
+}
+
+// parseAllNamedTemplatesWithMaxSize is parseAllNamedTemplates with files
+// larger than maxBytes skipped; a non-positive maxBytes means no limit. It
+// also returns the slash-separated paths, relative to the template root, of
+// the skipped files. Paths matched by ignore are not walked at all.
+func parseAllNamedTemplatesWithMaxSize(baseDir, templateRoot string, maxBytes int, ignore *GitignoreMatcher, logger *slog.Logger) (map[string][]NamedBlockEntry, []NamedBlockDuplicateError, []string) {
 	root := filepath.Join(baseDir, templateRoot)
 
 	var templateFiles []string
 	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() {
+		if err != nil {
+			return nil
+		}
+		if ignore.Match(path, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
 			return nil
 		}
 		if IsFileBasedPartial(path) {
@@ -30,20 +45,26 @@ func parseAllNamedTemplates(baseDir, templateRoot string) (map[string][]NamedBlo
 		return nil
 	})
 
-	registry := processTemplateFilesConcurrently(templateFiles, root)
+	logger.Debug("found template files", "root", root, "files", len(templateFiles))
+
+	registry, skipped := processTemplateFilesConcurrently(templateFiles, root, maxBytes)
+	for _, rel := range skipped {
+		logger.Debug("skipped template file", "file", rel, "maxBytes", maxBytes)
+	}
 	errors := detectDuplicateBlocks(registry)
-	return registry, errors
+	return registry, errors, skipped
 }
 
 // processTemplateFilesConcurrently processes template files using a worker pool.
-func processTemplateFilesConcurrently(templateFiles []string, root string) map[string][]NamedBlockEntry {
+func processTemplateFilesConcurrently(templateFiles []string, root string, maxBytes int) (map[string][]NamedBlockEntry, []string) {
 	if len(templateFiles) == 0 {
-		return make(map[string][]NamedBlockEntry)
+		return make(map[string][]NamedBlockEntry), nil
 	}
 
 	var (
 		mu       sync.Mutex
 		registry = make(map[string][]NamedBlockEntry)
+		skipped  []string
 	)
 
 	numWorkers := max(runtime.NumCPU(), 1)
@@ -57,21 +78,26 @@ func processTemplateFilesConcurrently(templateFiles []string, root string) map[s
 	var wg sync.WaitGroup
 	for range numWorkers {
 		wg.Go(func() {
-			processTemplateFileWorker(fileChan, root, &mu, registry)
+			processTemplateFileWorker(fileChan, root, maxBytes, &mu, registry, &skipped)
 		})
 	}
 	wg.Wait()
 
-	return registry
+	sort.Strings(skipped)
+	return registry, skipped
 }
 
 // processTemplateFileWorker reads files from fileChan, parses named blocks,
 // and merges results into the shared registry under the provided mutex.
+// Files larger than maxBytes (when positive) are not read; their relative
+// paths are appended to skipped instead.
 func processTemplateFileWorker(
 	fileChan <-chan string,
 	root string,
+	maxBytes int,
 	mu *sync.Mutex,
 	registry map[string][]NamedBlockEntry,
+	skipped *[]string,
 ) {
 	for path := range fileChan {
 		rel, err := filepath.Rel(root, path)
@@ -80,6 +106,19 @@ func processTemplateFileWorker(
 		}
 		rel = filepath.ToSlash(rel)
 
+		if maxBytes > 0 {
+			info, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			if info.Size() > int64(maxBytes) {
+				mu.Lock()
+				*skipped = append(*skipped, rel)
+				mu.Unlock()
+				continue
+			}
+		}
+
 		content, err := os.ReadFile(path)
 		if err != nil {
 			continue
@@ -122,7 +161,7 @@ func detectDuplicateBlocks(registry map[string][]NamedBlockEntry) []NamedBlockDu
 // allocates a [][]int per match and runs the NFA on every byte.  The hand-
 // written scanner below:
 //   - Allocates a fixed []actionSpan slice (reused via local var).
-//   - Only runs the regexp on the ~10-character keyword prefix, not on the
+//   - Only unquotes the name following the define/block keyword, not the
 //     full content.
 //   - Skips comments in a single byte comparison.
 //
@@ -135,6 +174,7 @@ func extractNamedTemplatesFromContent(content, absolutePath, templatePath string
 
 	var (
 		activeName  string
+		activeBlock bool
 		startOffset int
 		startLine   int
 		startCol    int
@@ -144,6 +184,7 @@ func extractNamedTemplatesFromContent(content, absolutePath, templatePath string
 	// Scan using the pre-scanner from content_validator.go (shared package).
 	// We cannot call scanActions directly from the validator package here
 	// (different package), so we inline the equivalent logic.
+	lines := newLineIndex(content)
 	i := 0
 	n := len(content)
 
@@ -206,22 +247,20 @@ func extractNamedTemplatesFromContent(content, absolutePath, templatePath string
 		keyword := firstWord(action)
 
 		// Compute 1-based line and column for diagnostics.
-		before := content[:fullStart]
-		lineNum := 1 + strings.Count(before, "\n")
-		lastNL := strings.LastIndexByte(before, '\n')
-		col := fullStart - lastNL // 1-based
+		lineNum, col := lines.position(fullStart)
 
 		switch keyword {
 		case "define", "block":
 			if activeName != "" {
 				depth++
 			} else {
-				m := defineOrBlockNameRe.FindStringSubmatch(action)
-				if m == nil {
+				name, _, ok := splitQuotedName(strings.TrimSpace(action[len(keyword):]))
+				if !ok {
 					i = fullEnd
 					continue
 				}
-				activeName = m[1]
+				activeName = name
+				activeBlock = keyword == "block"
 				startOffset = fullEnd
 				startLine = lineNum
 				startCol = col
@@ -244,6 +283,7 @@ func extractNamedTemplatesFromContent(content, absolutePath, templatePath string
 						TemplatePath: templatePath,
 						Line:         startLine,
 						Col:          startCol,
+						IsBlock:      activeBlock,
 					})
 					activeName = ""
 				}
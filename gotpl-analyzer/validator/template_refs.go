@@ -0,0 +1,170 @@
+package validator
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	templateparse "text/template/parse"
+)
+
+// TemplateRef is a single {{template}} action found in template content.
+type TemplateRef struct {
+	// Name is the referenced template name, unquoted.
+	Name string `json:"name"`
+
+	// Line is the 1-based line of the action.
+	Line int `json:"line"`
+
+	// Column is the 1-based column of the action's "template" keyword.
+	Column int `json:"column"`
+
+	// Context is the context argument as text/template prints it, empty
+	// when the action passes none.
+	Context string `json:"context,omitempty"`
+}
+
+// ExtractTemplateRefs returns every {{template}} action in content in source
+// order, without resolving or validating the targets. content is parsed with
+// text/template/parse, without checking that functions are defined, so
+// comments, quoting and {{block}} actions are handled as text/template
+// handles them. Content that does not parse, such as a {{template}} missing
+// its name, has no references.
+func ExtractTemplateRefs(content string) []TemplateRef {
+	tree := templateparse.New("content")
+	tree.Mode = templateparse.SkipFuncCheck
+	treeSet := make(map[string]*templateparse.Tree)
+	if _, err := tree.Parse(content, leftDelim, rightDelim, treeSet); err != nil {
+		return nil
+	}
+
+	// {{define}} and {{block}} bodies are separate trees of treeSet.
+	var nodes []*templateparse.TemplateNode
+	collectTemplateNodes(tree.Root, &nodes)
+	for _, t := range treeSet {
+		if t != tree {
+			collectTemplateNodes(t.Root, &nodes)
+		}
+	}
+	slices.SortFunc(nodes, func(a, b *templateparse.TemplateNode) int { return int(a.Pos) - int(b.Pos) })
+
+	var refs []TemplateRef
+	lines := newLineIndex(content)
+	for _, n := range nodes {
+		// A TemplateNode starts at its name; {{block}} yields one as well.
+		before := strings.TrimRight(content[:n.Pos], " \t\r\n")
+		if !strings.HasSuffix(before, "template") {
+			continue
+		}
+		line, col := lines.position(len(before) - len("template"))
+		ref := TemplateRef{Name: n.Name, Line: line, Column: col}
+		if n.Pipe != nil {
+			ref.Context = n.Pipe.String()
+		}
+		refs = append(refs, ref)
+	}
+	return refs
+}
+
+// collectTemplateNodes appends the TemplateNodes of list, including those
+// nested in if, range and with branches, to nodes.
+func collectTemplateNodes(list *templateparse.ListNode, nodes *[]*templateparse.TemplateNode) {
+	if list == nil {
+		return
+	}
+	for _, node := range list.Nodes {
+		switch n := node.(type) {
+		case *templateparse.TemplateNode:
+			*nodes = append(*nodes, n)
+		case *templateparse.IfNode:
+			collectTemplateNodes(n.List, nodes)
+			collectTemplateNodes(n.ElseList, nodes)
+		case *templateparse.RangeNode:
+			collectTemplateNodes(n.List, nodes)
+			collectTemplateNodes(n.ElseList, nodes)
+		case *templateparse.WithNode:
+			collectTemplateNodes(n.List, nodes)
+			collectTemplateNodes(n.ElseList, nodes)
+		}
+	}
+}
+
+// forEachAction calls fn, in source order, with the trimmed text of every
+// action in content and the offset it starts at. Comment actions are skipped
+// as a whole, and so is everything from an unterminated action on.
+func forEachAction(content string, fn func(action string, start int)) {
+	cur := 0
+	for cur < len(content) {
+		openRel := strings.Index(content[cur:], leftDelim)
+		if openRel == -1 {
+			break
+		}
+		openIdx := cur + openRel
+
+		start := openIdx + len(leftDelim)
+		if start < len(content) && content[start] == '-' {
+			start++
+		}
+		for start < len(content) && isWhitespace(content[start]) {
+			start++
+		}
+
+		// A comment may itself contain "}}"; skip to the end of the comment
+		// before looking for the closing delimiter.
+		searchFrom := start
+		if strings.HasPrefix(content[start:], "/*") {
+			endComment := strings.Index(content[start+2:], "*/")
+			if endComment == -1 {
+				break
+			}
+			searchFrom = start + 2 + endComment + 2
+		}
+		closeRel := strings.Index(content[searchFrom:], rightDelim)
+		if closeRel == -1 {
+			break
+		}
+		closeIdx := searchFrom + closeRel
+		cur = closeIdx + len(rightDelim)
+
+		if searchFrom != start {
+			continue
+		}
+
+		end := closeIdx
+		if end > start && content[end-1] == '-' {
+			end--
+		}
+		fn(strings.TrimSpace(content[start:end]), start)
+	}
+}
+
+// TemplateDeps maps every template file under baseDir/templateRoot, keyed by
+// its slash-separated path relative to the root, to the sorted, de-duplicated
+// names it includes through {{template}}. Files without references map to an
+// empty list so that every template appears as a node of the graph.
+func TemplateDeps(baseDir, templateRoot string) map[string][]string {
+	deps := make(map[string][]string)
+	root := filepath.Join(baseDir, templateRoot)
+	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || !IsFileBasedPartial(path) {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			rel = path
+		}
+
+		names := []string{}
+		for _, ref := range ExtractTemplateRefs(string(content)) {
+			names = append(names, ref.Name)
+		}
+		slices.Sort(names)
+		deps[filepath.ToSlash(rel)] = slices.Compact(names)
+		return nil
+	})
+	return deps
+}
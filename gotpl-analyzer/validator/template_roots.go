@@ -0,0 +1,302 @@
+package validator
+
+import (
+	"cmp"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+)
+
+// templateRootKey identifies one template tree.
+type templateRootKey struct {
+	baseDir, templateRoot string
+}
+
+// validatePerTemplateRoot groups renderCalls by the template tree
+// opts.TemplateRootResolver assigns to their Go file and validates each tree
+// on its own, exactly as ValidateTemplatesWithOptions validates a single one.
+// Trees that no render call resolves to are not validated.
+//
+// Results from a tree other than baseDir/templateRoot name their template by
+// its path relative to baseDir, e.g. "serviceA/templates/index.html", so that
+// same-named templates of different services stay distinguishable. Named
+// blocks and duplicate errors are merged; duplicates are only detected within
+// a tree, since each service parses its own templates.
+func validatePerTemplateRoot(
+	renderCalls []ast.RenderCall,
+	funcMaps []ast.FuncMapInfo,
+	baseDir string,
+	templateRoot string,
+	opts ValidateOptions,
+) ([]ValidationResult, map[string][]NamedBlockEntry, []NamedBlockDuplicateError, []string) {
+	resolve := opts.TemplateRootResolver
+	opts.TemplateRootResolver = nil
+
+	groups := make(map[templateRootKey][]ast.RenderCall)
+	for _, rc := range renderCalls {
+		b, r := resolve(rc.File)
+		key := templateRootKey{filepath.Clean(b), filepath.Clean(r)}
+		groups[key] = append(groups[key], rc)
+	}
+	keys := make([]templateRootKey, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	slices.SortFunc(keys, func(a, b templateRootKey) int {
+		return cmp.Compare(filepath.Join(a.baseDir, a.templateRoot), filepath.Join(b.baseDir, b.templateRoot))
+	})
+
+	defaultRoot := filepath.Join(baseDir, templateRoot)
+	var (
+		results         []ValidationResult
+		namedBlocks     = make(map[string][]NamedBlockEntry)
+		namedBlockDupes []NamedBlockDuplicateError
+		notes           []string
+	)
+	for _, key := range keys {
+		rootOpts := opts
+		root := filepath.Join(key.baseDir, key.templateRoot)
+		if root != defaultRoot {
+			rootOpts.sink = opts.sink.withRename(func(name string) string {
+				return relativeTemplateName(baseDir, root, name)
+			})
+		}
+		res, blocks, dupes, skipped := validateTemplateRoot(groups[key], funcMaps, key.baseDir, key.templateRoot, rootOpts)
+		results = append(results, res...)
+		for name, entries := range blocks {
+			namedBlocks[name] = append(namedBlocks[name], entries...)
+		}
+		namedBlockDupes = append(namedBlockDupes, dupes...)
+		notes = append(notes, skipped...)
+	}
+	return results, namedBlocks, namedBlockDupes, notes
+}
+
+// relativeTemplateName rewrites name, relative to root, as a slash-separated
+// path relative to baseDir. Names outside baseDir are returned unchanged.
+func relativeTemplateName(baseDir, root, name string) string {
+	rel, err := filepath.Rel(baseDir, filepath.Join(root, name))
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return name
+	}
+	return filepath.ToSlash(rel)
+}
+
+// templateTree is one root of validateTemplateRoots.
+type templateTree struct {
+	root    string // relative to baseDir
+	dir     string // baseDir joined with root
+	blocks  map[string][]NamedBlockEntry
+	files   []string // slash-separated, relative to dir
+	skipped map[string]bool
+	calls   []ast.RenderCall
+}
+
+// validateTemplateRoots validates templateRoot together with
+// opts.ExtraTemplateRoots. Each tree is validated as validateTemplateRoot
+// validates a single one, against its own named blocks plus the named blocks
+// and template files of the other trees. Those are added as foreign entries,
+// named by their path relative to baseDir, so that only their own tree
+// validates them. A render call is validated in the first tree containing
+// its template, as a file or a named block, and in the first tree otherwise.
+func validateTemplateRoots(
+	renderCalls []ast.RenderCall,
+	funcMaps []ast.FuncMapInfo,
+	baseDir string,
+	templateRoot string,
+	opts ValidateOptions,
+) ([]ValidationResult, map[string][]NamedBlockEntry, []NamedBlockDuplicateError, []string) {
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+
+	var (
+		results []ValidationResult
+		trees   []*templateTree
+		notes   []string
+	)
+	start := time.Now()
+	for _, root := range append([]string{templateRoot}, opts.ExtraTemplateRoots...) {
+		if err := checkTemplateRoot(baseDir, root); err != nil {
+			results = append(results, opts.sink.add([]ValidationResult{*err})...)
+			continue
+		}
+		t := &templateTree{root: root, dir: filepath.Join(baseDir, root), skipped: make(map[string]bool)}
+		var skipped []string
+		t.blocks, _, skipped = parseAllNamedTemplatesWithMaxSize(baseDir, root, opts.MaxTemplateBytes, opts.Ignore, logger)
+		opts.markRegistry(t.blocks)
+		for _, rel := range skipped {
+			t.skipped[rel] = true
+			notes = append(notes, fmt.Sprintf("skipped %s: exceeds max size", relativeTemplateName(baseDir, t.dir, rel)))
+		}
+		t.files = templateFilesUnder(t.dir, opts.Ignore)
+		trees = append(trees, t)
+	}
+	if len(trees) == 0 {
+		return results, map[string][]NamedBlockEntry{}, nil, notes
+	}
+
+	namedBlocks := make(map[string][]NamedBlockEntry)
+	for _, t := range trees {
+		for name, entries := range t.blocks {
+			namedBlocks[name] = append(namedBlocks[name], entries...)
+		}
+	}
+	var namedBlockErrors []NamedBlockDuplicateError
+	if opts.AllowBlockOverride {
+		for _, t := range trees {
+			namedBlockErrors = append(namedBlockErrors, dropBlockOverrideDuplicates(detectDuplicateBlocks(t.blocks))...)
+		}
+	} else {
+		namedBlockErrors = append(detectDuplicateBlocks(namedBlocks), duplicateTemplateFiles(trees)...)
+	}
+	logger.Info("parsed named templates", "roots", len(trees), "blocks", len(namedBlocks), "duplicates", len(namedBlockErrors), "skipped", len(notes), "duration", time.Since(start))
+
+	for _, rc := range renderCalls {
+		t := trees[0]
+		for _, candidate := range trees {
+			if info, err := os.Stat(filepath.Join(candidate.dir, rc.Template)); (err == nil && !info.IsDir()) || len(candidate.blocks[rc.Template]) > 0 {
+				t = candidate
+				break
+			}
+		}
+		t.calls = append(t.calls, rc)
+	}
+
+	funcMapRegistry := BuildFuncMapRegistry(funcMaps)
+	defaultDir := filepath.Join(baseDir, templateRoot)
+	for _, t := range trees {
+		registry, foreign := foreignRegistry(baseDir, t, trees, opts.AllowBlockOverride)
+		treeOpts := opts
+		if t.dir != defaultDir {
+			treeOpts.sink = opts.sink.withRename(func(name string) string {
+				if foreign[name] {
+					return name
+				}
+				return relativeTemplateName(baseDir, t.dir, name)
+			})
+		}
+		results = append(results, validateWithRegistry(t.calls, funcMapRegistry, baseDir, t.root, registry, t.skipped, treeOpts, logger)...)
+	}
+	return results, namedBlocks, namedBlockErrors, notes
+}
+
+// foreignRegistry returns the registry t is validated against: its own named
+// blocks followed by the foreign entries of the other trees, in order, and
+// the template names of those entries. A template file of another tree is
+// added as an entry named like the file unless t has one of the same name.
+// With allowOverride, a named block of another tree is only added when
+// neither t nor an earlier tree defines it.
+func foreignRegistry(baseDir string, t *templateTree, trees []*templateTree, allowOverride bool) (map[string][]NamedBlockEntry, map[string]bool) {
+	registry := make(map[string][]NamedBlockEntry, len(t.blocks))
+	for name, entries := range t.blocks {
+		registry[name] = slices.Clone(entries)
+	}
+	foreign := make(map[string]bool)
+	own := make(map[string]bool, len(t.files))
+	for _, rel := range t.files {
+		own[rel] = true
+	}
+
+	for _, other := range trees {
+		if other == t {
+			continue
+		}
+		for name, entries := range other.blocks {
+			if allowOverride && len(registry[name]) > 0 {
+				continue
+			}
+			for _, e := range entries {
+				e.TemplatePath = relativeTemplateName(baseDir, other.dir, e.TemplatePath)
+				e.foreign = true
+				foreign[e.TemplatePath] = true
+				registry[name] = append(registry[name], e)
+			}
+		}
+		for _, rel := range other.files {
+			if own[rel] || other.skipped[rel] {
+				continue
+			}
+			own[rel] = true
+			path := filepath.Join(other.dir, filepath.FromSlash(rel))
+			content, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			name := relativeTemplateName(baseDir, other.dir, rel)
+			foreign[name] = true
+			registry[rel] = append(registry[rel], NamedBlockEntry{
+				Name:         rel,
+				AbsolutePath: path,
+				TemplatePath: name,
+				Line:         1,
+				Col:          1,
+				Content:      string(content),
+				foreign:      true,
+			})
+		}
+	}
+	return registry, foreign
+}
+
+// duplicateTemplateFiles reports every template file found, under the same
+// relative path, in more than one of trees.
+func duplicateTemplateFiles(trees []*templateTree) []NamedBlockDuplicateError {
+	byName := make(map[string][]NamedBlockEntry)
+	for _, t := range trees {
+		for _, rel := range t.files {
+			byName[rel] = append(byName[rel], NamedBlockEntry{
+				Name:         rel,
+				AbsolutePath: filepath.Join(t.dir, filepath.FromSlash(rel)),
+				TemplatePath: rel,
+				Line:         1,
+				Col:          1,
+			})
+		}
+	}
+	var errors []NamedBlockDuplicateError
+	for _, name := range slices.Sorted(maps.Keys(byName)) {
+		if entries := byName[name]; len(entries) > 1 {
+			errors = append(errors, NamedBlockDuplicateError{
+				Name:    name,
+				Entries: entries,
+				Message: fmt.Sprintf(`Template "%s" found in more than one template root`, name),
+			})
+		}
+	}
+	return errors
+}
+
+// templateFilesUnder lists the template files under root not excluded by
+// ignore, as slash-separated paths relative to root.
+func templateFilesUnder(root string, ignore *GitignoreMatcher) []string {
+	var files []string
+	filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if ignore.Match(path, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || !IsFileBasedPartial(path) {
+			return nil
+		}
+		if rel, err := filepath.Rel(root, path); err == nil {
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	return files
+}
@@ -0,0 +1,193 @@
+package validator
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+)
+
+// TemplateSet caches the named blocks of a template tree so that individual
+// render calls can be validated without re-walking the tree each time, e.g.
+// on every keystroke in an editor. Parse once with NewTemplateSet, validate
+// with ValidateCall and keep the cache current with Reload.
+//
+// A TemplateSet is safe for concurrent use.
+type TemplateSet struct {
+	baseDir      string
+	templateRoot string
+	root         string
+	opts         ValidateOptions
+
+	mu       sync.RWMutex
+	byFile   map[string]map[string][]NamedBlockEntry // template path → blocks it declares
+	skipped  map[string]bool                         // template paths over opts.MaxTemplateBytes
+	registry map[string][]NamedBlockEntry            // merged view of byFile
+	funcMaps FuncMapRegistry
+}
+
+// NewTemplateSet parses all {{define}} and {{block}} declarations under
+// baseDir/templateRoot. It fails if that directory cannot be read.
+func NewTemplateSet(baseDir, templateRoot string) (*TemplateSet, error) {
+	return NewTemplateSetWithOptions(baseDir, templateRoot, ValidateOptions{})
+}
+
+// NewTemplateSetWithOptions is NewTemplateSet tuned by opts, which apply to
+// parsing, Reload and ValidateCall as they do in
+// ValidateTemplatesWithOptions. Files matched by opts.Ignore are not parsed,
+// and files larger than opts.MaxTemplateBytes contribute no named blocks and
+// are not validated. opts.TemplateRootResolver and opts.ExtraTemplateRoots
+// are ignored since a set covers a single tree, and so are the options of
+// whole-tree checks such as opts.Rules and opts.StrictParse.
+func NewTemplateSetWithOptions(baseDir, templateRoot string, opts ValidateOptions) (*TemplateSet, error) {
+	root := filepath.Join(baseDir, templateRoot)
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("template root %s is not a directory", root)
+	}
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	registry, _, skipped := parseAllNamedTemplatesWithMaxSize(baseDir, templateRoot, opts.MaxTemplateBytes, opts.Ignore, logger)
+	opts.markRegistry(registry)
+
+	skippedFiles := make(map[string]bool, len(skipped))
+	for _, rel := range skipped {
+		skippedFiles[rel] = true
+	}
+
+	byFile := make(map[string]map[string][]NamedBlockEntry)
+	for name, entries := range registry {
+		for _, e := range entries {
+			if byFile[e.TemplatePath] == nil {
+				byFile[e.TemplatePath] = make(map[string][]NamedBlockEntry)
+			}
+			byFile[e.TemplatePath][name] = append(byFile[e.TemplatePath][name], e)
+		}
+	}
+
+	return &TemplateSet{
+		baseDir:      baseDir,
+		templateRoot: templateRoot,
+		root:         root,
+		opts:         opts,
+		byFile:       byFile,
+		skipped:      skippedFiles,
+		registry:     registry,
+		funcMaps:     make(FuncMapRegistry),
+	}, nil
+}
+
+// SetFuncMaps replaces the function maps used to validate template function
+// calls.
+func (s *TemplateSet) SetFuncMaps(funcMaps []ast.FuncMapInfo) {
+	registry := BuildFuncMapRegistry(funcMaps)
+	s.mu.Lock()
+	s.funcMaps = registry
+	s.mu.Unlock()
+}
+
+// NamedBlocks returns the cached named blocks. The map is a snapshot and must
+// not be modified.
+func (s *TemplateSet) NamedBlocks() map[string][]NamedBlockEntry {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.registry
+}
+
+// ValidateCall validates the template targeted by rc against the cached
+// named blocks, like ValidateTemplatesWithOptions does for each of its render
+// calls with the options of the set. A target skipped for its size has no
+// results.
+func (s *TemplateSet) ValidateCall(rc ast.RenderCall) []ValidationResult {
+	s.mu.RLock()
+	registry, funcMaps, skipped := s.registry, s.funcMaps, s.skipped[rc.Template]
+	s.mu.RUnlock()
+	if skipped {
+		return nil
+	}
+
+	sink := newResultSink(s.opts, nil).withIgnore(newIgnoreIndex(s.baseDir, s.templateRoot))
+	vars := MergeCallContexts([]ast.RenderCall{rc})[rc.Template]
+	results := validateRenderTarget(rc, vars, rc.NilData && len(vars) == 0, s.baseDir, s.templateRoot, registry, funcMaps, s.opts.contentOptions())
+	results = sink.add(linkRenderCall(results, rc))
+	if note := sink.finish(); note != nil {
+		results = append(results, *note)
+	}
+	return results
+}
+
+// Reload re-parses the given template files, which may be absolute or
+// relative to the template root, and replaces the blocks they declared.
+// Deleted files, and files now ignored or too large, drop their blocks.
+func (s *TemplateSet) Reload(changedPaths []string) error {
+	parsed := make(map[string]map[string][]NamedBlockEntry, len(changedPaths))
+	oversized := make(map[string]bool, len(changedPaths))
+	for _, p := range changedPaths {
+		abs := p
+		if !filepath.IsAbs(abs) {
+			abs = filepath.Join(s.root, p)
+		}
+		rel, err := filepath.Rel(s.root, abs)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			return fmt.Errorf("%s is outside the template root %s", p, s.root)
+		}
+		rel = filepath.ToSlash(rel)
+
+		if s.opts.Ignore.Match(abs, false) {
+			parsed[rel] = nil
+			continue
+		}
+		if info, err := os.Stat(abs); err == nil && s.opts.MaxTemplateBytes > 0 && info.Size() > int64(s.opts.MaxTemplateBytes) {
+			parsed[rel] = nil
+			oversized[rel] = true
+			continue
+		}
+		content, err := os.ReadFile(abs)
+		if os.IsNotExist(err) || !IsFileBasedPartial(abs) {
+			parsed[rel] = nil
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		local := make(map[string][]NamedBlockEntry)
+		extractNamedTemplatesFromContent(string(content), abs, rel, local)
+		parsed[rel] = local
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for rel, blocks := range parsed {
+		if oversized[rel] {
+			s.skipped[rel] = true
+		} else {
+			delete(s.skipped, rel)
+		}
+		if len(blocks) == 0 {
+			delete(s.byFile, rel)
+		} else {
+			s.byFile[rel] = blocks
+		}
+	}
+
+	// Rebuild rather than patch so snapshots handed out earlier stay intact.
+	registry := make(map[string][]NamedBlockEntry, len(s.registry))
+	for _, blocks := range s.byFile {
+		for name, entries := range blocks {
+			registry[name] = append(registry[name], entries...)
+		}
+	}
+	s.opts.markRegistry(registry)
+	s.registry = registry
+	return nil
+}
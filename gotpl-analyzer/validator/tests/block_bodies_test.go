@@ -0,0 +1,66 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestValidateBlockBodies(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"page.html":   `{{template "card" .User}}{{range .Items}}{{template "row" .}}{{end}}{{template "card" $.Missing}}`,
+		"other.html":  `{{.Title}}`,
+		"blocks.html": `{{define "card"}}{{.Name}}{{.Nope}}{{end}}{{define "row"}}{{.Label}}{{template "cell" .}}{{end}}{{define "cell"}}{{.Label}}{{.Width}}{{end}}{{define "unused"}}{{.Title}}{{.Gone}}{{end}}`,
+	})
+	calls := []ast.RenderCall{
+		{
+			Template: "page.html",
+			Vars: []ast.TemplateVar{
+				{Name: "Title", TypeStr: "string"},
+				{Name: "User", TypeStr: "User", Fields: []ast.FieldInfo{{Name: "Name", TypeStr: "string"}}},
+				{Name: "Items", TypeStr: "[]Item", IsSlice: true, ElemType: "Item", Fields: []ast.FieldInfo{{Name: "Label", TypeStr: "string"}}},
+			},
+		},
+		{Template: "other.html", Vars: []ast.TemplateVar{{Name: "Title", TypeStr: "string"}}},
+	}
+
+	find := func(results []validator.ValidationResult, variable string) *validator.ValidationResult {
+		for i := range results {
+			if results[i].Variable == variable && results[i].Template == "blocks.html" {
+				return &results[i]
+			}
+		}
+		return nil
+	}
+
+	off, _, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "", validator.ValidateOptions{})
+	if r := find(off, ".Gone"); r != nil {
+		t.Errorf("expected the uncalled block to go unchecked by default, got %#v", r)
+	}
+
+	on, _, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "", validator.ValidateOptions{ValidateBlockBodies: true})
+	tests := []struct {
+		variable string
+		severity string // empty when no result is expected
+	}{
+		{".Nope", "error"},   // missing from the .User context card is called with
+		{".Name", ""},        // provided by .User
+		{".Label", ""},       // provided by the range element, also in the nested cell
+		{".Width", "error"},  // cell reached through row, whose context is the element
+		{".Title", ""},       // provided by every render call
+		{".Gone", "warning"}, // unused block, checked against the common variables
+	}
+	for _, tt := range tests {
+		r := find(on, tt.variable)
+		switch {
+		case tt.severity == "" && r != nil:
+			t.Errorf("%s: expected no result, got %#v", tt.variable, r)
+		case tt.severity != "" && r == nil:
+			t.Errorf("%s: expected a %s, got none in %#v", tt.variable, tt.severity, on)
+		case tt.severity != "" && r.Severity != tt.severity:
+			t.Errorf("%s: expected severity %s, got %#v", tt.variable, tt.severity, r)
+		}
+	}
+}
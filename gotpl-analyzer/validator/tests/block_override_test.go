@@ -0,0 +1,50 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestBlockOverrideValidatesOverridingDefine(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"base.html": `<main>{{block "content" .}}{{.Title}}{{end}}</main>`,
+		"page.html": `{{define "content"}}{{.Body}}{{end}}{{template "base.html" .}}`,
+	}
+	writeTree(t, dir, files)
+	calls := []ast.RenderCall{{
+		File:     "main.go",
+		Line:     1,
+		Template: "page.html",
+		Vars:     []ast.TemplateVar{{Name: "Title", TypeStr: "string"}},
+	}}
+
+	// Without the option any entry that validates cleanly is accepted, so the
+	// block default hides the override's missing field.
+	errs, _, dups, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "", validator.ValidateOptions{})
+	if len(errs) != 0 {
+		t.Errorf("expected the block default to satisfy the call, got %#v", errs)
+	}
+	if len(dups) != 1 {
+		t.Errorf("expected block and define to be reported as duplicates, got %#v", dups)
+	}
+
+	errs, namedBlocks, dups, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "", validator.ValidateOptions{AllowBlockOverride: true})
+	if len(errs) != 1 || !strings.Contains(errs[0].Message, `".Body"`) {
+		t.Fatalf("expected the override's .Body to be reported, got %#v", errs)
+	}
+	if len(dups) != 0 {
+		t.Errorf("expected no duplicate for an override, got %#v", dups)
+	}
+	for _, e := range namedBlocks["content"] {
+		if e.OverridesBlock != (e.TemplatePath == "page.html") {
+			t.Errorf("expected only the define in page.html to win, got %+v", e)
+		}
+		if e.IsBlock != (e.TemplatePath == "base.html") {
+			t.Errorf("expected only base.html to hold the block, got %+v", e)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package validator_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestBooleanChainValidatesEveryOperand(t *testing.T) {
+	vars := map[string]ast.TemplateVar{
+		"A": {Name: "A", TypeStr: "bool"},
+		"B": {Name: "B", TypeStr: "B", Fields: []ast.FieldInfo{
+			{Name: "C", TypeStr: "bool"},
+			{Name: "D", TypeStr: "D", Fields: []ast.FieldInfo{{Name: "E", TypeStr: "bool"}}},
+		}},
+		"F": {Name: "F", TypeStr: "bool"},
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string // undefined variables, in source order
+	}{
+		{"all defined", `{{if and .A .B.C (or .F .B.D.E)}}x{{end}}`, nil},
+		{"deeply parenthesized", `{{if and (or (and .A (not .B.C)) (or .F (and .B.D.E .A))) .F}}x{{end}}`, nil},
+		{"missing in nested group", `{{if and .A (or .F (and .B.X (not .Nope)))}}x{{end}}`, []string{".B.X", ".Nope"}},
+		{"missing deep field", `{{if or (and .B.D.Z .A) .F}}x{{end}}`, []string{".B.D.Z"}},
+		{"repeated missing reported once", `{{if or .Nope (and .A .Nope) (not .Nope)}}x{{end}}`, []string{".Nope"}},
+		{"repeated per action", `{{if .Nope}}{{.Nope}}{{end}}`, []string{".Nope", ".Nope"}},
+		{"else if chain", `{{if .A}}a{{else if and .F (or .Gone .B.C)}}b{{end}}`, []string{".Gone"}},
+		{"operators are not variables", `{{if and (eq .A .F) (ne .B.C .F) (or (lt 1 2) (not .A))}}x{{end}}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validator.ValidateTemplateContent(tt.content, vars, "page.html", ".", ".", 1, nil)
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Variable)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected errors for %v, got %#v", tt.want, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package validator_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestBuilders(t *testing.T) {
+	name := validator.Field("Name", "string")
+
+	cases := []struct {
+		got, want any
+	}{
+		{name, ast.FieldInfo{Name: "Name", TypeStr: "string"}},
+		{validator.Field("Tags", "[]string"), ast.FieldInfo{Name: "Tags", TypeStr: "[]string", IsSlice: true, ElemType: "string"}},
+		{validator.Field("Scores", "map[string]*Score"), ast.FieldInfo{Name: "Scores", TypeStr: "map[string]*Score", IsMap: true, KeyType: "string", ElemType: "Score"}},
+		{validator.Struct("user", "User", name), ast.TemplateVar{Name: "user", TypeStr: "User", Fields: []ast.FieldInfo{name}}},
+		{validator.Slice("users", "User", name), ast.TemplateVar{Name: "users", TypeStr: "[]User", IsSlice: true, ElemType: "User", Fields: []ast.FieldInfo{name}}},
+		{validator.Map("byID", "int", "User", name), ast.TemplateVar{Name: "byID", TypeStr: "map[int]User", IsMap: true, KeyType: "int", ElemType: "User", Fields: []ast.FieldInfo{name}}},
+	}
+	for _, c := range cases {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("got %+v, want %+v", c.got, c.want)
+		}
+	}
+}
+
+func TestBuildersValidate(t *testing.T) {
+	vars := []ast.TemplateVar{
+		validator.Struct("user", "User", validator.Field("Name", "string"), validator.Field("Tags", "[]string")),
+		validator.Slice("orders", "Order", validator.Field("Total", "float64")),
+		validator.Map("settings", "string", "Setting", validator.Field("Value", "string")),
+	}
+	content := `{{.user.Name}}{{range .user.Tags}}{{.}}{{end}}
+{{range .orders}}{{.Total}}{{.Missing}}{{end}}
+{{range $k, $s := .settings}}{{$k}}={{$s.Value}}{{end}}`
+
+	errs := validator.ValidateWith(content, vars, "page.html", nil)
+	if len(errs) != 1 || errs[0].Variable != ".Missing" {
+		t.Errorf("expected only .Missing to be reported, got %#v", errs)
+	}
+}
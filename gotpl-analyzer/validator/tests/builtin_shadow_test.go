@@ -0,0 +1,33 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestBuiltinShadowWarning(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"templates/index.html": `{{len .Items}} {{upper .Title}}`})
+	calls := []ast.RenderCall{{Template: "index.html", Vars: []ast.TemplateVar{
+		{Name: "Items", TypeStr: "[]string"},
+		{Name: "Title", TypeStr: "string"},
+	}}}
+	funcMaps := []ast.FuncMapInfo{
+		{Name: "len", DefFile: "funcs.go", DefLine: 12},
+		{Name: "upper", DefFile: "funcs.go", DefLine: 20},
+	}
+
+	results, _, _ := validator.ValidateTemplates(calls, funcMaps, dir, "templates")
+	if len(results) != 1 {
+		t.Fatalf("expected a single shadowing warning, got %+v", results)
+	}
+	r := results[0]
+	if r.Message != `Custom function "len" shadows a template builtin` || r.Severity != "warning" {
+		t.Errorf("unexpected result %+v", r)
+	}
+	if r.GoFile != "funcs.go" || r.GoLine != 12 {
+		t.Errorf("expected the warning at funcs.go:12, got %s:%d", r.GoFile, r.GoLine)
+	}
+}
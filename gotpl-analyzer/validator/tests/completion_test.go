@@ -0,0 +1,91 @@
+package validator_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+var completionVars = []ast.TemplateVar{
+	{Name: "Title", TypeStr: "string", Doc: "Title is the page title."},
+	{Name: "User", TypeStr: "User", Fields: []ast.FieldInfo{
+		{Name: "Name", TypeStr: "string", Doc: "Name is the display name."},
+		{Name: "Nick", TypeStr: "string"},
+		{Name: "Email", TypeStr: "string"},
+		{Name: "Address", TypeStr: "Address", Fields: []ast.FieldInfo{
+			{Name: "City", TypeStr: "string"},
+		}},
+		{Name: "FullName", TypeStr: "func() string", Returns: []ast.ParamInfo{{TypeStr: "string"}}},
+	}},
+	{Name: "Items", TypeStr: "[]Item", IsSlice: true, ElemType: "Item", Fields: []ast.FieldInfo{
+		{Name: "Label", TypeStr: "string"},
+		{Name: "Price", TypeStr: "float64"},
+	}},
+}
+
+// completionNames returns the names offered at the "|" marker in content.
+func completionNames(t *testing.T, content string) []string {
+	t.Helper()
+	offset := strings.Index(content, "|")
+	if offset == -1 {
+		t.Fatalf("no cursor marker in %q", content)
+	}
+	content = content[:offset] + content[offset+1:]
+	var names []string
+	for _, item := range validator.CompletionsAt(content, completionVars, offset) {
+		names = append(names, item.Name)
+	}
+	return names
+}
+
+func TestCompletionsAt(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"root variables", `<h1>{{ .| }}</h1>`, []string{"Items", "Title", "User"}},
+		{"root prefix", `{{ .T| }}`, []string{"Title"}},
+		{"struct fields and methods", `{{ .User.| }}`, []string{"Address", "Email", "FullName", "Name", "Nick"}},
+		{"field prefix", `{{ .User.N| }}`, []string{"Name", "Nick"}},
+		{"nested field", `{{ .User.Address.| }}`, []string{"City"}},
+		{"unterminated action", `<p>{{ .User.Addr|`, []string{"Address"}},
+		{"inside an if pipeline", `{{ if and .Title .User.E| }}`, []string{"Email"}},
+		{"range element scope", `{{range .Items}}{{ .| }}{{end}}`, []string{"Label", "Price"}},
+		{"with scope", `{{with .User}}{{ .Address.| }}{{end}}`, []string{"City"}},
+		{"root from nested scope", `{{range .Items}}{{ $.| }}{{end}}`, []string{"Items", "Title", "User"}},
+		{"root path from nested scope", `{{range .Items}}{{ $.User.Em| }}{{end}}`, []string{"Email"}},
+		{"locals in scope", `{{ $u := .User }}{{range $i, $item := .Items}}{{ $| }}{{end}}`, []string{"$i", "$item", "$u"}},
+		{"local fields", `{{ $u := .User }}{{ $u.Na| }}`, []string{"Name"}},
+		{"locals after their scope closed", `{{range $item := .Items}}{{end}}{{ $| }}`, nil},
+		{"scope closed before cursor", `{{with .User}}{{end}}{{ .| }}`, []string{"Items", "Title", "User"}},
+		{"no fields on a slice", `{{ .Items.| }}`, nil},
+		{"outside an action", `<p>.Us|</p>`, nil},
+		{"unknown base", `{{ .Nope.| }}`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := completionNames(t, tt.content); !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCompletionItemDetails(t *testing.T) {
+	content := `{{ .User.F }}`
+	items := validator.CompletionsAt(content, completionVars, strings.Index(content, "F")+1)
+	if len(items) != 1 || !items[0].IsMethod || items[0].Type != "func() string" {
+		t.Fatalf("expected the FullName method, got %#v", items)
+	}
+
+	content = `{{ .User.Na }}`
+	items = validator.CompletionsAt(content, completionVars, strings.Index(content, "Na")+2)
+	if len(items) != 1 || items[0].IsMethod || items[0].Doc != "Name is the display name." {
+		t.Fatalf("expected the documented Name field, got %#v", items)
+	}
+}
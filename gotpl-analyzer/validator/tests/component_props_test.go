@@ -0,0 +1,49 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestComponentProps(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"templates/components/card.html": "{{define \"card\"}}{{/* props: Title string, Count int */}}\n" +
+			"<h2>{{.Title}}</h2>{{end}}\n" +
+			"{{define \"plain\"}}{{.Title}}{{end}}",
+		"templates/page.html": `{{template "card" dict "Title" .Post.Title "Count" 1}}` + "\n" +
+			`{{template "card" dict "Title" .Post.Title}}` + "\n" +
+			`{{template "card" .Post}}` + "\n" +
+			`{{template "plain" .Post}}`,
+	})
+	post := ast.TemplateVar{Name: "Post", TypeStr: "Post", Fields: []ast.FieldInfo{{Name: "Title", TypeStr: "string"}}}
+	calls := []ast.RenderCall{{Template: "page.html", Vars: []ast.TemplateVar{post}}}
+
+	results, namedBlocks, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "templates", validator.ValidateOptions{ComponentProps: "props"})
+
+	var lines []int
+	for _, r := range results {
+		if r.Template != "page.html" || r.Message != `Component "card" requires prop "Count" which is not provided` || r.Severity != "error" {
+			t.Errorf("unexpected result %+v", r)
+			continue
+		}
+		lines = append(lines, r.Line)
+	}
+	if len(lines) != 2 || lines[0] != 2 || lines[1] != 3 {
+		t.Errorf("expected missing Count on lines 2 and 3, got %v", lines)
+	}
+
+	card := namedBlocks["card"]
+	want := []validator.ComponentProp{{Name: "Title", Type: "string"}, {Name: "Count", Type: "int"}}
+	if len(card) != 1 || len(card[0].Props) != 2 || card[0].Props[0] != want[0] || card[0].Props[1] != want[1] {
+		t.Errorf("expected card props %+v, got %+v", want, card)
+	}
+
+	// The annotation is opt-in.
+	results, _, _, _ = validator.ValidateTemplatesWithOptions(calls, nil, dir, "templates", validator.ValidateOptions{})
+	if len(results) != 0 {
+		t.Errorf("expected no results without ComponentProps, got %+v", results)
+	}
+}
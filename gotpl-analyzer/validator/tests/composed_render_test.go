@@ -0,0 +1,47 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// TestComposedRenderCall verifies that a call composing several templates
+// validates its last template against the data, resolves the blocks the
+// others define, and reports listed templates that do not exist.
+func TestComposedRenderCall(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"layouts/base.html": `{{define "layout"}}<title>{{.Title}}</title>{{template "content" .}}{{end}}`,
+		"page.html":         `{{template "layout" .}}{{define "content"}}<h1>{{.Title}}</h1>{{.Missing}}{{end}}`,
+	})
+	calls := []ast.RenderCall{{
+		File:         "handlers.go",
+		Line:         12,
+		Template:     "page.html",
+		ComposedWith: []string{"layouts/base.html", "layouts/gone.html"},
+		ResolvedVia:  ast.ResolvedViaSlice,
+		Vars:         []ast.TemplateVar{{Name: "Title", TypeStr: "string"}},
+	}}
+
+	results, _, _ := validator.ValidateTemplates(calls, nil, dir, "")
+
+	var missing, gone []validator.ValidationResult
+	for _, r := range results {
+		switch {
+		case r.Variable == ".Missing":
+			missing = append(missing, r)
+		case r.Template == "layouts/gone.html":
+			gone = append(gone, r)
+		default:
+			t.Errorf("unexpected result %+v", r)
+		}
+	}
+	if len(missing) == 0 {
+		t.Errorf("expected .Missing to be reported against the call's data, got %+v", results)
+	}
+	if len(gone) != 1 || gone[0].Message != "Template or named block not found: layouts/gone.html" || gone[0].GoFile != "handlers.go" || gone[0].GoLine != 12 {
+		t.Errorf("expected the missing layout to be reported at the call, got %+v", gone)
+	}
+}
@@ -0,0 +1,64 @@
+package validator_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// TestCRLFPositions checks that templates checked out with CRLF line endings
+// report the same lines and columns as their LF equivalents.
+func TestCRLFPositions(t *testing.T) {
+	lf := map[string]string{
+		"templates/page.html": "<p>\n  {{ .User.Nope }}\n{{ range .Items }}\n  {{ .Bad }}{{ end }}\n" +
+			"{{ template \"part\" .User }}\n{{ if\n  .Missing }}x{{ end }}\n{{ (index .Items 0).Zip }}\n" +
+			"{{ block \"b\" .User }}\n  {{ .Zed }}\n{{ end }}\n",
+		"templates/part.html": "{{ define \"part\" }}\n  {{ .Name }}\n  {{ .Qq }}\n{{ end }}\n",
+	}
+	crlf := make(map[string]string, len(lf))
+	for name, content := range lf {
+		crlf[name] = strings.ReplaceAll(content, "\n", "\r\n")
+	}
+
+	user := ast.TemplateVar{Name: "User", TypeStr: "User", Fields: []ast.FieldInfo{{Name: "Name", TypeStr: "string"}}}
+	items := ast.TemplateVar{Name: "Items", TypeStr: "[]User", IsSlice: true, ElemType: "User", Fields: user.Fields}
+	calls := []ast.RenderCall{{Template: "page.html", Vars: []ast.TemplateVar{user, items}}}
+
+	positions := func(files map[string]string) []string {
+		dir := t.TempDir()
+		writeTree(t, dir, files)
+		results, blocks, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "templates", validator.ValidateOptions{})
+		var out []string
+		for _, r := range results {
+			out = append(out, fmt.Sprintf("%s %d:%d-%d:%d %s", r.Template, r.Line, r.Column, r.EndLine, r.EndColumn, r.Message))
+		}
+		for name, entries := range blocks {
+			for _, e := range entries {
+				out = append(out, fmt.Sprintf("block %s %s %d:%d", name, e.TemplatePath, e.Line, e.Col))
+			}
+		}
+		for _, ref := range validator.ExtractTemplateRefs(files["templates/page.html"]) {
+			out = append(out, fmt.Sprintf("ref %s %d:%d", ref.Name, ref.Line, ref.Column))
+		}
+		slices.Sort(out)
+		return out
+	}
+
+	want, got := positions(lf), positions(crlf)
+	if len(want) == 0 {
+		t.Fatal("expected positions to compare")
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("CRLF positions differ from LF:\ngot:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	content := "{{ .User.Name }}\r\n{{ .User.Na"
+	completions := validator.CompletionsAt(content, []ast.TemplateVar{user}, len(content))
+	if len(completions) != 1 || completions[0].Name != "Name" {
+		t.Errorf("expected completion of Name on a CRLF second line, got %#v", completions)
+	}
+}
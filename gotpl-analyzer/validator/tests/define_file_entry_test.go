@@ -0,0 +1,88 @@
+package validator_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+const defineOnlyLayout = `{{/* base layout */}}
+{{define "layout"}}
+<h1>{{.Title}}</h1>
+<p>{{.Missing}}</p>
+{{end}}
+`
+
+func TestDefineIsFileEntryValidatesDefineBody(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "layout.html"), []byte(defineOnlyLayout), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	call := ast.RenderCall{
+		File:     "handlers.go",
+		Line:     10,
+		Template: "layout.html",
+		Vars:     []ast.TemplateVar{{Name: "Title", TypeStr: "string"}},
+	}
+
+	// Without the option the file's top-level content is empty and the
+	// define body is never checked against the render call's variables.
+	errs, _, _ := validator.ValidateTemplates([]ast.RenderCall{call}, nil, dir, "")
+	for _, e := range errs {
+		if e.Variable == ".Missing" {
+			t.Fatalf("did not expect .Missing to be reported without DefineIsFileEntry: %#v", e)
+		}
+	}
+
+	errs, _, _, _ = validator.ValidateTemplatesWithOptions([]ast.RenderCall{call}, nil, dir, "", validator.ValidateOptions{DefineIsFileEntry: true})
+
+	var found *validator.ValidationResult
+	for i := range errs {
+		if errs[i].Variable == ".Missing" {
+			found = &errs[i]
+		}
+		if errs[i].Variable == ".Title" {
+			t.Errorf("unexpected error for .Title: %#v", errs[i])
+		}
+	}
+	if found == nil {
+		t.Fatalf("expected .Missing to be reported, got %#v", errs)
+	}
+	if found.Line != 4 || found.GoFile != "handlers.go" {
+		t.Errorf("expected error on line 4 linked to handlers.go, got line %d goFile %q", found.Line, found.GoFile)
+	}
+}
+
+func TestFileEntryDefine(t *testing.T) {
+	cases := []struct {
+		name     string
+		template string
+		content  string
+		wantOK   bool
+	}{
+		{"matching define", "views/layout.html", defineOnlyLayout, true},
+		{"trim markers", "layout.html", "{{define \"layout\" -}}\n{{.Title}}\n{{- end -}}\n", true},
+		{"name differs from file", "base.html", defineOnlyLayout, false},
+		{"extra top-level content", "layout.html", "<html>{{define \"layout\"}}x{{end}}</html>", false},
+		{"second define", "layout.html", "{{define \"layout\"}}x{{end}}{{define \"nav\"}}y{{end}}", false},
+		{"block renders itself", "layout.html", "{{block \"layout\" .}}x{{end}}", false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, _, ok := validator.FileEntryDefine(tc.content, tc.template)
+			if ok != tc.wantOK {
+				t.Errorf("FileEntryDefine ok = %v, want %v", ok, tc.wantOK)
+			}
+		})
+	}
+
+	body, line, _ := validator.FileEntryDefine(defineOnlyLayout, "layout.html")
+	if line != 2 || body != "\n<h1>{{.Title}}</h1>\n<p>{{.Missing}}</p>\n" {
+		t.Errorf("unexpected body %q at line %d", body, line)
+	}
+}
@@ -0,0 +1,78 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestCheckDelimiterBalance(t *testing.T) {
+	type pos struct {
+		line, col int
+		message   string
+	}
+	cases := []struct {
+		name, content, open, close string
+		want                       []pos
+	}{
+		{"balanced", "<p>{{.A}}</p>\n{{if .B}}{{end}}", "{{", "}}", nil},
+		{"quoted and commented delimiters", "{{printf \"}} {{\" .A}}{{/* }} {{ */}}{{`}}`}}", "{{", "}}", nil},
+		{"dangling open mid-file", "{{.A}}\n<p>{{ .B</p>\n{{.C}}", "{{", "}}", []pos{
+			{2, 4, "Unclosed action tag '{{' at line 2 — add the closing '}}'"},
+		}},
+		{"unclosed at end", "{{.A}}\n{{.B", "{{", "}}", []pos{
+			{2, 1, "Unclosed action tag '{{' at line 2 — add the closing '}}'"},
+		}},
+		{"stray close", "{{.A}}\n<p>done}}</p>", "{{", "}}", nil},
+		{"inline javascript", "<script>var cfg = {a: {b: 1}};</script>\n{{.A}}", "{{", "}}", nil},
+		{"inline css", "<style>@media print { p { color: red }}</style>", "{{", "}}", nil},
+		{"custom delimiters", "[[.A]] {{.B}}\n[[.C", "[[", "]]", []pos{
+			{2, 1, "Unclosed action tag '[[' at line 2 — add the closing ']]'"},
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			results := validator.CheckDelimiterBalance(c.content, c.open, c.close)
+			if len(results) != len(c.want) {
+				t.Fatalf("expected %d results, got %#v", len(c.want), results)
+			}
+			for i, r := range results {
+				w := c.want[i]
+				if r.Line != w.line || r.Column != w.col || r.Message != w.message || r.Severity != validator.SeveritySyntax {
+					t.Errorf("got %d:%d %q (%s), want %d:%d %q", r.Line, r.Column, r.Message, r.Severity, w.line, w.col, w.message)
+				}
+			}
+		})
+	}
+}
+
+func TestUnbalancedDelimitersKeepValidating(t *testing.T) {
+	vars := map[string]ast.TemplateVar{
+		"A": {Name: "A", TypeStr: "string"},
+		"B": {Name: "B", TypeStr: "string"},
+	}
+	content := "{{.A}}\n<p>{{ .A</p>\n{{.B}} }}\n{{.Missing}}"
+
+	errs := validator.ValidateTemplateContent(content, vars, "page.html", ".", ".", 1, nil)
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 results, got %#v", errs)
+	}
+	if errs[0].Severity != validator.SeveritySyntax || errs[0].Line != 2 || errs[0].Template != "page.html" {
+		t.Errorf("expected the dangling {{ on line 2, got %+v", errs[0])
+	}
+	if errs[1].Variable != ".Missing" || errs[1].Line != 4 {
+		t.Errorf("expected .Missing to be reported on line 4, got %+v", errs[1])
+	}
+}
+
+func TestInlineScriptAndStyleBracesAreNotSyntaxErrors(t *testing.T) {
+	vars := map[string]ast.TemplateVar{"Theme": {Name: "Theme", TypeStr: "string"}}
+	content := "<script>var cfg = {a: {b: 1}};</script>\n" +
+		"<style>@media print { p { color: red }}</style>\n" +
+		"<body class=\"{{.Theme}}\"></body>"
+
+	if errs := validator.ValidateTemplateContent(content, vars, "page.html", ".", ".", 1, nil); len(errs) != 0 {
+		t.Errorf("expected no results, got %#v", errs)
+	}
+}
@@ -0,0 +1,61 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+var deprecatedVars = map[string]ast.TemplateVar{
+	"User": {
+		Name:    "User",
+		TypeStr: "User",
+		Fields: []ast.FieldInfo{
+			{Name: "Name", TypeStr: "string"},
+			{Name: "OldName", TypeStr: "string", Deprecated: "use Name instead."},
+			{Name: "Legacy", TypeStr: "Profile", Deprecated: "use Profile.", Fields: []ast.FieldInfo{
+				{Name: "Bio", TypeStr: "string"},
+			}},
+		},
+	},
+	"Users": {
+		Name:     "Users",
+		TypeStr:  "[]User",
+		IsSlice:  true,
+		ElemType: "User",
+		Fields: []ast.FieldInfo{
+			{Name: "OldName", TypeStr: "string", Deprecated: "use Name instead."},
+		},
+	},
+}
+
+func TestDeprecatedFieldWarning(t *testing.T) {
+	content := `{{.User.Name}} {{.User.OldName}}`
+
+	errs := validator.ValidateTemplateContent(content, deprecatedVars, "test.html", ".", ".", 1, nil)
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 warning, got %d: %#v", len(errs), errs)
+	}
+	if errs[0].Severity != "warning" || errs[0].Message != `Field "OldName" is deprecated: use Name instead.` {
+		t.Errorf("Unexpected result %#v", errs[0])
+	}
+}
+
+func TestDeprecatedIntermediateFieldIsNotReported(t *testing.T) {
+	content := `{{.User.Legacy.Bio}}`
+
+	errs := validator.ValidateTemplateContent(content, deprecatedVars, "test.html", ".", ".", 1, nil)
+	if len(errs) != 0 {
+		t.Errorf("Expected no warnings for an intermediate segment, got %#v", errs)
+	}
+}
+
+func TestDeprecatedFieldInRangeScope(t *testing.T) {
+	content := `{{range .Users}}{{.OldName}}{{end}}`
+
+	errs := validator.ValidateTemplateContent(content, deprecatedVars, "test.html", ".", ".", 1, nil)
+	if len(errs) != 1 || errs[0].Variable != ".OldName" || errs[0].Severity != "warning" {
+		t.Errorf("Expected a deprecation warning for .OldName, got %#v", errs)
+	}
+}
@@ -0,0 +1,83 @@
+package validator_test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// TestDescribeType verifies that a named type is described with its field
+// tree, docs and methods without any render call using it.
+func TestDescribeType(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"go.mod": "module example.com/test\ngo 1.21\n",
+		"main.go": `package main
+
+// User is a registered account.
+type User struct {
+	// Name is the display name.
+	Name    string
+	Address Address
+	secret  string
+}
+
+type Address struct {
+	City string
+}
+
+// Greeting returns a salutation.
+func (u User) Greeting() string { return "hi " + u.Name }
+
+func main() {}
+`,
+	})
+
+	pkgs, err := ast.LoadPackages(dir, ast.DefaultConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tv, err := validator.DescribeType(pkgs, "main.User")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tv.DefLine != 4 || tv.Doc == "" || filepath.Base(tv.DefFile) != "main.go" {
+		t.Errorf("expected definition at main.go:4 with a doc, got %s:%d doc %q", tv.DefFile, tv.DefLine, tv.Doc)
+	}
+
+	fields := make(map[string]ast.FieldInfo, len(tv.Fields))
+	for _, f := range tv.Fields {
+		fields[f.Name] = f
+	}
+	if strings.TrimSpace(fields["Name"].Doc) != "Name is the display name." {
+		t.Errorf("expected field doc for Name, got %+v", fields["Name"])
+	}
+	if len(fields["Address"].Fields) != 1 || fields["Address"].Fields[0].Name != "City" {
+		t.Errorf("expected nested Address.City, got %+v", fields["Address"])
+	}
+	if len(fields["Greeting"].Returns) != 1 {
+		t.Errorf("expected Greeting method, got %+v", fields["Greeting"])
+	}
+	if _, ok := fields["secret"]; ok {
+		t.Errorf("expected unexported fields to be left out by default, got %+v", fields["secret"])
+	}
+
+	slice, err := validator.DescribeType(pkgs, "[]main.User")
+	if err != nil || !slice.IsSlice || len(slice.Fields) != len(tv.Fields) {
+		t.Errorf("expected slice description with the element fields, got %+v, %v", slice, err)
+	}
+
+	config := ast.DefaultConfig
+	config.IncludeUnexported = true
+	withUnexported, err := validator.DescribeTypeWithConfig(pkgs, "main.User", config)
+	if err != nil || len(withUnexported.Fields) != len(tv.Fields)+1 {
+		t.Errorf("expected IncludeUnexported to add the secret field, got %+v, %v", withUnexported.Fields, err)
+	}
+
+	if _, err := validator.DescribeType(pkgs, "main.Missing"); err == nil {
+		t.Errorf("expected an error for an unknown type")
+	}
+}
@@ -0,0 +1,32 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestDuplicateVarWarnings(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"templates/page.html": `{{ .user.Name }}`})
+	calls := []ast.RenderCall{{
+		File:          "handlers.go",
+		Line:          20,
+		Template:      "page.html",
+		Vars:          []ast.TemplateVar{{Name: "user", TypeStr: "User", Fields: []ast.FieldInfo{{Name: "Name", TypeStr: "string"}}}},
+		DuplicateVars: []ast.DuplicateVar{{Name: "user", DefFile: "handlers.go", DefLine: 17}},
+	}}
+
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "templates", validator.ValidateOptions{})
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %#v", results)
+	}
+	r := results[0]
+	if r.Severity != "warning" || r.Message != `Variable "user" is set multiple times for this render call; last value wins` {
+		t.Errorf("unexpected result %+v", r)
+	}
+	if r.Template != "page.html" || r.GoFile != "handlers.go" || r.GoLine != 17 {
+		t.Errorf("expected the warning at handlers.go:17 for page.html, got %+v", r)
+	}
+}
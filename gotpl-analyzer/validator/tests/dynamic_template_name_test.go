@@ -0,0 +1,113 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+var dynamicTemplateVars = []ast.TemplateVar{
+	{Name: "Partial", TypeStr: "string"},
+	{Name: "Title", TypeStr: "string"},
+}
+
+func validateDynamicNames(content, severity string) []validator.ValidationResult {
+	return validator.ValidateContentWithOptions(content, dynamicTemplateVars, false, "test.html", ".", ".", 1, nil, nil,
+		validator.ValidateOptions{DynamicTemplateNameSeverity: severity})
+}
+
+func TestDynamicTemplateNameIsNoted(t *testing.T) {
+	content := `{{$name := "card"}}{{template $name .}}{{template .Partial .}}`
+
+	errs := validateDynamicNames(content, "info")
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 notes, got %d: %#v", len(errs), errs)
+	}
+	for i, want := range []string{"$name", ".Partial"} {
+		if errs[i].Variable != want || errs[i].Severity != "info" {
+			t.Errorf("Expected info note for %s, got %#v", want, errs[i])
+		}
+	}
+
+	if got := validateDynamicNames(content, ""); len(got) != 0 {
+		t.Errorf("Expected no notes by default, got %#v", got)
+	}
+}
+
+func TestDynamicTemplateNameNotNotedByPublicContentAPI(t *testing.T) {
+	content := `{{template .Partial .}}`
+	vars := map[string]ast.TemplateVar{"Partial": dynamicTemplateVars[0]}
+
+	if errs := validator.ValidateTemplateContent(content, vars, "test.html", ".", ".", 1, nil); len(errs) != 0 {
+		t.Errorf("Expected no notes from ValidateTemplateContent, got %#v", errs)
+	}
+}
+
+func TestDynamicTemplateNameSeverity(t *testing.T) {
+	content := `{{template .Partial .}}{{.Missing}}`
+
+	errs := validateDynamicNames(content, "warning")
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 results, got %d: %#v", len(errs), errs)
+	}
+	if errs[0].Variable != ".Partial" || errs[0].Severity != "warning" {
+		t.Errorf("Expected the note to be a warning, got %#v", errs[0])
+	}
+	if errs[1].Variable != ".Missing" || errs[1].Severity != "error" {
+		t.Errorf("Expected other results to be untouched, got %#v", errs[1])
+	}
+}
+
+func TestDynamicTemplateNameSeverityInTree(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"page.html": `{{template .Partial .}}`,
+	})
+	calls := []ast.RenderCall{{Template: "page.html", Vars: dynamicTemplateVars}}
+
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, ".", validator.ValidateOptions{
+		DynamicTemplateNameSeverity: "error",
+	})
+	if len(results) != 1 || results[0].Variable != ".Partial" || results[0].Severity != "error" {
+		t.Errorf("Expected one error for .Partial, got %#v", results)
+	}
+
+	if results, _, _, _ = validator.ValidateTemplatesWithOptions(calls, nil, dir, ".", validator.ValidateOptions{}); len(results) != 0 {
+		t.Errorf("Expected no notes by default, got %#v", results)
+	}
+}
+
+func TestValidDynamicTemplateNameSeverity(t *testing.T) {
+	for _, s := range []string{"", "info", "warning", "error"} {
+		if !validator.ValidDynamicTemplateNameSeverity(s) {
+			t.Errorf("Expected %q to be valid", s)
+		}
+	}
+	for _, s := range []string{"warn", "INFO", "fatal"} {
+		if validator.ValidDynamicTemplateNameSeverity(s) {
+			t.Errorf("Expected %q to be rejected", s)
+		}
+	}
+}
+
+func TestDynamicTemplateNameVariableMustExist(t *testing.T) {
+	content := `{{template $missing .}}{{template .Nope .}}`
+
+	errs := validateDynamicNames(content, "")
+
+	undefined := map[string]bool{}
+	for _, e := range errs {
+		if e.Severity == "error" {
+			undefined[e.Variable] = true
+		}
+	}
+	if !undefined["$missing"] || !undefined[".Nope"] {
+		t.Errorf("Expected undefined name variables to be reported, got %#v", errs)
+	}
+	for _, e := range errs {
+		if e.Variable != "$missing" && e.Variable != ".Nope" {
+			t.Errorf("Unexpected result %#v", e)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package validator_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// scopeDepthRule records the depth of the scope stack at every action.
+type scopeDepthRule struct {
+	depths *[]string
+}
+
+func (r scopeDepthRule) Check(ctx validator.RuleContext) []validator.ValidationResult {
+	for _, a := range ctx.Actions {
+		*r.depths = append(*r.depths, fmt.Sprintf("%s:%d", a.Text, len(a.ScopeStack)))
+	}
+	return nil
+}
+
+var elseIfVars = []ast.TemplateVar{
+	{Name: "A", TypeStr: "bool"},
+	{Name: "B", TypeStr: "B", Fields: []ast.FieldInfo{{Name: "C", TypeStr: "bool"}}},
+	{Name: "Title", TypeStr: "string"},
+	{Name: "User", TypeStr: "User", Fields: []ast.FieldInfo{{Name: "Name", TypeStr: "string"}}},
+}
+
+func TestElseIfChainScopeDepth(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"page.html": `{{if .A}}{{.Title}}{{else if .B.C}}{{.Title}}{{else if .A}}{{.Title}}{{else}}{{.Title}}{{end}}{{.Title}}`})
+
+	var depths []string
+	calls := []ast.RenderCall{{Template: "page.html", Vars: elseIfVars}}
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "", validator.ValidateOptions{Rules: []validator.Rule{scopeDepthRule{&depths}}})
+	if len(results) != 0 {
+		t.Fatalf("expected no results, got %#v", results)
+	}
+
+	// Every branch body runs in exactly one frame above the root, every
+	// condition in the scope outside the chain, and the chain leaves no frame
+	// behind.
+	want := "if .A:1 .Title:2 else if .B.C:1 .Title:2 else if .A:1 .Title:2 else:1 .Title:2 .Title:1"
+	if got := strings.Join(depths, " "); got != want {
+		t.Errorf("scope depths\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestElseIfConditionUsesOuterScope(t *testing.T) {
+	// The else-if condition and the branch bodies after {{with}} see the
+	// root scope, not User; .Name is only valid in the with body.
+	content := `{{with .User}}{{.Name}}{{else if .B.C}}{{.Title}}{{.Name}}{{else}}{{.Title}}{{end}}{{end}}`
+	vars := map[string]ast.TemplateVar{}
+	for _, v := range elseIfVars {
+		vars[v.Name] = v
+	}
+	errs := validator.ValidateTemplateContent(content, vars, "page.html", ".", ".", 1, nil)
+
+	if len(errs) != 2 {
+		t.Fatalf("expected 2 errors, got %#v", errs)
+	}
+	if errs[0].Variable != ".Name" {
+		t.Errorf("expected .Name in the else-if body to be reported, got %+v", errs[0])
+	}
+	// The chain closed with the first {{end}}; a leaked frame would hide the
+	// second one.
+	if errs[1].Severity != validator.SeveritySyntax || !strings.Contains(errs[1].Message, "unexpected {{end}}") {
+		t.Errorf("expected the stray {{end}} to be reported, got %+v", errs[1])
+	}
+}
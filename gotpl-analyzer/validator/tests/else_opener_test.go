@@ -0,0 +1,80 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+var elseOpenerVars = map[string]ast.TemplateVar{
+	"Title": {Name: "Title", TypeStr: "string"},
+	"Items": {
+		Name:     "Items",
+		TypeStr:  "[]Item",
+		IsSlice:  true,
+		ElemType: "Item",
+		Fields: []ast.FieldInfo{
+			{Name: "Name", TypeStr: "string"},
+		},
+	},
+	"User": {
+		Name:    "User",
+		TypeStr: "User",
+		Fields: []ast.FieldInfo{
+			{Name: "Email", TypeStr: "string"},
+		},
+	},
+}
+
+func TestRangeElseUsesOuterScope(t *testing.T) {
+	content := `{{range .Items}}{{.Name}}{{else}}No items for {{.Title}}{{end}}`
+
+	errs := validator.ValidateTemplateContent(content, elseOpenerVars, "test.html", ".", ".", 1, nil)
+	for _, e := range errs {
+		t.Logf("Error: %s (variable: %s)", e.Message, e.Variable)
+	}
+	if len(errs) != 0 {
+		t.Errorf("Expected 0 errors, got %d", len(errs))
+	}
+}
+
+func TestRangeElseRejectsElementFields(t *testing.T) {
+	content := `{{range .Items}}{{.Name}}{{else}}{{.Name}}{{end}}`
+
+	errs := validator.ValidateTemplateContent(content, elseOpenerVars, "test.html", ".", ".", 1, nil)
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d: %#v", len(errs), errs)
+	}
+	if errs[0].Variable != ".Name" {
+		t.Errorf("Expected error on .Name, got %q", errs[0].Variable)
+	}
+}
+
+func TestElseWithEstablishesNewScope(t *testing.T) {
+	content := `{{with .Items}}{{len .}}{{else with .User}}{{.Email}}{{end}}`
+
+	errs := validator.ValidateTemplateContent(content, elseOpenerVars, "test.html", ".", ".", 1, nil)
+	for _, e := range errs {
+		t.Logf("Error: %s (variable: %s)", e.Message, e.Variable)
+	}
+	if len(errs) != 0 {
+		t.Errorf("Expected 0 errors, got %d", len(errs))
+	}
+}
+
+func TestElseAfterElseIsReported(t *testing.T) {
+	content := `{{if .Title}}a{{else}}b{{else}}c{{end}}`
+
+	errs := validator.ValidateTemplateContent(content, elseOpenerVars, "test.html", ".", ".", 1, nil)
+	found := false
+	for _, e := range errs {
+		if strings.Contains(e.Message, "{{else}} is not valid after {{else}}") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Expected invalid else error, got %#v", errs)
+	}
+}
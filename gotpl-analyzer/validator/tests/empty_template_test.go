@@ -0,0 +1,50 @@
+package validator_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestWarnEmptyTemplates(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"templates/app.min.js":  "function f(){return 1}",
+		"templates/page.html":   `<h1>{{.Title}}</h1>{{template "footer.html"}}`,
+		"templates/footer.html": "<footer>static</footer>",
+		"templates/about.html":  "<p>static</p>",
+		"templates/draft.html":  "{{/* TODO: {{.Title}} */}}<p>coming soon</p>",
+	})
+	vars := []ast.TemplateVar{{Name: "Title", TypeStr: "string"}}
+	calls := []ast.RenderCall{
+		{Template: "app.min.js", File: "handlers.go", Line: 7, Vars: vars},
+		{Template: "page.html", Vars: vars},
+		{Template: "footer.html", Vars: vars},
+		{Template: "about.html"},
+		{Template: "draft.html", Vars: vars},
+	}
+
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "templates", validator.ValidateOptions{WarnEmptyTemplates: true})
+	if len(results) != 2 {
+		t.Fatalf("expected two warnings, got %+v", results)
+	}
+	slices.SortFunc(results, func(a, b validator.ValidationResult) int { return strings.Compare(a.Template, b.Template) })
+	if d := results[1]; d.Template != "draft.html" || d.Rule != "empty-template" {
+		t.Errorf("expected the comment-only draft.html to be reported, got %+v", d)
+	}
+	r := results[0]
+	if r.Message != `Template "app.min.js" contains no template actions` || r.Severity != "warning" || r.Template != "app.min.js" {
+		t.Errorf("unexpected result %+v", r)
+	}
+	if r.GoFile != "handlers.go" || r.GoLine != 7 {
+		t.Errorf("expected the warning linked to handlers.go:7, got %s:%d", r.GoFile, r.GoLine)
+	}
+
+	results, _, _, _ = validator.ValidateTemplatesWithOptions(calls, nil, dir, "templates", validator.ValidateOptions{})
+	if len(results) != 0 {
+		t.Errorf("expected no results without WarnEmptyTemplates, got %+v", results)
+	}
+}
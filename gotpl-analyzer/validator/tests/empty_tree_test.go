@@ -0,0 +1,127 @@
+package validator_test
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// noTemplateCalls returns n render calls to templates that do not exist.
+func noTemplateCalls(n int) []ast.RenderCall {
+	calls := make([]ast.RenderCall, n)
+	for i := range calls {
+		calls[i] = ast.RenderCall{
+			File:     fmt.Sprintf("handlers/h%d.go", i),
+			Line:     10,
+			Template: fmt.Sprintf("page%d", i%50),
+			Vars:     []ast.TemplateVar{{Name: "Title", TypeStr: "string"}},
+		}
+	}
+	return calls
+}
+
+func TestValidateTemplatesWithoutTemplateFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# no templates"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	results, namedBlocks, dupes, _ := validator.ValidateTemplatesWithOptions(noTemplateCalls(100), nil, dir, "", validator.ValidateOptions{
+		Logger: slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	})
+
+	logs := buf.String()
+	if !strings.Contains(logs, `msg="no template files, validating render calls only"`) {
+		t.Errorf("expected the no-templates fast path, got:\n%s", logs)
+	}
+	for _, phase := range []string{"parsed named templates", "validated template tree", "validated orphaned named blocks"} {
+		if strings.Contains(logs, phase) {
+			t.Errorf("expected no %q phase, got:\n%s", phase, logs)
+		}
+	}
+
+	if len(namedBlocks) != 0 || len(dupes) != 0 {
+		t.Errorf("expected no named blocks, got %v and %v", namedBlocks, dupes)
+	}
+	// Each of the 50 distinct templates is reported once, at its first call.
+	if len(results) != 50 {
+		t.Fatalf("expected 50 results, got %d: %+v", len(results), results)
+	}
+	for _, r := range results {
+		if !strings.HasPrefix(r.Message, "Template or named block not found") || r.GoFile == "" {
+			t.Errorf("unexpected result %+v", r)
+		}
+	}
+}
+
+func TestValidateTemplatesExistingTargetTakesFullPath(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "page.txt"), []byte("{{.Missing}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	calls := []ast.RenderCall{{Template: "page.txt", Vars: []ast.TemplateVar{{Name: "Title", TypeStr: "string"}}}}
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "", validator.ValidateOptions{
+		Logger: slog.New(slog.NewTextHandler(&buf, nil)),
+	})
+
+	if strings.Contains(buf.String(), "no template files") {
+		t.Errorf("expected the full path for an existing render target, got:\n%s", buf.String())
+	}
+	if len(results) != 1 {
+		t.Errorf("expected 1 result for page.txt, got %+v", results)
+	}
+}
+
+func TestValidateTemplatesOnlyIgnoredTemplateFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		".gitignore":       "build/\n",
+		"build/page0.html": "{{.Missing}}",
+	})
+	ignore, err := validator.NewGitignoreMatcher(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(noTemplateCalls(1), nil, dir, "", validator.ValidateOptions{
+		Ignore: ignore,
+		Logger: slog.New(slog.NewTextHandler(&buf, nil)),
+	})
+
+	if logs := buf.String(); !strings.Contains(logs, "no template files") || strings.Contains(logs, "validated template tree") {
+		t.Errorf("expected the tree walk to be skipped when every template is ignored, got:\n%s", logs)
+	}
+	if len(results) != 1 || !strings.HasPrefix(results[0].Message, "Template or named block not found") {
+		t.Errorf("expected only the missing render target, got %+v", results)
+	}
+}
+
+func BenchmarkValidateTemplatesNoTemplates(b *testing.B) {
+	dir := b.TempDir()
+	calls := noTemplateCalls(5000)
+
+	// The benchmark only measures the fast path if it is taken.
+	var buf bytes.Buffer
+	validator.ValidateTemplatesWithOptions(calls, nil, dir, "", validator.ValidateOptions{
+		Logger: slog.New(slog.NewTextHandler(&buf, nil)),
+	})
+	if logs := buf.String(); !strings.Contains(logs, "no template files") || strings.Contains(logs, "validated template tree") {
+		b.Fatalf("expected validation of the tree to be skipped, got:\n%s", logs)
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		validator.ValidateTemplates(calls, nil, dir, "")
+	}
+}
@@ -0,0 +1,88 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+var errorRangeVars = map[string]ast.TemplateVar{
+	"User": {
+		Name:    "User",
+		TypeStr: "User",
+		Fields: []ast.FieldInfo{
+			{Name: "Name", TypeStr: "string"},
+			{Name: "Profile", TypeStr: "Profile", Fields: []ast.FieldInfo{
+				{Name: "Avatar", TypeStr: "string"},
+			}},
+		},
+	},
+}
+
+type errorRange struct {
+	line, col, endLine, endCol int
+}
+
+func rangeOf(r validator.ValidationResult) errorRange {
+	return errorRange{r.Line, r.Column, r.EndLine, r.EndColumn}
+}
+
+func TestErrorRangeCoversVariable(t *testing.T) {
+	// Columns are 1-based; EndColumn is just past the expression.
+	content := `<p>{{ .User.Naem }}</p>`
+
+	errs := validator.ValidateTemplateContent(content, errorRangeVars, "test.html", ".", ".", 1, nil)
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d: %#v", len(errs), errs)
+	}
+	want := errorRange{1, 7, 1, 17}
+	if got := rangeOf(errs[0]); got != want {
+		t.Errorf("Expected range %+v for .User.Naem, got %+v", want, got)
+	}
+}
+
+func TestErrorRangeStopsAtFailingSegment(t *testing.T) {
+	content := `{{ .User.Profil.Avatar }}`
+
+	errs := validator.ValidateTemplateContent(content, errorRangeVars, "test.html", ".", ".", 1, nil)
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d: %#v", len(errs), errs)
+	}
+	if errs[0].Variable != ".User.Profil.Avatar" {
+		t.Errorf("Expected Variable .User.Profil.Avatar, got %q", errs[0].Variable)
+	}
+	// Underline ".User.Profil" only.
+	want := errorRange{1, 4, 1, 16}
+	if got := rangeOf(errs[0]); got != want {
+		t.Errorf("Expected range %+v, got %+v", want, got)
+	}
+}
+
+func TestErrorRangeUsesTokenOffset(t *testing.T) {
+	// .Nam appears inside .User.Name first; the range must point at the
+	// standalone token, not the earlier substring match.
+	content := `{{ printf "%s %s" .User.Name .Nam }}`
+
+	errs := validator.ValidateTemplateContent(content, errorRangeVars, "test.html", ".", ".", 1, nil)
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d: %#v", len(errs), errs)
+	}
+	want := errorRange{1, 30, 1, 34}
+	if got := rangeOf(errs[0]); got != want {
+		t.Errorf("Expected range %+v for .Nam, got %+v", want, got)
+	}
+}
+
+func TestErrorRangeMultilineAction(t *testing.T) {
+	content := "{{ printf \"%s\"\n     .User.Naem }}"
+
+	errs := validator.ValidateTemplateContent(content, errorRangeVars, "test.html", ".", ".", 1, nil)
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d: %#v", len(errs), errs)
+	}
+	want := errorRange{2, 6, 2, 16}
+	if got := rangeOf(errs[0]); got != want {
+		t.Errorf("Expected range %+v on the second line, got %+v", want, got)
+	}
+}
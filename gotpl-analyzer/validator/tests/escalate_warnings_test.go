@@ -0,0 +1,31 @@
+package validator_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestEscalateWarnings(t *testing.T) {
+	results := []validator.ValidationResult{
+		{Template: "a.html", Line: 2, Column: 3, Variable: ".X", Message: "only some callers", Severity: "warning"},
+		{Template: "a.html", Line: 4, Message: "undefined", Severity: "error"},
+		{Template: "b.html", Line: 1, Message: "dynamic name", Severity: "info"},
+		{Template: "b.html", Line: 1, Message: "missing {{end}}", Severity: validator.SeveritySyntax},
+	}
+	want := []validator.ValidationResult{
+		{Template: "a.html", Line: 2, Column: 3, Variable: ".X", Message: "only some callers", Severity: "error"},
+		{Template: "a.html", Line: 4, Message: "undefined", Severity: "error"},
+		{Template: "b.html", Line: 1, Message: "dynamic name", Severity: "info"},
+		{Template: "b.html", Line: 1, Message: "missing {{end}}", Severity: validator.SeveritySyntax},
+	}
+
+	got := validator.EscalateWarnings(results)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EscalateWarnings() = %+v, want %+v", got, want)
+	}
+	if again := validator.EscalateWarnings(got); !reflect.DeepEqual(again, want) {
+		t.Errorf("EscalateWarnings is not idempotent: %+v", again)
+	}
+}
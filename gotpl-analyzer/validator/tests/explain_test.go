@@ -0,0 +1,118 @@
+package validator_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+const explainTemplate = `<h1>{{.Title}}</h1>
+{{with .User}}
+  {{range $i, $a := .Addresses}}
+    <p>{{.City}}</p>
+  {{else}}
+    none
+  {{end}}
+{{end}}
+`
+
+var explainVars = []ast.TemplateVar{
+	{Name: "Title", TypeStr: "string"},
+	{Name: "User", TypeStr: "User", Fields: []ast.FieldInfo{
+		{Name: "Name", TypeStr: "string"},
+		{Name: "Addresses", TypeStr: "[]Address", IsSlice: true, ElemType: "Address", Fields: []ast.FieldInfo{
+			{Name: "City", TypeStr: "string"},
+		}},
+	}},
+}
+
+// fieldNames returns the names of fields.
+func fieldNames(fields []ast.FieldInfo) []string {
+	var names []string
+	for _, f := range fields {
+		names = append(names, f.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// openers returns the opener of each frame of e.
+func openers(e validator.ScopeExplanation) []string {
+	var out []string
+	for _, f := range e.Scopes {
+		out = append(out, f.Opener)
+	}
+	return out
+}
+
+func TestExplainAtRangeElement(t *testing.T) {
+	// Inside {{.City}} on line 4: the position moves to the action's {{.
+	e := validator.ExplainAt(explainTemplate, explainVars, 4, 12)
+	if len(e.Scopes) == 0 {
+		t.Fatal("expected an explanation")
+	}
+	if e.Line != 4 || e.Column != 8 {
+		t.Errorf("expected the action start 4:8, got %d:%d", e.Line, e.Column)
+	}
+	if e.DotType != "Address" {
+		t.Errorf("expected dot type Address, got %q", e.DotType)
+	}
+	if got := fieldNames(e.DotFields); !slices.Equal(got, []string{"City"}) {
+		t.Errorf("expected dot fields [City], got %v", got)
+	}
+	if got := openers(e); !slices.Equal(got, []string{"root", "with", "range"}) {
+		t.Errorf("unexpected scope stack %v", got)
+	}
+	if e.Scopes[1].TypeStr != "User" {
+		t.Errorf("expected the with frame to be User, got %+v", e.Scopes[1])
+	}
+	if _, ok := e.Locals["$a"]; !ok {
+		t.Errorf("expected $a in scope, got %v", e.Locals)
+	}
+	if _, ok := e.Locals["$i"]; !ok {
+		t.Errorf("expected $i in scope, got %v", e.Locals)
+	}
+}
+
+func TestExplainAtRoot(t *testing.T) {
+	e := validator.ExplainAt(explainTemplate, explainVars, 1, 1)
+	if len(e.Scopes) == 0 {
+		t.Fatal("expected an explanation")
+	}
+	if e.DotType != "" {
+		t.Errorf("expected the root data, got %q", e.DotType)
+	}
+	if got := fieldNames(e.DotFields); !slices.Equal(got, []string{"Title", "User"}) {
+		t.Errorf("expected the root variables, got %v", got)
+	}
+	if got := openers(e); !slices.Equal(got, []string{"root"}) {
+		t.Errorf("unexpected scope stack %v", got)
+	}
+}
+
+func TestExplainAtRangeElse(t *testing.T) {
+	// Text of the range's {{else}} branch runs in the with scope again.
+	e := validator.ExplainAt(explainTemplate, explainVars, 6, 5)
+	if len(e.Scopes) == 0 {
+		t.Fatal("expected an explanation")
+	}
+	if got := openers(e); !slices.Equal(got, []string{"root", "with", "else"}) {
+		t.Errorf("unexpected scope stack %v", got)
+	}
+	if e.DotType != "User" {
+		t.Errorf("expected dot type User, got %q", e.DotType)
+	}
+	if _, ok := e.Locals["$a"]; ok {
+		t.Errorf("expected $a out of scope in the else branch, got %v", e.Locals)
+	}
+}
+
+func TestExplainAtOutOfRange(t *testing.T) {
+	for _, pos := range [][2]int{{0, 1}, {1, 0}, {20, 1}, {1, 200}} {
+		if e := validator.ExplainAt(explainTemplate, explainVars, pos[0], pos[1]); len(e.Scopes) != 0 {
+			t.Errorf("expected no scope for %v, got %+v", pos, e)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestValidateTemplatesExtraTemplateRoots(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"web/templates/home.html":        `<h1>{{.Title}}</h1>{{template "footer" .}}{{.Missing}}`,
+		"web/templates/partials.html":    `{{define "footer"}}<footer>{{.Title}}</footer>{{end}}`,
+		"web/templates/banner.html":      `<header>{{.Title}}</header>`,
+		"email/templates/welcome.html":   `{{template "banner.html" .}}<p>{{.Name}}</p>{{template "signature.html" .}}{{template "footer" .}}`,
+		"email/templates/signature.html": `{{.Name}}{{.Unknown}}`,
+	})
+	calls := []ast.RenderCall{
+		{Template: "home.html", Vars: []ast.TemplateVar{{Name: "Title", TypeStr: "string"}}},
+		{Template: "welcome.html", Vars: []ast.TemplateVar{{Name: "Name", TypeStr: "string"}, {Name: "Title", TypeStr: "string"}}},
+	}
+
+	results, namedBlocks, dupes, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "web/templates", validator.ValidateOptions{
+		ExtraTemplateRoots: []string{"email/templates"},
+	})
+	if len(dupes) != 0 {
+		t.Errorf("expected no duplicates, got %#v", dupes)
+	}
+	if len(namedBlocks["footer"]) != 1 {
+		t.Errorf("expected footer to be registered once, got %#v", namedBlocks["footer"])
+	}
+
+	want := map[string]string{
+		"home.html":                    ".Missing",
+		"email/templates/welcome.html": ".Unknown",
+	}
+	for _, r := range results {
+		if r.Severity != "error" {
+			continue
+		}
+		if want[r.Template] != r.Variable {
+			t.Errorf("unexpected result %#v", r)
+			continue
+		}
+		delete(want, r.Template)
+	}
+	for template, variable := range want {
+		t.Errorf("expected %s in %s, got %#v", variable, template, results)
+	}
+}
+
+func TestValidateTemplatesExtraTemplateRootsDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"web/templates/layout.html":   `{{define "footer"}}web{{end}}`,
+		"email/templates/layout.html": `{{define "footer"}}email{{end}}`,
+	})
+	opts := validator.ValidateOptions{ExtraTemplateRoots: []string{"email/templates"}}
+
+	_, _, dupes, _ := validator.ValidateTemplatesWithOptions(nil, nil, dir, "web/templates", opts)
+	names := make(map[string]bool)
+	for _, d := range dupes {
+		names[d.Name] = true
+	}
+	if len(dupes) != 2 || !names["footer"] || !names["layout.html"] {
+		t.Errorf("expected footer and layout.html duplicates, got %#v", dupes)
+	}
+
+	opts.AllowBlockOverride = true
+	if _, _, dupes, _ = validator.ValidateTemplatesWithOptions(nil, nil, dir, "web/templates", opts); len(dupes) != 0 {
+		t.Errorf("expected no duplicates with AllowBlockOverride, got %#v", dupes)
+	}
+}
+
+func TestValidateTemplatesExtraTemplateRootMissing(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"web/templates/home.html": `{{.Title}}`,
+	})
+	calls := []ast.RenderCall{{Template: "home.html", Vars: []ast.TemplateVar{{Name: "Title", TypeStr: "string"}}}}
+
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "web/templates", validator.ValidateOptions{
+		ExtraTemplateRoots: []string{"email/templates"},
+	})
+	if len(results) != 1 || results[0].Template != "email/templates" {
+		t.Errorf("expected only the missing root to be reported, got %#v", results)
+	}
+}
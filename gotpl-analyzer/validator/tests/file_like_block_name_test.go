@@ -0,0 +1,46 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// A named block whose name looks like a file must resolve through the
+// registry rather than being looked up on disk.
+func TestFileLikeNamedBlockResolvesFromRegistry(t *testing.T) {
+	content := `{{define "content.html"}}<p>{{.Title}}</p>{{end}}{{template "content.html" .}}`
+	vars := map[string]ast.TemplateVar{
+		"Title": {Name: "Title", TypeStr: "string"},
+	}
+
+	errs := validator.ValidateTemplateContent(content, vars, "test.html", t.TempDir(), ".", 1, nil)
+	for _, e := range errs {
+		t.Logf("Error: %s (variable: %s)", e.Message, e.Variable)
+		if strings.Contains(e.Message, "could not be found") {
+			t.Errorf("named block content.html was treated as a missing file")
+		}
+	}
+	if len(errs) != 0 {
+		t.Errorf("Expected 0 errors, got %d", len(errs))
+	}
+}
+
+func TestFileLikeNamedBlockStillValidatesBody(t *testing.T) {
+	content := `{{define "content.html"}}<p>{{.Missing}}</p>{{end}}{{template "content.html" .}}`
+	vars := map[string]ast.TemplateVar{
+		"Title": {Name: "Title", TypeStr: "string"},
+	}
+
+	errs := validator.ValidateTemplateContent(content, vars, "test.html", t.TempDir(), ".", 1, nil)
+	if len(errs) == 0 {
+		t.Fatal("Expected an error for .Missing inside the named block")
+	}
+	for _, e := range errs {
+		if strings.Contains(e.Message, "could not be found") {
+			t.Errorf("named block content.html was treated as a missing file: %s", e.Message)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestFuncMapSignatures(t *testing.T) {
+	funcMaps := []ast.FuncMapInfo{
+		{
+			Name:     "join",
+			Params:   []ast.ParamInfo{{Name: "sep", TypeStr: "string"}, {Name: "parts", TypeStr: "[]string"}},
+			Returns:  []ast.ParamInfo{{TypeStr: "string"}},
+			Variadic: true,
+			Doc:      "join concatenates parts.",
+		},
+		{
+			Name:    "lookup",
+			Params:  []ast.ParamInfo{{TypeStr: "string"}},
+			Returns: []ast.ParamInfo{{TypeStr: "*User", Fields: []ast.FieldInfo{{Name: "Name", TypeStr: "string"}}}, {TypeStr: "error"}},
+		},
+		{Name: "join", Params: []ast.ParamInfo{{TypeStr: "int"}}},
+		{Name: "now"},
+	}
+
+	sigs := validator.FuncMapSignatures(funcMaps)
+	if len(sigs) != 3 {
+		t.Fatalf("expected 3 signatures, got %+v", sigs)
+	}
+
+	want := []struct {
+		name      string
+		signature string
+		variadic  bool
+	}{
+		{"join", "func(sep string, parts ...string) string", true},
+		{"lookup", "func(string) (*User, error)", false},
+		{"now", "func()", false},
+	}
+	for i, w := range want {
+		s := sigs[i]
+		if s.Name != w.name || s.Signature != w.signature || s.Variadic != w.variadic {
+			t.Errorf("signature %d: expected %s %q variadic=%v, got %s %q variadic=%v",
+				i, w.name, w.signature, w.variadic, s.Name, s.Signature, s.Variadic)
+		}
+	}
+
+	if got := sigs[0].Params; len(got) != 2 || got[1] != (validator.SignatureParam{Name: "parts", Type: "[]string"}) {
+		t.Errorf("unexpected join params %+v", got)
+	}
+	if sigs[0].Doc != "join concatenates parts." {
+		t.Errorf("expected doc to be kept, got %q", sigs[0].Doc)
+	}
+	if sigs[2].Params == nil || sigs[2].Returns == nil {
+		t.Errorf("expected empty, non-nil params and returns for now, got %+v", sigs[2])
+	}
+}
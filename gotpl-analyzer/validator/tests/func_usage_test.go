@@ -0,0 +1,42 @@
+package validator_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestTemplateFunctionUsage(t *testing.T) {
+	content := "{{/* {{commented .X}} */}}\n" +
+		"<p>{{formatDate .CreatedAt \"2006\"}} {{.User.Name}} {{.User.Greet \"hi\"}}</p>\n" +
+		"{{if eq .Status \"ok\"}}{{.Title | upper | printf \"%s\"}}{{end}}\n" +
+		"{{range $i, $o := sortBy .Orders \"date\"}}{{$o.ID}}{{end}}\n" +
+		"{{$total := sum .Orders}}{{call .Fn 1}}{{with formatDate .UpdatedAt \"2006\"}}{{.}}{{end}}\n" +
+		"{{template \"row.html\" (dict \"A\" 1)}}"
+
+	got := validator.TemplateFunctionUsage(content)
+	want := []string{"eq", "formatDate", "printf", "sortBy", "sum", "upper"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TemplateFunctionUsage() = %v, want %v", got, want)
+	}
+}
+
+func TestFunctionUsageByTemplate(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"index.html":        `{{formatDate .At}}{{len .Items}}{{printf "%d" .N}}{{money .Total}}`,
+		"partials/nav.html": `<nav>{{.Title}}</nav>`,
+	})
+	funcMaps := []ast.FuncMapInfo{{Name: "formatDate"}, {Name: "len"}}
+
+	got := validator.FunctionUsageByTemplate(dir, "", funcMaps)
+	want := map[string]validator.FunctionUsage{
+		"index.html":        {Custom: []string{"formatDate", "len"}, Builtin: []string{"printf"}, Unknown: []string{"money"}},
+		"partials/nav.html": {Custom: []string{}, Builtin: []string{}, Unknown: []string{}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FunctionUsageByTemplate() =\n%+v\nwant\n%+v", got, want)
+	}
+}
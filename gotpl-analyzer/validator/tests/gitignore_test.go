@@ -0,0 +1,96 @@
+package validator_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestGitignoreMatcher(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTree(t, dir, map[string]string{
+		".gitignore":     "# build output\n*.log\n!keep.log\nbuild/\n/dist\ndocs/**/draft.html\n",
+		"sub/.gitignore": "local.html\n",
+	})
+
+	m, err := validator.NewGitignoreMatcher(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"a.log", false, true},
+		{"nested/a.log", false, true},
+		{"keep.log", false, false},
+		{"build", true, true},
+		{"build", false, false},
+		{"build/out.html", false, true},
+		{"web/build/out.html", false, true},
+		{"dist/app.js", false, true},
+		{"web/dist/app.js", false, false},
+		{"docs/draft.html", false, true},
+		{"docs/a/b/draft.html", false, true},
+		{"draft.html", false, false},
+		{"sub/local.html", false, true},
+		{"sub/deeper/local.html", false, true},
+		{"local.html", false, false},
+	}
+	for _, tt := range tests {
+		if got := m.Match(filepath.Join(dir, tt.path), tt.isDir); got != tt.want {
+			t.Errorf("Match(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+
+	var nilMatcher *validator.GitignoreMatcher
+	if nilMatcher.Match(filepath.Join(dir, "a.log"), false) {
+		t.Errorf("nil matcher must match nothing")
+	}
+}
+
+func TestGitignoreExcludesTemplates(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		".gitignore":           "generated/\n",
+		"page.html":            `{{define "card"}}{{.Title}}{{end}}`,
+		"generated/stale.html": `{{define "stale"}}{{.Title}}{{end}}{{template "missing" .}}`,
+	})
+	m, err := validator.NewGitignoreMatcher(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs, namedBlocks, _, _ := validator.ValidateTemplatesWithOptions(nil, nil, dir, "", validator.ValidateOptions{Ignore: m})
+	if _, ok := namedBlocks["stale"]; ok {
+		t.Errorf("ignored file must not contribute named blocks, got %v", namedBlocks)
+	}
+	if _, ok := namedBlocks["card"]; !ok {
+		t.Errorf("expected named block from page.html, got %v", namedBlocks)
+	}
+	for _, e := range errs {
+		if e.Template == "generated/stale.html" {
+			t.Errorf("ignored file must not be validated, got %#v", e)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package validator_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestGroupByGoFile(t *testing.T) {
+	results := []validator.ValidationResult{
+		{Template: "users.html", Variable: ".A", GoFile: "handlers/users.go", GoLine: 10},
+		{Template: "home.html", Variable: ".B", GoFile: "handlers/home.go", GoLine: 5},
+		{Template: "users.html", Variable: ".C", GoFile: "handlers/users.go", GoLine: 10},
+		{Template: "report.html", Variable: ".D"},
+		{Template: "users.html", Variable: ".E", GoFile: "handlers/users.go", GoLine: 42},
+	}
+
+	groups := validator.GroupByGoFile(results)
+	want := map[string][]string{
+		"handlers/users.go":         {".A", ".C", ".E"},
+		"handlers/home.go":          {".B"},
+		validator.TemplateTreeGroup: {".D"},
+	}
+	if len(groups) != len(want) {
+		t.Fatalf("expected %d groups, got %#v", len(want), groups)
+	}
+	for key, vars := range want {
+		group := groups[key]
+		if len(group) != len(vars) {
+			t.Errorf("group %s: expected %v, got %#v", key, vars, group)
+			continue
+		}
+		for i, v := range vars {
+			if group[i].Variable != v {
+				t.Errorf("group %s: expected %v in order, got %#v", key, vars, group)
+				break
+			}
+		}
+	}
+	if groups["handlers/users.go"][2].GoLine != 42 {
+		t.Errorf("expected results to keep their Go line, got %+v", groups["handlers/users.go"][2])
+	}
+}
+
+// TestGroupByGoFileContextFile verifies that the results of a context file's
+// synthetic render calls are grouped under ContextFileGroup and those of the
+// rest of the tree under TemplateTreeGroup.
+func TestGroupByGoFileContextFile(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"context.json":      `{"index.html": {"title": "string"}}`,
+		"views/index.html":  `{{.title}}{{.missing}}`,
+		"views/orphan.html": `{{.anything}}`,
+	})
+
+	result := ast.AnalyzeContextFile(filepath.Join(dir, "context.json"), ast.DefaultConfig)
+	if len(result.RenderCalls) != 1 || result.RenderCalls[0].File != ast.ContextFileRenderCall {
+		t.Fatalf("expected a synthetic render call, got %+v", result.RenderCalls)
+	}
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(result.RenderCalls, nil, dir, "views", validator.ValidateOptions{WarnMissingContext: true})
+
+	groups := validator.GroupByGoFile(results)
+	if len(groups) != 2 {
+		t.Fatalf("expected two groups, got %#v", groups)
+	}
+	if g := groups[validator.ContextFileGroup]; len(g) != 1 || g[0].Variable != ".missing" {
+		t.Errorf("expected .missing under %s, got %#v", validator.ContextFileGroup, g)
+	}
+	if g := groups[validator.TemplateTreeGroup]; len(g) != 1 || g[0].Template != "orphan.html" {
+		t.Errorf("expected the orphan.html warning under %s, got %#v", validator.TemplateTreeGroup, g)
+	}
+}
@@ -0,0 +1,106 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestTemplateInclusionCycleIsReported(t *testing.T) {
+	content := `{{define "a"}}{{.Title}}{{template "b" .}}{{end}}` +
+		`{{define "b"}}{{template "a" .}}{{end}}` +
+		`{{template "a" .}}`
+	vars := map[string]ast.TemplateVar{
+		"Title": {Name: "Title", TypeStr: "string"},
+	}
+
+	errs := validator.ValidateTemplateContent(content, vars, "test.html", ".", ".", 1, nil)
+
+	found := false
+	for _, e := range errs {
+		t.Logf("%s: %s", e.Severity, e.Message)
+		if e.Severity == "warning" && strings.Contains(e.Message, "Template inclusion cycle detected: a > b > a") {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("expected an inclusion cycle warning, got %#v", errs)
+	}
+}
+
+func TestSelfIncludingTemplateIsReported(t *testing.T) {
+	content := `{{define "loop"}}{{.Title}}{{template "loop" .}}{{end}}{{template "loop" .}}`
+	vars := map[string]ast.TemplateVar{
+		"Title": {Name: "Title", TypeStr: "string"},
+	}
+
+	errs := validator.ValidateTemplateContent(content, vars, "test.html", ".", ".", 1, nil)
+	if len(errs) != 1 || !strings.Contains(errs[0].Message, "Template inclusion cycle detected: loop > loop") {
+		t.Errorf("expected a self-inclusion cycle warning, got %#v", errs)
+	}
+}
+
+// TestConditionalRecursionIsNotReported verifies that a recursion guarded by
+// an if, range or with, which ends at execution time, terminates without a
+// cycle warning.
+func TestConditionalRecursionIsNotReported(t *testing.T) {
+	node := ast.TemplateVar{
+		Name:    "Root",
+		TypeStr: "Node",
+		Fields: []ast.FieldInfo{
+			{Name: "Title", TypeStr: "string"},
+			{Name: "Next", TypeStr: "*Node", Fields: []ast.FieldInfo{{Name: "Title", TypeStr: "string"}}},
+			{Name: "Children", TypeStr: "[]Node", IsSlice: true, ElemType: "Node", Fields: []ast.FieldInfo{{Name: "Title", TypeStr: "string"}}},
+		},
+	}
+	vars := map[string]ast.TemplateVar{"Root": node}
+
+	for name, content := range map[string]string{
+		"range": `{{define "tree"}}{{.Title}}{{range .Children}}{{template "tree" .}}{{end}}{{end}}{{template "tree" .Root}}`,
+		"with":  `{{define "list"}}{{.Title}}{{with .Next}}{{template "list" .}}{{end}}{{end}}{{template "list" .Root}}`,
+		"if in a chain": `{{define "a"}}{{.Title}}{{template "b" .}}{{end}}` +
+			`{{define "b"}}{{if .Next}}{{template "a" .}}{{end}}{{end}}{{template "a" .Root}}`,
+		"else": `{{define "tree"}}{{if not .Children}}{{.Title}}{{else}}{{template "tree" .}}{{end}}{{end}}{{template "tree" .Root}}`,
+	} {
+		errs := validator.ValidateTemplateContent(content, vars, "test.html", ".", ".", 1, nil)
+		if len(errs) != 0 {
+			t.Errorf("%s: expected no results, got %#v", name, errs)
+		}
+	}
+}
+
+func TestSelfIncludingFileIsReported(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"templates/partials/recursive.html": `{{ .Name }}{{ template "partials/recursive.html" . }}`,
+		"templates/page.html":               `{{ template "partials/recursive.html" .User }}`,
+		"templates/other.html":              `{{ .Missing }}`,
+	})
+	user := ast.TemplateVar{Name: "User", TypeStr: "User", Fields: []ast.FieldInfo{{Name: "Name", TypeStr: "string"}}}
+	calls := []ast.RenderCall{
+		{Template: "page.html", Vars: []ast.TemplateVar{user}},
+		{Template: "other.html", Vars: []ast.TemplateVar{user}},
+	}
+
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "templates", validator.ValidateOptions{})
+
+	var selfInclude, missing bool
+	for _, r := range results {
+		switch {
+		case r.Template == "page.html" && strings.HasSuffix(r.Message, `Template "partials/recursive.html" includes itself`):
+			selfInclude = true
+		case r.Template == "other.html" && r.Variable == ".Missing":
+			missing = true
+		default:
+			t.Errorf("unexpected result %+v", r)
+		}
+	}
+	if !selfInclude {
+		t.Errorf("expected a self-inclusion warning, got %#v", results)
+	}
+	if !missing {
+		t.Errorf("expected the rest of the project to be validated, got %#v", results)
+	}
+}
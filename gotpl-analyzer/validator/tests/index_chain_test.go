@@ -0,0 +1,69 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+var indexChainVars = map[string]ast.TemplateVar{
+	"Users": {
+		Name: "Users", TypeStr: "[]User", IsSlice: true, ElemType: "User",
+		Fields: []ast.FieldInfo{
+			{Name: "Name", TypeStr: "string"},
+			{Name: "Address", TypeStr: "Address", Fields: []ast.FieldInfo{{Name: "City", TypeStr: "string"}}},
+		},
+	},
+	"Matrix": {
+		Name: "Matrix", TypeStr: "[][]Cell", IsSlice: true, ElemType: "[]Cell",
+		Fields: []ast.FieldInfo{{Name: "Value", TypeStr: "int"}},
+	},
+}
+
+func TestIndexChainValid(t *testing.T) {
+	for _, content := range []string{
+		`{{ (index .Users 0).Address.City }}`,
+		`{{ (index .Users 0).Name | printf "%s" }}`,
+		`{{ (index (index .Matrix 0) 1).Value }}`,
+		`{{ $u := index .Users 0 }}{{ $u.Address.City }}`,
+		`{{ range .Users }}{{ (index $.Users 0).Address.City }}{{ end }}`,
+	} {
+		errs := validator.ValidateTemplateContent(content, indexChainVars, "t.html", ".", ".", 1, nil)
+		if len(errs) != 0 {
+			t.Errorf("%s: expected no errors, got %#v", content, errs)
+		}
+	}
+}
+
+func TestIndexChainInvalid(t *testing.T) {
+	cases := []struct {
+		content  string
+		variable string
+		message  string
+	}{
+		{`{{ (index .Users 0).Address.Nope }}`, "(index .Users 0).Address.Nope", `Field "Nope" does not exist on type Address`},
+		{`{{ (index .Users 0).Nope.City }}`, "(index .Users 0).Nope.City", `Field "Nope" does not exist on type User`},
+		{`{{ (index (index .Matrix 0) 1).Nope }}`, "(index (index .Matrix 0) 1).Nope", `Field "Nope" does not exist on type Cell`},
+	}
+
+	for _, tc := range cases {
+		errs := validator.ValidateTemplateContent(tc.content, indexChainVars, "t.html", ".", ".", 1, nil)
+		if len(errs) != 1 {
+			t.Errorf("%s: expected 1 error, got %#v", tc.content, errs)
+			continue
+		}
+		if errs[0].Variable != tc.variable || errs[0].Message != tc.message {
+			t.Errorf("%s: expected %q for %s, got %q for %s", tc.content, tc.message, tc.variable, errs[0].Message, errs[0].Variable)
+		}
+		if errs[0].Column != 4 {
+			t.Errorf("%s: expected error to start at the opening parenthesis, got column %d", tc.content, errs[0].Column)
+		}
+	}
+
+	// Fields referenced inside the parentheses are still checked as usual.
+	errs := validator.ValidateTemplateContent(`{{ (index .Missing 0).Name }}`, indexChainVars, "t.html", ".", ".", 1, nil)
+	if len(errs) != 1 || errs[0].Variable != ".Missing" {
+		t.Errorf("expected only .Missing to be reported, got %#v", errs)
+	}
+}
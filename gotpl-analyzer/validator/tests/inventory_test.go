@@ -0,0 +1,37 @@
+package validator_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestInventory(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"templates/index.html":    `{{template "header" .}}{{.Title}}`,
+		"templates/unused.html":   `<p>never rendered</p>`,
+		"templates/partials.html": `{{define "header"}}<h1>{{.Title}}</h1>{{end}}{{define "footer"}}{{end}}`,
+	})
+	calls := []ast.RenderCall{
+		{Template: "index.html", File: "a.go", Line: 1},
+		{Template: "index.html", File: "b.go", Line: 2},
+		{Template: "missing.html", File: "a.go", Line: 9},
+		{Template: "", File: "a.go", Line: 12},
+	}
+
+	got := validator.Inventory(calls, dir, "templates")
+	want := []validator.InventoryEntry{
+		{Template: "footer", IsNamedBlock: true},
+		{Template: "header", IsNamedBlock: true, Included: true},
+		{Template: "index.html", IsFile: true, Rendered: true, RenderCallCount: 2},
+		{Template: "missing.html", Rendered: true, RenderCallCount: 1},
+		{Template: "partials.html", IsFile: true},
+		{Template: "unused.html", IsFile: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Inventory:\n got %+v\nwant %+v", got, want)
+	}
+}
@@ -0,0 +1,41 @@
+package validator_test
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestValidateTemplatesLogger(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"page.html": `{{define "card"}}{{.Title}}{{end}}{{template "card" .}}`,
+		"huge.html": strings.Repeat("x", 2048),
+	}
+	writeTree(t, dir, files)
+
+	var buf bytes.Buffer
+	calls := []ast.RenderCall{{Template: "page.html", Vars: []ast.TemplateVar{{Name: "Title", TypeStr: "string"}}}}
+	validator.ValidateTemplatesWithOptions(calls, nil, dir, "", validator.ValidateOptions{
+		MaxTemplateBytes: 1024,
+		Logger:           slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+	})
+
+	logs := buf.String()
+	for _, want := range []string{
+		`msg="found template files"`,
+		`msg="skipped template file" file=huge.html maxBytes=1024`,
+		`msg="parsed named templates" blocks=1 duplicates=0 skipped=1`,
+		`msg="validated render call targets" templates=1 results=0`,
+		`msg="validated template tree"`,
+		`msg="validated orphaned named blocks"`,
+	} {
+		if !strings.Contains(logs, want) {
+			t.Errorf("expected log containing %q, got:\n%s", want, logs)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package validator_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestMaxErrors(t *testing.T) {
+	dir := t.TempDir()
+	files := make(map[string]string)
+	var calls []ast.RenderCall
+	for i := range 500 {
+		name := fmt.Sprintf("page%d.html", i)
+		files["templates/"+name] = strings.Repeat("{{.Missing}}\n", 4)
+		calls = append(calls, ast.RenderCall{Template: name, Vars: []ast.TemplateVar{{Name: "Title", TypeStr: "string"}}})
+	}
+	writeTree(t, dir, files)
+
+	all, _, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "templates", validator.ValidateOptions{})
+	if len(all) != 2000 {
+		t.Fatalf("expected 2000 results without a cap, got %d", len(all))
+	}
+
+	const limit = 10
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "templates", validator.ValidateOptions{MaxErrors: limit})
+	if len(results) != limit+1 {
+		t.Fatalf("expected %d results plus the suppression note, got %d", limit, len(results))
+	}
+	note := results[limit]
+	if note.Severity != "error" || !strings.HasSuffix(note.Message, fmt.Sprintf("additional errors suppressed (limit %d)", limit)) {
+		t.Errorf("unexpected suppression note %+v", note)
+	}
+	var suppressed int
+	if _, err := fmt.Sscanf(note.Message, "… %d additional", &suppressed); err != nil || suppressed < 1 || suppressed >= len(all)-limit {
+		t.Errorf("expected workers to stop early, note %q", note.Message)
+	}
+}
+
+func TestMaxErrorsNoteNamesSeverity(t *testing.T) {
+	dir := t.TempDir()
+	files := make(map[string]string)
+	var calls []ast.RenderCall
+	for i := range 20 {
+		name := fmt.Sprintf("empty%d.html", i)
+		files["templates/"+name] = "<p>static</p>"
+		calls = append(calls, ast.RenderCall{Template: name, Vars: []ast.TemplateVar{{Name: "Title", TypeStr: "string"}}})
+	}
+	writeTree(t, dir, files)
+
+	const limit = 5
+	opts := validator.ValidateOptions{MaxErrors: limit, WarnEmptyTemplates: true}
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "templates", opts)
+	if len(results) != limit+1 {
+		t.Fatalf("expected %d results plus the suppression note, got %#v", limit, results)
+	}
+	note := results[limit]
+	if note.Severity != "warning" || !strings.Contains(note.Message, "additional warnings suppressed") {
+		t.Errorf("expected a warning note about suppressed warnings, got %+v", note)
+	}
+
+	opts.WarningsAsErrors = true
+	results, _, _, _ = validator.ValidateTemplatesWithOptions(calls, nil, dir, "templates", opts)
+	if len(results) != limit+1 {
+		t.Fatalf("expected %d results plus the suppression note, got %#v", limit, results)
+	}
+	for _, r := range results[:limit] {
+		if r.Severity != "error" {
+			t.Errorf("expected escalated results, got %+v", r)
+		}
+	}
+	note = results[limit]
+	if note.Severity != "error" || !strings.Contains(note.Message, "additional errors suppressed") {
+		t.Errorf("expected an error note about suppressed errors, got %+v", note)
+	}
+}
+
+func TestMaxErrorsIgnoresFilteredResults(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"templates/page.html": strings.Repeat("{{template .Partial .}}\n", 10) + "{{.Missing}}",
+	})
+	calls := []ast.RenderCall{{Template: "page.html", Vars: []ast.TemplateVar{{Name: "Partial", TypeStr: "string"}}}}
+
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "templates", validator.ValidateOptions{MaxErrors: 1})
+	if len(results) != 1 || results[0].Variable != ".Missing" {
+		t.Errorf("expected only .Missing within the cap, got %#v", results)
+	}
+}
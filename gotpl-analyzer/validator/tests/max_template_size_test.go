@@ -0,0 +1,71 @@
+package validator_test
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestMaxTemplateSizeSkipsLargeFiles(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"small.html":          `{{define "card"}}{{.Missing}}{{end}}`,
+		"generated/huge.html": `{{define "huge"}}{{.Missing}}{{end}}` + strings.Repeat("x", 4096),
+	}
+	writeTree(t, dir, files)
+
+	errs, namedBlocks, _, skipped := validator.ValidateTemplatesWithOptions(nil, nil, dir, "", validator.ValidateOptions{MaxTemplateBytes: 1024})
+
+	if want := []string{"skipped generated/huge.html: exceeds max size"}; !reflect.DeepEqual(skipped, want) {
+		t.Errorf("expected skip notes %q, got %q", want, skipped)
+	}
+	if _, ok := namedBlocks["huge"]; ok {
+		t.Errorf("skipped file must not contribute named blocks")
+	}
+	if _, ok := namedBlocks["card"]; !ok {
+		t.Errorf("expected named block from small file, got %v", namedBlocks)
+	}
+	for _, e := range errs {
+		if strings.HasSuffix(e.Template, "huge.html") {
+			t.Errorf("skipped file must not be validated, got %#v", e)
+		}
+	}
+
+	_, namedBlocks, _, skipped = validator.ValidateTemplatesWithOptions(nil, nil, dir, "", validator.ValidateOptions{})
+	if len(skipped) != 0 || len(namedBlocks) != 2 {
+		t.Errorf("expected no limit by default, got skipped %q and blocks %v", skipped, namedBlocks)
+	}
+}
+
+func TestMaxTemplateSizeSkipsLargeRenderTargets(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"index.html":   `{{template "report.html" .}}{{.Title}}`,
+		"report.html":  `{{.Missing}}` + strings.Repeat("x", 4096),
+		"archive.html": strings.Repeat("x", 4096),
+	})
+	calls := []ast.RenderCall{
+		{Template: "report.html", Vars: []ast.TemplateVar{{Name: "Title", TypeStr: "string"}}},
+		{Template: "archive.html", Vars: []ast.TemplateVar{{Name: "Title", TypeStr: "string"}}},
+	}
+
+	errs, _, _, skipped := validator.ValidateTemplatesWithOptions(calls, nil, dir, "", validator.ValidateOptions{
+		MaxTemplateBytes:   1024,
+		WarnEmptyTemplates: true,
+	})
+
+	want := []string{"skipped archive.html: exceeds max size", "skipped report.html: exceeds max size"}
+	slices.Sort(skipped)
+	if !reflect.DeepEqual(skipped, want) {
+		t.Errorf("expected skip notes %q, got %q", want, skipped)
+	}
+	for _, e := range errs {
+		if e.Template == "report.html" || e.Template == "archive.html" {
+			t.Errorf("skipped render target must not be validated, got %#v", e)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package validator_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestMergeCallContexts(t *testing.T) {
+	calls := []ast.RenderCall{
+		{Template: "page.html", Vars: []ast.TemplateVar{{Name: "Title"}, {Name: "User"}}},
+		{Template: "page.html", Vars: []ast.TemplateVar{{Name: "Title"}, {Name: "Flash"}}},
+		{Template: "other.html", Vars: []ast.TemplateVar{{Name: "Items"}}},
+	}
+
+	merged := validator.MergeCallContexts(calls)
+	var names []string
+	for _, v := range merged["page.html"] {
+		names = append(names, v.Name)
+	}
+	if len(names) != 3 || names[0] != "Title" || names[1] != "User" || names[2] != "Flash" {
+		t.Errorf("expected union Title, User, Flash, got %v", names)
+	}
+	if len(merged["other.html"]) != 1 {
+		t.Errorf("expected other.html to keep its own context, got %v", merged["other.html"])
+	}
+}
+
+func TestMergeContextsWarnsOnPartialVariables(t *testing.T) {
+	dir := t.TempDir()
+	content := "<h1>{{.Title}}</h1>\n{{if .User}}{{.User.Name}}{{end}}\n{{.Missing}}\n"
+	if err := os.WriteFile(filepath.Join(dir, "page.html"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	user := ast.TemplateVar{Name: "User", TypeStr: "User", Fields: []ast.FieldInfo{{Name: "Name", TypeStr: "string"}}}
+	title := ast.TemplateVar{Name: "Title", TypeStr: "string"}
+	calls := []ast.RenderCall{
+		{File: "a.go", Line: 10, Template: "page.html", Vars: []ast.TemplateVar{title, user}},
+		{File: "b.go", Line: 20, Template: "page.html", Vars: []ast.TemplateVar{title}},
+		{File: "c.go", Line: 30, Template: "page.html", Vars: []ast.TemplateVar{title, user}},
+	}
+
+	// Without the option the merged context hides the partial variable.
+	errs, _, _ := validator.ValidateTemplates(calls, nil, dir, "")
+	for _, e := range errs {
+		if e.Severity == "warning" {
+			t.Fatalf("did not expect warnings without MergeContexts, got %#v", e)
+		}
+	}
+
+	errs, _, _, _ = validator.ValidateTemplatesWithOptions(calls, nil, dir, "", validator.ValidateOptions{MergeContexts: true})
+
+	warned := map[string]bool{}
+	var missing int
+	for _, e := range errs {
+		switch {
+		case e.Severity == "warning":
+			if e.Message != `Variable "`+e.Variable+`" is only provided by some render calls` {
+				t.Errorf("unexpected warning message %q", e.Message)
+			}
+			warned[e.Variable] = true
+		case e.Variable == ".Missing":
+			missing++
+		default:
+			t.Errorf("unexpected result %#v", e)
+		}
+	}
+	if !warned[".User"] || !warned[".User.Name"] || len(warned) != 2 {
+		t.Errorf("expected warnings for .User and .User.Name only, got %v", warned)
+	}
+	if missing != 1 {
+		t.Errorf("expected .Missing to be reported once, got %d", missing)
+	}
+}
+
+func TestMergeContextsWithEmptySharedContext(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "page.html"), []byte("{{.Flash}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := []ast.RenderCall{
+		{Template: "page.html", NilData: true},
+		{Template: "page.html", Vars: []ast.TemplateVar{{Name: "Flash", TypeStr: "string"}}},
+	}
+	errs, _, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "", validator.ValidateOptions{MergeContexts: true})
+	if len(errs) != 1 || errs[0].Variable != ".Flash" || errs[0].Severity != "warning" {
+		t.Errorf("expected a single partial-variable warning for .Flash, got %#v", errs)
+	}
+}
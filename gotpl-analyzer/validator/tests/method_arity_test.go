@@ -0,0 +1,135 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+var methodArityVars = map[string]ast.TemplateVar{
+	"User": {
+		Name:    "User",
+		TypeStr: "User",
+		Fields: []ast.FieldInfo{
+			{Name: "Name", TypeStr: "string"},
+			{Name: "Groups", TypeStr: "[]string", IsSlice: true, ElemType: "string"},
+			{
+				Name:    "HasRole",
+				TypeStr: "method",
+				Params:  []ast.ParamInfo{{Name: "role", TypeStr: "string"}},
+				Returns: []ast.ParamInfo{{TypeStr: "bool"}},
+			},
+			{
+				Name:    "DisplayName",
+				TypeStr: "method",
+				Returns: []ast.ParamInfo{{TypeStr: "string"}},
+			},
+			{
+				Name:     "InAnyGroup",
+				TypeStr:  "method",
+				Params:   []ast.ParamInfo{{Name: "groups", TypeStr: "[]string"}},
+				Returns:  []ast.ParamInfo{{TypeStr: "bool"}},
+				Variadic: boolPtr(false),
+			},
+			{
+				// Variadic is unknown, as for a method described by hand.
+				Name:    "AnyOf",
+				TypeStr: "method",
+				Params:  []ast.ParamInfo{{Name: "groups", TypeStr: "[]string"}},
+				Returns: []ast.ParamInfo{{TypeStr: "bool"}},
+			},
+			{
+				Name:     "InGroups",
+				TypeStr:  "method",
+				Params:   []ast.ParamInfo{{Name: "kind", TypeStr: "string"}, {Name: "groups", TypeStr: "[]string"}},
+				Returns:  []ast.ParamInfo{{TypeStr: "bool"}},
+				Variadic: boolPtr(true),
+			},
+		},
+	},
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+func TestMethodArityValid(t *testing.T) {
+	content := `{{if .User.HasRole "admin"}}{{.User.DisplayName}}{{end}}` +
+		`{{with $u := .User}}{{if $u.HasRole "staff"}}ok{{end}}{{end}}` +
+		`{{if "admin" | .User.HasRole}}ok{{end}}` +
+		`{{if and (.User.HasRole "a") .User.Name}}ok{{end}}` +
+		`{{if .User.InAnyGroup .User.Groups}}{{end}}` +
+		`{{if .User.AnyOf}}{{end}}{{if .User.AnyOf "a" "b"}}{{end}}` +
+		`{{printf "%s" .User.DisplayName}}{{printf "%v" .User.AnyOf}}` +
+		`{{if .User.InGroups "staff"}}{{end}}{{if .User.InGroups "staff" "a" "b"}}{{end}}`
+
+	errs := validator.ValidateTemplateContent(content, methodArityVars, "test.html", ".", ".", 1, nil)
+	for _, e := range errs {
+		t.Logf("Error: %s (variable: %s)", e.Message, e.Variable)
+	}
+	if len(errs) != 0 {
+		t.Errorf("Expected 0 errors, got %d", len(errs))
+	}
+}
+
+func TestMethodArityMismatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		message string
+	}{
+		{
+			name:    "missing argument",
+			content: `{{if .User.HasRole}}{{end}}`,
+			message: `Method "HasRole" expects 1 arguments but got 0`,
+		},
+		{
+			name:    "extra argument",
+			content: `{{.User.DisplayName "x"}}`,
+			message: `Method "DisplayName" expects 0 arguments but got 1`,
+		},
+		{
+			name:    "method passed as argument",
+			content: `{{if not .User.HasRole}}{{end}}`,
+			message: `Method "HasRole" requires arguments and cannot be used as a value`,
+		},
+		{
+			name:    "method with arguments used as printf value",
+			content: `{{printf "%s" .User.HasRole}}`,
+			message: `Method "HasRole" requires arguments and cannot be used as a value`,
+		},
+		{
+			name:    "variadic method without its required arguments",
+			content: `{{.User.InGroups}}`,
+			message: `Method "InGroups" expects at least 1 arguments but got 0`,
+		},
+		{
+			name:    "slice parameter of a non-variadic method",
+			content: `{{if .User.InAnyGroup "a" "b"}}{{end}}`,
+			message: `Method "InAnyGroup" expects 1 arguments but got 2`,
+		},
+		{
+			name:    "non-variadic method with a slice parameter used as a value",
+			content: `{{printf "%v" .User.InAnyGroup}}`,
+			message: `Method "InAnyGroup" requires arguments and cannot be used as a value`,
+		},
+		{
+			name:    "piped value counts",
+			content: `{{"a" | .User.HasRole "b"}}`,
+			message: `Method "HasRole" expects 1 arguments but got 2`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validator.ValidateTemplateContent(tt.content, methodArityVars, "test.html", ".", ".", 1, nil)
+			if len(errs) != 1 {
+				t.Fatalf("Expected 1 error, got %d: %#v", len(errs), errs)
+			}
+			if errs[0].Message != tt.message {
+				t.Errorf("Expected message %q, got %q", tt.message, errs[0].Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package validator_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// TestRangeOverNamedSliceType verifies that ranging over a named slice type,
+// both as a variable and as a struct field, exposes the element's fields.
+func TestRangeOverNamedSliceType(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"app/go.mod": "module example.com/test\ngo 1.21\n",
+		"app/main.go": `package main
+
+type Context struct{}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+type User struct{ Name string }
+type UserList []User
+
+type Team struct {
+	Members UserList
+}
+
+func handler(c *Context, users UserList, team Team) {
+	c.Render("users.html", map[string]interface{}{"users": users, "team": team})
+}
+`,
+		"templates/users.html": `{{range .users}}{{.Name}}{{.Email}}{{end}}
+{{range .team.Members}}{{.Name}}{{.Age}}{{end}}`,
+	})
+
+	analysis := ast.AnalyzeDir(filepath.Join(dir, "app"), "", ast.DefaultConfig)
+	if len(analysis.Errors) > 0 || len(analysis.RenderCalls) != 1 {
+		t.Fatalf("expected 1 render call, got %d and errors %v", len(analysis.RenderCalls), analysis.Errors)
+	}
+
+	var members *ast.FieldInfo
+	for _, v := range analysis.RenderCalls[0].Vars {
+		if v.Name == "team" && len(v.Fields) == 1 {
+			members = &v.Fields[0]
+		}
+	}
+	if members == nil || !members.IsSlice || members.ElemType != "main.User" || len(members.Fields) != 1 || members.Fields[0].Name != "Name" {
+		t.Fatalf("expected team.Members to be a slice of main.User with its fields, got %+v", members)
+	}
+
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(analysis.RenderCalls, nil, dir, "templates", validator.ValidateOptions{})
+	if len(results) != 2 || results[0].Variable != ".Email" || results[1].Variable != ".Age" {
+		t.Errorf("expected only .Email and .Age to be reported, got %#v", results)
+	}
+}
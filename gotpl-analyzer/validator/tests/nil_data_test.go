@@ -0,0 +1,72 @@
+package validator_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestNilDataRootReference(t *testing.T) {
+	dir := t.TempDir()
+	content := "{{.Foo}}\n{{if true}}{{.Bar.Baz}}{{end}}\n{{range .Items}}{{.}}{{end}}\n"
+	if err := os.WriteFile(filepath.Join(dir, "x.html"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	call := ast.RenderCall{File: "handlers.go", Line: 7, Template: "x.html", NilData: true}
+	errs, _, _ := validator.ValidateTemplates([]ast.RenderCall{call}, nil, dir, "")
+
+	want := map[string]bool{".Foo": true, ".Bar.Baz": true, ".Items": true}
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d errors, got %#v", len(want), errs)
+	}
+	for _, e := range errs {
+		if !want[e.Variable] {
+			t.Errorf("unexpected error %#v", e)
+			continue
+		}
+		msg := `Template references "` + e.Variable + `" but render call passed nil data`
+		if e.Message != msg || e.Severity != "error" {
+			t.Errorf("expected %q, got %#v", msg, e)
+		}
+	}
+}
+
+func TestNilDataTemplates(t *testing.T) {
+	calls := []ast.RenderCall{
+		{Template: "nil.html", NilData: true},
+		{Template: "nil.html", NilData: true},
+		{Template: "mixed.html", NilData: true},
+		{Template: "mixed.html", Vars: []ast.TemplateVar{{Name: "Foo"}}},
+		{Template: "data.html", Vars: []ast.TemplateVar{{Name: "Foo"}}},
+	}
+
+	got := validator.NilDataTemplates(calls)
+	if len(got) != 1 || !got["nil.html"] {
+		t.Errorf("expected only nil.html, got %v", got)
+	}
+	if vars := validator.MergeCallContexts(calls)["nil.html"]; len(vars) != 0 {
+		t.Errorf("expected no variables for nil.html, got %#v", vars)
+	}
+}
+
+func TestNilDataMixedWithRealData(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "x.html"), []byte("{{.Foo}}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := []ast.RenderCall{
+		{File: "a.go", Line: 1, Template: "x.html", NilData: true},
+		{File: "b.go", Line: 2, Template: "x.html", Vars: []ast.TemplateVar{{Name: "Foo", TypeStr: "string"}}},
+	}
+	errs, _, _ := validator.ValidateTemplates(calls, nil, dir, "")
+	for _, e := range errs {
+		if e.Severity == "error" {
+			t.Errorf("did not expect errors when another call supplies data, got %#v", e)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestValidateOnly(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"templates/users/list.html":  `{{template "row" .}}{{.Missing}}`,
+		"templates/users/edit.html":  `{{.Nope}}`,
+		"templates/admin/index.html": `{{.Absent}}`,
+		"templates/partials.html":    `{{define "row"}}{{.Title}}{{end}}`,
+		"templates/orphan.html":      `{{.Unrendered.Field}}`,
+	})
+	vars := []ast.TemplateVar{{Name: "Title", TypeStr: "string"}}
+	calls := []ast.RenderCall{
+		{Template: "users/list.html", Vars: vars},
+		{Template: "users/edit.html", Vars: vars},
+		{Template: "admin/index.html", Vars: vars},
+	}
+
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "templates", validator.ValidateOptions{Only: "users/l*.html"})
+	if len(results) != 1 {
+		t.Fatalf("expected only the users/list.html result, got %+v", results)
+	}
+	if r := results[0]; r.Template != "users/list.html" || r.Variable != ".Missing" {
+		t.Errorf("unexpected result %+v", r)
+	}
+
+	all, _, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "templates", validator.ValidateOptions{})
+	if len(all) <= len(results) {
+		t.Errorf("expected more results without Only, got %+v", all)
+	}
+
+	if got := validator.FilterRenderCalls(calls, "users/*.html"); len(got) != 2 {
+		t.Errorf("FilterRenderCalls: expected the two users calls, got %+v", got)
+	}
+	if got := validator.FilterRenderCalls(calls, ""); len(got) != len(calls) {
+		t.Errorf("FilterRenderCalls: empty glob should keep every call, got %+v", got)
+	}
+}
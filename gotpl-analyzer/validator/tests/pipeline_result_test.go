@@ -0,0 +1,56 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+var pipelineResultVars = map[string]ast.TemplateVar{
+	"N":     {Name: "N", TypeStr: "int"},
+	"Items": {Name: "Items", TypeStr: "[]string", IsSlice: true, ElemType: "string"},
+	"User":  {Name: "User", TypeStr: "User", Fields: []ast.FieldInfo{{Name: "Name", TypeStr: "string"}}},
+}
+
+var pipelineResultFuncs = validator.BuildFuncMapRegistry([]ast.FuncMapInfo{
+	{Name: "title", Returns: []ast.ParamInfo{{TypeStr: "string"}}},
+	{Name: "lookup", Returns: []ast.ParamInfo{{TypeStr: "any"}}},
+})
+
+func TestPipelineResultFieldValid(t *testing.T) {
+	for _, content := range []string{
+		`{{ .Items | len }}`,
+		`{{ (printf "%d" .N) }}`,
+		`{{ (lookup "x").Foo }}`,
+		`{{ (and .User .User).Name }}`,
+	} {
+		errs := validator.ValidateTemplateContent(content, pipelineResultVars, "t.html", ".", ".", 1, nil, pipelineResultFuncs)
+		if len(errs) != 0 {
+			t.Errorf("%s: expected no errors, got %#v", content, errs)
+		}
+	}
+}
+
+func TestPipelineResultFieldInvalid(t *testing.T) {
+	cases := []struct {
+		content string
+		message string
+	}{
+		{`{{ (printf "%d" .N).Length }}`, "string has no field Length"},
+		{`{{ (.Items | len).Foo }}`, "int has no field Foo"},
+		{`{{ (eq .N 1).Foo }}`, "bool has no field Foo"},
+		{`{{ ("x" | title).Foo }}`, "string has no field Foo"},
+	}
+
+	for _, tc := range cases {
+		errs := validator.ValidateTemplateContent(tc.content, pipelineResultVars, "t.html", ".", ".", 1, nil, pipelineResultFuncs)
+		if len(errs) != 1 {
+			t.Errorf("%s: expected 1 error, got %#v", tc.content, errs)
+			continue
+		}
+		if errs[0].Message != tc.message || errs[0].Severity != "error" {
+			t.Errorf("%s: expected error %q, got %s %q", tc.content, tc.message, errs[0].Severity, errs[0].Message)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package validator_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// TestRangeOverPointerToCollectionField verifies that *[]T and *map[K]T
+// fields keep their collection metadata and element fields, so that range
+// and with over them resolve the element's fields.
+func TestRangeOverPointerToCollectionField(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"app/go.mod": "module example.com/test\ngo 1.21\n",
+		"app/main.go": `package main
+
+type Context struct{}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+type Order struct{ ID int }
+
+type User struct {
+	Orders  *[]Order
+	ByRef   *map[string]Order
+}
+
+func handler(c *Context, user User) {
+	c.Render("orders.html", map[string]interface{}{"user": user})
+}
+`,
+		"templates/orders.html": `{{range .user.Orders}}{{.ID}}{{.Total}}{{end}}
+{{with .user.Orders}}{{range .}}{{.ID}}{{end}}{{end}}
+{{range $ref, $o := .user.ByRef}}{{$ref}}{{$o.ID}}{{$o.Status}}{{end}}
+{{with .user.ByRef}}{{range .}}{{.ID}}{{end}}{{end}}`,
+	})
+
+	analysis := ast.AnalyzeDir(filepath.Join(dir, "app"), "", ast.DefaultConfig)
+	if len(analysis.Errors) > 0 || len(analysis.RenderCalls) != 1 {
+		t.Fatalf("expected 1 render call, got %d and errors %v", len(analysis.RenderCalls), analysis.Errors)
+	}
+
+	fields := make(map[string]ast.FieldInfo)
+	for _, v := range analysis.RenderCalls[0].Vars {
+		if v.Name == "user" {
+			for _, f := range v.Fields {
+				fields[f.Name] = f
+			}
+		}
+	}
+	orders := fields["Orders"]
+	if !orders.IsSlice || orders.ElemType != "main.Order" || len(orders.Fields) != 1 || orders.Fields[0].Name != "ID" {
+		t.Errorf("expected user.Orders to be a slice of main.Order with its fields, got %+v", orders)
+	}
+	byRef := fields["ByRef"]
+	if !byRef.IsMap || byRef.KeyType != "string" || byRef.ElemType != "main.Order" || len(byRef.Fields) != 1 || byRef.Fields[0].Name != "ID" {
+		t.Errorf("expected user.ByRef to be a map of main.Order with its fields, got %+v", byRef)
+	}
+
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(analysis.RenderCalls, nil, dir, "templates", validator.ValidateOptions{})
+	if len(results) != 2 || results[0].Variable != ".Total" || results[1].Variable != "$o.Status" {
+		t.Errorf("expected only .Total and $o.Status to be reported, got %#v", results)
+	}
+}
@@ -0,0 +1,68 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// pointerSliceVars mirrors what the analyzer extracts for []*User and
+// map[string]*User: the element's fields are attached to the collection.
+var pointerSliceVars = func() map[string]ast.TemplateVar {
+	userFields := []ast.FieldInfo{
+		{Name: "Name", TypeStr: "string"},
+		{Name: "Address", TypeStr: "*main.Address", Fields: []ast.FieldInfo{{Name: "City", TypeStr: "string"}}},
+	}
+	return map[string]ast.TemplateVar{
+		"Users": {Name: "Users", TypeStr: "[]*main.User", IsSlice: true, ElemType: "*main.User", Fields: userFields},
+		"ByID":  {Name: "ByID", TypeStr: "map[string]*main.User", IsMap: true, KeyType: "string", ElemType: "*main.User", Fields: userFields},
+	}
+}()
+
+func TestRangeOverPointerSlice(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string // undefined variables
+	}{
+		{"fields", `{{range .Users}}{{.Name}} {{.Address.City}}{{end}}`, nil},
+		{"value variable", `{{range $u := .Users}}{{$u.Name}} {{$u.Address.City}}{{end}}`, nil},
+		{"index and value", `{{range $i, $u := .Users}}{{$i}} {{$u.Address.City}}{{end}}`, nil},
+		{"map of pointers", `{{range $id, $u := .ByID}}{{$id}} {{$u.Name}}{{end}}{{range .ByID}}{{.Address.City}}{{end}}`, nil},
+		{"unknown field", `{{range .Users}}{{.Name}}{{.Nope}}{{end}}`, []string{".Nope"}},
+		{"unknown nested field", `{{range .Users}}{{.Address.Nope}}{{end}}`, []string{".Address.Nope"}},
+		{"unknown field on value variable", `{{range $u := .Users}}{{$u.Nope}}{{end}}`, []string{"$u.Nope"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validator.ValidateTemplateContent(tt.content, pointerSliceVars, "users.html", ".", ".", 1, nil)
+			if len(errs) != len(tt.want) {
+				t.Fatalf("expected errors for %v, got %#v", tt.want, errs)
+			}
+			for i, e := range errs {
+				if e.Variable != tt.want[i] {
+					t.Errorf("expected an error for %s, got %#v", tt.want[i], e)
+				}
+			}
+		})
+	}
+}
+
+func TestPointerElementTypeIsDereferenced(t *testing.T) {
+	errs := validator.ValidateTemplateContent(`{{(index .Users 0).Nope}}`, pointerSliceVars, "users.html", ".", ".", 1, nil)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %#v", errs)
+	}
+	if want := `Field "Nope" does not exist on type main.User`; errs[0].Message != want {
+		t.Errorf("expected %q, got %q", want, errs[0].Message)
+	}
+
+	content := `{{range .Users}}{{.Name}}{{end}}`
+	hover := validator.GetHoverResult(content, pointerSliceVars, "users.html", ".", ".", 0, 1, strings.Index(content, ".Name")+2, nil, nil, nil)
+	if hover == nil || hover.DotType != "main.User" {
+		t.Errorf("expected dot to be main.User inside the range, got %#v", hover)
+	}
+}
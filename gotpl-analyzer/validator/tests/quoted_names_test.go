@@ -0,0 +1,74 @@
+package validator_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+const quotedNamesContent = `{{define "weird name"}}{{.Title}}{{end}}` +
+	`{{define "say \"hi\""}}{{.Missing}}{{end}}` +
+	"{{template \"weird name\" .}}{{template `say \"hi\"` .}}"
+
+func TestQuotedNamesRegisterUnescaped(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "page.html"), []byte(quotedNamesContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, namedBlocks, _, _ := validator.ValidateTemplatesWithOptions(nil, nil, dir, "", validator.ValidateOptions{})
+	targets := validator.FindPartialTargets(dir, "")
+	for _, name := range []string{"weird name", `say "hi"`} {
+		if _, ok := namedBlocks[name]; !ok {
+			t.Errorf("expected named block %q, got %v", name, namedBlocks)
+		}
+		if !targets[name] {
+			t.Errorf("expected partial target %q, got %v", name, targets)
+		}
+	}
+}
+
+func TestQuotedNamesResolveCallTargets(t *testing.T) {
+	vars := map[string]ast.TemplateVar{
+		"Title": {Name: "Title", TypeStr: "string"},
+	}
+
+	errs := validator.ValidateTemplateContent(quotedNamesContent, vars, "page.html", ".", ".", 1, nil)
+	if len(errs) != 1 {
+		t.Fatalf("expected only the .Missing error, got %#v", errs)
+	}
+	if errs[0].Variable != ".Missing" || strings.Contains(errs[0].Message, "not found") {
+		t.Errorf("expected .Missing to be reported through the escaped call, got %#v", errs[0])
+	}
+}
+
+func TestBlockNameWithSpacesRoundTrips(t *testing.T) {
+	dir := t.TempDir()
+	body := `{{if .Title}}<h1>{{.Title}}</h1>{{end}}`
+	content := `{{define "page header"}}` + body + `{{end}}{{template "page header" .}}`
+	if err := os.WriteFile(filepath.Join(dir, "page.html"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	calls := []ast.RenderCall{{
+		File:     "main.go",
+		Line:     1,
+		Template: "page.html",
+		Vars:     []ast.TemplateVar{{Name: "Title", TypeStr: "string"}},
+	}}
+
+	errs, namedBlocks, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "", validator.ValidateOptions{})
+	if len(errs) != 0 {
+		t.Errorf("expected no errors, got %#v", errs)
+	}
+	entries := namedBlocks["page header"]
+	if len(entries) != 1 {
+		t.Fatalf("expected one \"page header\" entry, got %v", namedBlocks)
+	}
+	if entries[0].Content != body {
+		t.Errorf("expected nested {{end}} to stay in the block body, got %q", entries[0].Content)
+	}
+}
@@ -0,0 +1,85 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// groupedVars mirrors what the ast extractor produces for a
+// map[string][]User variable: the inline Fields belong to the innermost
+// element type (User), not to the []User value.
+var groupedVars = map[string]ast.TemplateVar{
+	"Grouped": {
+		Name:     "Grouped",
+		TypeStr:  "map[string][]User",
+		IsMap:    true,
+		KeyType:  "string",
+		ElemType: "[]User",
+		Fields: []ast.FieldInfo{
+			{Name: "Name", TypeStr: "string"},
+			{Name: "Email", TypeStr: "string"},
+		},
+	},
+}
+
+func TestRangeOverMapOfSlices(t *testing.T) {
+	content := `{{range .Grouped}}{{range .}}{{.Name}} {{.Email}}{{end}}{{end}}`
+
+	errs := validator.ValidateTemplateContent(content, groupedVars, "test.html", ".", ".", 1, nil)
+	for _, e := range errs {
+		t.Logf("Error: %s (variable: %s)", e.Message, e.Variable)
+	}
+	if len(errs) != 0 {
+		t.Errorf("Expected 0 errors, got %d", len(errs))
+	}
+}
+
+func TestRangeOverMapOfSlicesRejectsUnknownField(t *testing.T) {
+	content := `{{range $group, $users := .Grouped}}{{range $users}}{{.Missing}}{{end}}{{end}}`
+
+	errs := validator.ValidateTemplateContent(content, groupedVars, "test.html", ".", ".", 1, nil)
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d: %#v", len(errs), errs)
+	}
+	if errs[0].Variable != ".Missing" {
+		t.Errorf("Expected error on .Missing, got %q", errs[0].Variable)
+	}
+}
+
+func TestRangeOverMapOfSlicesHoverType(t *testing.T) {
+	content := `{{range .Grouped}}{{range .}}{{.Name}}{{end}}{{end}}`
+
+	result := validator.GetHoverResult(content, groupedVars, "test.html", ".", ".", 0, 1, 33, nil, nil, nil)
+	if result == nil {
+		t.Fatal("expected hover result")
+	}
+	if result.DotType != "User" {
+		t.Errorf("expected dot type User, got %q", result.DotType)
+	}
+}
+
+func TestRangeOverNestedMapKeepsKeyType(t *testing.T) {
+	vars := map[string]ast.TemplateVar{
+		"Matrix": {
+			Name:     "Matrix",
+			TypeStr:  "map[string]map[int]User",
+			IsMap:    true,
+			KeyType:  "string",
+			ElemType: "map[int]User",
+			Fields: []ast.FieldInfo{
+				{Name: "Name", TypeStr: "string"},
+			},
+		},
+	}
+	content := `{{range .Matrix}}{{range $id, $user := .}}{{$id}}{{$user.Name}}{{end}}{{end}}`
+
+	result := validator.GetHoverResult(content, vars, "test.html", ".", ".", 0, 1, 47, nil, nil, nil)
+	if result == nil {
+		t.Fatal("expected hover result")
+	}
+	if result.TypeStr != "int" {
+		t.Errorf("expected $id to be int, got %q", result.TypeStr)
+	}
+}
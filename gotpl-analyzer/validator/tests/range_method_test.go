@@ -0,0 +1,75 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// TestRangeOverMethod checks that ranging over a method returning a slice or
+// map iterates its elements, as the analyzer records methods with their
+// return types rather than as collections.
+func TestRangeOverMethod(t *testing.T) {
+	order := []ast.FieldInfo{{Name: "Total", TypeStr: "float64"}}
+	vars := map[string]ast.TemplateVar{
+		"User": {Name: "User", TypeStr: "main.User", Fields: []ast.FieldInfo{
+			{Name: "Name", TypeStr: "string"},
+			{Name: "Orders", TypeStr: "method", Returns: []ast.ParamInfo{{TypeStr: "[]main.Order", Fields: order}}},
+			{Name: "ByID", TypeStr: "method", Returns: []ast.ParamInfo{{TypeStr: "map[int]*main.Order", Fields: order}}},
+			{Name: "Pair", TypeStr: "method", Returns: []ast.ParamInfo{
+				{TypeStr: "[]main.Order", Fields: order},
+				{TypeStr: "error"},
+			}},
+		}},
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "slice method",
+			content: `{{range .User.Orders}}{{.Total}}{{.Nope}}{{end}}`,
+			want:    []string{".Nope"},
+		},
+		{
+			name:    "slice method with element variable",
+			content: `{{range $i, $o := .User.Orders}}{{$o.Total}}{{$o.Nope}}{{end}}`,
+			want:    []string{"$o.Nope"},
+		},
+		{
+			name:    "map method",
+			content: `{{range $k, $v := .User.ByID}}{{.Total}}{{$v.Total}}{{.Nope}}{{end}}`,
+			want:    []string{".Nope"},
+		},
+		{
+			name:    "method inside with",
+			content: `{{with .User}}{{range .Orders}}{{.Total}}{{.Nope}}{{end}}{{end}}`,
+			want:    []string{".Nope"},
+		},
+		{
+			name:    "multiple results",
+			content: `{{range .User.Pair}}{{.Total}}{{.Anything}}{{end}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validator.ValidateTemplateContent(tt.content, vars, "t.html", "", "", 1, nil, nil)
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Variable)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v: %+v", got, tt.want, errs)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("result %d: got %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
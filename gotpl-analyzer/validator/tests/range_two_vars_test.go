@@ -0,0 +1,63 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// TestRangeTwoVariables checks that {{range $i, $e := ...}} binds the index
+// or key to $i and the element to both $e and the dot, for slices and maps.
+func TestRangeTwoVariables(t *testing.T) {
+	item := []ast.FieldInfo{{Name: "Name", TypeStr: "string"}}
+	vars := map[string]ast.TemplateVar{
+		"Items":  {Name: "Items", TypeStr: "[]Item", IsSlice: true, ElemType: "Item", Fields: item},
+		"ByName": {Name: "ByName", TypeStr: "map[string]Item", IsMap: true, KeyType: "string", ElemType: "Item", Fields: item},
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "slice index and element",
+			content: `{{range $i, $e := .Items}}{{$i}} {{$e.Name}} {{.Name}}{{end}}`,
+		},
+		{
+			name:    "field on slice index",
+			content: `{{range $i, $e := .Items}}{{$i.Anything}}{{end}}`,
+			want:    []string{"int has no fields"},
+		},
+		{
+			name:    "unknown element field",
+			content: `{{range $i, $e := .Items}}{{$e.Nope}}{{end}}`,
+			want:    []string{`"$e.Nope" is not defined`},
+		},
+		{
+			name:    "map key and value",
+			content: `{{range $k, $v := .ByName}}{{$k}} {{$v.Name}} {{.Name}}{{end}}`,
+		},
+		{
+			name:    "field on map key",
+			content: `{{range $k, $v := .ByName}}{{$k.Anything}}{{end}}`,
+			want:    []string{"string has no fields"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validator.ValidateTemplateContent(tt.content, vars, "t.html", "", "", 1, nil, nil)
+			if len(errs) != len(tt.want) {
+				t.Fatalf("got %d results, want %d: %+v", len(errs), len(tt.want), errs)
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(errs[i].Message, want) {
+					t.Errorf("result %d: message %q does not contain %q", i, errs[i].Message, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// panicRule panics on the template named bad.html and reports every other
+// template it sees.
+type panicRule struct{}
+
+func (panicRule) Check(ctx validator.RuleContext) []validator.ValidationResult {
+	if ctx.Template == "bad.html" {
+		var m map[string]int
+		m["boom"]++ // assignment to entry in nil map
+	}
+	return []validator.ValidationResult{{Line: 1, Column: 1, Message: "checked", Severity: "warning"}}
+}
+
+// TestPanicInOneTemplateDoesNotAbortRun verifies that a panic while
+// validating one template becomes a syntax result for that template and that
+// every other template is still validated.
+func TestPanicInOneTemplateDoesNotAbortRun(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{"bad.html": `{{.Title}}`}
+	for i := range 20 {
+		files["page"+string(rune('a'+i))+".html"] = `{{.Title}}`
+	}
+	writeTree(t, dir, files)
+
+	calls := []ast.RenderCall{{Template: "bad.html", Vars: []ast.TemplateVar{{Name: "Title", TypeStr: "string"}}}}
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "", validator.ValidateOptions{
+		Rules: []validator.Rule{panicRule{}},
+	})
+
+	var internal []validator.ValidationResult
+	checked := 0
+	for _, r := range results {
+		switch {
+		case strings.HasPrefix(r.Message, "internal error"):
+			internal = append(internal, r)
+		case r.Message == "checked":
+			checked++
+		}
+	}
+
+	if len(internal) != 1 {
+		t.Fatalf("expected 1 internal error, got %+v", internal)
+	}
+	r := internal[0]
+	if r.Template != "bad.html" || r.Severity != validator.SeveritySyntax {
+		t.Errorf("unexpected internal error result %+v", r)
+	}
+	if !strings.Contains(r.Message, `validating "bad.html"`) || !strings.Contains(r.Message, "nil map") {
+		t.Errorf("expected the template and panic in the message, got %q", r.Message)
+	}
+	if checked != 20 {
+		t.Errorf("expected the 20 other templates to be checked, got %d", checked)
+	}
+}
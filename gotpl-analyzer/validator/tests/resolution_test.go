@@ -0,0 +1,31 @@
+package validator_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestDescribeResolution(t *testing.T) {
+	calls := []ast.RenderCall{
+		{File: "b.go", Line: 9, Template: "b.html", TemplateExpr: "tpl", ResolvedVia: ast.ResolvedViaVariable},
+		{File: "b.go", Line: 9, Template: "a.html", TemplateExpr: "tpl", ResolvedVia: ast.ResolvedViaVariable},
+		{File: "a.go", Line: 3, Template: "home.html", TemplateExpr: `"home.html"`, ResolvedVia: ast.ResolvedViaLiteral},
+		{File: "b.go", Line: 2, TemplateExpr: "pageName()", ResolvedVia: ast.ResolvedViaFailed},
+		{File: ast.ContextFileRenderCall, Line: 1, Template: "orphan.html"},
+	}
+
+	want := []validator.Resolution{
+		{File: "a.go", Line: 3, Expr: `"home.html"`, Templates: []string{"home.html"}, ResolvedVia: ast.ResolvedViaLiteral},
+		{File: "b.go", Line: 2, Expr: "pageName()", Templates: []string{}, ResolvedVia: ast.ResolvedViaFailed},
+		{File: "b.go", Line: 9, Expr: "tpl", Templates: []string{"a.html", "b.html"}, ResolvedVia: ast.ResolvedViaVariable},
+		{File: ast.ContextFileRenderCall, Line: 1, Templates: []string{"orphan.html"}, ResolvedVia: ast.ContextFileRenderCall},
+	}
+
+	got := validator.DescribeResolution(calls)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DescribeResolution mismatch\n got: %+v\nwant: %+v", got, want)
+	}
+}
@@ -0,0 +1,71 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// TestRootAccessInNestedScopes checks that $.X resolves against the root
+// context at any depth of with and range nesting, while the dot keeps
+// resolving against the innermost scope.
+func TestRootAccessInNestedScopes(t *testing.T) {
+	order := []ast.FieldInfo{{Name: "ID", TypeStr: "int"}}
+	vars := map[string]ast.TemplateVar{
+		"CurrentYear": {Name: "CurrentYear", TypeStr: "int"},
+		"Site":        {Name: "Site", TypeStr: "Site", Fields: []ast.FieldInfo{{Name: "Name", TypeStr: "string"}}},
+		"User": {Name: "User", TypeStr: "User", Fields: []ast.FieldInfo{
+			{Name: "Name", TypeStr: "string"},
+			{Name: "Orders", TypeStr: "[]Order", IsSlice: true, ElemType: "Order", Fields: order},
+		}},
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "with",
+			content: `{{with .User}}{{$.CurrentYear}}{{.Name}}{{end}}`,
+		},
+		{
+			name:    "with and range",
+			content: `{{with .User}}{{range .Orders}}{{$.CurrentYear}}{{$.Site.Name}}{{.ID}}{{end}}{{end}}`,
+		},
+		{
+			name:    "intermediate fields are not root fields",
+			content: `{{with .User}}{{range .Orders}}{{$.Name}}{{$.ID}}{{$.Orders}}{{end}}{{end}}`,
+			want:    []string{"$.Name", "$.ID", "$.Orders"},
+		},
+		{
+			name:    "dot stays innermost",
+			content: `{{with .User}}{{range .Orders}}{{.CurrentYear}}{{end}}{{end}}`,
+			want:    []string{".CurrentYear"},
+		},
+		{
+			name:    "unknown root field",
+			content: `{{range .User.Orders}}{{with $.Site}}{{$.Nope}}{{.Name}}{{end}}{{end}}`,
+			want:    []string{"$.Nope"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validator.ValidateTemplateContent(tt.content, vars, "t.html", "", "", 1, nil, nil)
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Variable)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %v, want %v: %+v", got, tt.want, errs)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("result %d: got %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package validator_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// TestRootAliasLocal verifies that {{$root := $}} binds $root to the root
+// context for the rest of the declaring scope, at any nesting depth.
+func TestRootAliasLocal(t *testing.T) {
+	varMap := map[string]ast.TemplateVar{
+		"User": {Name: "User", TypeStr: "User", Fields: []ast.FieldInfo{{Name: "Email", TypeStr: "string"}}},
+		"Items": {Name: "Items", TypeStr: "[]Item", IsSlice: true, ElemType: "Item", Fields: []ast.FieldInfo{
+			{Name: "Name", TypeStr: "string"},
+			{Name: "Tags", TypeStr: "[]string"},
+		}},
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		invalid []string
+	}{
+		{
+			name:    "inside range",
+			content: `{{$root := $}}{{range .Items}}{{$root.User.Email}}{{.Name}}{{end}}`,
+		},
+		{
+			name:    "nested range and with",
+			content: `{{$root := $}}{{range .Items}}{{range .Tags}}{{with $root.User}}{{.Email}} {{$root.User.Email}}{{end}}{{end}}{{end}}`,
+		},
+		{
+			name:    "declared from dot at the top level",
+			content: `{{$root := .}}{{range .Items}}{{$root.User.Email}}{{end}}`,
+		},
+		{
+			name:    "unknown fields",
+			content: `{{$root := $}}{{range .Items}}{{$root.User.Nope}} {{$root.Name}}{{end}}`,
+			invalid: []string{"$root.User.Nope", "$root.Name"},
+		},
+		{
+			name:    "out of scope after end",
+			content: `{{range .Items}}{{$root := $}}{{$root.User.Email}}{{end}}{{$root.User.Email}}`,
+			invalid: []string{"$root.User.Email"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, e := range validator.ValidateTemplateContent(tt.content, varMap, "test.html", ".", ".", 1, nil) {
+				got = append(got, e.Variable)
+			}
+			if !slices.Equal(got, tt.invalid) {
+				t.Errorf("expected %v to be reported, got %v", tt.invalid, got)
+			}
+		})
+	}
+}
+
+func TestRootAliasCompletions(t *testing.T) {
+	vars := []ast.TemplateVar{
+		{Name: "User", TypeStr: "User", Fields: []ast.FieldInfo{{Name: "Email", TypeStr: "string"}}},
+		{Name: "Items", TypeStr: "[]Item", IsSlice: true, ElemType: "Item", Fields: []ast.FieldInfo{{Name: "Name", TypeStr: "string"}}},
+	}
+
+	for content, want := range map[string][]string{
+		`{{$root := $}}{{range .Items}}{{$root.`:      {"Items", "User"},
+		`{{$root := $}}{{range .Items}}{{$root.User.`: {"Email"},
+	} {
+		var names []string
+		for _, item := range validator.CompletionsAt(content, vars, len(content)) {
+			names = append(names, item.Name)
+		}
+		if !slices.Equal(names, want) {
+			t.Errorf("%s: expected %v, got %v", content, want, names)
+		}
+	}
+}
@@ -0,0 +1,172 @@
+package validator_test
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+var ruleVars = []ast.TemplateVar{
+	{Name: "CSRFToken", TypeStr: "string"},
+	{Name: "User", TypeStr: "User", Fields: []ast.FieldInfo{
+		{Name: "Name", TypeStr: "string"},
+		{Name: "Password", TypeStr: "string"},
+	}},
+	{Name: "Users", TypeStr: "[]User", IsSlice: true, ElemType: "User", Fields: []ast.FieldInfo{
+		{Name: "Name", TypeStr: "string"},
+		{Name: "Password", TypeStr: "string"},
+	}},
+}
+
+func validateWithRules(t *testing.T, files map[string]string, rendered []string, rules ...validator.Rule) []validator.ValidationResult {
+	t.Helper()
+	dir := t.TempDir()
+	writeTree(t, dir, files)
+	var calls []ast.RenderCall
+	for _, name := range rendered {
+		calls = append(calls, ast.RenderCall{Template: name, Vars: ruleVars})
+	}
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "", validator.ValidateOptions{Rules: rules})
+	return results
+}
+
+func TestRequireVariableRule(t *testing.T) {
+	results := validateWithRules(t, map[string]string{
+		"form.html":    `<form>{{if .User}}<input value="{{$.CSRFToken}}">{{end}}</form>`,
+		"nested.html":  `{{with .User}}{{.CSRFToken}}{{end}}`,
+		"missing.html": `<p>{{.User.Name}}</p>`,
+		"partial.html": `<p>no token</p>`,
+	}, []string{"form.html", "nested.html", "missing.html"}, validator.RequireVariable{Name: "CSRFToken"})
+
+	var templates []string
+	for _, r := range results {
+		if r.Message == "Template must reference .CSRFToken" {
+			templates = append(templates, r.Template)
+		}
+	}
+	slices.Sort(templates)
+	// .CSRFToken inside {{with .User}} is User.CSRFToken, not the root one.
+	if want := []string{"missing.html", "nested.html"}; !slices.Equal(templates, want) {
+		t.Errorf("expected %v to be reported, got %v in %#v", want, templates, results)
+	}
+}
+
+func TestForbidFieldPathRule(t *testing.T) {
+	content := `{{.User.Password}}
+{{with .User}}{{.Name}} {{.Password}}{{end}}
+{{if .User}}{{$.User.Password}}{{end}}
+{{range .Users}}{{.Password}}{{end}}
+{{define "row"}}{{.User.Password}}{{end}}
+{{.User.Name}}`
+	results := validateWithRules(t, map[string]string{"page.html": content}, []string{"page.html"},
+		validator.ForbidFieldPath{Path: "User.Password", Severity: "warning"})
+
+	type hit struct {
+		line     int
+		variable string
+	}
+	var got []hit
+	for _, r := range results {
+		if strings.HasPrefix(r.Message, "Access to .User.Password") {
+			if r.Severity != "warning" {
+				t.Errorf("expected the configured severity, got %#v", r)
+			}
+			got = append(got, hit{r.Line, r.Variable})
+		}
+	}
+	slices.SortFunc(got, func(a, b hit) int { return a.line - b.line })
+	want := []hit{{1, ".User.Password"}, {2, ".Password"}, {3, "$.User.Password"}, {5, ".User.Password"}}
+	if !slices.Equal(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// ruleFunc adapts a function to validator.Rule.
+type ruleFunc func(validator.RuleContext) []validator.ValidationResult
+
+func (f ruleFunc) Check(ctx validator.RuleContext) []validator.ValidationResult { return f(ctx) }
+
+func TestCustomRuleSeesActionsAndScopes(t *testing.T) {
+	// Flags {{.Name}} used directly inside a range, defaulting the template
+	// and severity of the result.
+	noRangeName := ruleFunc(func(ctx validator.RuleContext) []validator.ValidationResult {
+		var results []validator.ValidationResult
+		for _, a := range ctx.Actions {
+			if a.Text == ".Name" && len(a.ScopeStack) > 1 {
+				results = append(results, validator.ValidationResult{Line: a.Line, Column: a.Column, Message: "name in range"})
+			}
+		}
+		return results
+	})
+
+	results := validateWithRules(t, map[string]string{
+		"list.html": "{{.User.Name}}\n{{range .Users}}{{.Name}}{{end}}",
+	}, []string{"list.html"}, noRangeName)
+
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %#v", results)
+	}
+	r := results[0]
+	if r.Template != "list.html" || r.Severity != "error" || r.Line != 2 || r.Column != 19 {
+		t.Errorf("unexpected result %#v", r)
+	}
+}
+
+func TestRuleActionsFromValidation(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"page.html": `{{.Title}}{{template "row.html" .}}`,
+		"row.html":  `{{.Title}}`,
+	})
+	// page.html is rendered with nil data, which only validation knows of.
+	calls := []ast.RenderCall{{Template: "page.html", NilData: true}}
+
+	nilData := make(map[string]bool)
+	var mu sync.Mutex
+	record := ruleFunc(func(ctx validator.RuleContext) []validator.ValidationResult {
+		if len(ctx.Actions) == 0 {
+			t.Errorf("expected actions for %s", ctx.Template)
+			return nil
+		}
+		mu.Lock()
+		nilData[ctx.Template] = ctx.Actions[0].ScopeStack[0].NilData
+		mu.Unlock()
+		return nil
+	})
+	validator.ValidateTemplatesWithOptions(calls, nil, dir, "", validator.ValidateOptions{Rules: []validator.Rule{record}})
+
+	// The partial, only validated through page.html, is replayed on its own.
+	if want := map[string]bool{"page.html": true, "row.html": false}; !maps.Equal(nilData, want) {
+		t.Errorf("expected rule contexts %v, got %v", want, nilData)
+	}
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := validator.ParseRules([]string{"require-variable:CSRFToken", "forbid-field-path:User.Password:warning"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []validator.Rule{
+		validator.RequireVariable{Name: "CSRFToken"},
+		validator.ForbidFieldPath{Path: "User.Password", Severity: "warning"},
+	}
+	if !slices.Equal(rules, want) {
+		t.Errorf("ParseRules() = %#v, want %#v", rules, want)
+	}
+
+	for spec, msg := range map[string]string{
+		"require-variable":           "missing argument",
+		"forbid-field-path:X:fatal":  `invalid severity "fatal"`,
+		"require-field:CSRFToken":    `unknown rule "require-field"`,
+		"forbid-field-path::warning": "missing argument",
+	} {
+		if _, err := validator.ParseRules([]string{spec}); err == nil || !strings.Contains(err.Error(), msg) {
+			t.Errorf("ParseRules(%q): expected error containing %q, got %v", spec, msg, err)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package validator_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// sameFileDefineVars passes a slice of users whose element has a Name field.
+var sameFileDefineVars = []ast.TemplateVar{{
+	Name:     "Users",
+	TypeStr:  "[]User",
+	IsSlice:  true,
+	ElemType: "User",
+	Fields:   []ast.FieldInfo{{Name: "Name", TypeStr: "string"}},
+}}
+
+const sameFileDefinePage = `{{define "row"}}<tr><td>{{.Name}}</td><td>{{.Email}}</td></tr>{{end}}
+<table>{{range .Users}}{{template "row" .}}{{end}}</table>
+`
+
+// TestEntryTemplateDefinesAndUsesBlock verifies that a block defined in the
+// entry template itself resolves when the entry is validated without a
+// registry, and that its body is checked against the element type.
+func TestEntryTemplateDefinesAndUsesBlock(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "users.html")
+	if err := os.WriteFile(path, []byte(sameFileDefinePage), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	results := validator.ValidateTemplateFile(path, sameFileDefineVars, "users.html", dir, "", nil)
+
+	if len(results) != 1 {
+		t.Fatalf("expected only the .Email error, got %+v", results)
+	}
+	r := results[0]
+	if strings.Contains(r.Message, "not found") {
+		t.Errorf("expected the row block to resolve, got %q", r.Message)
+	}
+	if !strings.Contains(r.Message, "Email") || r.Line != 1 {
+		t.Errorf("expected .Email reported on line 1, got %+v", r)
+	}
+}
+
+// TestIgnoredEntryTemplateDefinesAndUsesBlock covers an entry template left
+// out of the named-block scan, here by an ignore rule, but still rendered.
+func TestIgnoredEntryTemplateDefinesAndUsesBlock(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "users.html"), []byte(sameFileDefinePage), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("users.html\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ignore, err := validator.NewGitignoreMatcher(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	calls := []ast.RenderCall{{Template: "users.html", Vars: sameFileDefineVars}}
+	results, namedBlocks, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "", validator.ValidateOptions{Ignore: ignore})
+
+	if _, ok := namedBlocks["row"]; ok {
+		t.Fatal("expected the ignored file to contribute no named blocks to the scan")
+	}
+	if len(results) != 1 || !strings.Contains(results[0].Message, "Email") {
+		t.Errorf("expected only the .Email error, got %+v", results)
+	}
+}
+
+// TestScannedEntryTemplateDefinesAndUsesBlock covers the usual case of an
+// entry template whose blocks are also in the scanned registry: merging its
+// own blocks again must not report the block body twice.
+func TestScannedEntryTemplateDefinesAndUsesBlock(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "users.html"), []byte(sameFileDefinePage), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	calls := []ast.RenderCall{{Template: "users.html", Vars: sameFileDefineVars}}
+	results, namedBlocks, dupes := validator.ValidateTemplates(calls, nil, dir, "")
+
+	if len(namedBlocks["row"]) != 1 || len(dupes) != 0 {
+		t.Fatalf("expected one row block, got %v and %v", namedBlocks["row"], dupes)
+	}
+	if len(results) != 1 || !strings.Contains(results[0].Message, "Email") {
+		t.Errorf("expected only the .Email error, got %+v", results)
+	}
+}
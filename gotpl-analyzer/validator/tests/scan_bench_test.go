@@ -0,0 +1,46 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// largeTemplate builds a template with n blocks of actions, written without
+// newlines as minified or generated markup often is, plus n named blocks.
+func largeTemplate(n int) string {
+	var sb strings.Builder
+	for range n {
+		sb.WriteString(`<li>{{if .User}}{{.User.Name}}{{else}}{{.Title}}{{end}}</li>`)
+	}
+	sb.WriteString("\n")
+	for i := range n {
+		sb.WriteString(`{{define "row` + strings.Repeat("x", i%7) + `"}}<td>{{.}}</td>{{end}}` + "\n")
+	}
+	sb.WriteString(`{{template "rowx" .Title}}`)
+	return sb.String()
+}
+
+func BenchmarkValidateTemplateContentLarge(b *testing.B) {
+	content := largeTemplate(2000)
+	vars := map[string]ast.TemplateVar{
+		"Title": {Name: "Title", TypeStr: "string"},
+		"User": {Name: "User", TypeStr: "User", Fields: []ast.FieldInfo{
+			{Name: "Name", TypeStr: "string"},
+		}},
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		validator.ValidateTemplateContent(content, vars, "large.html", ".", ".", 1, nil)
+	}
+}
+
+func BenchmarkExtractTemplateRefsLarge(b *testing.B) {
+	content := strings.Repeat(`<p>{{template "row" .}}</p>`, 5000)
+	b.ReportAllocs()
+	for b.Loop() {
+		validator.ExtractTemplateRefs(content)
+	}
+}
@@ -0,0 +1,90 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+var securityLintVars = map[string]ast.TemplateVar{
+	"User": {
+		Name:    "User",
+		TypeStr: "User",
+		Fields: []ast.FieldInfo{
+			{Name: "Bio", TypeStr: "string"},
+			{Name: "BioHTML", TypeStr: "template.HTML"},
+		},
+	},
+}
+
+// validateSecurityLints validates content with the security lints enabled.
+func validateSecurityLints(content string, funcMaps validator.FuncMapRegistry) []validator.ValidationResult {
+	var vars []ast.TemplateVar
+	for _, v := range securityLintVars {
+		vars = append(vars, v)
+	}
+	return validator.ValidateContentWithOptions(content, vars, false, "test.html", ".", ".", 1, nil, funcMaps, validator.ValidateOptions{SecurityLints: true})
+}
+
+var securityLintFuncs = validator.FuncMapRegistry{
+	"safeHTML": {Name: "safeHTML", Unescaped: true},
+	"upper":    {Name: "upper"},
+}
+
+func TestSecurityLintFlagsUnescapedString(t *testing.T) {
+	content := `{{.User.Bio | safeHTML}}{{safeHTML .User.Bio}}`
+
+	errs := validateSecurityLints(content, securityLintFuncs)
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 warnings, got %d: %#v", len(errs), errs)
+	}
+	for _, e := range errs {
+		if e.Severity != "warning" {
+			t.Errorf("Expected warning severity, got %q", e.Severity)
+		}
+		if e.Message != `Field ".User.Bio" (string) is rendered unescaped — potential XSS` {
+			t.Errorf("Unexpected message %q", e.Message)
+		}
+	}
+}
+
+func TestSecurityLintAllowsSafeTypes(t *testing.T) {
+	content := `{{.User.BioHTML | safeHTML}}{{.User.Bio | upper}}{{.User.Bio}}`
+
+	errs := validateSecurityLints(content, securityLintFuncs)
+	if len(errs) != 0 {
+		t.Errorf("Expected 0 errors, got %d: %#v", len(errs), errs)
+	}
+}
+
+func TestSecurityLintFlagsRedundantHTMLEscape(t *testing.T) {
+	content := `{{.User.BioHTML | html}}{{html .User.Bio}}`
+
+	errs := validateSecurityLints(content, securityLintFuncs)
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 warning, got %d: %#v", len(errs), errs)
+	}
+	if !strings.Contains(errs[0].Message, "escaping it with html is redundant") || errs[0].Variable != ".User.BioHTML" {
+		t.Errorf("Unexpected diagnostic %#v", errs[0])
+	}
+}
+
+func TestSecurityLintRedundantHTMLEscapeWithoutFuncMap(t *testing.T) {
+	content := `{{.User.BioHTML | html}}`
+
+	errs := validateSecurityLints(content, nil)
+	if len(errs) != 1 || errs[0].Rule != "escaping" || errs[0].Variable != ".User.BioHTML" {
+		t.Errorf("Expected the redundant html warning without a custom FuncMap, got %#v", errs)
+	}
+}
+
+func TestSecurityLintDisabledByDefault(t *testing.T) {
+	content := `{{.User.Bio | safeHTML}}{{.User.BioHTML | html}}`
+
+	errs := validator.ValidateTemplateContent(content, securityLintVars, "test.html", ".", ".", 1, nil, securityLintFuncs)
+	if len(errs) != 0 {
+		t.Errorf("Expected 0 errors with security lints off, got %d: %#v", len(errs), errs)
+	}
+}
@@ -0,0 +1,111 @@
+package validator_test
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestValidateTemplatesStream(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"templates/users/list.html": "{{.Missing}}{{define \"x\"}}{{end}}",
+		"templates/users/show.html": "{{.User.Nope}}",
+		"templates/home.html":       "{{.Gone}}{{define \"x\"}}{{end}}",
+	}
+	writeTree(t, dir, files)
+	user := ast.TemplateVar{Name: "User", TypeStr: "User", Fields: []ast.FieldInfo{{Name: "Name", TypeStr: "string"}}}
+	var calls []ast.RenderCall
+	for _, name := range []string{"users/list.html", "users/show.html", "home.html"} {
+		calls = append(calls, ast.RenderCall{Template: name, Vars: []ast.TemplateVar{user}})
+	}
+
+	stream := func(opts validator.ValidateOptions) ([]validator.ValidationResult, []validator.NamedBlockDuplicateError) {
+		var mu sync.Mutex
+		var got []validator.ValidationResult
+		_, dupes, _ := validator.ValidateTemplatesStream(calls, nil, dir, "templates", opts, func(r validator.ValidationResult) {
+			mu.Lock()
+			defer mu.Unlock()
+			got = append(got, r)
+		})
+		return got, dupes
+	}
+	key := func(r validator.ValidationResult) string {
+		return fmt.Sprintf("%s:%d:%d:%s", r.Template, r.Line, r.Column, r.Message)
+	}
+	keys := func(results []validator.ValidationResult) []string {
+		var out []string
+		for _, r := range results {
+			out = append(out, key(r))
+		}
+		slices.Sort(out)
+		return out
+	}
+
+	want, _, wantDupes, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "templates", validator.ValidateOptions{})
+	got, dupes := stream(validator.ValidateOptions{})
+	if !slices.Equal(keys(got), keys(want)) {
+		t.Errorf("streamed results differ:\ngot  %v\nwant %v", keys(got), keys(want))
+	}
+	if len(dupes) != len(wantDupes) || len(dupes) != 1 {
+		t.Errorf("expected 1 duplicate named block error, got %d", len(dupes))
+	}
+
+	got, _ = stream(validator.ValidateOptions{Only: "users/*"})
+	if len(got) != 2 {
+		t.Fatalf("expected 2 streamed results with -only, got %d: %v", len(got), keys(got))
+	}
+	for _, r := range got {
+		if !strings.HasPrefix(r.Template, "users/") {
+			t.Errorf("result for %q streamed despite -only", r.Template)
+		}
+	}
+
+	// Workers stop once the cap is reached, so whether the rest were found,
+	// and a suppression note follows, depends on scheduling.
+	got, _ = stream(validator.ValidateOptions{MaxErrors: 1})
+	if len(got) == 0 || len(got) > 2 || strings.Contains(got[0].Message, "suppressed") {
+		t.Fatalf("expected 1 streamed result and at most a suppression note, got %v", keys(got))
+	}
+	if len(got) == 2 && !strings.Contains(got[1].Message, "additional errors suppressed (limit 1)") {
+		t.Errorf("expected the suppression note last, got %v", keys(got))
+	}
+}
+
+func TestValidateTemplatesStreamEscalatesBeforeCap(t *testing.T) {
+	dir := t.TempDir()
+	files := make(map[string]string)
+	var calls []ast.RenderCall
+	for i := range 10 {
+		name := fmt.Sprintf("empty%d.html", i)
+		files["templates/"+name] = "<p>static</p>"
+		calls = append(calls, ast.RenderCall{Template: name, Vars: []ast.TemplateVar{{Name: "Title", TypeStr: "string"}}})
+	}
+	writeTree(t, dir, files)
+
+	var mu sync.Mutex
+	var got []validator.ValidationResult
+	opts := validator.ValidateOptions{MaxErrors: 3, WarnEmptyTemplates: true, WarningsAsErrors: true}
+	validator.ValidateTemplatesStream(calls, nil, dir, "templates", opts, func(r validator.ValidationResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		got = append(got, r)
+	})
+
+	if len(got) != 4 {
+		t.Fatalf("expected 3 streamed results and a suppression note, got %#v", got)
+	}
+	for _, r := range got {
+		if r.Severity != "error" {
+			t.Errorf("expected every streamed result to be an error, got %+v", r)
+		}
+	}
+	if !strings.Contains(got[3].Message, "additional errors suppressed (limit 3)") {
+		t.Errorf("expected the suppression note last, got %+v", got[3])
+	}
+}
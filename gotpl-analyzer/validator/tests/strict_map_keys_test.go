@@ -0,0 +1,77 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+var strictMapVars = map[string]ast.TemplateVar{
+	"Config": {
+		Name:     "Config",
+		TypeStr:  "map[string]string",
+		IsMap:    true,
+		KeyType:  "string",
+		ElemType: "string",
+		MapKeys:  []string{"database", "host"},
+	},
+	"Labels": {
+		Name:     "Labels",
+		TypeStr:  "map[string]string",
+		IsMap:    true,
+		KeyType:  "string",
+		ElemType: "string",
+	},
+	"Settings": {
+		Name:     "Settings",
+		TypeStr:  "map[string]Setting",
+		IsMap:    true,
+		KeyType:  "string",
+		ElemType: "Setting",
+		Fields: []ast.FieldInfo{
+			{Name: "Value", TypeStr: "string"},
+		},
+	},
+}
+
+func TestStrictMapKeysWarnsOnUnknownKey(t *testing.T) {
+	content := `{{.Config.database}} {{.Config.databse}}`
+
+	errs := validator.ValidateTemplateContent(content, strictMapVars, "test.html", ".", ".", 1, nil)
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d: %#v", len(errs), errs)
+	}
+	if errs[0].Variable != ".Config.databse" {
+		t.Errorf("Expected warning on .Config.databse, got %q", errs[0].Variable)
+	}
+	if errs[0].Severity != "warning" {
+		t.Errorf("Expected severity warning, got %q", errs[0].Severity)
+	}
+}
+
+func TestDynamicMapAcceptsAnyKey(t *testing.T) {
+	content := `{{.Labels.anything}}`
+
+	errs := validator.ValidateTemplateContent(content, strictMapVars, "test.html", ".", ".", 1, nil)
+	if len(errs) != 0 {
+		t.Errorf("Expected 0 errors, got %d: %#v", len(errs), errs)
+	}
+}
+
+func TestMapOfStructValidatesValueFields(t *testing.T) {
+	content := `{{.Settings.theme.Value}}{{.Settings.theme.Missing}}{{with .Settings}}{{.theme.Value}}{{.theme.Missing}}{{end}}`
+
+	errs := validator.ValidateTemplateContent(content, strictMapVars, "test.html", ".", ".", 1, nil)
+	for _, e := range errs {
+		t.Logf("Error: %s (variable: %s)", e.Message, e.Variable)
+	}
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 errors, got %d", len(errs))
+	}
+	for _, e := range errs {
+		if e.Variable != ".Settings.theme.Missing" && e.Variable != ".theme.Missing" {
+			t.Errorf("Unexpected error on %q", e.Variable)
+		}
+	}
+}
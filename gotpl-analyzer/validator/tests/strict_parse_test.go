@@ -0,0 +1,72 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestStrictParse(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"empty_if.html": "<p>\n{{if}}yes{{end}}\n</p>",
+		"bad_char.html": "{{.Title}\n}}",
+		"unknown.html":  "{{shout .Title}}\n{{range}}{{end}}",
+		"unclosed.html": "{{if .Title}}",
+		"valid.html":    `{{define "x"}}{{upper .}}{{end}}{{template "x" .Title}}`,
+	})
+	calls := []ast.RenderCall{{Template: "valid.html", Vars: []ast.TemplateVar{{Name: "Title", TypeStr: "string"}}}}
+	funcMaps := []ast.FuncMapInfo{{Name: "upper"}}
+
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(calls, funcMaps, dir, "", validator.ValidateOptions{StrictParse: true})
+
+	type key struct {
+		template string
+		line     int
+		message  string
+	}
+	strict := make(map[key]bool)
+	syntax := make(map[string]int)
+	for _, r := range results {
+		if r.Severity != validator.SeveritySyntax {
+			continue
+		}
+		syntax[r.Template]++
+		strict[key{r.Template, r.Line, r.Message}] = true
+	}
+
+	for _, want := range []key{
+		{"empty_if.html", 2, "Template does not parse: missing value for if"},
+		{"bad_char.html", 1, "Template does not parse: bad character U+007D '}'"},
+		// The unknown function is left to the FuncMap check.
+		{"unknown.html", 2, "Template does not parse: missing value for range"},
+	} {
+		if !strict[want] {
+			t.Errorf("expected %+v, got %+v", want, results)
+		}
+	}
+	// The scanner already reports the missing {{end}}; the parse adds nothing.
+	if syntax["unclosed.html"] != 1 {
+		t.Errorf("expected a single syntax result for unclosed.html, got %d", syntax["unclosed.html"])
+	}
+	if syntax["valid.html"] != 0 {
+		t.Errorf("expected valid.html to parse, got %+v", results)
+	}
+
+	results, _, _, _ = validator.ValidateTemplatesWithOptions(calls, funcMaps, dir, "", validator.ValidateOptions{})
+	for _, r := range results {
+		if r.Template == "empty_if.html" && r.Severity == validator.SeveritySyntax {
+			t.Errorf("expected no strict parse results without StrictParse, got %+v", r)
+		}
+	}
+}
+
+func TestStrictParseTemplateIgnoreComment(t *testing.T) {
+	if results := validator.StrictParseTemplate("<p>\n{{if}}yes{{end}}", "page.html", nil); len(results) != 1 {
+		t.Fatalf("expected a strict parse error, got %#v", results)
+	}
+	if results := validator.StrictParseTemplate("{{/* rex:ignore */}}\n{{if}}yes{{end}}", "page.html", nil); len(results) != 0 {
+		t.Errorf("expected the ignored error to be left out, got %#v", results)
+	}
+}
@@ -0,0 +1,71 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+var structOutputVars = []ast.TemplateVar{
+	{Name: "User", TypeStr: "User", Fields: []ast.FieldInfo{
+		{Name: "Name", TypeStr: "string"},
+		{Name: "Joined", TypeStr: "time.Time", Fields: []ast.FieldInfo{
+			{Name: "String", TypeStr: "method", Returns: []ast.ParamInfo{{TypeStr: "string"}}},
+		}},
+	}},
+	{Name: "Tags", TypeStr: "[]string", IsSlice: true, ElemType: "string"},
+	{Name: "Title", TypeStr: "string"},
+}
+
+const structOutputContent = `{{.User}}{{if .User}}{{.User.Name}}{{end}}
+{{with .User}}{{.}}{{.Joined}}{{end}}
+{{$u := .User}}{{$u}}{{.Tags}}{{.Title}}{{.}}`
+
+func TestWarnStructOutput(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"page.html": structOutputContent})
+	calls := []ast.RenderCall{{Template: "page.html", Vars: structOutputVars}}
+
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "", validator.ValidateOptions{})
+	if len(results) != 0 {
+		t.Fatalf("expected no results without WarnStructOutput, got %#v", results)
+	}
+
+	results, _, _, _ = validator.ValidateTemplatesWithOptions(calls, nil, dir, "", validator.ValidateOptions{WarnStructOutput: true})
+	want := []struct {
+		variable     string
+		line, column int
+	}{
+		{".User", 1, 3},
+		{".", 2, 17},
+		{"$u", 3, 18},
+	}
+	if len(results) != len(want) {
+		t.Fatalf("expected %d warnings, got %#v", len(want), results)
+	}
+	for i, w := range want {
+		r := results[i]
+		if r.Variable != w.variable || r.Line != w.line || r.Column != w.column || r.Severity != "warning" {
+			t.Errorf("got %s at %d:%d (%s), want %s at %d:%d", r.Variable, r.Line, r.Column, r.Severity, w.variable, w.line, w.column)
+		}
+	}
+	if msg := `Rendering ".User" directly prints a struct; did you mean a specific field?`; results[0].Message != msg {
+		t.Errorf("got message %q, want %q", results[0].Message, msg)
+	}
+}
+
+func TestStructOutputWarnings(t *testing.T) {
+	results := validator.StructOutputWarnings(structOutputContent, structOutputVars, "page.html", 1, nil, nil)
+	if len(results) != 3 || results[0].Template != "page.html" {
+		t.Errorf("expected 3 warnings for page.html, got %#v", results)
+	}
+}
+
+func TestStructOutputWarningsIgnoreComment(t *testing.T) {
+	content := "{{/* rex:ignore struct-output */}}\n{{.User}}{{.User}}"
+	results := validator.StructOutputWarnings(content, structOutputVars, "page.html", 5, nil, nil)
+	if len(results) != 1 || results[0].Line != 6 || results[0].Column != 12 {
+		t.Errorf("expected only the second warning at 6:12, got %#v", results)
+	}
+}
@@ -0,0 +1,73 @@
+package validator_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestUndefinedRootVariableSuggestions(t *testing.T) {
+	vars := map[string]ast.TemplateVar{
+		"Posts": {Name: "Posts", TypeStr: "[]string"},
+		"User":  {Name: "User", TypeStr: "string"},
+		"Users": {Name: "Users", TypeStr: "[]string"},
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"single close key", `{{.Post}}`, `Template variable ".Post" is not defined in the current scope — did you mean "Posts"?`},
+		{"nested access", `{{.Postz.Title}}`, `Template variable ".Postz.Title" is not defined in the current scope — did you mean "Posts"?`},
+		{"ambiguous", `{{.Usr}}`, `Template variable ".Usr" is not defined in the current scope`},
+		{"too far", `{{.Comments}}`, `Template variable ".Comments" is not defined in the current scope`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validator.ValidateTemplateContent(tt.content, vars, "page.html", ".", ".", 1, nil)
+			if len(errs) != 1 {
+				t.Fatalf("expected 1 error, got %#v", errs)
+			}
+			if errs[0].Message != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, errs[0].Message)
+			}
+		})
+	}
+}
+
+func TestUndefinedRootVariableSuggestionSkipsNilData(t *testing.T) {
+	calls := []ast.RenderCall{{Template: "page.html", NilData: true}}
+	nilData := validator.NilDataTemplates(calls)["page.html"]
+
+	errs := validator.ValidateContentWithOptions(`{{.Title}}`, nil, nilData, "page.html", ".", ".", 1, nil, nil, validator.ValidateOptions{})
+	if len(errs) != 1 || errs[0].Rule != "nil-data" || strings.Contains(errs[0].Message, "did you mean") {
+		t.Errorf("expected a plain nil-data error, got %#v", errs)
+	}
+}
+
+// TestUndefinedRootVariableSuggestionLargeContext verifies that, with more
+// variables than are compared, the suggestion does not depend on map order.
+func TestUndefinedRootVariableSuggestionLargeContext(t *testing.T) {
+	vars := map[string]ast.TemplateVar{"Title": {Name: "Title", TypeStr: "string"}}
+	for i := range 3000 {
+		name := fmt.Sprintf("var%04d", i)
+		vars[name] = ast.TemplateVar{Name: name, TypeStr: "string"}
+	}
+
+	for range 20 {
+		errs := validator.ValidateTemplateContent(`{{.Titel}}{{.Ttle.Size}}`, vars, "page.html", ".", ".", 1, nil)
+		if len(errs) != 2 {
+			t.Fatalf("expected two errors, got %#v", errs)
+		}
+		for _, err := range errs {
+			if !strings.HasSuffix(err.Message, `did you mean "Title"?`) {
+				t.Fatalf("expected a suggestion of Title, got %#v", err)
+			}
+		}
+	}
+}
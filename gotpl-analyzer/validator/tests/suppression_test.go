@@ -0,0 +1,115 @@
+package validator_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestIgnoreComment(t *testing.T) {
+	vars := map[string]ast.TemplateVar{"Title": {Name: "Title", TypeStr: "string"}}
+	cases := []struct {
+		name, content string
+		want          []string
+	}{
+		{
+			"specific rule",
+			"{{/* rex:ignore undefined-variable */}}\n{{nofunc .Injected}}{{.Missing}}",
+			[]string{"undefined-function nofunc", "undefined-variable .Missing"},
+		},
+		{
+			"all rules",
+			"{{- /* rex:ignore */ -}}\n{{nofunc .Injected}}{{.Missing}}",
+			[]string{"undefined-variable .Missing"},
+		},
+		{
+			"rule list",
+			"{{/* rex:ignore undefined-function, undefined-variable */}}\n{{nofunc .Injected}}",
+			nil,
+		},
+		{
+			"other rule",
+			"{{/* rex:ignore syntax */}}\n{{.Injected}}",
+			[]string{"undefined-variable .Injected"},
+		},
+		{
+			"plain comment",
+			"{{/* rex:ignored */}}\n{{.Injected}}",
+			[]string{"undefined-variable .Injected"},
+		},
+		{
+			"only the next action",
+			"{{/* rex:ignore */}}{{.Title}}\n{{.Injected}}",
+			[]string{"undefined-variable .Injected"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			errs := validator.ValidateTemplateContent(c.content, vars, "page.html", ".", ".", 1, nil, validator.FuncMapRegistry{})
+			var got []string
+			for _, e := range errs {
+				got = append(got, validator.RuleID(e)+" "+e.Variable)
+			}
+			if len(got) != len(c.want) {
+				t.Fatalf("got %q, want %q", got, c.want)
+			}
+			for i := range got {
+				if got[i] != c.want[i] {
+					t.Errorf("got %q, want %q", got, c.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestRuleID(t *testing.T) {
+	vars := map[string]ast.TemplateVar{
+		"User": {Name: "User", TypeStr: "User", Fields: []ast.FieldInfo{{Name: "Name", TypeStr: "string"}}},
+	}
+	content := "{{.Missing}}\n{{(.User).Nme}}\n{{nofunc .User}}\n{{template \"nav.html\" .}}\n{{else}}"
+
+	dir := t.TempDir()
+	registry := map[string][]validator.NamedBlockEntry{}
+	errs := validator.ValidateTemplateContent(content, vars, "page.html", dir, ".", 1, registry, validator.FuncMapRegistry{})
+	want := map[int]string{1: "undefined-variable", 2: "unknown-field", 3: "undefined-function", 4: "missing-template", 5: "syntax"}
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d results, got %#v", len(want), errs)
+	}
+	for _, e := range errs {
+		if e.Rule != want[e.Line] || validator.RuleID(e) != e.Rule {
+			t.Errorf("line %d: got rule %q, want %q (%s)", e.Line, e.Rule, want[e.Line], e.Message)
+		}
+	}
+}
+
+func TestIgnoreCommentAfterAllPasses(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"templates/page.html": "{{/* rex:ignore struct-output */}}\n{{.User}}{{.Missing}}\n{{.User}}",
+		"handlers.go": "package main\n\n" +
+			"// rex:ignore duplicate-var\n" +
+			"var title = \"a\"\n\n" +
+			"func printf() {} // rex:ignore builtin-shadow\n",
+	})
+	user := ast.TemplateVar{Name: "User", TypeStr: "main.User", Fields: []ast.FieldInfo{{Name: "Name", TypeStr: "string"}}}
+	calls := []ast.RenderCall{{
+		Template:      "page.html",
+		Vars:          []ast.TemplateVar{user},
+		DuplicateVars: []ast.DuplicateVar{{Name: "Title", DefFile: "handlers.go", DefLine: 4}},
+	}}
+	funcMaps := []ast.FuncMapInfo{{Name: "printf", DefFile: "handlers.go", DefLine: 6}}
+
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(calls, funcMaps, dir, "templates", validator.ValidateOptions{WarnStructOutput: true})
+	var got []string
+	for _, r := range results {
+		got = append(got, fmt.Sprintf("%s:%d %s", r.Rule, r.Line, r.Variable))
+	}
+	want := []string{"undefined-variable:2 .Missing", "struct-output:3 .User"}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,66 @@
+package validator_test
+
+import (
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// TestSwitchAssignedTemplateName verifies that a template name assigned in
+// the branches of a switch yields one render call per branch, and that each
+// of those templates is validated against the render call's variables.
+func TestSwitchAssignedTemplateName(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"app/go.mod": "module example.com/test\ngo 1.21\n",
+		"app/main.go": `package main
+
+type Context struct{}
+func (c *Context) Render(tpl string, data map[string]interface{}) {}
+
+type User struct{ Name, Role string }
+
+func handler(c *Context, user User) {
+	var tpl string
+	switch user.Role {
+	case "admin":
+		tpl = "admin.html"
+	default:
+		tpl = "user.html"
+	}
+	c.Render(tpl, map[string]interface{}{"user": user})
+}
+`,
+		"templates/admin.html": `{{ .user.Role }} {{ .user.Permissions }}`,
+		"templates/user.html":  `{{ .user.Name }} {{ .user.Email }}`,
+	})
+
+	analysis := ast.AnalyzeDir(filepath.Join(dir, "app"), "", ast.DefaultConfig)
+	if len(analysis.Errors) > 0 {
+		t.Fatalf("analysis errors: %v", analysis.Errors)
+	}
+
+	var names []string
+	for _, rc := range analysis.RenderCalls {
+		names = append(names, rc.Template)
+	}
+	sort.Strings(names)
+	if len(names) != 2 || names[0] != "admin.html" || names[1] != "user.html" {
+		t.Fatalf("expected render calls for admin.html and user.html, got %v", names)
+	}
+
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(analysis.RenderCalls, nil, dir, "templates", validator.ValidateOptions{})
+	byTemplate := make(map[string][]string)
+	for _, r := range results {
+		byTemplate[r.Template] = append(byTemplate[r.Template], r.Variable)
+	}
+	if len(byTemplate["admin.html"]) != 1 || len(byTemplate["user.html"]) != 1 {
+		t.Fatalf("expected one error per template, got %#v", results)
+	}
+	if byTemplate["admin.html"][0] != ".user.Permissions" || byTemplate["user.html"][0] != ".user.Email" {
+		t.Errorf("unexpected errors %v", byTemplate)
+	}
+}
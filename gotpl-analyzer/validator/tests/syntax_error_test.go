@@ -0,0 +1,97 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestStructuralErrorsAreSyntaxDiagnostics(t *testing.T) {
+	cases := []struct {
+		name     string
+		content  string
+		wantLine int
+		wantMsg  string
+	}{
+		{"unexpected end", "<p>{{.Title}}</p>\n{{end}}\n", 2, "unexpected {{end}} at line 2"},
+		{"else without opener", "{{else}}\n", 1, "{{else}} at line 1 has no matching opening block"},
+		{"else after else", "{{if .A}}\n{{else}}\n{{else}}\n{{end}}", 3, "{{else}} is not valid after {{else}} (block opened at line 1)"},
+		{"unclosed blocks", "{{if .A}}\n<ul>\n{{range .Items}}\n<li></li>\n", 3, "missing {{end}} for: {{if}} (line 1), {{range}} (line 3)"},
+		{"unclosed else branch", "\n{{with .A}}\n{{else}}\n", 3, "missing {{end}} for: {{else}} (line 2)"},
+		{"unclosed action", "{{if .A}}\n{{.Title", 2, "Unclosed action tag '{{' at line 2"},
+	}
+
+	vars := map[string]ast.TemplateVar{
+		"Title": {Name: "Title", TypeStr: "string"},
+		"A":     {Name: "A", TypeStr: "bool"},
+		"Items": {Name: "Items", TypeStr: "[]string", IsSlice: true, ElemType: "string"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := validator.ValidateTemplateContent(tc.content, vars, "test.html", ".", ".", 1, nil)
+
+			var found bool
+			for _, e := range errs {
+				if e.Severity == validator.SeveritySyntax && strings.Contains(e.Message, tc.wantMsg) {
+					found = true
+					if e.Line != tc.wantLine {
+						t.Errorf("expected line %d, got %d", tc.wantLine, e.Line)
+					}
+				}
+			}
+			if !found {
+				t.Errorf("expected syntax diagnostic containing %q, got %#v", tc.wantMsg, errs)
+			}
+		})
+	}
+}
+
+// TestValidationContinuesAfterStrayCloser verifies that actions after a
+// stray {{end}} or {{else}} are still validated and that the syntax result
+// has a 1-based column.
+func TestValidationContinuesAfterStrayCloser(t *testing.T) {
+	vars := map[string]ast.TemplateVar{"Title": {Name: "Title", TypeStr: "string"}}
+	for _, content := range []string{"{{end}}\n{{ .Nope }}", "  {{else}}\n{{ .Nope }}"} {
+		errs := validator.ValidateTemplateContent(content, vars, "test.html", ".", ".", 1, nil)
+		if len(errs) != 2 || errs[0].Severity != validator.SeveritySyntax || errs[1].Variable != ".Nope" || errs[1].Line != 2 {
+			t.Errorf("%q: expected the syntax result and .Nope on line 2, got %#v", content, errs)
+			continue
+		}
+		if want := strings.Index(content, "{{") + 3; errs[0].Column != want {
+			t.Errorf("%q: expected the syntax result at column %d, got %d", content, want, errs[0].Column)
+		}
+	}
+}
+
+// TestMalformedTemplateDoesNotStopSiblings verifies that a structural error in
+// one file is reported while other templates are still validated.
+func TestMalformedTemplateDoesNotStopSiblings(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"broken.html": "{{if .A}}{{end}}{{end}}",
+		"good.html":   "{{.Missing}}",
+	}
+	writeTree(t, dir, files)
+
+	calls := []ast.RenderCall{
+		{File: "a.go", Line: 1, Template: "broken.html", Vars: []ast.TemplateVar{{Name: "A", TypeStr: "bool"}}},
+		{File: "a.go", Line: 2, Template: "good.html", Vars: []ast.TemplateVar{{Name: "A", TypeStr: "bool"}}},
+	}
+	errs, _, _ := validator.ValidateTemplates(calls, nil, dir, "")
+
+	var syntax, missing bool
+	for _, e := range errs {
+		if e.Template == "broken.html" && e.Severity == validator.SeveritySyntax {
+			syntax = true
+		}
+		if e.Template == "good.html" && e.Variable == ".Missing" {
+			missing = true
+		}
+	}
+	if !syntax || !missing {
+		t.Errorf("expected a syntax error for broken.html and .Missing in good.html, got %#v", errs)
+	}
+}
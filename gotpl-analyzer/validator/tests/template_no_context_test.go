@@ -0,0 +1,85 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+var noContextVars = map[string]ast.TemplateVar{
+	"Title": {Name: "Title", TypeStr: "string"},
+}
+
+func TestTemplateWithoutContextPassesNil(t *testing.T) {
+	content := `{{define "card"}}<h2>{{.Title}}</h2>{{end}}{{template "card"}}`
+
+	errs := validator.ValidateTemplateContent(content, noContextVars, "page.html", ".", ".", 1, nil)
+	if len(errs) != 1 {
+		t.Fatalf("expected 1 error, got %d: %#v", len(errs), errs)
+	}
+	want := `Template "card" accesses .Title but was included with no context`
+	if !strings.Contains(errs[0].Message, want) || errs[0].Severity != "error" {
+		t.Errorf("expected %q, got %#v", want, errs[0])
+	}
+}
+
+func TestTemplateWithExplicitDotUsesCallerScope(t *testing.T) {
+	content := `{{define "card"}}<h2>{{.Title}}</h2>{{end}}{{template "card" .}}`
+
+	errs := validator.ValidateTemplateContent(content, noContextVars, "page.html", ".", ".", 1, nil)
+	if len(errs) != 0 {
+		t.Errorf("expected no errors with explicit dot, got %#v", errs)
+	}
+}
+
+func TestTemplateWithoutContextIgnoresUnusedDot(t *testing.T) {
+	content := `{{define "footer"}}<footer>static</footer>{{end}}{{template "footer"}}`
+
+	errs := validator.ValidateTemplateContent(content, noContextVars, "page.html", ".", ".", 1, nil)
+	if len(errs) != 0 {
+		t.Errorf("expected no errors for a partial that does not use dot, got %#v", errs)
+	}
+}
+
+func TestFilePartialWithoutContext(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"page.html": `{{template "card.html"}}{{template "card.html" .}}`,
+		"card.html": `{{.Title}}`,
+	}
+	writeTree(t, dir, files)
+
+	errs := validator.ValidateTemplateContent(files["page.html"], noContextVars, "page.html", dir, "", 1, nil)
+	if len(errs) != 1 {
+		t.Fatalf("expected only the include without context to be reported, got %#v", errs)
+	}
+	if want := `Template "card.html" accesses .Title but was included with no context`; !strings.Contains(errs[0].Message, want) {
+		t.Errorf("expected %q, got %q", want, errs[0].Message)
+	}
+	if errs[0].Column != 3 {
+		t.Errorf("expected the error at the first include, got column %d", errs[0].Column)
+	}
+}
+
+// TestNestedTemplateWithoutContext verifies that a nil context is reported
+// against the include that passed it, not an outer one passing dot, and
+// that nil render data is still reported as such.
+func TestNestedTemplateWithoutContext(t *testing.T) {
+	content := `{{define "inner"}}{{.Title}}{{end}}` +
+		`{{define "card"}}{{template "inner"}}{{end}}{{template "card" .}}`
+
+	errs := validator.ValidateTemplateContent(content, noContextVars, "page.html", ".", ".", 1, nil)
+	want := `[in named template "card" @ page.html] [in named template "inner" @ page.html] Template "inner" accesses .Title but was included with no context`
+	if len(errs) != 1 || errs[0].Message != want {
+		t.Errorf("expected %q, got %#v", want, errs)
+	}
+
+	calls := []ast.RenderCall{{Template: "page.html", NilData: true}}
+	nilData := validator.NilDataTemplates(calls)["page.html"]
+	errs = validator.ValidateContentWithOptions(`{{.Title}}`, nil, nilData, "page.html", ".", ".", 1, nil, nil, validator.ValidateOptions{})
+	if want := `Template references ".Title" but render call passed nil data`; len(errs) != 1 || errs[0].Message != want {
+		t.Errorf("expected %q, got %#v", want, errs)
+	}
+}
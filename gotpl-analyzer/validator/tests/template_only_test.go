@@ -0,0 +1,90 @@
+package validator_test
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestTemplateOnlyValidatesAgainstContextFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"context.json": `{
+  "global": {"user": "string"},
+  "index.html": {"title": "string"}
+}`,
+		"views/index.html":   `{{.title}} {{.user}} {{.missing}}{{template "partial.html" .}}`,
+		"views/partial.html": `{{.title}}`,
+		"views/orphan.html":  `{{.anything}}`,
+	}
+	writeTree(t, dir, files)
+
+	result := ast.AnalyzeContextFile(filepath.Join(dir, "context.json"), ast.DefaultConfig)
+	if len(result.Errors) != 0 {
+		t.Fatalf("unexpected analysis errors: %v", result.Errors)
+	}
+	if len(result.RenderCalls) != 1 || result.RenderCalls[0].Template != "index.html" {
+		t.Fatalf("expected a single synthetic call for index.html, got %+v", result.RenderCalls)
+	}
+
+	errs, _, _, _ := validator.ValidateTemplatesWithOptions(result.RenderCalls, result.FuncMaps, dir, "views", validator.ValidateOptions{})
+	var undefined []string
+	for _, e := range errs {
+		if e.Severity == "error" {
+			undefined = append(undefined, e.Template+":"+e.Variable)
+		}
+	}
+	if len(undefined) != 1 || !strings.Contains(undefined[0], "missing") {
+		t.Errorf("expected only .missing to be reported, got %v", undefined)
+	}
+
+	warnings := validator.MissingContextWarnings(result.RenderCalls, dir, "views")
+	if len(warnings) != 1 {
+		t.Fatalf("expected one missing-context warning, got %+v", warnings)
+	}
+	if w := warnings[0]; w.Template != "orphan.html" || w.Severity != "warning" ||
+		w.Message != `No context defined for template "orphan.html"` {
+		t.Errorf("unexpected warning %+v", w)
+	}
+}
+
+// TestWarnMissingContext verifies that missing-context warnings cover every
+// template root and pass through the same filters, escalation and cap as
+// the other results.
+func TestWarnMissingContext(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"web/index.html":     "{{.Title}}",
+		"web/about.html":     "{{.Title}}",
+		"email/welcome.html": "{{.Title}}",
+	})
+	calls := []ast.RenderCall{{Template: "index.html", Vars: []ast.TemplateVar{{Name: "Title", TypeStr: "string"}}}}
+	opts := validator.ValidateOptions{ExtraTemplateRoots: []string{"email"}, WarnMissingContext: true, WarningsAsErrors: true}
+
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "web", opts)
+	var got []string
+	for _, r := range results {
+		if r.Rule == "missing-context" && r.Severity == "error" {
+			got = append(got, r.Template)
+		}
+	}
+	if want := []string{"about.html", "email/welcome.html"}; !slices.Equal(got, want) {
+		t.Errorf("expected escalated warnings for %v, got %#v", want, results)
+	}
+
+	opts.Only = "about.html"
+	if results, _, _, _ = validator.ValidateTemplatesWithOptions(calls, nil, dir, "web", opts); len(results) != 1 || results[0].Template != "about.html" {
+		t.Errorf("expected only about.html with Only, got %#v", results)
+	}
+
+	opts.Only = ""
+	opts.MaxErrors = 1
+	results, _, _, _ = validator.ValidateTemplatesWithOptions(calls, nil, dir, "web", opts)
+	if len(results) != 2 || results[0].Rule != "missing-context" || !strings.Contains(results[1].Message, "1 additional errors suppressed") {
+		t.Errorf("expected one warning followed by the suppression note, got %#v", results)
+	}
+}
@@ -0,0 +1,66 @@
+package validator_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestExtractTemplateRefs(t *testing.T) {
+	content := "{{/* {{template \"commented.html\"}} */}}\n" +
+		"<body>{{- template \"layout.html\" . -}}\n" +
+		"  {{template `nav \"main\"` .User}}{{block \"content\" .}}{{end}}\n" +
+		"{{template \"footer.html\"}}"
+
+	got := validator.ExtractTemplateRefs(content)
+	want := []validator.TemplateRef{
+		{Name: "layout.html", Line: 2, Column: 11, Context: "."},
+		{Name: `nav "main"`, Line: 3, Column: 5, Context: ".User"},
+		{Name: "footer.html", Line: 4, Column: 3},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractTemplateRefs() =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestExtractTemplateRefsNested(t *testing.T) {
+	content := "{{define \"page\"}}{{if .User}}{{template \"nav.html\" (dict \"user\" .User)}}{{end}}{{end}}\n" +
+		"{{range .Items}}{{template \"row.html\" .}}{{else}}{{template \"empty.html\"}}{{end}}"
+
+	got := validator.ExtractTemplateRefs(content)
+	want := []validator.TemplateRef{
+		{Name: "nav.html", Line: 1, Column: 32, Context: `(dict "user" .User)`},
+		{Name: "row.html", Line: 2, Column: 19, Context: "."},
+		{Name: "empty.html", Line: 2, Column: 52},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractTemplateRefs() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	for _, content := range []string{"{{template}}", "{{template .Name}}", `{{if .X}}{{template "a.html"}}`} {
+		if got := validator.ExtractTemplateRefs(content); got != nil {
+			t.Errorf("ExtractTemplateRefs(%q) = %+v, want none", content, got)
+		}
+	}
+}
+
+func TestTemplateDeps(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"index.html":        `{{template "layout.html" .}}{{template "partials/nav.html" .}}{{template "layout.html" .}}`,
+		"layout.html":       `<html>{{block "content" .}}{{end}}</html>`,
+		"partials/nav.html": `<nav></nav>`,
+	}
+	writeTree(t, dir, files)
+
+	got := validator.TemplateDeps(dir, "")
+	want := map[string][]string{
+		"index.html":        {"layout.html", "partials/nav.html"},
+		"layout.html":       {},
+		"partials/nav.html": {},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("TemplateDeps() = %v, want %v", got, want)
+	}
+}
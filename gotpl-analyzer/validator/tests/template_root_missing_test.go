@@ -0,0 +1,40 @@
+package validator_test
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestMissingTemplateRoot(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"templates/index.html": "{{.Title}}", "views.html": ""})
+	calls := []ast.RenderCall{
+		{Template: "index", Vars: []ast.TemplateVar{{Name: "Title", TypeStr: "string"}}},
+		{Template: "users", Vars: []ast.TemplateVar{{Name: "Users", TypeStr: "[]string"}}},
+	}
+
+	for root, msg := range map[string]string{
+		"tempaltes":  "Template root %q does not exist",
+		"views.html": "Template root %q is not a directory",
+	} {
+		results, _, _ := validator.ValidateTemplates(calls, nil, dir, root)
+		if len(results) != 1 {
+			t.Fatalf("%s: expected a single result, got %+v", root, results)
+		}
+		want := fmt.Sprintf(msg, filepath.Join(dir, root))
+		if r := results[0]; r.Message != want || r.Severity != "error" || r.Template != root {
+			t.Errorf("%s: expected %q, got %+v", root, want, r)
+		}
+	}
+
+	results, _, _ := validator.ValidateTemplates(calls, nil, dir, "templates")
+	for _, r := range results {
+		if r.Message == fmt.Sprintf("Template root %q does not exist", filepath.Join(dir, "templates")) {
+			t.Errorf("unexpected root error for an existing root: %+v", r)
+		}
+	}
+}
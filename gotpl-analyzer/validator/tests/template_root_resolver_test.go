@@ -0,0 +1,84 @@
+package validator_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestPerPackageTemplateRoot(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"serviceA/templates/index.html":    `{{.Title}}`,
+		"serviceA/handlers/deep/h.go":      "package deep",
+		"serviceB/h.go":                    "package serviceB",
+		"shared/templates/x.html":          "",
+		"views/fallback.html":              "",
+		"serviceC/internal/web/handler.go": "package web",
+	})
+	resolve := ast.PerPackageTemplateRoot("templates", dir, "views")
+
+	tests := []struct {
+		file     string
+		wantBase string
+		wantRoot string
+	}{
+		{filepath.Join(dir, "serviceA/handlers/deep/h.go"), filepath.Join(dir, "serviceA"), "templates"},
+		{"serviceA/handlers/deep/h.go", filepath.Join(dir, "serviceA"), "templates"},
+		{filepath.Join(dir, "serviceB/h.go"), dir, "views"},
+		{filepath.Join(dir, "serviceC/internal/web/handler.go"), dir, "views"},
+		{"/elsewhere/main.go", dir, "views"},
+	}
+	for _, tt := range tests {
+		base, root := resolve(tt.file)
+		if base != tt.wantBase || root != tt.wantRoot {
+			t.Errorf("%s: expected (%s, %s), got (%s, %s)", tt.file, tt.wantBase, tt.wantRoot, base, root)
+		}
+	}
+}
+
+func TestValidateTemplatesPerServiceRoot(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"serviceA/templates/index.html": `<h1>{{.Title}}</h1>{{template "nav.html" .}}`,
+		"serviceA/templates/nav.html":   `<nav>{{.Title}}</nav>`,
+		"serviceB/templates/index.html": `<p>{{.Name}}</p>{{.Missing}}`,
+	})
+	calls := []ast.RenderCall{
+		{
+			File:     filepath.Join(dir, "serviceA/handlers/home.go"),
+			Template: "index.html",
+			Vars:     []ast.TemplateVar{{Name: "Title", TypeStr: "string"}},
+		},
+		{
+			File:     filepath.Join(dir, "serviceB/handlers/home.go"),
+			Template: "index.html",
+			Vars:     []ast.TemplateVar{{Name: "Name", TypeStr: "string"}},
+		},
+	}
+
+	// With a single root, serviceB's call is validated against serviceA's
+	// index.html and serviceB's own template is never checked.
+	single, _, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "serviceA/templates", validator.ValidateOptions{})
+	for _, r := range single {
+		if r.Variable == ".Missing" {
+			t.Fatalf("did not expect serviceB's template to be validated with a single root, got %#v", single)
+		}
+	}
+
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(calls, nil, dir, "serviceA/templates", validator.ValidateOptions{
+		TemplateRootResolver: ast.PerPackageTemplateRoot("templates", dir, "serviceA/templates"),
+	})
+	if len(results) != 1 {
+		t.Fatalf("expected 1 result, got %#v", results)
+	}
+	got := results[0]
+	if got.Template != "serviceB/templates/index.html" || got.Variable != ".Missing" {
+		t.Errorf("expected .Missing in serviceB/templates/index.html, got %#v", got)
+	}
+	if got.GoFile != calls[1].File {
+		t.Errorf("expected the result to link to %s, got %s", calls[1].File, got.GoFile)
+	}
+}
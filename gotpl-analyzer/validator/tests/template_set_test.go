@@ -0,0 +1,141 @@
+package validator_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestTemplateSetValidateCall(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"views/page.html":          `{{template "card" .}}`,
+		"views/partials/card.html": `{{define "card"}}{{.Title}}{{end}}`,
+	})
+
+	set, err := validator.NewTemplateSet(dir, "views")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	call := ast.RenderCall{
+		File:     "handlers.go",
+		Line:     12,
+		Template: "page.html",
+		Vars:     []ast.TemplateVar{{Name: "Title", TypeStr: "string"}},
+	}
+	if errs := set.ValidateCall(call); len(errs) != 0 {
+		t.Fatalf("expected no errors, got %#v", errs)
+	}
+
+	// The cache is only refreshed by Reload.
+	writeTree(t, dir, map[string]string{"views/partials/card.html": `{{define "card"}}{{.Subtitle}}{{end}}`})
+	if errs := set.ValidateCall(call); len(errs) != 0 {
+		t.Fatalf("expected the cached block to be used before Reload, got %#v", errs)
+	}
+
+	if err := set.Reload([]string{"partials/card.html"}); err != nil {
+		t.Fatal(err)
+	}
+	errs := set.ValidateCall(call)
+	if len(errs) != 1 || errs[0].Variable != ".Subtitle" || errs[0].GoFile != "handlers.go" || errs[0].GoLine != 12 {
+		t.Fatalf("expected .Subtitle linked to handlers.go:12 after Reload, got %#v", errs)
+	}
+
+	// Deleting the file drops its blocks.
+	if err := os.Remove(filepath.Join(dir, "views", "partials", "card.html")); err != nil {
+		t.Fatal(err)
+	}
+	if err := set.Reload([]string{filepath.Join(dir, "views", "partials", "card.html")}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := set.NamedBlocks()["card"]; ok {
+		t.Errorf("expected card to be removed after its file was deleted")
+	}
+}
+
+func TestTemplateSetErrors(t *testing.T) {
+	if _, err := validator.NewTemplateSet(t.TempDir(), "missing"); err == nil {
+		t.Error("expected an error for a missing template root")
+	}
+
+	set, err := validator.NewTemplateSet(t.TempDir(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := set.Reload([]string{"../outside.html"}); err == nil {
+		t.Error("expected an error for a path outside the template root")
+	}
+}
+
+func TestTemplateSetOptions(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		".gitignore":          "views/drafts/\n",
+		"views/layout.html":   `<main>{{block "content" .}}{{.Title}}{{end}}</main>`,
+		"views/content.html":  `{{define "content"}}{{.Body}}{{end}}`,
+		"views/dynamic.html":  `{{template .Title .}}`,
+		"views/huge.html":     `{{define "huge"}}{{end}}{{.Missing}}` + strings.Repeat("x", 2048),
+		"views/drafts/a.html": `{{define "draft"}}{{end}}`,
+	})
+	ignore, err := validator.NewGitignoreMatcher(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := validator.ValidateOptions{
+		AllowBlockOverride:          true,
+		Ignore:                      ignore,
+		MaxTemplateBytes:            1024,
+		DynamicTemplateNameSeverity: "warning",
+	}
+	vars := []ast.TemplateVar{{Name: "Title", TypeStr: "string"}}
+
+	set, err := validator.NewTemplateSetWithOptions(dir, "views", opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocks := set.NamedBlocks()
+	if _, ok := blocks["draft"]; ok {
+		t.Errorf("expected the ignored file not to be parsed, got %v", blocks["draft"])
+	}
+	if _, ok := blocks["huge"]; ok {
+		t.Errorf("expected the oversized file not to be parsed, got %v", blocks["huge"])
+	}
+
+	// The define overrides the block's default body.
+	if errs := set.ValidateCall(ast.RenderCall{Template: "layout.html", Vars: vars}); len(errs) != 1 || errs[0].Variable != ".Body" {
+		t.Errorf("expected .Body from the block override, got %#v", errs)
+	}
+	if errs := set.ValidateCall(ast.RenderCall{Template: "huge.html", Vars: vars}); len(errs) != 0 {
+		t.Errorf("expected the oversized template to be skipped, got %#v", errs)
+	}
+	if errs := set.ValidateCall(ast.RenderCall{Template: "dynamic.html", Vars: vars}); len(errs) != 1 || errs[0].Rule != "dynamic-template-name" {
+		t.Errorf("expected a dynamic template name note, got %#v", errs)
+	}
+
+	// Reload picks up a file shrinking below the limit.
+	writeTree(t, dir, map[string]string{"views/huge.html": `{{.Missing}}`})
+	if err := set.Reload([]string{"huge.html"}); err != nil {
+		t.Fatal(err)
+	}
+	if errs := set.ValidateCall(ast.RenderCall{Template: "huge.html", Vars: vars}); len(errs) != 1 || errs[0].Variable != ".Missing" {
+		t.Errorf("expected .Missing once the template fits, got %#v", errs)
+	}
+
+	// Without options, the block's default body satisfies the call and
+	// dynamic names are not reported.
+	set, err = validator.NewTemplateSet(dir, "views")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if errs := set.ValidateCall(ast.RenderCall{Template: "layout.html", Vars: vars}); len(errs) != 0 {
+		t.Errorf("expected the block's default body to be accepted, got %#v", errs)
+	}
+	if errs := set.ValidateCall(ast.RenderCall{Template: "dynamic.html", Vars: vars}); len(errs) != 0 {
+		t.Errorf("expected no dynamic template name notes by default, got %#v", errs)
+	}
+}
@@ -0,0 +1,45 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// Unexported fields only reach the validator when the analyzer ran with
+// IncludeUnexported, so their presence in the field list is what enables them.
+func TestUnexportedFieldAcceptedWhenExtracted(t *testing.T) {
+	vars := map[string]ast.TemplateVar{
+		"account": {
+			Name:    "account",
+			TypeStr: "Account",
+			Fields: []ast.FieldInfo{
+				{Name: "Name", TypeStr: "string"},
+				{Name: "balance", TypeStr: "int", Unexported: true},
+			},
+		},
+	}
+
+	errs := validator.ValidateTemplateContent(`{{.account.balance}}`, vars, "test.html", ".", ".", 1, nil)
+	if len(errs) != 0 {
+		t.Errorf("Expected 0 errors, got %d: %#v", len(errs), errs)
+	}
+}
+
+func TestUnexportedFieldRejectedByDefault(t *testing.T) {
+	vars := map[string]ast.TemplateVar{
+		"account": {
+			Name:    "account",
+			TypeStr: "Account",
+			Fields: []ast.FieldInfo{
+				{Name: "Name", TypeStr: "string"},
+			},
+		},
+	}
+
+	errs := validator.ValidateTemplateContent(`{{.account.balance}}`, vars, "test.html", ".", ".", 1, nil)
+	if len(errs) != 1 {
+		t.Fatalf("Expected 1 error, got %d: %#v", len(errs), errs)
+	}
+}
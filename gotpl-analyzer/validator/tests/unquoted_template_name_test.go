@@ -0,0 +1,44 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestUnquotedTemplateName(t *testing.T) {
+	vars := map[string]ast.TemplateVar{"A": {Name: "A", TypeStr: "string"}}
+	cases := []struct {
+		name, content, want string
+		col                 int
+	}{
+		{"define", `{{define myblock}}{{.A}}{{end}}`, `Template/block name must be a quoted string, got "myblock"`, 10},
+		{"template", `{{.A}}{{template foo .}}`, `Template/block name must be a quoted string, got "foo"`, 18},
+		{"block", `{{block content .}}{{.A}}{{end}}`, `Template/block name must be a quoted string, got "content"`, 9},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			errs := validator.ValidateTemplateContent(c.content, vars, "page.html", ".", ".", 1, nil)
+			if len(errs) != 1 {
+				t.Fatalf("expected 1 result, got %#v", errs)
+			}
+			e := errs[0]
+			if e.Message != c.want || e.Severity != validator.SeveritySyntax || e.Line != 1 || e.Column != c.col {
+				t.Errorf("got %d:%d %q (%s), want 1:%d %q", e.Line, e.Column, e.Message, e.Severity, c.col, c.want)
+			}
+		})
+	}
+}
+
+func TestQuotedAndDynamicTemplateNamesAccepted(t *testing.T) {
+	vars := map[string]ast.TemplateVar{"Name": {Name: "Name", TypeStr: "string"}}
+	content := "{{define \"a\"}}x{{end}}{{define `b`}}y{{end}}{{template \"a\"}}{{template `b` .}}{{template .Name}}"
+
+	errs := validator.ValidateTemplateContent(content, vars, "page.html", ".", ".", 1, nil)
+	for _, e := range errs {
+		if e.Severity == validator.SeveritySyntax {
+			t.Errorf("unexpected syntax result %+v", e)
+		}
+	}
+}
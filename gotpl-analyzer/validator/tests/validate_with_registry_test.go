@@ -0,0 +1,72 @@
+package validator_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestValidateTemplatesWithRegistry(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"views/page.html":          `{{template "card" .}}{{.Missing}}`,
+		"views/partials/card.html": `{{define "card"}}{{.Title}}{{.Subtitle}}{{end}}`,
+	})
+	calls := []ast.RenderCall{{
+		File:     "handlers.go",
+		Line:     12,
+		Template: "page.html",
+		Vars:     []ast.TemplateVar{{Name: "Title", TypeStr: "string"}},
+	}}
+
+	want, _, _ := validator.ValidateTemplates(calls, nil, dir, "views")
+	if len(want) == 0 {
+		t.Fatal("expected results from ValidateTemplates")
+	}
+
+	registry, _ := validator.ParseAllNamedTemplates(dir, "views")
+	for range 2 {
+		got := validator.ValidateTemplatesWithRegistry(calls, nil, dir, "views", registry, validator.ValidateOptions{})
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %#v, want %#v", got, want)
+		}
+	}
+
+	// The card body comes from the registry, not from a new parse.
+	got := validator.ValidateTemplatesWithRegistry(calls, nil, dir, "views", map[string][]validator.NamedBlockEntry{}, validator.ValidateOptions{})
+	for _, r := range got {
+		if strings.Contains(r.Message, ".Subtitle") {
+			t.Errorf("expected the card body to be skipped with an empty registry, got %+v", r)
+		}
+	}
+	if len(got) != 1 || got[0].Variable != ".Missing" {
+		t.Errorf("expected only .Missing with an empty registry, got %#v", got)
+	}
+}
+
+func TestValidateTemplatesWithRegistryFuncMapsAndOptions(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"views/page.html": `{{upper .Title}}{{shout .Title}}{{.Missing}}{{.Gone}}`,
+	})
+	calls := []ast.RenderCall{{Template: "page.html", Vars: []ast.TemplateVar{{Name: "Title", TypeStr: "string"}}}}
+	funcMaps := []ast.FuncMapInfo{{Name: "upper", Args: []string{"string"}, Returns: []ast.ParamInfo{{TypeStr: "string"}}}}
+	registry, _ := validator.ParseAllNamedTemplates(dir, "views")
+
+	got := validator.ValidateTemplatesWithRegistry(calls, funcMaps, dir, "views", registry, validator.ValidateOptions{})
+	undefined := map[string]bool{}
+	for _, r := range got {
+		undefined[r.Variable] = true
+	}
+	if undefined["upper"] || !undefined["shout"] || !undefined[".Missing"] || !undefined[".Gone"] {
+		t.Errorf("expected shout, .Missing and .Gone but not the custom upper to be reported, got %#v", got)
+	}
+
+	got = validator.ValidateTemplatesWithRegistry(calls, funcMaps, dir, "views", registry, validator.ValidateOptions{MaxErrors: 1})
+	if len(got) != 2 || !strings.Contains(got[1].Message, "suppressed (limit 1)") {
+		t.Errorf("expected MaxErrors to apply, got %#v", got)
+	}
+}
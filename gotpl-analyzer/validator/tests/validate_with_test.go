@@ -0,0 +1,60 @@
+package validator_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestMergeContexts(t *testing.T) {
+	setCalls := []ast.TemplateVar{
+		{Name: "User", TypeStr: "User", Fields: []ast.FieldInfo{{Name: "Name", TypeStr: "string"}}},
+		{Name: "Count", TypeStr: "int"},
+	}
+	data := []ast.TemplateVar{
+		{Name: "Title", TypeStr: "string"},
+		{Name: "Count", TypeStr: "int64"},
+		{Name: "User", TypeStr: "User", Fields: []ast.FieldInfo{{Name: "Email", TypeStr: "string"}}},
+	}
+
+	merged, conflicts := validator.MergeContextsWithConflicts(setCalls, data)
+
+	var names []string
+	for _, v := range merged {
+		names = append(names, v.Name+":"+v.TypeStr)
+	}
+	if want := []string{"User:User", "Count:int64", "Title:string"}; !slices.Equal(names, want) {
+		t.Errorf("expected %v, got %v", want, names)
+	}
+	if merged[0].Fields[0].Name != "Email" {
+		t.Errorf("expected the later User definition to win, got %+v", merged[0])
+	}
+	if len(conflicts) != 1 || conflicts[0].Name != "Count" || !slices.Equal(conflicts[0].Types, []string{"int", "int64"}) {
+		t.Errorf("expected a Count int/int64 conflict, got %+v", conflicts)
+	}
+
+	if got := validator.MergeContexts(setCalls, data); len(got) != len(merged) {
+		t.Errorf("expected MergeContexts to match, got %+v", got)
+	}
+}
+
+func TestValidateWith(t *testing.T) {
+	vars := validator.MergeContexts(
+		[]ast.TemplateVar{{Name: "Title", TypeStr: "string"}},
+		[]ast.TemplateVar{{Name: "User", TypeStr: "User", Fields: []ast.FieldInfo{{Name: "Name", TypeStr: "string"}}}},
+	)
+	content := `{{define "header"}}{{.Name}}{{.Nope}}{{end}}` +
+		`{{.Title}}{{.User.Name}}{{.Missing}}{{template "header" .User}}`
+
+	results := validator.ValidateWith(content, vars, "page.html", nil)
+
+	var got []string
+	for _, r := range results {
+		got = append(got, r.Variable)
+	}
+	if want := []string{".Missing", ".Nope"}; !slices.Equal(got, want) {
+		t.Errorf("expected errors for %v, got %#v", want, results)
+	}
+}
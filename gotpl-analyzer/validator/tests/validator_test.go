@@ -120,6 +120,23 @@ func TestValidateTemplateContent(t *testing.T) {
 			name:    "Valid range with variable assignment",
 			content: "{{ range $i := .Items }}{{ .Title }}{{ end }}",
 		},
+		{
+			// Unlike a Go range clause, a single range variable in a template
+			// is bound to the element, not the index.
+			name:    "Single range variable is the element",
+			content: "{{ range $item := .Items }}{{ $item.Title }}{{ end }}",
+		},
+		{
+			name:            "Single range variable element fields are checked",
+			content:         "{{ range $item := .Items }}{{ $item.Invalid }}{{ end }}",
+			wantErr:         true,
+			wantVariable:    "$item.Invalid",
+			wantMessagePart: "Invalid",
+		},
+		{
+			name:    "Two range variables bind index and element",
+			content: "{{ range $i, $item := .Items }}{{ $i }}{{ $item.Title }}{{ end }}",
+		},
 
 		// --- With scope ---
 		{
@@ -0,0 +1,70 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// shadowingCalls renders a template whose range element, User, has a Users
+// field named like the root variable it is ranged from.
+func shadowingCalls() []ast.RenderCall {
+	userFields := []ast.FieldInfo{
+		{Name: "Name", TypeStr: "string"},
+		{Name: "Users", TypeStr: "[]string", IsSlice: true, ElemType: "string"},
+	}
+	return []ast.RenderCall{{
+		File:     "handlers.go",
+		Line:     12,
+		Template: "users.html",
+		Vars: []ast.TemplateVar{
+			{Name: "Users", TypeStr: "[]User", IsSlice: true, ElemType: "User", Fields: userFields},
+			{Name: "Owner", TypeStr: "User", Fields: userFields},
+			{Name: "Title", TypeStr: "string"},
+		},
+	}}
+}
+
+func TestWarnShadowing(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{"templates/users.html": `{{if .Users}}{{.Users}}{{end}}
+{{range .Users}}{{.Name}} {{$.Users}} {{.Users}}{{end}}
+{{with .Owner}}{{if .Name}}{{.Users}}{{end}}{{end}}`})
+
+	results, _, _, _ := validator.ValidateTemplatesWithOptions(shadowingCalls(), nil, dir, "templates", validator.ValidateOptions{})
+	if len(results) != 0 {
+		t.Fatalf("expected no results without WarnShadowing, got %#v", results)
+	}
+
+	results, _, _, _ = validator.ValidateTemplatesWithOptions(shadowingCalls(), nil, dir, "templates", validator.ValidateOptions{WarnShadowing: true})
+	if len(results) != 2 {
+		t.Fatalf("expected 2 warnings, got %#v", results)
+	}
+	want := `".Users" refers to the Users field of the current scope, not the root variable; use $.Users if the root was intended`
+	for i, line := range []int{2, 3} {
+		r := results[i]
+		if r.Severity != "warning" || r.Message != want || r.Variable != ".Users" || r.Line != line {
+			t.Errorf("unexpected result %+v, want a warning on line %d", r, line)
+		}
+	}
+}
+
+func TestWarnShadowingContent(t *testing.T) {
+	vars := shadowingCalls()[0].Vars
+	content := `{{range .Users}}{{.Users}}{{.Missing}}{{end}}`
+
+	varMap := map[string]ast.TemplateVar{}
+	for _, v := range vars {
+		varMap[v.Name] = v
+	}
+	errs := validator.ValidateTemplateContent(content, varMap, "users.html", ".", ".", 1, nil)
+	if len(errs) != 1 || errs[0].Variable != ".Missing" {
+		t.Errorf("expected only the .Missing error from ValidateTemplateContent, got %#v", errs)
+	}
+
+	errs = validator.ValidateContentWithOptions(content, vars, false, "users.html", ".", ".", 1, nil, nil, validator.ValidateOptions{WarnShadowing: true})
+	if len(errs) != 2 || errs[0].Rule != "shadowed-root" || errs[1].Variable != ".Missing" {
+		t.Errorf("expected the warning and the error when enabled, got %#v", errs)
+	}
+}
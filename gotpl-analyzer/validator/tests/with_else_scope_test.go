@@ -0,0 +1,100 @@
+package validator_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// TestWithElseUsesOuterScope verifies that the else branch of a {{with}} is
+// validated against the scope enclosing the with, since it only runs when
+// the with's pipeline is empty.
+func TestWithElseUsesOuterScope(t *testing.T) {
+	user := []ast.FieldInfo{{Name: "Name", TypeStr: "string"}}
+	varMap := map[string]ast.TemplateVar{
+		"User":     {Name: "User", TypeStr: "User", Fields: user},
+		"Fallback": {Name: "Fallback", TypeStr: "string"},
+		"Teams": {Name: "Teams", TypeStr: "[]Team", IsSlice: true, ElemType: "Team", Fields: []ast.FieldInfo{
+			{Name: "Title", TypeStr: "string"},
+			{Name: "Lead", TypeStr: "User", Fields: user},
+		}},
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		invalid []string
+	}{
+		{
+			name:    "root variable in else",
+			content: `{{with .User}}{{.Name}}{{else}}{{.Fallback}}{{end}}`,
+		},
+		{
+			name:    "with field in else",
+			content: `{{with .User}}{{.Name}}{{else}}{{.Name}}{{end}}`,
+			invalid: []string{".Name"},
+		},
+		{
+			name:    "else if",
+			content: `{{with .User}}{{.Name}}{{else if .Fallback}}{{.Fallback}} {{.Name}}{{end}}`,
+			invalid: []string{".Name"},
+		},
+		{
+			name:    "scope after end",
+			content: `{{with .User}}{{.Name}}{{else}}{{.Fallback}}{{end}}{{.Fallback}} {{.Name}}`,
+			invalid: []string{".Name"},
+		},
+		{
+			name:    "inside range",
+			content: `{{range .Teams}}{{with .Lead}}{{.Name}}{{else}}{{.Title}} {{.Name}}{{end}}{{end}}`,
+			invalid: []string{".Name"},
+		},
+		{
+			name:    "nested with",
+			content: `{{with .User}}{{with .Name}}{{.}}{{else}}{{.Name}}{{end}}{{else}}{{.Fallback}}{{end}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			errs := validator.ValidateTemplateContent(tt.content, varMap, "test.html", ".", ".", 1, nil)
+			var got []string
+			for _, e := range errs {
+				got = append(got, e.Variable)
+			}
+			if len(got) != len(tt.invalid) {
+				t.Fatalf("expected %v to be reported, got %+v", tt.invalid, errs)
+			}
+			for i := range got {
+				if got[i] != tt.invalid[i] {
+					t.Errorf("expected %v to be reported, got %v", tt.invalid, got)
+				}
+			}
+		})
+	}
+}
+
+// TestWithElseCompletionsUseOuterScope verifies that scope replay for
+// completions also leaves the with scope at its else branch.
+func TestWithElseCompletionsUseOuterScope(t *testing.T) {
+	vars := []ast.TemplateVar{
+		{Name: "User", TypeStr: "User", Fields: []ast.FieldInfo{{Name: "Name", TypeStr: "string"}}},
+		{Name: "Fallback", TypeStr: "string"},
+	}
+
+	for content, want := range map[string]string{
+		`{{with .User}}{{.`:                      "Name",
+		`{{with .User}}{{.Name}}{{else}}{{.`:     "Fallback User",
+		`{{with .User}}{{else if .Fallback}}{{.`: "Fallback User",
+	} {
+		var names []string
+		for _, item := range validator.CompletionsAt(content, vars, len(content)) {
+			names = append(names, item.Name)
+		}
+		if got := strings.Join(names, " "); got != want {
+			t.Errorf("%s: expected %q, got %q", content, want, got)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package validator_test
+
+import (
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+// dbConfigFields are the fields of DBConfig, the value type of the
+// map[string]DBConfig variables below.
+var dbConfigFields = []ast.FieldInfo{
+	{Name: "Host", TypeStr: "string"},
+	{Name: "Port", TypeStr: "int"},
+}
+
+var configVars = map[string]ast.TemplateVar{
+	"Config": {
+		Name:     "Config",
+		TypeStr:  "map[string]DBConfig",
+		IsMap:    true,
+		KeyType:  "string",
+		ElemType: "DBConfig",
+		Fields:   dbConfigFields,
+	},
+	"App": {
+		Name:    "App",
+		TypeStr: "App",
+		Fields: []ast.FieldInfo{{
+			Name:     "Config",
+			TypeStr:  "map[string]DBConfig",
+			IsMap:    true,
+			KeyType:  "string",
+			ElemType: "DBConfig",
+			Fields:   dbConfigFields,
+		}},
+	},
+}
+
+func TestWithMapValue(t *testing.T) {
+	content := `{{with .Config.database}}{{.Host}}:{{.Port}}{{end}}
+{{with .App.Config.database}}{{.Host}}{{end}}
+{{with .Config}}{{with .database}}{{.Port}}{{end}}{{end}}
+{{with $db := .Config.database}}{{$db.Host}}{{end}}`
+
+	errs := validator.ValidateTemplateContent(content, configVars, "test.html", ".", ".", 1, nil)
+	for _, e := range errs {
+		t.Logf("Error: %s (variable: %s)", e.Message, e.Variable)
+	}
+	if len(errs) != 0 {
+		t.Errorf("Expected 0 errors, got %d", len(errs))
+	}
+}
+
+func TestWithMapValueRejectsUnknownField(t *testing.T) {
+	content := `{{with .Config.database}}{{.Missing}}{{end}}
+{{with .App.Config.database}}{{.Absent}}{{end}}`
+
+	errs := validator.ValidateTemplateContent(content, configVars, "test.html", ".", ".", 1, nil)
+	if len(errs) != 2 {
+		t.Fatalf("Expected 2 errors, got %d: %#v", len(errs), errs)
+	}
+	if errs[0].Variable != ".Missing" || errs[1].Variable != ".Absent" {
+		t.Errorf("Expected errors on .Missing and .Absent, got %q and %q", errs[0].Variable, errs[1].Variable)
+	}
+}
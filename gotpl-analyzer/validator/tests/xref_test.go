@@ -0,0 +1,41 @@
+package validator_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/validator"
+)
+
+func TestBuildXRef(t *testing.T) {
+	calls := []ast.RenderCall{
+		{File: "handlers/user.go", Line: 40, Template: "page.html"},
+		{File: "handlers/user.go", Line: 12, Template: "partials/nav.html"},
+		{File: "handlers/user.go", Line: 12, Template: "page.html"},
+		{File: "handlers/user.go", Line: 12, Template: "page.html"}, // duplicate site
+		{File: "handlers/admin.go", Line: 7, Template: "page.html"},
+		{File: ast.ContextFileRenderCall, Line: 1, Template: "orphan.html"},
+	}
+
+	xref := validator.BuildXRef(calls)
+
+	wantPage := []validator.XRefCall{
+		{File: "handlers/admin.go", Line: 7},
+		{File: "handlers/user.go", Line: 12},
+		{File: "handlers/user.go", Line: 40},
+	}
+	if got := xref.TemplatesToCalls["page.html"]; !reflect.DeepEqual(got, wantPage) {
+		t.Errorf("page.html calls = %#v, want %#v", got, wantPage)
+	}
+
+	wantUser := []string{"page.html", "partials/nav.html"}
+	if got := xref.CallsToTemplates["handlers/user.go"]; !reflect.DeepEqual(got, wantUser) {
+		t.Errorf("handlers/user.go templates = %#v, want %#v", got, wantUser)
+	}
+
+	orphan := xref.TemplatesToCalls["orphan.html"]
+	if len(orphan) != 1 || !orphan[0].Synthetic {
+		t.Errorf("expected a single synthetic call for orphan.html, got %#v", orphan)
+	}
+}
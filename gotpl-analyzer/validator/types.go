@@ -15,15 +15,27 @@ type ValidationResult struct {
 	// Column is the column number within the template file where the issue occurs.
 	Column int `json:"column"`
 
+	// EndLine is the line number where the offending expression ends.
+	EndLine int `json:"endLine,omitempty"`
+
+	// EndColumn is the column just past the end of the offending expression.
+	EndColumn int `json:"endColumn,omitempty"`
+
 	// Variable is the name of the template variable or expression that caused the issue.
 	Variable string `json:"variable"`
 
 	// Message is a human-readable description of the validation issue.
 	Message string `json:"message"`
 
-	// Severity indicates the severity of the issue (e.g., "error", "warning").
+	// Severity indicates the severity of the issue (e.g., "error", "warning",
+	// or SeveritySyntax for structural errors).
 	Severity string `json:"severity"`
 
+	// Rule is the id of the check that produced the result, such as
+	// "undefined-variable" or "syntax" (see RuleID). Custom rules and notes
+	// have none.
+	Rule string `json:"rule,omitempty"`
+
 	// GoFile is the path to the Go file that rendered the template, if applicable.
 	GoFile string `json:"goFile,omitempty"`
 
@@ -35,8 +47,22 @@ type ValidationResult struct {
 
 	// TemplateNameEndCol is the ending column of the template name literal in the Go file, if applicable.
 	TemplateNameEndCol int `json:"templateNameEndCol,omitempty"`
+
+	// span is the length of the prefix of Variable that caused the issue
+	// (e.g. up to the failing field segment). Zero means all of Variable.
+	span int
+
+	// nilData marks an error for a reference to dot when the template was
+	// executed with nil data; see rootUndefinedError.
+	nilData bool
 }
 
+// SeveritySyntax marks structural template errors such as an unexpected
+// {{end}}, an {{else}} without an opener or blocks left unclosed at the end
+// of the template. They are reported like any other result so one malformed
+// template never stops the others from being validated.
+const SeveritySyntax = "syntax"
+
 // ScopeType represents the contextual scope within a template, tracking available variables and their types.
 type ScopeType struct {
 	// IsRoot indicates if this is the top-level scope.
@@ -66,6 +92,25 @@ type ScopeType struct {
 
 	// IsMap indicates if the current scope represents a map.
 	IsMap bool
+
+	// NilData indicates that the template is executed with nil data, so every
+	// reference to a field of the root scope is an error. Only set on the root
+	// scope and the frames copied from it.
+	NilData bool
+
+	// nilInclude is the template included by a {{template}} call without a
+	// context argument, when that call rather than the render call is why
+	// the template is executed with nil data. Set along with NilData.
+	nilInclude string
+
+	// suggestions caches the candidates for "did you mean" hints on unknown
+	// root variables. Only set on the root frame of a template's validation.
+	suggestions *suggestionCandidates
+
+	// opener is the action keyword that pushed this frame while replaying a
+	// template up to a position, e.g. "range" or "else with". Empty for the
+	// root frame and for frames pushed during validation.
+	opener string
 }
 
 // NamedBlockEntry represents a {{define}} or {{block}} declaration found within a template file.
@@ -87,6 +132,24 @@ type NamedBlockEntry struct {
 
 	// Content is the raw content of the named block. It is omitted from JSON output.
 	Content string `json:"-"`
+
+	// IsBlock is true when the entry was declared with {{block}} rather than
+	// {{define}}, so its body is a default that a define may override.
+	IsBlock bool `json:"isBlock,omitempty"`
+
+	// OverridesBlock is true for a {{define}} that replaces the default body
+	// of a {{block}} with the same name. It is only set when
+	// ValidateOptions.AllowBlockOverride is enabled.
+	OverridesBlock bool `json:"overridesBlock,omitempty"`
+
+	// Props are the variables the block declares it requires in a props
+	// annotation. They are only set when ValidateOptions.ComponentProps is.
+	Props []ComponentProp `json:"props,omitempty"`
+
+	// foreign marks an entry from another template root (see
+	// ValidateOptions.ExtraTemplateRoots). It resolves {{template}} calls
+	// but is validated as part of its own root only.
+	foreign bool
 }
 
 // NamedBlockDuplicateError is reported when multiple template blocks with the same name are found across the project.
@@ -2,6 +2,7 @@ package validator
 
 import (
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
@@ -30,13 +31,63 @@ func IsFileBasedPartial(name string) bool {
 	return false
 }
 
+// lineIndex holds the byte offset at which each line of a template starts, so
+// that line and column lookups cost O(log n) instead of rescanning the content
+// before every action.
+type lineIndex []int
+
+// newLineIndex records the line starts of content.
+func newLineIndex(content string) lineIndex {
+	starts := make(lineIndex, 1, strings.Count(content, "\n")+1)
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// line returns the 1-based line containing the byte at offset.
+func (li lineIndex) line(offset int) int {
+	n, found := slices.BinarySearch(li, offset)
+	if found {
+		return n + 1
+	}
+	return n
+}
+
+// lineStart returns the offset of the first byte of the line containing offset.
+func (li lineIndex) lineStart(offset int) int {
+	return li[li.line(offset)-1]
+}
+
+// position returns the 1-based line and column of the byte at offset.
+func (li lineIndex) position(offset int) (line, col int) {
+	line = li.line(offset)
+	return line, offset - li[line-1] + 1
+}
+
 // isWhitespace checks if a byte is whitespace (space, tab, newline, carriage return).
 func isWhitespace(b byte) bool {
 	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
 }
 
-// ValidateTemplateFileStr exposes internal validation for testing.
-// Validates template content string with provided variables.
+// ValidateWith validates template content named name against vars, the
+// context the template is executed with. It is the entry point for callers
+// that assemble the context themselves, e.g. with MergeContexts;
+// ValidateTemplates and its variants derive it from render calls instead.
+//
+// {{template}} calls resolve against registry, which may be nil, and the
+// {{define}}s of content itself; file-based partials are looked up relative
+// to the working directory. Use ValidateTemplateFileStr to resolve them
+// under a template root or to check custom functions.
+func ValidateWith(content string, vars []ast.TemplateVar, name string, registry map[string][]NamedBlockEntry) []ValidationResult {
+	return ValidateTemplateContent(content, buildVarMap(vars), name, ".", ".", 1, registry)
+}
+
+// ValidateTemplateFileStr is ValidateWith for a template under
+// baseDir/templateRoot, which file-based partials resolve against, using
+// funcMaps for custom functions.
 func ValidateTemplateFileStr(
 	content string,
 	vars []ast.TemplateVar,
@@ -49,7 +100,8 @@ func ValidateTemplateFileStr(
 	return ValidateTemplateContent(content, varMap, templateName, baseDir, templateRoot, 1, registry, funcMaps)
 }
 
-// ValidateNamedBlockContent validates a named block body with a non-default line offset.
+// ValidateNamedBlockContent is ValidateTemplateFileStr for a named block
+// body, whose first line is lineOffset of the file declaring it.
 func ValidateNamedBlockContent(
 	content string,
 	vars []ast.TemplateVar,
@@ -63,6 +115,26 @@ func ValidateNamedBlockContent(
 	return ValidateTemplateContent(content, varMap, templateName, baseDir, templateRoot, lineOffset, registry, funcMaps)
 }
 
+// ValidateContentWithOptions is ValidateNamedBlockContent with the settings
+// of opts that apply to a single template, such as
+// DynamicTemplateNameSeverity and WarnShadowing. Settings of whole runs, such
+// as MaxErrors or Rules, are ignored. Use a lineOffset of 1 for a whole file.
+// Set nilData for a template every render call executes with nil data, as
+// listed by NilDataTemplates.
+func ValidateContentWithOptions(
+	content string,
+	vars []ast.TemplateVar,
+	nilData bool,
+	templateName string,
+	baseDir, templateRoot string,
+	lineOffset int,
+	registry map[string][]NamedBlockEntry,
+	funcMaps FuncMapRegistry,
+	opts ValidateOptions,
+) []ValidationResult {
+	return validateTemplateContent(content, buildVarMap(vars), nilData, templateName, baseDir, templateRoot, lineOffset, registry, funcMaps, opts.contentOptions(), nil)
+}
+
 // ParseAllNamedTemplates exposes named template parsing for testing.
 func ParseAllNamedTemplates(baseDir, templateRoot string) (map[string][]NamedBlockEntry, []NamedBlockDuplicateError) {
 	return parseAllNamedTemplates(baseDir, templateRoot)
@@ -17,12 +17,15 @@ package validator
 
 import (
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
 )
@@ -55,29 +58,410 @@ func ValidateTemplates(
 	baseDir string,
 	templateRoot string,
 ) ([]ValidationResult, map[string][]NamedBlockEntry, []NamedBlockDuplicateError) {
+	results, namedBlocks, namedBlockErrors, _ := ValidateTemplatesWithOptions(renderCalls, funcMaps, baseDir, templateRoot, ValidateOptions{})
+	return results, namedBlocks, namedBlockErrors
+}
+
+// ValidateOptions tunes ValidateTemplatesWithOptions. The zero value behaves
+// like ValidateTemplates.
+type ValidateOptions struct {
+	// MaxTemplateBytes skips template files larger than this many bytes, for
+	// trees containing huge generated HTML that is not a real template (see
+	// ast.AnalysisConfig.MaxTemplateBytes). Non-positive means no limit.
+	MaxTemplateBytes int
+
+	// Logger receives per-phase timings and counts. Nil discards them.
+	Logger *slog.Logger
+
+	// AllowBlockOverride resolves a name declared both as a {{block}} and a
+	// {{define}} to the define, as html/template does when a child template
+	// overrides a base layout's default (see ast.AnalysisConfig.AllowBlockOverride).
+	AllowBlockOverride bool
+
+	// Ignore excludes the template files it matches from named-block parsing
+	// and tree validation, e.g. a matcher built from .gitignore files. Nil
+	// walks every file.
+	Ignore *GitignoreMatcher
+
+	// TemplateRootResolver validates each render call against the template
+	// base directory and root returned for its Go file instead of baseDir and
+	// templateRoot (see ast.AnalysisConfig.TemplateRootResolver). Nil uses
+	// baseDir and templateRoot for every call.
+	TemplateRootResolver func(goFile string) (baseDir, templateRoot string)
+
+	// ExtraTemplateRoots are further template roots under baseDir validated
+	// together with templateRoot, e.g. "email/templates" next to
+	// "web/templates". Each root is walked and validated on its own, but
+	// render calls, {{template}} calls and file-based partials resolve
+	// against every root, in order. A template file or named block found in
+	// more than one root is a duplicate unless AllowBlockOverride is set, in
+	// which case a root's own templates win over those of other roots.
+	// Results from an extra root name their template by its path relative
+	// to baseDir, as with TemplateRootResolver, which takes precedence.
+	ExtraTemplateRoots []string
+
+	// ValidateBlockBodies additionally validates every {{define}} and
+	// {{block}} body on its own, against the union of the contexts its call
+	// sites pass (see ast.AnalysisConfig.ValidateBlockBodies).
+	ValidateBlockBodies bool
+
+	// Rules are project-specific checks run on every template file and named
+	// block after validation. They run concurrently; see Rule.
+	Rules []Rule
+
+	// StrictParse additionally parses every template file with text/template
+	// and reports the first parse error of files without another syntax
+	// result (see ast.AnalysisConfig.StrictParse).
+	StrictParse bool
+
+	// WarnEmptyTemplates warns on render call targets that receive variables
+	// but contain no actions (see ast.AnalysisConfig.WarnEmptyTemplates).
+	WarnEmptyTemplates bool
+
+	// MergeContexts additionally validates each template targeted by render
+	// calls that pass different variables against the variables all of them
+	// pass, and warns on references to the others (see
+	// ast.AnalysisConfig.MergeContexts).
+	MergeContexts bool
+
+	// DefineIsFileEntry validates a render call target consisting solely of
+	// a {{define}} named after the file against the define's body (see
+	// FileEntryDefine and ast.AnalysisConfig.DefineIsFileEntry).
+	DefineIsFileEntry bool
+
+	// DynamicTemplateNameSeverity is the severity ("info", "warning" or
+	// "error") of the note for a {{template}} whose name is a $variable or
+	// .Field and so cannot be verified (see
+	// ast.AnalysisConfig.DynamicTemplateNameSeverity). Empty emits no note.
+	DynamicTemplateNameSeverity string
+
+	// ComponentProps is the marker of the props annotations checked at
+	// {{template}} calls (see ast.AnalysisConfig.ComponentProps). Empty
+	// disables the check.
+	ComponentProps string
+
+	// WarnShadowing keeps the warnings for fields of range and with elements
+	// named like a root variable (see ast.AnalysisConfig.WarnShadowing).
+	WarnShadowing bool
+
+	// SecurityLints reports escaping mistakes: plain strings passed to a
+	// FuncMap entry marked ast.FuncMapInfo.Unescaped and template.HTML values
+	// escaped again with html (see ast.AnalysisConfig.SecurityLints).
+	SecurityLints bool
+
+	// WarnStructOutput warns on output actions such as {{.User}} that print
+	// a struct as a whole (see ast.AnalysisConfig.WarnStructOutput). The
+	// check runs with Rules.
+	WarnStructOutput bool
+
+	// MaxErrors caps the number of results returned, of any severity (see
+	// ast.AnalysisConfig.MaxErrors). Non-positive means no limit.
+	MaxErrors int
+
+	// WarningsAsErrors reports every warning as an error (see
+	// EscalateWarnings), before the results are counted against MaxErrors.
+	WarningsAsErrors bool
+
+	// WarnMissingContext warns on every template file of templateRoot and
+	// ExtraTemplateRoots that no render call targets and no other template
+	// includes, for runs whose render calls all come from a context file
+	// (see MissingContextWarnings).
+	WarnMissingContext bool
+
+	// sink receives every result (see resultSink). It is shared by the
+	// per-tree runs of TemplateRootResolver.
+	sink *resultSink
+
+	// Only restricts validation to the render calls whose template matches
+	// this glob (see MatchTemplate), and the results to those of matching
+	// templates. Named blocks are still parsed from the whole tree and every
+	// render call still contributes its context. Empty validates everything.
+	Only string
+}
+
+// ValidDynamicTemplateNameSeverity reports whether s is an accepted
+// ValidateOptions.DynamicTemplateNameSeverity: empty, "info", "warning" or
+// "error".
+func ValidDynamicTemplateNameSeverity(s string) bool {
+	switch s {
+	case "", "info", "warning", "error":
+		return true
+	}
+	return false
+}
+
+// ValidateTemplatesWithOptions is ValidateTemplates tuned by opts. Files
+// skipped because of opts.MaxTemplateBytes contribute no named blocks and are
+// not validated as part of the tree; a non-fatal note is returned for each one.
+//
+// With opts.MaxErrors set, validation stops once that many results have been
+// found and the results are cut to the limit, followed by a note saying how
+// many were suppressed.
+//
+// When baseDir/templateRoot is not a directory, the only template result is
+// an error saying so, instead of a "not found" error for every render call.
+//
+// FuncMap entries shadowing a text/template builtin are reported as warnings
+// at their definitions (see builtinShadowWarnings).
+func ValidateTemplatesWithOptions(
+	renderCalls []ast.RenderCall,
+	funcMaps []ast.FuncMapInfo,
+	baseDir string,
+	templateRoot string,
+	opts ValidateOptions,
+) ([]ValidationResult, map[string][]NamedBlockEntry, []NamedBlockDuplicateError, []string) {
+	return validateTemplates(renderCalls, funcMaps, baseDir, templateRoot, opts, nil)
+}
+
+// ValidateTemplatesStream is ValidateTemplatesWithOptions passing each
+// result to emit as soon as it is found instead of returning them, for
+// consumers that report results incrementally. emit is called from the
+// validation workers and must be safe for concurrent use. Results are
+// emitted in no particular order.
+func ValidateTemplatesStream(
+	renderCalls []ast.RenderCall,
+	funcMaps []ast.FuncMapInfo,
+	baseDir string,
+	templateRoot string,
+	opts ValidateOptions,
+	emit func(ValidationResult),
+) (map[string][]NamedBlockEntry, []NamedBlockDuplicateError, []string) {
+	_, namedBlocks, namedBlockErrors, notes := validateTemplates(renderCalls, funcMaps, baseDir, templateRoot, opts, emit)
+	return namedBlocks, namedBlockErrors, notes
+}
+
+// validateTemplates implements ValidateTemplatesWithOptions and
+// ValidateTemplatesStream, streaming to emit when it is not nil.
+func validateTemplates(
+	renderCalls []ast.RenderCall,
+	funcMaps []ast.FuncMapInfo,
+	baseDir string,
+	templateRoot string,
+	opts ValidateOptions,
+	emit func(ValidationResult),
+) ([]ValidationResult, map[string][]NamedBlockEntry, []NamedBlockDuplicateError, []string) {
+	validate := validateTemplateRoot
+	switch {
+	case opts.TemplateRootResolver != nil:
+		validate = validatePerTemplateRoot
+	case len(opts.ExtraTemplateRoots) > 0:
+		validate = validateTemplateRoots
+	}
+	opts.sink = newResultSink(opts, emit).withIgnore(newIgnoreIndex(baseDir, templateRoot))
+	results, namedBlocks, namedBlockErrors, notes := validate(renderCalls, funcMaps, baseDir, templateRoot, opts)
+	if opts.WarnMissingContext {
+		results = append(results, opts.sink.add(missingContextWarnings(renderCalls, baseDir, templateRoot, opts.ExtraTemplateRoots))...)
+	}
+	results = append(results, opts.sink.add(builtinShadowWarnings(funcMaps))...)
+	if note := opts.sink.finish(); note != nil {
+		results = append(results, *note)
+	}
+	return results, namedBlocks, namedBlockErrors, notes
+}
+
+// validateTemplateRoot is ValidateTemplatesWithOptions for a single template
+// root, ignoring opts.TemplateRootResolver.
+func validateTemplateRoot(
+	renderCalls []ast.RenderCall,
+	funcMaps []ast.FuncMapInfo,
+	baseDir string,
+	templateRoot string,
+	opts ValidateOptions,
+) ([]ValidationResult, map[string][]NamedBlockEntry, []NamedBlockDuplicateError, []string) {
+	if err := checkTemplateRoot(baseDir, templateRoot); err != nil {
+		return opts.sink.add([]ValidationResult{*err}), map[string][]NamedBlockEntry{}, nil, nil
+	}
+
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+
 	funcMapRegistry := BuildFuncMapRegistry(funcMaps)
+	root := filepath.Join(baseDir, templateRoot)
+	if !hasTemplateFiles(root, opts.Ignore) && !anyRenderTargetExists(renderCalls, root) {
+		logger.Info("no template files, validating render calls only", "root", root, "renderCalls", len(renderCalls))
+		return validateWithoutTemplates(renderCalls, funcMapRegistry, baseDir, templateRoot, opts), map[string][]NamedBlockEntry{}, nil, nil
+	}
+
 	// Parse all named blocks from the entire template tree.
-	namedBlocks, namedBlockErrors := parseAllNamedTemplates(baseDir, templateRoot)
+	start := time.Now()
+	namedBlocks, namedBlockErrors, skipped := parseAllNamedTemplatesWithMaxSize(baseDir, templateRoot, opts.MaxTemplateBytes, opts.Ignore, logger)
+	logger.Info("parsed named templates", "blocks", len(namedBlocks), "duplicates", len(namedBlockErrors), "skipped", len(skipped), "duration", time.Since(start))
+
+	opts.markRegistry(namedBlocks)
+	if opts.AllowBlockOverride {
+		namedBlockErrors = dropBlockOverrideDuplicates(namedBlockErrors)
+	}
+
+	skippedFiles := make(map[string]bool, len(skipped))
+	var notes []string
+	for _, rel := range skipped {
+		skippedFiles[rel] = true
+		notes = append(notes, fmt.Sprintf("skipped %s: exceeds max size", rel))
+	}
+
+	allErrors := validateWithRegistry(renderCalls, funcMapRegistry, baseDir, templateRoot, namedBlocks, skippedFiles, opts, logger)
+	return allErrors, namedBlocks, namedBlockErrors, notes
+}
+
+// ValidateTemplatesWithRegistry is ValidateTemplates without the parse phase:
+// it validates against registry, as returned by ParseAllNamedTemplates or
+// TemplateSet.NamedBlocks, instead of re-parsing every named block of the
+// tree. Long-running tools can parse once and validate many times; keeping
+// the registry in step with the template files is up to the caller.
+// Duplicate block definitions are reported by the parse, not here.
+//
+// opts tunes validation as in ValidateTemplatesWithOptions, except that
+// opts.TemplateRootResolver and opts.ExtraTemplateRoots are ignored since the
+// registry covers a single tree. opts.AllowBlockOverride and
+// opts.ComponentProps annotate the entries of registry in place.
+func ValidateTemplatesWithRegistry(
+	renderCalls []ast.RenderCall,
+	funcMaps []ast.FuncMapInfo,
+	baseDir string,
+	templateRoot string,
+	registry map[string][]NamedBlockEntry,
+	opts ValidateOptions,
+) []ValidationResult {
+	opts.sink = newResultSink(opts, nil).withIgnore(newIgnoreIndex(baseDir, templateRoot))
+	if err := checkTemplateRoot(baseDir, templateRoot); err != nil {
+		return opts.sink.add([]ValidationResult{*err})
+	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	opts.markRegistry(registry)
+
+	results := validateWithRegistry(renderCalls, BuildFuncMapRegistry(funcMaps), baseDir, templateRoot, registry, nil, opts, logger)
+	results = append(results, opts.sink.add(builtinShadowWarnings(funcMaps))...)
+	if note := opts.sink.finish(); note != nil {
+		results = append(results, *note)
+	}
+	return results
+}
+
+// validateWithRegistry is the validation half of validateTemplateRoot, run
+// against already parsed named blocks. Files in skippedFiles are not
+// validated as part of the tree.
+func validateWithRegistry(
+	renderCalls []ast.RenderCall,
+	funcMapRegistry FuncMapRegistry,
+	baseDir string,
+	templateRoot string,
+	namedBlocks map[string][]NamedBlockEntry,
+	skippedFiles map[string]bool,
+	opts ValidateOptions,
+	logger *slog.Logger,
+) []ValidationResult {
+	// Results name templates of this tree, so its directives apply.
+	opts.sink = opts.sink.withIgnore(newIgnoreIndex(baseDir, templateRoot))
 
 	// Build template-name → merged var list from all render calls.
-	renderVarsByTemplate := buildRenderVarIndex(renderCalls)
+	renderVarsByTemplate := MergeCallContexts(renderCalls)
 
 	// Find all templates used as partials to avoid validating them with empty context.
-	partialTargets := FindPartialTargets(baseDir, templateRoot)
+	partialTargets := findPartialTargets(baseDir, templateRoot, skippedFiles)
+
+	rules := opts.Rules
+	if opts.WarnStructOutput {
+		rules = append(slices.Clip(rules), structOutputRule{})
+	}
+	// Rules see the actions of each template as its validation visits them.
+	contentOpts := opts.contentOptions()
+	if len(rules) > 0 {
+		contentOpts.actions = newRuleActions()
+	}
 
 	// Validate render-call targets (existing behaviour).
-	renderErrors := validateRenderCallsConcurrently(renderCalls, baseDir, templateRoot, namedBlocks, partialTargets, funcMapRegistry)
+	start := time.Now()
+	renderErrors := validateRenderCallsConcurrently(FilterRenderCalls(renderCalls, opts.Only), baseDir, templateRoot, namedBlocks, partialTargets, skippedFiles, funcMapRegistry, opts.MergeContexts, contentOpts, opts.sink)
+	logger.Info("validated render call targets", "templates", len(renderVarsByTemplate), "results", len(renderErrors), "duration", time.Since(start))
 
 	// Validate all files in the tree not already covered.
-	treeErrors := validateTemplateTree(baseDir, templateRoot, namedBlocks, renderVarsByTemplate, partialTargets, funcMapRegistry)
+	start = time.Now()
+	treeErrors := validateTemplateTree(baseDir, templateRoot, namedBlocks, renderVarsByTemplate, partialTargets, skippedFiles, opts.Ignore, funcMapRegistry, contentOpts, opts.sink)
+	logger.Info("validated template tree", "results", len(treeErrors), "duration", time.Since(start))
 
 	// Validate named blocks not already covered by a render call.
-	blockErrors := validateOrphanedNamedBlocks(namedBlocks, renderVarsByTemplate, baseDir, templateRoot, partialTargets, funcMapRegistry)
+	start = time.Now()
+	blockErrors := validateOrphanedNamedBlocks(namedBlocks, renderVarsByTemplate, baseDir, templateRoot, partialTargets, funcMapRegistry, contentOpts, opts.sink)
+	logger.Info("validated orphaned named blocks", "results", len(blockErrors), "duration", time.Since(start))
 
 	allErrors := append(renderErrors, treeErrors...)
 	allErrors = append(allErrors, blockErrors...)
 
-	return allErrors, namedBlocks, namedBlockErrors
+	allErrors = append(allErrors, opts.sink.add(duplicateVarWarnings(FilterRenderCalls(renderCalls, opts.Only)))...)
+
+	if opts.WarnEmptyTemplates {
+		allErrors = append(allErrors, opts.sink.add(emptyTemplateWarnings(FilterRenderCalls(renderCalls, opts.Only), baseDir, templateRoot, partialTargets, skippedFiles))...)
+	}
+
+	if opts.ValidateBlockBodies {
+		start = time.Now()
+		bodyErrors := validateBlockBodies(renderCalls, namedBlocks, renderVarsByTemplate, baseDir, templateRoot, funcMapRegistry, opts.contentOptions(), opts.sink)
+		logger.Info("validated block bodies", "results", len(bodyErrors), "duration", time.Since(start))
+		allErrors = append(allErrors, bodyErrors...)
+	}
+
+	if opts.StrictParse {
+		start = time.Now()
+		parseErrors := validateStrictParse(allErrors, baseDir, templateRoot, skippedFiles, opts.Ignore, funcMapRegistry, opts.sink)
+		logger.Info("parsed templates strictly", "results", len(parseErrors), "duration", time.Since(start))
+		allErrors = append(allErrors, parseErrors...)
+	}
+
+	if len(rules) > 0 {
+		start = time.Now()
+		ruleErrors := runRules(rules, contentOpts.actions, baseDir, templateRoot, namedBlocks, renderVarsByTemplate, skippedFiles, opts.Ignore, funcMapRegistry, opts.sink)
+		logger.Info("ran custom rules", "rules", len(rules), "results", len(ruleErrors), "duration", time.Since(start))
+		allErrors = append(allErrors, ruleErrors...)
+	}
+
+	return allErrors
+}
+
+// MarkRegistry annotates the entries of registry in place as
+// opts.AllowBlockOverride and opts.ComponentProps ask for, like the registry
+// ValidateTemplatesWithOptions returns. Callers that replace entries of such
+// a registry, as the daemon does for unsaved templates, call it again.
+func (opts ValidateOptions) MarkRegistry(registry map[string][]NamedBlockEntry) {
+	opts.markRegistry(registry)
+}
+
+// markRegistry annotates the entries of registry in place as
+// opts.AllowBlockOverride and opts.ComponentProps ask for.
+func (opts ValidateOptions) markRegistry(registry map[string][]NamedBlockEntry) {
+	if opts.AllowBlockOverride {
+		markBlockOverrides(registry)
+	}
+	if opts.ComponentProps != "" {
+		markComponentProps(registry, opts.ComponentProps)
+	}
+}
+
+// checkTemplateRoot returns an error result when baseDir/templateRoot does not
+// exist or is not a directory.
+func checkTemplateRoot(baseDir, templateRoot string) *ValidationResult {
+	root := filepath.Join(baseDir, templateRoot)
+	info, err := os.Stat(root)
+	var msg string
+	switch {
+	case err != nil:
+		msg = fmt.Sprintf("Template root %q does not exist", root)
+	case !info.IsDir():
+		msg = fmt.Sprintf("Template root %q is not a directory", root)
+	default:
+		return nil
+	}
+	return &ValidationResult{
+		Template: templateRoot,
+		Line:     1,
+		Column:   1,
+		Message:  msg,
+		Severity: "error",
+	}
 }
 
 func BuildFuncMapRegistry(funcMaps []ast.FuncMapInfo) FuncMapRegistry {
@@ -88,10 +472,18 @@ func BuildFuncMapRegistry(funcMaps []ast.FuncMapInfo) FuncMapRegistry {
 	return registry
 }
 
-var templateRegex = regexp.MustCompile(`\{\{-?\s*(?:template|block|define)\s+["'\x60]([^"'\x60]+)["'\x60]`)
+// templateRegex matches the opening of a {{template}}, {{block}} or {{define}}
+// action up to its name, which is read with splitQuotedName.
+var templateRegex = regexp.MustCompile(`\{\{-?\s*(?:template|block|define)\s+`)
 
 // FindPartialTargets scans all template files to find targets of {{template "..."}} or {{block "..."}} calls.
 func FindPartialTargets(baseDir, templateRoot string) map[string]bool {
+	return findPartialTargets(baseDir, templateRoot, nil)
+}
+
+// findPartialTargets is FindPartialTargets without reading the files in
+// skippedFiles, keyed by their slash-separated path relative to the root.
+func findPartialTargets(baseDir, templateRoot string, skippedFiles map[string]bool) map[string]bool {
 	targets := make(map[string]bool)
 
 	root := filepath.Join(baseDir, templateRoot)
@@ -102,11 +494,16 @@ func FindPartialTargets(baseDir, templateRoot string) map[string]bool {
 		if !IsFileBasedPartial(path) {
 			return nil
 		}
+		if rel, err := filepath.Rel(root, path); err == nil && skippedFiles[filepath.ToSlash(rel)] {
+			return nil
+		}
 		content, err := os.ReadFile(path)
 		if err == nil {
-			matches := templateRegex.FindAllStringSubmatch(string(content), -1)
-			for _, m := range matches {
-				targets[m[1]] = true
+			text := string(content)
+			for _, m := range templateRegex.FindAllStringIndex(text, -1) {
+				if name, _, ok := splitQuotedName(text[m[1]:]); ok {
+					targets[name] = true
+				}
 			}
 		}
 		return nil
@@ -115,10 +512,11 @@ func FindPartialTargets(baseDir, templateRoot string) map[string]bool {
 	return targets
 }
 
-// buildRenderVarIndex creates a lookup: template-name → merged TemplateVar list.
+// MergeCallContexts creates a lookup: template-name → merged TemplateVar list.
 // When multiple render calls target the same template the variable sets are
-// unioned so validation gets the broadest possible context.
-func buildRenderVarIndex(renderCalls []ast.RenderCall) map[string][]ast.TemplateVar {
+// unioned into one superset context, so each template is validated once with
+// the broadest possible context.
+func MergeCallContexts(renderCalls []ast.RenderCall) map[string][]ast.TemplateVar {
 	idx := make(map[string][]ast.TemplateVar, len(renderCalls))
 	seen := make(map[string]map[string]bool, len(renderCalls))
 
@@ -138,16 +536,148 @@ func buildRenderVarIndex(renderCalls []ast.RenderCall) map[string][]ast.Template
 	return idx
 }
 
+// NilDataTemplates returns the templates that every render call targeting
+// them executes with nil data (see ast.RenderCall.NilData) and no variables.
+// Their root scope is strict and every reference to dot is reported as
+// reading from nil data.
+func NilDataTemplates(renderCalls []ast.RenderCall) map[string]bool {
+	nilData := make(map[string]bool, len(renderCalls))
+	for _, rc := range renderCalls {
+		if _, ok := nilData[rc.Template]; !ok {
+			nilData[rc.Template] = true
+		}
+		nilData[rc.Template] = nilData[rc.Template] && rc.NilData && len(rc.Vars) == 0
+	}
+	for name, isNil := range nilData {
+		if !isNil {
+			delete(nilData, name)
+		}
+	}
+	return nilData
+}
+
+// ContextConflict is a variable that MergeContexts found with different types
+// in the merged sets.
+type ContextConflict struct {
+	// Name is the variable name.
+	Name string `json:"name"`
+
+	// Types are the distinct types of the variable in merge order. The last
+	// one is the type kept.
+	Types []string `json:"types"`
+}
+
+// MergeContexts unions variable sets by name into one context for
+// ValidateWith, e.g. the variables of several Set calls and a data map. A
+// variable present in more than one set takes its definition from the last
+// one; the result keeps the order in which names first appear.
+func MergeContexts(vars ...[]ast.TemplateVar) []ast.TemplateVar {
+	merged, _ := MergeContextsWithConflicts(vars...)
+	return merged
+}
+
+// MergeContextsWithConflicts is MergeContexts also returning the variables
+// whose type differs between sets, sorted by name.
+func MergeContextsWithConflicts(vars ...[]ast.TemplateVar) ([]ast.TemplateVar, []ContextConflict) {
+	var merged []ast.TemplateVar
+	index := make(map[string]int)
+	types := make(map[string][]string)
+	for _, set := range vars {
+		for _, v := range set {
+			if !slices.Contains(types[v.Name], v.TypeStr) {
+				types[v.Name] = append(types[v.Name], v.TypeStr)
+			}
+			if i, ok := index[v.Name]; ok {
+				merged[i] = v
+				continue
+			}
+			index[v.Name] = len(merged)
+			merged = append(merged, v)
+		}
+	}
+
+	var conflicts []ContextConflict
+	for _, v := range merged {
+		if ts := types[v.Name]; len(ts) > 1 {
+			// Keep the winning type last even if it appeared earlier.
+			ts = append(slices.DeleteFunc(ts, func(t string) bool { return t == v.TypeStr }), v.TypeStr)
+			conflicts = append(conflicts, ContextConflict{Name: v.Name, Types: ts})
+		}
+	}
+	slices.SortFunc(conflicts, func(a, b ContextConflict) int { return strings.Compare(a.Name, b.Name) })
+	return merged, conflicts
+}
+
+// duplicateVarWarnings reports the variables a handler sets more than once
+// for a render call (see ast.RenderCall.DuplicateVars) at the definition of
+// the value that wins.
+func duplicateVarWarnings(renderCalls []ast.RenderCall) []ValidationResult {
+	var warnings []ValidationResult
+	for _, rc := range renderCalls {
+		for _, d := range rc.DuplicateVars {
+			warnings = append(warnings, ValidationResult{
+				Template: rc.Template,
+				Variable: "." + d.Name,
+				Message:  fmt.Sprintf("Variable %q is set multiple times for this render call; last value wins", d.Name),
+				Severity: "warning",
+				Rule:     "duplicate-var",
+				GoFile:   d.DefFile,
+				GoLine:   d.DefLine,
+			})
+		}
+	}
+	return warnings
+}
+
+// linkRenderCall points results at the Go render call rc that produced them.
+func linkRenderCall(results []ValidationResult, rc ast.RenderCall) []ValidationResult {
+	for i := range results {
+		results[i].GoFile = rc.File
+		results[i].GoLine = rc.Line
+		results[i].TemplateNameStartCol = rc.TemplateNameStartCol
+		results[i].TemplateNameEndCol = rc.TemplateNameEndCol
+	}
+	return results
+}
+
+// validateRenderTarget validates the template targeted by rc against vars,
+// or against nil data when nilData is set, honouring
+// ValidateOptions.DefineIsFileEntry.
+func validateRenderTarget(
+	rc ast.RenderCall,
+	vars []ast.TemplateVar,
+	nilData bool,
+	baseDir, templateRoot string,
+	namedBlocks map[string][]NamedBlockEntry,
+	funcMaps FuncMapRegistry,
+	opts contentOptions,
+) []ValidationResult {
+	templatePath := filepath.Join(baseDir, templateRoot, rc.Template)
+	if opts.defineIsFileEntry {
+		if errs, ok := validateDefineFileEntry(
+			templatePath, vars, nilData, rc.Template, baseDir, templateRoot, namedBlocks, funcMaps, opts,
+		); ok {
+			return errs
+		}
+	}
+	return validateTemplateFile(templatePath, vars, nilData, rc.Template, baseDir, templateRoot, namedBlocks, funcMaps, opts, []includeStep{{name: rc.Template}})
+}
+
 // validateTemplateTree walks every template file under baseDir/templateRoot and
 // validates files whose relative name was NOT already directly targeted by a
-// render call AND is NOT used as a partial. Already-validated files are skipped.
+// render call AND is NOT used as a partial. Already-validated and oversized
+// files are skipped.
 func validateTemplateTree(
 	baseDir string,
 	templateRoot string,
 	namedBlocks map[string][]NamedBlockEntry,
 	renderVarsByTemplate map[string][]ast.TemplateVar,
 	partialTargets map[string]bool,
+	skippedFiles map[string]bool,
+	ignore *GitignoreMatcher,
 	funcMaps FuncMapRegistry,
+	opts contentOptions,
+	sink *resultSink,
 ) []ValidationResult {
 	root := filepath.Join(baseDir, templateRoot)
 
@@ -159,10 +689,16 @@ func validateTemplateTree(
 
 	var items []workItem
 	filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info == nil || info.IsDir() {
+		if err != nil || info == nil {
 			return nil
 		}
-		if !IsFileBasedPartial(path) {
+		if ignore.Match(path, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() || !IsFileBasedPartial(path) {
 			return nil
 		}
 
@@ -182,6 +718,11 @@ func validateTemplateTree(
 			return nil
 		}
 
+		// Skip files that exceeded the size limit while parsing named blocks.
+		if skippedFiles[rel] {
+			return nil
+		}
+
 		items = append(items, workItem{
 			absPath: path,
 			relName: rel,
@@ -194,19 +735,24 @@ func validateTemplateTree(
 		return nil
 	}
 
-	return runWorkers(len(items), func(chunk []int) []ValidationResult {
+	return runWorkers(len(items), sink, func(chunk []int) []ValidationResult {
 		var errs []ValidationResult
 		for _, i := range chunk {
 			item := items[i]
-			errs = append(errs, ValidateTemplateFile(
-				item.absPath,
-				item.vars,
-				item.relName,
-				baseDir,
-				templateRoot,
-				namedBlocks,
-				funcMaps,
-			)...)
+			errs = append(errs, validateSafely(item.relName, func() []ValidationResult {
+				return validateTemplateFile(
+					item.absPath,
+					item.vars,
+					false,
+					item.relName,
+					baseDir,
+					templateRoot,
+					namedBlocks,
+					funcMaps,
+					opts,
+					[]includeStep{{name: item.relName}},
+				)
+			})...)
 		}
 		return errs
 	})
@@ -242,6 +788,8 @@ func validateOrphanedNamedBlocks(
 	templateRoot string,
 	partialTargets map[string]bool,
 	funcMaps FuncMapRegistry,
+	opts contentOptions,
+	sink *resultSink,
 ) []ValidationResult {
 	type workItem struct {
 		entry NamedBlockEntry
@@ -260,6 +808,9 @@ func validateOrphanedNamedBlocks(
 		}
 
 		for _, entry := range entries {
+			if entry.foreign {
+				continue
+			}
 			items = append(items, workItem{
 				entry: entry,
 				vars:  renderVarsByTemplate[name],
@@ -271,21 +822,25 @@ func validateOrphanedNamedBlocks(
 		return nil
 	}
 
-	return runWorkers(len(items), func(chunk []int) []ValidationResult {
+	return runWorkers(len(items), sink, func(chunk []int) []ValidationResult {
 		var errs []ValidationResult
 		for _, i := range chunk {
 			item := items[i]
-			varMap := buildVarMap(item.vars)
-			errs = append(errs, ValidateTemplateContent(
-				item.entry.Content,
-				varMap,
-				item.entry.TemplatePath,
-				baseDir,
-				templateRoot,
-				item.entry.Line,
-				namedBlocks,
-				funcMaps,
-			)...)
+			errs = append(errs, validateSafely(item.entry.TemplatePath, func() []ValidationResult {
+				return validateTemplateContent(
+					item.entry.Content,
+					buildVarMap(item.vars),
+					false,
+					item.entry.TemplatePath,
+					baseDir,
+					templateRoot,
+					item.entry.Line,
+					namedBlocks,
+					funcMaps,
+					opts,
+					opts.actions.recorder(item.entry.TemplatePath, item.entry.Name, item.entry.Line),
+				)
+			})...)
 		}
 		return errs
 	})
@@ -293,7 +848,11 @@ func validateOrphanedNamedBlocks(
 
 // runWorkers fans out index-based work to one goroutine per CPU core and
 // aggregates the results.  fn receives a slice of item indices to process.
-func runWorkers(total int, fn func([]int) []ValidationResult) []ValidationResult {
+//
+// With a non-nil sink, items are handed to fn one at a time, their results
+// pass through sink as soon as they are produced and a worker stops taking
+// new items once sink is full.
+func runWorkers(total int, sink *resultSink, fn func([]int) []ValidationResult) []ValidationResult {
 	numWorkers := max(runtime.NumCPU(), 1)
 	chunkSize := (total + numWorkers - 1) / numWorkers
 
@@ -315,7 +874,18 @@ func runWorkers(total int, fn func([]int) []ValidationResult) []ValidationResult
 		wg.Add(1)
 		go func(idx []int) {
 			defer wg.Done()
-			resultChan <- fn(idx)
+			if sink == nil {
+				resultChan <- fn(idx)
+				return
+			}
+			var results []ValidationResult
+			for _, i := range idx {
+				if sink.full() {
+					break
+				}
+				results = append(results, sink.add(fn([]int{i}))...)
+			}
+			resultChan <- results
 		}(indices)
 	}
 
@@ -332,20 +902,34 @@ func runWorkers(total int, fn func([]int) []ValidationResult) []ValidationResult
 }
 
 // validateRenderCallsConcurrently validates multiple render calls concurrently.
+// Targets in skippedFiles, which exceed ValidateOptions.MaxTemplateBytes and
+// are already reported by a note, are not validated. With mergeContexts set,
+// templates targeted by calls that disagree on their variables are also
+// checked with partialContextWarnings.
 func validateRenderCallsConcurrently(
 	renderCalls []ast.RenderCall,
 	baseDir string,
 	templateRoot string,
 	namedBlocks map[string][]NamedBlockEntry,
 	partialTargets map[string]bool,
+	skippedFiles map[string]bool,
 	funcMaps FuncMapRegistry,
+	mergeContexts bool,
+	opts contentOptions,
+	sink *resultSink,
 ) []ValidationResult {
 	if len(renderCalls) == 0 {
 		return nil
 	}
 
 	// Build the union var index FIRST — same as what the daemon uses for live validation.
-	renderVarsByTemplate := buildRenderVarIndex(renderCalls)
+	renderVarsByTemplate := MergeCallContexts(renderCalls)
+	nilDataTemplates := NilDataTemplates(renderCalls)
+	var sharedVars map[string][]ast.TemplateVar
+	var partialVars map[string]map[string]bool
+	if mergeContexts {
+		sharedVars, partialVars = sharedCallContexts(renderCalls)
+	}
 
 	// Deduplicate: only validate each unique template once, with unioned vars.
 	type workItem struct {
@@ -357,7 +941,7 @@ func validateRenderCallsConcurrently(
 	seen := make(map[string]bool)
 	var items []workItem
 	for _, rc := range renderCalls {
-		if seen[rc.Template] {
+		if seen[rc.Template] || skippedFiles[rc.Template] {
 			continue
 		}
 		seen[rc.Template] = true
@@ -371,24 +955,114 @@ func validateRenderCallsConcurrently(
 		})
 	}
 
-	return runWorkers(len(items), func(chunk []int) []ValidationResult {
+	results := runWorkers(len(items), sink, func(chunk []int) []ValidationResult {
 		var errors []ValidationResult
 		for _, i := range chunk {
 			item := items[i]
-			templatePath := filepath.Join(baseDir, templateRoot, item.template)
-			rcErrors := ValidateTemplateFile(
-				templatePath, item.vars, item.template, baseDir, templateRoot, namedBlocks, funcMaps,
-			)
-			for j := range rcErrors {
-				rcErrors[j].GoFile = item.rc.File
-				rcErrors[j].GoLine = item.rc.Line
-				rcErrors[j].TemplateNameStartCol = item.rc.TemplateNameStartCol
-				rcErrors[j].TemplateNameEndCol = item.rc.TemplateNameEndCol
-			}
-			errors = append(errors, rcErrors...)
+			rcErrors := validateSafely(item.template, func() []ValidationResult {
+				rcErrors := validateRenderTarget(item.rc, item.vars, nilDataTemplates[item.template], baseDir, templateRoot, namedBlocks, funcMaps, opts)
+				if shared, ok := sharedVars[item.template]; ok {
+					rcErrors = append(rcErrors, partialContextWarnings(
+						item.rc, shared, partialVars[item.template], baseDir, templateRoot, namedBlocks, funcMaps, opts,
+					)...)
+				}
+				return rcErrors
+			})
+			errors = append(errors, linkRenderCall(rcErrors, item.rc)...)
 		}
 		return errors
 	})
+	return append(results, sink.add(missingComposedTemplates(renderCalls, baseDir, templateRoot, namedBlocks))...)
+}
+
+// missingComposedTemplates reports the templates listed in a render call's
+// ComposedWith that are neither a file under the template root nor a named
+// block.
+func missingComposedTemplates(
+	renderCalls []ast.RenderCall,
+	baseDir, templateRoot string,
+	namedBlocks map[string][]NamedBlockEntry,
+) []ValidationResult {
+	var results []ValidationResult
+	for _, rc := range renderCalls {
+		for _, name := range rc.ComposedWith {
+			if _, ok := namedBlocks[name]; ok {
+				continue
+			}
+			if info, err := os.Stat(filepath.Join(baseDir, templateRoot, name)); err == nil && !info.IsDir() {
+				continue
+			}
+			results = append(results, ValidationResult{
+				Template: name,
+				Line:     1,
+				Column:   1,
+				Message:  fmt.Sprintf("Template or named block not found: %s", name),
+				Severity: "error",
+				Rule:     "missing-template",
+				GoFile:   rc.File,
+				GoLine:   rc.Line,
+			})
+		}
+	}
+	return results
+}
+
+// emptyTemplateWarnings reports the render call targets that are files
+// without a single action although the call passes variables. Actions are
+// found as forEachAction finds them, so comments do not count. Templates also
+// included through {{template}} are left alone, since a static partial is
+// usually intentional. Files in skippedFiles are not read.
+func emptyTemplateWarnings(
+	renderCalls []ast.RenderCall,
+	baseDir, templateRoot string,
+	partialTargets map[string]bool,
+	skippedFiles map[string]bool,
+) []ValidationResult {
+	var results []ValidationResult
+	seen := make(map[string]bool)
+	for _, rc := range renderCalls {
+		if len(rc.Vars) == 0 || seen[rc.Template] || partialTargets[rc.Template] || skippedFiles[rc.Template] {
+			continue
+		}
+		seen[rc.Template] = true
+		content, err := os.ReadFile(filepath.Join(baseDir, templateRoot, rc.Template))
+		if err != nil {
+			continue
+		}
+		hasAction := false
+		forEachAction(string(content), func(string, int) { hasAction = true })
+		if hasAction {
+			continue
+		}
+		results = append(results, linkRenderCall([]ValidationResult{{
+			Template: rc.Template,
+			Line:     1,
+			Column:   1,
+			Message:  fmt.Sprintf("Template %q contains no template actions", rc.Template),
+			Severity: "warning",
+			Rule:     "empty-template",
+		}}, rc)...)
+	}
+	return results
+}
+
+// builtinShadowWarnings reports every FuncMap entry named after a
+// text/template builtin, which silently changes what templates calling it do.
+func builtinShadowWarnings(funcMaps []ast.FuncMapInfo) []ValidationResult {
+	var results []ValidationResult
+	for _, fm := range funcMaps {
+		if !slices.Contains(ast.TemplateBuiltins, fm.Name) {
+			continue
+		}
+		results = append(results, ValidationResult{
+			Message:  fmt.Sprintf("Custom function %q shadows a template builtin", fm.Name),
+			Severity: "warning",
+			Rule:     "builtin-shadow",
+			GoFile:   fm.DefFile,
+			GoLine:   fm.DefLine,
+		})
+	}
+	return results
 }
 
 var validTemplateName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
@@ -409,15 +1083,39 @@ func ValidateTemplateFile(
 	registry map[string][]NamedBlockEntry,
 	funcMaps ...FuncMapRegistry,
 ) []ValidationResult {
-	effectiveFuncMaps := optionalFuncMapRegistry(funcMaps...)
+	return validateTemplateFile(templatePath, vars, false, templateName, baseDir, templateRoot, registry, optionalFuncMapRegistry(funcMaps...), contentOptions{}, []includeStep{{name: templateName}})
+}
+
+// validateTemplateFile is ValidateTemplateFile with nilData set when the
+// template is executed with nil data and the inclusion path of the enclosing
+// {{template}} calls, used for cycle detection.
+func validateTemplateFile(
+	templatePath string,
+	vars []ast.TemplateVar,
+	nilData bool,
+	templateName string,
+	baseDir, templateRoot string,
+	registry map[string][]NamedBlockEntry,
+	effectiveFuncMaps FuncMapRegistry,
+	opts contentOptions,
+	includePath []includeStep,
+) []ValidationResult {
+	// Only a template validated on its own, rather than through a
+	// {{template}} call, records its actions for rules.
+	record := func(template, block string, line int) func(RuleAction) {
+		if len(includePath) > 1 {
+			return nil
+		}
+		return opts.actions.recorder(template, block, line)
+	}
 
 	if entry, ok := findOverlayTemplateEntry(registry, templateName); ok {
 		varMap := buildVarMap(vars)
 		// Overlay content: merge once then use internal path.
 		effectiveRegistry := mergeNamedBlockRegistry(registry, entry.Content, entry.TemplatePath)
 		return validateTemplateContentWithRegistry(
-			entry.Content, varMap, entry.TemplatePath,
-			baseDir, templateRoot, 1, effectiveRegistry, effectiveFuncMaps,
+			entry.Content, varMap, nilData, entry.TemplatePath,
+			baseDir, templateRoot, 1, effectiveRegistry, effectiveFuncMaps, opts, includePath, record(templateName, "", 1),
 		)
 	}
 
@@ -428,8 +1126,8 @@ func ValidateTemplateFile(
 			entry := entries[0]
 			effectiveRegistry := mergeNamedBlockRegistry(registry, entry.Content, entry.TemplatePath)
 			return validateTemplateContentWithRegistry(
-				entry.Content, varMap, entry.TemplatePath,
-				baseDir, templateRoot, entry.Line, effectiveRegistry, effectiveFuncMaps,
+				entry.Content, varMap, nilData, entry.TemplatePath,
+				baseDir, templateRoot, entry.Line, effectiveRegistry, effectiveFuncMaps, opts, includePath, record(entry.TemplatePath, templateName, entry.Line),
 			)
 		}
 
@@ -441,6 +1139,7 @@ func ValidateTemplateFile(
 			Template: templateName, Line: 1, Column: 1,
 			Message:  fmt.Sprintf("Template or named block not found: %s", templateName),
 			Severity: "error",
+			Rule:     "missing-template",
 		}}
 	}
 
@@ -449,8 +1148,8 @@ func ValidateTemplateFile(
 	// will use this registry without re-merging.
 	effectiveRegistry := mergeNamedBlockRegistry(registry, string(content), templateName)
 	return validateTemplateContentWithRegistry(
-		string(content), varMap, templateName,
-		baseDir, templateRoot, 1, effectiveRegistry, effectiveFuncMaps,
+		string(content), varMap, nilData, templateName,
+		baseDir, templateRoot, 1, effectiveRegistry, effectiveFuncMaps, opts, includePath, record(templateName, "", 1),
 	)
 }
 
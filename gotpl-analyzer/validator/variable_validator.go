@@ -1,6 +1,8 @@
 package validator
 
 import (
+	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
@@ -177,13 +179,12 @@ func typeHasMethod(typeName, methodName string) bool {
 //   - varExpr: Variable expression to validate (e.g., ".User.Name")
 //   - scopeStack: Current scope stack
 //   - varMap: Root variable map
-//   - line, col: Source location for error reporting
-//   - templateName: Template name for error reporting
+//   - warnShadowing: Warn on element fields named like a root variable
 //
 // Returns: ValidationResult pointer if error found, nil if valid
 //
 // Thread-safety: Read-only operations, safe for concurrent calls.
-func validateVariableInScope(varExpr string, scopeStack []ScopeType, varMap map[string]ast.TemplateVar) *ValidationResult {
+func validateVariableInScope(varExpr string, scopeStack []ScopeType, varMap map[string]ast.TemplateVar, warnShadowing bool) *ValidationResult {
 	varExpr = strings.TrimSpace(varExpr)
 
 	if varExpr == "." || varExpr == "$" {
@@ -199,7 +200,7 @@ func validateVariableInScope(varExpr string, scopeStack []ScopeType, varMap map[
 			return nil
 		}
 		if len(localVar.Fields) == 0 && !localVar.IsMap && !localVar.IsSlice {
-			return nil
+			return basicTypeFieldError(varExpr, localVar.TypeStr, remainder)
 		}
 		return validateNestedFields(varExpr, remainder, localVar.Fields, localVar.TypeStr, localVar.IsMap, localVar.ElemType)
 	}
@@ -218,10 +219,9 @@ func validateVariableInScope(varExpr string, scopeStack []ScopeType, varMap map[
 		fieldName := parts[1]
 
 		if currentScope.IsMap {
-			if len(parts) > 2 {
-				return validateNestedFields(varExpr, parts[2:], nil, currentScope.ElemType, false, "")
-			}
-			return nil
+			// The key itself is dynamic; anything after it is validated
+			// against the value type (e.g. map[string]Settings → .key.Field).
+			return validateNestedFields(varExpr, parts[1:], currentScope.Fields, currentScope.TypeStr, true, currentScope.ElemType)
 		}
 
 		var foundField *ast.FieldInfo
@@ -235,16 +235,23 @@ func validateVariableInScope(varExpr string, scopeStack []ScopeType, varMap map[
 
 		if foundField != nil {
 			if len(parts) > 2 {
-				return validateNestedFields(varExpr, parts[2:], foundField.Fields, foundField.TypeStr, foundField.IsMap, foundField.ElemType)
+				if err := validateNestedFields(varExpr, parts[2:], foundField.Fields, foundField.TypeStr, foundField.IsMap, foundField.ElemType); err != nil {
+					return err
+				}
+			} else if warn := deprecatedFieldWarning(varExpr, *foundField); warn != nil {
+				return warn
+			}
+			if warnShadowing && !currentScope.IsRoot && isRootVariable(fieldName, scopeStack[0], varMap) {
+				return shadowedRootWarning(varExpr, fieldName)
 			}
 			return nil
 		}
 
-		if len(currentScope.Fields) == 0 {
+		if len(currentScope.Fields) == 0 && !currentScope.NilData {
 			return nil
 		}
 
-		return undefinedVariableError(varExpr)
+		return rootUndefinedError(varExpr, currentScope)
 	}
 
 	// ── Root variable access ───────────────────────────────────────────────
@@ -254,7 +261,7 @@ func validateVariableInScope(varExpr string, scopeStack []ScopeType, varMap map[
 		rootScope := scopeStack[0]
 		for _, f := range rootScope.Fields {
 			if f.Name == rootVar {
-				return nil
+				return deprecatedFieldWarning(varExpr, f)
 			}
 		}
 
@@ -265,11 +272,11 @@ func validateVariableInScope(varExpr string, scopeStack []ScopeType, varMap map[
 		// Only report an error when we have concrete field metadata for the root
 		// scope. When the scope is unresolved (empty fields) stay permissive to
 		// avoid false positives from partials rendered from multiple templates.
-		if len(rootScope.Fields) == 0 && len(varMap) == 0 {
+		if len(rootScope.Fields) == 0 && len(varMap) == 0 && !rootScope.NilData {
 			return nil
 		}
 
-		return undefinedVariableError(varExpr)
+		return withRootSuggestion(rootUndefinedError(varExpr, rootScope), rootVar, rootScope.suggestions.list(varMap, rootScope.Fields))
 	}
 
 	// ── Nested access: .Var.Field.SubField ─────────────────────────────────
@@ -288,13 +295,18 @@ func validateVariableInScope(varExpr string, scopeStack []ScopeType, varMap map[
 				return validateNestedFields(varExpr, parts[2:], f.Fields, f.TypeStr, f.IsMap, f.ElemType)
 			}
 		}
-		if len(rootScope.Fields) == 0 && len(varMap) == 0 {
+		if len(rootScope.Fields) == 0 && len(varMap) == 0 && !rootScope.NilData {
 			return nil
 		}
-		return undefinedVariableError(varExpr)
+		return withRootSuggestion(rootUndefinedError(varExpr, rootScope), rootVar, rootScope.suggestions.list(varMap, rootScope.Fields))
 	}
 
 	// rootVarInfo is guaranteed non-nil beyond this point.
+	if rootVarInfo.IsMap {
+		if err := validateMapKey(varExpr, parts[2], rootVarInfo.MapKeys); err != nil {
+			return err
+		}
+	}
 	if rootVarInfo.IsMap && len(parts) == 3 {
 		return nil
 	}
@@ -335,9 +347,11 @@ func validateNestedFields(fullExpr string, fieldParts []string, fields []ast.Fie
 	parentType := parentTypeName
 	currentIsMap := isMap
 	currentElemType := elemType
+	var last *ast.FieldInfo // field matched by the most recent segment
 
 	// Traverse each field in the path
-	for _, fieldName := range fieldParts {
+	for i, fieldName := range fieldParts {
+		last = nil
 		if currentIsMap {
 			// ── Map key access ─────────────────────────────────────────────
 			// Any key is valid for map access.
@@ -401,9 +415,10 @@ func validateNestedFields(fullExpr string, fieldParts []string, fields []ast.Fie
 		var nextIsMap bool
 		var nextElemType string
 
-		for _, f := range currentFields {
+		for j, f := range currentFields {
 			if f.Name == fieldName {
 				found = true
+				last = &currentFields[j]
 				nextFields = f.Fields
 				parentType = f.TypeStr
 				nextIsMap = f.IsMap
@@ -423,7 +438,9 @@ func validateNestedFields(fullExpr string, fieldParts []string, fields []ast.Fie
 				return nil
 			}
 
-			return undefinedVariableError(fullExpr)
+			err := undefinedVariableError(fullExpr)
+			err.span = segmentEnd(fullExpr, fieldParts[i+1:])
+			return err
 		}
 
 		// Move to next level in hierarchy
@@ -432,15 +449,190 @@ func validateNestedFields(fullExpr string, fieldParts []string, fields []ast.Fie
 		currentElemType = nextElemType
 	}
 
+	// Only the terminal segment is checked: .User.OldName warns, while
+	// .OldName.Field on a deprecated intermediate does not.
+	if last != nil {
+		return deprecatedFieldWarning(fullExpr, *last)
+	}
 	return nil
 }
 
+// validateParenChain validates the field chain applied to a parenthesized
+// expression, e.g. (index .Users 0).Address.City, against the inferred type
+// of the expression. Chains on expressions of unknown type are accepted.
+//
+// Pipeline results of a basic type, such as (printf "%d" .N).Length or
+// (.Items | len).Foo, have no fields at all; the result type of a builtin is
+// fixed and that of a custom function comes from its FuncMapInfo.Returns.
+func validateParenChain(inner string, chain []string, scopeStack []ScopeType, varMap map[string]ast.TemplateVar, funcMaps FuncMapRegistry) *ValidationResult {
+	base := InferExpressionType(inner, varMap, scopeStack, nil, funcMaps, nil)
+	if base == nil {
+		return nil
+	}
+
+	fullExpr := "(" + inner + ")." + strings.Join(chain, ".")
+	if len(base.Fields) == 0 {
+		if err := basicTypeFieldError(fullExpr, base.TypeStr, chain); err != nil {
+			err.Message = fmt.Sprintf("%s has no field %s", base.TypeStr, chain[0])
+			return err
+		}
+	}
+	err := validateNestedFields(fullExpr, chain, base.Fields, base.TypeStr, base.IsMap, base.ElemType)
+	if err == nil || err.Severity != "error" {
+		return err
+	}
+
+	// Name the type that lacks the field; the expression itself is not a
+	// variable, so the generic "not defined" message would be misleading.
+	typeName, fields := base.TypeStr, base.Fields
+	for _, name := range chain {
+		f := findFieldInfo(fields, name)
+		if f == nil {
+			err.Message = fmt.Sprintf("Field %q does not exist on type %s", name, strings.TrimLeft(typeName, "*"))
+			err.Rule = "unknown-field"
+			break
+		}
+		if f.IsMap {
+			break
+		}
+		typeName, fields = f.TypeStr, f.Fields
+	}
+	return err
+}
+
+// deprecatedFieldWarning reports a reference to a field marked deprecated in
+// its doc comment (see ast.AnalysisConfig.WarnDeprecated).
+func deprecatedFieldWarning(varExpr string, f ast.FieldInfo) *ValidationResult {
+	if f.Deprecated == "" {
+		return nil
+	}
+	return &ValidationResult{
+		Variable: varExpr,
+		Message:  fmt.Sprintf("Field %q is deprecated: %s", f.Name, f.Deprecated),
+		Severity: "warning",
+		Rule:     "deprecated-field",
+	}
+}
+
+// validateMapKey checks key against the finite key set recorded for a map
+// built from a composite literal (see ast.AnalysisConfig.StrictMapKeys).
+// Maps without a recorded key set are dynamic and accept any key.
+func validateMapKey(varExpr, key string, knownKeys []string) *ValidationResult {
+	if len(knownKeys) == 0 || slices.Contains(knownKeys, key) {
+		return nil
+	}
+	return &ValidationResult{
+		Variable: varExpr,
+		Message:  fmt.Sprintf(`Map key %q is not one of the known keys (%s)`, key, strings.Join(knownKeys, ", ")),
+		Severity: "warning",
+		Rule:     "unknown-map-key",
+	}
+}
+
+// rootUndefinedError reports an unknown field of a root-derived scope. When
+// the template is executed with nil data the error names the nil argument,
+// or the {{template}} call that passed no context, instead of the missing
+// field.
+func rootUndefinedError(varExpr string, scope ScopeType) *ValidationResult {
+	if scope.NilData {
+		message := fmt.Sprintf("Template references %q but render call passed nil data", varExpr)
+		if scope.nilInclude != "" {
+			message = fmt.Sprintf("Template %q accesses %s but was included with no context", scope.nilInclude, varExpr)
+		}
+		return &ValidationResult{
+			Variable: varExpr,
+			Message:  message,
+			Severity: "error",
+			Rule:     "nil-data",
+			nilData:  true,
+		}
+	}
+	return undefinedVariableError(varExpr)
+}
+
+// isRootVariable reports whether name is a variable of the template's root
+// scope.
+func isRootVariable(name string, rootScope ScopeType, varMap map[string]ast.TemplateVar) bool {
+	if _, ok := varMap[name]; ok {
+		return true
+	}
+	for _, f := range rootScope.Fields {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// shadowedRootWarning reports a reference inside a range or with block that
+// resolves to a field of the element although a root variable has the same
+// name. Go templates resolve it to the element's field, which is rarely what
+// was meant when the names collide.
+func shadowedRootWarning(varExpr, name string) *ValidationResult {
+	return &ValidationResult{
+		Variable: varExpr,
+		Message:  fmt.Sprintf("%q refers to the %s field of the current scope, not the root variable; use $.%s if the root was intended", "."+name, name, name),
+		Severity: "warning",
+		Rule:     "shadowed-root",
+		span:     len(name) + 1,
+	}
+}
+
 func undefinedVariableError(varExpr string) *ValidationResult {
 	return &ValidationResult{
 		Variable: varExpr,
 		Message:  `Template variable "` + varExpr + `" is not defined in the current scope`,
 		Severity: "error",
+		Rule:     "undefined-variable",
+	}
+}
+
+// basicTypeFieldError reports field access on a local of a predeclared
+// basic type, such as the int index of {{range $i, $e := .Items}} in
+// $i.Name. Locals of any other field-less type are accepted, since their
+// fields or methods may simply be unknown.
+func basicTypeFieldError(varExpr, typeStr string, rest []string) *ValidationResult {
+	if typeStr != "string" && typeStr != "bool" && !isNumericTypeName(typeStr) {
+		return nil
+	}
+	err := undefinedVariableError(varExpr)
+	err.Message = fmt.Sprintf("Field %q does not exist: %s has no fields", rest[0], typeStr)
+	err.Rule = "unknown-field"
+	err.span = segmentEnd(varExpr, rest[1:])
+	return err
+}
+
+// segmentEnd returns the length of the prefix of expr that ends just before
+// the trailing field segments in rest, i.e. the end of the failing segment.
+func segmentEnd(expr string, rest []string) int {
+	if len(rest) == 0 {
+		return len(expr)
+	}
+	return max(len(expr)-len(strings.Join(rest, "."))-1, 0)
+}
+
+// setRange positions r at the expression starting offset bytes into action,
+// whose first byte sits at line/col. Actions may span lines, so the start is
+// re-based past any newline before offset. The end covers span bytes, or all
+// of Variable when no narrower span was recorded.
+func (r *ValidationResult) setRange(action string, offset, line, col int) {
+	offset = min(offset, len(action))
+	before := action[:offset]
+	if nl := strings.LastIndexByte(before, '\n'); nl != -1 {
+		line += strings.Count(before, "\n")
+		col = offset - nl
+	} else {
+		col += offset
+	}
+
+	width := r.span
+	if width == 0 {
+		width = len(r.Variable)
 	}
+	r.Line = line
+	r.Column = col
+	r.EndLine = line
+	r.EndColumn = col + width
 }
 
 // validateContextArg checks whether a template call context expression
@@ -457,6 +649,7 @@ func validateContextArg(
 	scopeStack []ScopeType,
 	varMap map[string]ast.TemplateVar,
 	funcMaps FuncMapRegistry,
+	warnShadowing bool,
 ) *ValidationResult {
 	// Special cases always valid
 	if contextArg == "" || contextArg == "." || contextArg == "$" {
@@ -469,5 +662,5 @@ func validateContextArg(
 	}
 
 	// Validate using standard validation logic
-	return validateVariableInScope(contextArg, scopeStack, varMap)
+	return validateVariableInScope(contextArg, scopeStack, varMap, warnShadowing)
 }
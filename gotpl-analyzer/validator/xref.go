@@ -0,0 +1,84 @@
+package validator
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/abiiranathan/go-template-lsp/gotpl-analyzer/ast"
+)
+
+// XRefCall identifies a single render call site that targets a template.
+type XRefCall struct {
+	// File is the Go file containing the render call, relative to the analyzed directory.
+	File string `json:"file"`
+
+	// Line is the line number of the render call in File.
+	Line int `json:"line"`
+
+	// Synthetic is true when the call was synthesized from the context file
+	// rather than discovered in Go source.
+	Synthetic bool `json:"synthetic,omitempty"`
+}
+
+// XRef is a bidirectional index between templates and the Go render calls
+// that target them.
+type XRef struct {
+	// TemplatesToCalls maps each template name to the call sites rendering it,
+	// sorted by file then line.
+	TemplatesToCalls map[string][]XRefCall `json:"templatesToCalls"`
+
+	// CallsToTemplates maps each Go file to the sorted set of templates it renders.
+	CallsToTemplates map[string][]string `json:"callsToTemplates"`
+}
+
+// BuildXRef builds the template/Go cross-reference index from render calls
+// produced by ast.AnalyzeDir. Duplicate call sites (for example one call that
+// resolves to the same template name twice) are collapsed and every list is
+// sorted so the output is reproducible across runs.
+func BuildXRef(calls []ast.RenderCall) XRef {
+	xref := XRef{
+		TemplatesToCalls: make(map[string][]XRefCall),
+		CallsToTemplates: make(map[string][]string),
+	}
+
+	seenCalls := make(map[string]map[XRefCall]bool)
+	seenTemplates := make(map[string]map[string]bool)
+
+	for _, rc := range calls {
+		if rc.Template == "" {
+			continue
+		}
+
+		call := XRefCall{
+			File:      rc.File,
+			Line:      rc.Line,
+			Synthetic: rc.File == ast.ContextFileRenderCall,
+		}
+		if seenCalls[rc.Template] == nil {
+			seenCalls[rc.Template] = make(map[XRefCall]bool)
+		}
+		if !seenCalls[rc.Template][call] {
+			seenCalls[rc.Template][call] = true
+			xref.TemplatesToCalls[rc.Template] = append(xref.TemplatesToCalls[rc.Template], call)
+		}
+
+		if seenTemplates[rc.File] == nil {
+			seenTemplates[rc.File] = make(map[string]bool)
+		}
+		if !seenTemplates[rc.File][rc.Template] {
+			seenTemplates[rc.File][rc.Template] = true
+			xref.CallsToTemplates[rc.File] = append(xref.CallsToTemplates[rc.File], rc.Template)
+		}
+	}
+
+	for _, sites := range xref.TemplatesToCalls {
+		slices.SortFunc(sites, func(a, b XRefCall) int {
+			return cmp.Or(cmp.Compare(a.File, b.File), cmp.Compare(a.Line, b.Line))
+		})
+	}
+	for _, templates := range xref.CallsToTemplates {
+		slices.Sort(templates)
+	}
+
+	return xref
+}